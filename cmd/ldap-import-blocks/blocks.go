@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// groupObjectClasses are the object classes of the entries imported as
+// ldap_group rather than ldap_object.
+var groupObjectClasses = []string{"groupOfNames", "groupOfUniqueNames", "posixGroup", "groupOfURLs"}
+
+// resourceType returns the type of the resource to import an entry as.
+func resourceType(entry *ldap.Entry) string {
+	for _, class := range entry.GetAttributeValues("objectClass") {
+		for _, group := range groupObjectClasses {
+			if strings.EqualFold(class, group) {
+				return "ldap_group"
+			}
+		}
+	}
+	return "ldap_object"
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// resourceName derives a Terraform resource name from the RDN of an entry.
+func resourceName(dn string) string {
+	name := ""
+	if parsed, err := ldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 {
+		values := []string{}
+		for _, attribute := range parsed.RDNs[0].Attributes {
+			values = append(values, attribute.Value)
+		}
+		name = strings.Join(values, "_")
+	}
+	name = strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "entry"
+	}
+	if name[0] >= '0' && name[0] <= '9' || name[0] == '-' {
+		name = "_" + name
+	}
+	return name
+}
+
+// writeImportBlocks writes an import block for each entry; resources of the
+// same type are given unique names.
+func writeImportBlocks(w io.Writer, entries []*ldap.Entry) error {
+	used := map[string]bool{}
+	for _, entry := range entries {
+		kind := resourceType(entry)
+		base := resourceName(entry.DN)
+		name := base
+		for i := 2; used[kind+"."+name]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[kind+"."+name] = true
+
+		if _, err := fmt.Fprintf(w, "import {\n  to = %s.%s\n  id = %q\n}\n\n", kind, name, entry.DN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
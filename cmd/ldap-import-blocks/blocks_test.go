@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestResourceName(t *testing.T) {
+	cases := map[string]string{
+		"uid=jdoe,ou=users,dc=example,dc=com":          "jdoe",
+		"cn=Domain Admins,ou=groups,dc=example,dc=com": "domain_admins",
+		"cn=2fa-users,dc=example,dc=com":               "_2fa-users",
+		"cn=a+sn=b,dc=example,dc=com":                  "a_b",
+		"cn=Ünïcode,dc=example,dc=com":                 "n_code",
+		"cn=***,dc=example,dc=com":                     "entry",
+	}
+	for dn, expected := range cases {
+		if name := resourceName(dn); name != expected {
+			t.Errorf("%q: expected %q, got %q", dn, expected, name)
+		}
+	}
+}
+
+func TestWriteImportBlocks(t *testing.T) {
+	entries := []*ldap.Entry{
+		ldap.NewEntry("ou=people,dc=example,dc=com", map[string][]string{"objectClass": {"organizationalUnit"}}),
+		ldap.NewEntry("cn=admins,ou=people,dc=example,dc=com", map[string][]string{"objectClass": {"top", "groupOfNames"}}),
+		ldap.NewEntry("cn=admins,ou=groups,dc=example,dc=com", map[string][]string{"objectClass": {"posixGroup"}}),
+		ldap.NewEntry("cn=admins,dc=example,dc=com", map[string][]string{"objectClass": {"device"}}),
+	}
+
+	var buffer bytes.Buffer
+	if err := writeImportBlocks(&buffer, entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `import {
+  to = ldap_object.people
+  id = "ou=people,dc=example,dc=com"
+}
+
+import {
+  to = ldap_group.admins
+  id = "cn=admins,ou=people,dc=example,dc=com"
+}
+
+import {
+  to = ldap_group.admins_2
+  id = "cn=admins,ou=groups,dc=example,dc=com"
+}
+
+import {
+  to = ldap_object.admins
+  id = "cn=admins,dc=example,dc=com"
+}
+
+`
+	if buffer.String() != expected {
+		t.Errorf("unexpected import blocks:\n%s", buffer.String())
+	}
+}
@@ -0,0 +1,108 @@
+// Command ldap-import-blocks writes Terraform import blocks for the entries
+// found under a base DN, to be used with terraform plan -generate-config-out.
+//
+// It connects to the server with the same environment variables as the
+// provider (LDAP_HOST, LDAP_PORT, LDAP_BIND_USER, LDAP_BIND_PASSWORD...):
+//
+//	ldap-import-blocks -base ou=groups,dc=example,dc=com > imports.tf
+//	terraform plan -generate-config-out=generated.tf
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func main() {
+	var base, scope, filter, output string
+
+	flag.StringVar(&base, "base", "", "the DN of the entries to import, along with the entries below it (required)")
+	flag.StringVar(&scope, "scope", "sub", "the scope of the search: base, one or sub")
+	flag.StringVar(&filter, "filter", "(objectClass=*)", "only import the entries matching this LDAP filter")
+	flag.StringVar(&output, "o", "", "the file to write the import blocks to (default: standard output)")
+	flag.Parse()
+
+	if base == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	scopes := map[string]int{"base": ldap.ScopeBaseObject, "one": ldap.ScopeSingleLevel, "sub": ldap.ScopeWholeSubtree}
+	searchScope, ok := scopes[scope]
+	if !ok {
+		log.Fatalf("invalid scope %q: must be one of base, one or sub", scope)
+	}
+
+	conn, err := client.DialAndBind(configFromEnv())
+	if err != nil {
+		log.Fatalf("error connecting to the LDAP server: %v", err)
+	}
+	defer conn.Close()
+
+	request := ldap.NewSearchRequest(
+		base,
+		searchScope,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		filter,
+		[]string{"objectClass"},
+		nil,
+	)
+	sr, err := conn.SearchWithPaging(request, 500)
+	if err != nil {
+		log.Fatalf("error searching %q: %v", base, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeImportBlocks(w, sr.Entries); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote import blocks for %d entries", len(sr.Entries))
+}
+
+// configFromEnv returns the connection settings from the environment
+// variables the provider reads its defaults from.
+func configFromEnv() *client.Config {
+	boolEnv := func(name string) bool {
+		b, _ := strconv.ParseBool(os.Getenv(name))
+		return b
+	}
+	port := 389
+	if v := os.Getenv("LDAP_PORT"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("invalid LDAP_PORT %q", v)
+		}
+		port = p
+	}
+	config := &client.Config{
+		LDAPHost:     os.Getenv("LDAP_HOST"),
+		LDAPPort:     port,
+		LDAPISocket:  os.Getenv("LDAP_LDAPI_SOCKET"),
+		BindMethod:   os.Getenv("LDAP_BIND_METHOD"),
+		BindUser:     os.Getenv("LDAP_BIND_USER"),
+		BindPassword: os.Getenv("LDAP_BIND_PASSWORD"),
+		StartTLS:     boolEnv("LDAP_START_TLS"),
+		TLS:          boolEnv("LDAP_TLS"),
+		TLSInsecure:  boolEnv("LDAP_TLS_INSECURE"),
+	}
+	if config.LDAPHost == "" && config.LDAPISocket == "" {
+		log.Fatal("one of LDAP_HOST or LDAP_LDAPI_SOCKET must be set")
+	}
+	return config
+}
@@ -0,0 +1,11 @@
+data "ldap_bind" "service" {
+  dn       = ldap_object.service.dn
+  password = var.service_password
+}
+
+check "service_credentials" {
+  assert {
+    condition     = data.ldap_bind.service.success
+    error_message = "Cannot bind as ${data.ldap_bind.service.dn}: ${data.ldap_bind.service.diagnostic_message}"
+  }
+}
@@ -0,0 +1,13 @@
+data "ldap_count" "admins" {
+  base_dn = "ou=people,dc=example,dc=com"
+  filter  = "(memberOf=cn=admins,ou=groups,dc=example,dc=com)"
+}
+
+output "admins" {
+  value = data.ldap_count.admins.entry_count
+
+  precondition {
+    condition     = data.ldap_count.admins.entry_count <= 5
+    error_message = "There must be no more than 5 administrators."
+  }
+}
@@ -0,0 +1,13 @@
+data "ldap_deleted_objects" "leaver" {
+  filter     = "(sAMAccountName=jdoe)"
+  attributes = ["sAMAccountName"]
+}
+
+output "leaver_deleted" {
+  value = length(data.ldap_deleted_objects.leaver.entries) > 0
+
+  precondition {
+    condition     = length(data.ldap_deleted_objects.leaver.entries) <= 1
+    error_message = "Several tombstones match jdoe."
+  }
+}
@@ -0,0 +1,10 @@
+data "ldap_dn_exists" "legacy_ou" {
+  dn = "ou=legacy,dc=example,dc=com"
+}
+
+# only migrate the legacy users when their organizational unit still exists
+resource "ldap_ou_tree" "migrated" {
+  count = data.ldap_dn_exists.legacy_ou.exists ? 1 : 0
+
+  dn = "ou=migrated,ou=people,dc=example,dc=com"
+}
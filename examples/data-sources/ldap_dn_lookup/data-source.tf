@@ -0,0 +1,16 @@
+variable "admins" {
+  type    = list(string)
+  default = ["jdoe", "asmith"]
+}
+
+data "ldap_dn_lookup" "admins" {
+  base_dn = "ou=users,dc=example,dc=com"
+  names   = var.admins
+  filter  = "(objectClass=inetOrgPerson)"
+}
+
+resource "ldap_group" "admins" {
+  dn             = "cn=admins,ou=groups,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  member         = data.ldap_dn_lookup.admins.dn_list
+}
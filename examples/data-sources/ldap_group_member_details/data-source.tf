@@ -0,0 +1,8 @@
+data "ldap_group_member_details" "admins" {
+  dn         = "cn=admins,ou=groups,dc=example,dc=com"
+  attributes = ["uid", "mail", "displayName"]
+}
+
+output "admin_mails" {
+  value = [for member in data.ldap_group_member_details.admins.members : member.attributes.mail if lookup(member.attributes, "mail", "") != ""]
+}
@@ -0,0 +1,15 @@
+data "ldap_group_members" "admins" {
+  dn                = "cn=admins,ou=groups,dc=example,dc=com"
+  resolve_attribute = "uid"
+}
+
+resource "ldap_object" "home" {
+  for_each = data.ldap_group_members.admins.resolved
+
+  dn             = "cn=${each.value},ou=homes,dc=example,dc=com"
+  object_classes = ["applicationProcess"]
+  attributes = [
+    { cn = each.value },
+    { description = "Home of ${each.key}" },
+  ]
+}
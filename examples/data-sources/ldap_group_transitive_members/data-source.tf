@@ -0,0 +1,8 @@
+data "ldap_group_transitive_members" "engineering" {
+  dn        = "cn=engineering,ou=groups,dc=example,dc=com"
+  max_depth = 5
+}
+
+output "engineers" {
+  value = data.ldap_group_transitive_members.engineering.members
+}
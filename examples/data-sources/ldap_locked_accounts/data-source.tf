@@ -0,0 +1,13 @@
+data "ldap_locked_accounts" "people" {
+  base_dn = "ou=people,dc=example,dc=com"
+  filter  = "(objectClass=person)"
+}
+
+# resets the accounts locked out by failed binds, leaving those locked by an
+# administrator
+resource "ldap_account_lock" "reset" {
+  for_each = toset([for account in data.ldap_locked_accounts.people.accounts : account.dn if !account.permanent])
+
+  dn     = each.value
+  locked = false
+}
@@ -0,0 +1,10 @@
+data "ldap_monitor" "this" {}
+
+output "ldap_connections" {
+  value = data.ldap_monitor.this.current_connections
+
+  precondition {
+    condition     = data.ldap_monitor.this.active_threads < data.ldap_monitor.this.max_threads
+    error_message = "All the worker threads of the LDAP server are busy."
+  }
+}
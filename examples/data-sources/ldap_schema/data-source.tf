@@ -0,0 +1,12 @@
+data "ldap_schema" "person" {
+  include_object_classes = ["inetOrgPerson", "posixAccount"]
+}
+
+# Required attributes of inetOrgPerson (excluding the ones of its superclasses)
+output "inet_org_person_must" {
+  value = [for oc in data.ldap_schema.person.object_classes : oc.must if oc.name == "inetOrgPerson"][0]
+}
+
+output "single_valued_attributes" {
+  value = [for at in data.ldap_schema.person.attribute_types : at.name if at.single_value]
+}
@@ -23,3 +23,17 @@ data "ldap_search_map" "groups" {
   paged_size           = 500
   requested_attributes = ["cn", "member"]
 }
+
+# Huge directory — read one window of the sorted results (needs VLV support)
+data "ldap_search_map" "users_page_3" {
+  base_dn       = "ou=users,dc=example,dc=com"
+  filter        = "(objectClass=inetOrgPerson)"
+  key_attribute = "uid"
+  sort_by       = ["uid"]
+  window_offset = 2001
+  window_size   = 1000
+}
+
+output "users_total" {
+  value = data.ldap_search_map.users_page_3.total_count
+}
@@ -0,0 +1,12 @@
+data "ldap_server_capabilities" "server" {}
+
+# the tree is only created when the server can delete it in one request
+resource "ldap_ou_tree" "scratch" {
+  count = data.ldap_server_capabilities.server.supports_tree_delete ? 1 : 0
+
+  dn = "ou=scratch,dc=example,dc=com"
+}
+
+output "sasl_mechanisms" {
+  value = data.ldap_server_capabilities.server.sasl_mechanisms
+}
@@ -0,0 +1,17 @@
+variable "service_password" {
+  type      = string
+  sensitive = true
+  ephemeral = true
+}
+
+ephemeral "ldap_bind" "service" {
+  dn       = ldap_object.service.dn
+  password = var.service_password
+}
+
+# checks the credentials without the password ever reaching the state
+resource "terraform_data" "service_credentials" {
+  provisioner "local-exec" {
+    command = ephemeral.ldap_bind.service.success ? "true" : "echo 'cannot bind: ${ephemeral.ldap_bind.service.diagnostic_message}' >&2; false"
+  }
+}
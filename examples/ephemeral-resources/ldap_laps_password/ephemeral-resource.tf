@@ -0,0 +1,14 @@
+ephemeral "ldap_laps_password" "ws01" {
+  dn = "CN=WS01,OU=Workstations,DC=example,DC=com"
+}
+
+# hands the password over to a runbook without it ever reaching the state
+resource "terraform_data" "rotate_service" {
+  provisioner "local-exec" {
+    command = "./runbooks/rotate-service.sh WS01"
+    environment = {
+      ADMIN_USER     = ephemeral.ldap_laps_password.ws01.account_name
+      ADMIN_PASSWORD = ephemeral.ldap_laps_password.ws01.password
+    }
+  }
+}
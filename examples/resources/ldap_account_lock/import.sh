@@ -0,0 +1 @@
+$ terraform import ldap_account_lock.jdoe uid=jdoe,ou=users,dc=example,dc=com
@@ -0,0 +1,5 @@
+# locks out a compromised account until it is set to false again
+resource "ldap_account_lock" "jdoe" {
+  dn     = "uid=jdoe,ou=users,dc=example,dc=com"
+  locked = true
+}
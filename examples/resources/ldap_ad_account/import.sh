@@ -0,0 +1 @@
+$ terraform import ldap_ad_account.leaver "CN=John Doe,OU=Users,DC=example,DC=com"
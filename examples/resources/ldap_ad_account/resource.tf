@@ -0,0 +1,10 @@
+# disables a leaver, and keeps a service account unlocked
+resource "ldap_ad_account" "leaver" {
+  dn      = "CN=John Doe,OU=Users,DC=example,DC=com"
+  enabled = false
+}
+
+resource "ldap_ad_account" "svc_backup" {
+  dn         = "CN=svc-backup,OU=Service Accounts,DC=example,DC=com"
+  locked_out = false
+}
@@ -0,0 +1 @@
+$ terraform import ldap_alias.jdoe uid=jdoe,ou=views,dc=example,dc=com
@@ -0,0 +1,10 @@
+resource "ldap_object" "views" {
+  dn             = "ou=views,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+# presents uid=jdoe,ou=people,dc=example,dc=com under ou=views
+resource "ldap_alias" "jdoe" {
+  dn                  = "uid=jdoe,${ldap_object.views.dn}"
+  aliased_object_name = "uid=jdoe,ou=people,dc=example,dc=com"
+}
@@ -0,0 +1 @@
+$ terraform import ldap_attribute.jdoe_shell 'uid=jdoe,ou=users,dc=example,dc=com|loginShell'
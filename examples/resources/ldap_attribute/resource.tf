@@ -0,0 +1,6 @@
+# uid=jdoe is provisioned by the HR synchronization
+resource "ldap_attribute" "jdoe_shell" {
+  dn     = "uid=jdoe,ou=users,dc=example,dc=com"
+  name   = "loginShell"
+  values = ["/bin/zsh"]
+}
@@ -0,0 +1 @@
+$ terraform import ldap_attribute_value.alias 'uid=jdoe,ou=users,dc=example,dc=com|mailAlternateAddress|john.doe@example.com'
@@ -0,0 +1,6 @@
+# the key of the deployment user, which may have others added elsewhere
+resource "ldap_attribute_value" "deploy_key" {
+  dn    = "uid=deploy,ou=users,dc=example,dc=com"
+  name  = "sshPublicKey"
+  value = file("${path.module}/deploy.pub")
+}
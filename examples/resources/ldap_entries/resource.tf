@@ -0,0 +1,25 @@
+locals {
+  hosts = {
+    web01 = "10.0.0.11"
+    web02 = "10.0.0.12"
+    db01  = "10.0.1.21"
+  }
+}
+
+resource "ldap_entries" "hosts" {
+  entries = merge(
+    {
+      "ou=hosts,dc=example,dc=com" = jsonencode({
+        objectClass = ["organizationalUnit"]
+        ou          = "hosts"
+      })
+    },
+    {
+      for name, ip in local.hosts : "cn=${name},ou=hosts,dc=example,dc=com" => jsonencode({
+        objectClass  = ["device", "ipHost"]
+        cn           = name
+        ipHostNumber = ip
+      })
+    },
+  )
+}
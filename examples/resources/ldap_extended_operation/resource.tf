@@ -0,0 +1,20 @@
+# Refresh a dynamic entry (RFC 2589) for another day whenever its ttl changes.
+resource "ldap_extended_operation" "refresh_meeting" {
+  request_name = "1.3.6.1.4.1.1466.101.119.1"
+
+  # SEQUENCE { entryName [0] "cn=meeting,ou=dynamic,dc=example,dc=com", requestTtl [1] 86400 }
+  request_value = "MC6AJ2NuPW1lZXRpbmcsb3U9ZHluYW1pYyxkYz1leGFtcGxlLGRjPWNvbYEDAVGA"
+
+  triggers = {
+    ttl = 86400
+  }
+}
+
+# Who am I? (RFC 4532)
+resource "ldap_extended_operation" "whoami" {
+  request_name = "1.3.6.1.4.1.4203.1.11.3"
+}
+
+output "authzid" {
+  value = base64decode(ldap_extended_operation.whoami.response_value)
+}
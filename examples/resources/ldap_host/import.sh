@@ -0,0 +1 @@
+$ terraform import ldap_host.web01 cn=web01,ou=hosts,dc=example,dc=com
@@ -0,0 +1,7 @@
+resource "ldap_host" "web01" {
+  dn             = "cn=web01,ou=hosts,dc=example,dc=com"
+  ip_host_number = ["192.0.2.10"]
+  mac_address    = ["00:1b:21:0a:3c:4f"]
+  boot_file      = "pxelinux.0"
+  description    = "Web server"
+}
@@ -0,0 +1 @@
+$ terraform import ldap_kerberos_principal.jdoe krbPrincipalName=jdoe@EXAMPLE.COM,cn=EXAMPLE.COM,cn=krbContainer,dc=example,dc=com
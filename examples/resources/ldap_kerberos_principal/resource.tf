@@ -0,0 +1,6 @@
+resource "ldap_kerberos_principal" "jdoe" {
+  dn                   = "krbPrincipalName=jdoe@EXAMPLE.COM,cn=EXAMPLE.COM,cn=krbContainer,dc=example,dc=com"
+  principal_name       = "jdoe@EXAMPLE.COM"
+  password_policy_dn   = "cn=users,cn=EXAMPLE.COM,cn=krbContainer,dc=example,dc=com"
+  principal_expiration = "2025-12-31T23:59:59Z"
+}
@@ -0,0 +1,21 @@
+resource "ldap_ldif" "people" {
+  content = <<-EOT
+    dn: ou=people,dc=example,dc=com
+    objectClass: organizationalUnit
+    ou: people
+
+    dn: uid=jdoe,ou=people,dc=example,dc=com
+    objectClass: inetOrgPerson
+    uid: jdoe
+    cn: John Doe
+    sn: Doe
+    mail: jdoe@example.com
+  EOT
+}
+
+# or migrate an existing export
+resource "ldap_ldif" "groups" {
+  content = file("${path.module}/groups.ldif")
+
+  depends_on = [ldap_ldif.people]
+}
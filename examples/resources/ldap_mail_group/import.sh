@@ -0,0 +1 @@
+$ terraform import ldap_mail_group.staff cn=staff,ou=lists,dc=example,dc=com
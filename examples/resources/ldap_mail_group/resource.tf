@@ -0,0 +1,18 @@
+resource "ldap_mail_group" "staff" {
+  dn   = "cn=staff,ou=lists,dc=example,dc=com"
+  mail = "staff@example.com"
+
+  members = [
+    "uid=jdoe,ou=people,dc=example,dc=com",
+  ]
+  member_addresses = [
+    "partner@example.org",
+  ]
+}
+
+# an alias read by Postfix or Sendmail
+resource "ldap_mail_group" "postmaster" {
+  dn               = "cn=postmaster,ou=aliases,dc=example,dc=com"
+  kind             = "nisMailAlias"
+  member_addresses = ["jdoe@example.com"]
+}
@@ -20,3 +20,57 @@ resource "ldap_object" "a123456" {
     { loginShell = "/bin/bash" }
   ]
 }
+
+# refuse to overwrite out-of-band changes made since the last refresh
+resource "ldap_object" "service" {
+  dn               = "cn=service,${ldap_object.users_example_com.dn}"
+  object_classes   = ["device"]
+  attributes       = [{ description = "managed by Terraform" }]
+  assertion_filter = "(!(description=locked))"
+  assert_unchanged = true
+}
+
+# only manage the description, leaving the other attributes to other systems
+resource "ldap_object" "shared" {
+  dn                 = "ou=shared,dc=example,dc=com"
+  object_classes     = ["top", "organizationalUnit"]
+  attributes         = [{ description = "managed by Terraform" }]
+  managed_attributes = ["description"]
+}
+
+# hide the password from plans and state output
+resource "ldap_object" "bind" {
+  dn                   = "cn=bind,${ldap_object.users_example_com.dn}"
+  object_classes       = ["simpleSecurityObject", "organizationalRole"]
+  attributes           = [{ description = "bind account" }]
+  sensitive_attributes = [{ userPassword = var.bind_password }]
+}
+
+# set the password without storing it in the state (Terraform 1.11+);
+# bump password_version to send a new one
+resource "ldap_object" "robot" {
+  dn               = "cn=robot,${ldap_object.users_example_com.dn}"
+  object_classes   = ["simpleSecurityObject", "organizationalRole"]
+  password_wo      = var.robot_password
+  password_version = 1
+}
+
+# declare the DN by its parts: the value is escaped, and changing it or the
+# parent renames or moves the entry in place
+resource "ldap_object" "sales" {
+  rdn_attribute  = "ou"
+  rdn_value      = "Sales, EMEA"
+  parent_dn      = ldap_object.users_example_com.dn
+  object_classes = ["top", "organizationalUnit"]
+}
+
+# attributes built programmatically, as a map of lists of values
+resource "ldap_object" "jdoe" {
+  dn             = "uid=jdoe,${ldap_object.users_example_com.dn}"
+  object_classes = ["inetOrgPerson"]
+  attributes_json = jsonencode({
+    cn   = ["John Doe"]
+    sn   = ["Doe"]
+    mail = [for domain in ["example.com", "example.org"] : "jdoe@${domain}"]
+  })
+}
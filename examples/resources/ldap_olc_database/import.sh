@@ -0,0 +1,3 @@
+# either the DN of the database entry or its suffix
+$ terraform import ldap_olc_database.example "olcDatabase={2}mdb,cn=config"
+$ terraform import ldap_olc_database.example dc=example,dc=com
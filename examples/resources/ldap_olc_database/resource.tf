@@ -0,0 +1,20 @@
+resource "ldap_olc_database" "example" {
+  provider = ldap.config # see ldap_olc_global for an ldapi/EXTERNAL provider
+
+  suffix        = "dc=example,dc=com"
+  directory     = "/var/lib/ldap/example"
+  root_dn       = "cn=admin,dc=example,dc=com"
+  root_password = "{SSHA}xIfrWhOoKUgRgPzf0Ohu7PmBMaKrMyS9"
+  max_size      = 1073741824
+
+  indexes = [
+    "objectClass eq",
+    "uid eq",
+    "cn,sn eq,sub",
+  ]
+
+  access = [
+    "to attrs=userPassword by self write by anonymous auth by * none",
+    "to * by self write by users read by * none",
+  ]
+}
@@ -0,0 +1 @@
+$ terraform import ldap_olc_global.this cn=config
@@ -0,0 +1,19 @@
+# cn=config is usually only writable by root over the ldapi socket
+provider "ldap" {
+  alias        = "config"
+  ldapi_socket = "/var/run/slapd/ldapi"
+  bind_method  = "external"
+}
+
+resource "ldap_olc_global" "this" {
+  provider = ldap.config
+
+  log_level    = ["stats", "sync"]
+  size_limit   = "1000"
+  idle_timeout = 600
+
+  tls_certificate_file     = "/etc/ldap/tls/server.crt"
+  tls_certificate_key_file = "/etc/ldap/tls/server.key"
+  tls_ca_certificate_file  = "/etc/ldap/tls/ca.crt"
+  tls_protocol_min         = "3.3"
+}
@@ -0,0 +1,3 @@
+# either the DN of the schema entry or its bare name
+$ terraform import ldap_olc_schema.example "cn={4}example,cn=schema,cn=config"
+$ terraform import ldap_olc_schema.example example
@@ -0,0 +1,31 @@
+resource "ldap_olc_schema" "example" {
+  provider = ldap.config # see ldap_olc_global for an ldapi/EXTERNAL provider
+
+  name = "example"
+
+  object_identifiers = [
+    "exampleOID 1.3.6.1.4.1.99999",
+    "exampleAttributeType exampleOID:1",
+    "exampleObjectClass exampleOID:2",
+  ]
+
+  attribute_types = [
+    "( exampleAttributeType:1 NAME 'exampleBadgeNumber' DESC 'Badge number' EQUALITY caseIgnoreMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 SINGLE-VALUE )",
+  ]
+
+  object_classes = [
+    "( exampleObjectClass:1 NAME 'exampleEmployee' DESC 'Employee extensions' SUP top AUXILIARY MAY ( exampleBadgeNumber ) )",
+  ]
+}
+
+resource "ldap_object" "jdoe" {
+  dn             = "uid=jdoe,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson", "exampleEmployee"]
+  attributes = [
+    { sn = "Doe" },
+    { cn = "John Doe" },
+    { exampleBadgeNumber = "42" },
+  ]
+
+  depends_on = [ldap_olc_schema.example]
+}
@@ -0,0 +1 @@
+$ terraform import ldap_ou_tree.paris ou=paris,ou=europe,ou=sites,dc=example,dc=com
@@ -0,0 +1,5 @@
+# creates ou=europe and ou=paris if they do not exist yet
+resource "ldap_ou_tree" "paris" {
+  dn                      = "ou=paris,ou=europe,ou=sites,dc=example,dc=com"
+  remove_empty_on_destroy = true
+}
@@ -0,0 +1 @@
+$ terraform import ldap_password_policy.default cn=default,ou=policies,dc=example,dc=com
@@ -0,0 +1,20 @@
+resource "ldap_object" "policies_example_com" {
+  dn             = "ou=policies,dc=example,dc=com"
+  object_classes = ["top", "organizationalUnit"]
+}
+
+resource "ldap_password_policy" "default" {
+  dn = "cn=default,${ldap_object.policies_example_com.dn}"
+
+  pwd_max_age                = 7776000 # 90 days
+  pwd_min_length             = 12
+  pwd_in_history             = 5
+  pwd_check_quality          = 2
+  pwd_expire_warning         = 604800 # 7 days
+  pwd_lockout                = true
+  pwd_lockout_duration       = 900
+  pwd_max_failure            = 5
+  pwd_failure_count_interval = 300
+  pwd_must_change            = true
+  pwd_allow_user_change      = true
+}
@@ -0,0 +1 @@
+$ terraform import ldap_referral.emea ou=emea,dc=example,dc=com
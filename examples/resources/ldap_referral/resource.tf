@@ -0,0 +1,5 @@
+# ou=emea is held by another server
+resource "ldap_referral" "emea" {
+  dn  = "ou=emea,dc=example,dc=com"
+  ref = ["ldap://emea.example.com/ou=emea,dc=example,dc=com"]
+}
@@ -0,0 +1 @@
+$ terraform import ldap_service_account.backup uid=backup,ou=services,dc=example,dc=com
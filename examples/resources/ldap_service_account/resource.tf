@@ -0,0 +1,16 @@
+resource "ldap_service_account" "backup" {
+  dn          = "uid=backup,ou=services,dc=example,dc=com"
+  description = "Nightly backup job"
+
+  rotation {
+    rotation_days = 90
+    keepers = {
+      owner = "ops"
+    }
+  }
+}
+
+output "backup_password" {
+  value     = ldap_service_account.backup.password
+  sensitive = true
+}
@@ -0,0 +1,79 @@
+// Package adtime converts the timestamps of Active Directory (e.g.
+// accountExpires, pwdLastSet or lockoutTime), which count 100-nanosecond
+// intervals since January 1, 1601 UTC, to and from RFC 3339 strings.
+package adtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Never is the value of accountExpires for accounts which never expire, as
+// is 0.
+const Never int64 = math.MaxInt64
+
+// Unset is the value of timestamps which are not set, e.g. the pwdLastSet of
+// accounts which must change their password or the lockoutTime of accounts
+// which are not locked.
+const Unset int64 = 0
+
+// epochOffset is the number of 100-nanosecond intervals between January 1,
+// 1601 and January 1, 1970.
+const epochOffset int64 = 116444736000000000
+
+// IsSentinel tells whether a timestamp is one of the special values standing
+// for no time, 0 or Never.
+func IsSentinel(v int64) bool {
+	return v == Unset || v == Never
+}
+
+// ToTime returns the time of a timestamp, in UTC; sentinel values have no
+// time.
+func ToTime(v int64) (time.Time, bool) {
+	if IsSentinel(v) || v < 0 {
+		return time.Time{}, false
+	}
+	unix := v - epochOffset
+	return time.Unix(unix/1e7, (unix%1e7)*100).UTC(), true
+}
+
+// FromTime returns the timestamp of a time.
+func FromTime(t time.Time) int64 {
+	return t.Unix()*1e7 + int64(t.Nanosecond())/100 + epochOffset
+}
+
+// Parse parses the value of a timestamp attribute.
+func Parse(s string) (int64, error) {
+	v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Active Directory timestamp %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// Format returns the RFC 3339 string of the value of a timestamp attribute,
+// or the empty string for sentinel values.
+func Format(s string) (string, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	t, ok := ToTime(v)
+	if !ok {
+		return "", nil
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+// FromRFC3339 returns the value of a timestamp attribute holding the time of
+// an RFC 3339 string.
+func FromRFC3339(s string) (string, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(FromTime(t), 10), nil
+}
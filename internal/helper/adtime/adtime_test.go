@@ -0,0 +1,56 @@
+package adtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToTime(t *testing.T) {
+	got, ok := ToTime(133519968000000000)
+	want := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	if !ok || !got.Equal(want) {
+		t.Errorf("ToTime(133519968000000000) = %v, %v, want %v", got, ok, want)
+	}
+	for _, v := range []int64{Unset, Never} {
+		if _, ok := ToTime(v); ok {
+			t.Errorf("expected no time for %d", v)
+		}
+	}
+}
+
+func TestFromTime(t *testing.T) {
+	ts := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	if got := FromTime(ts); got != 133519968000000000 {
+		t.Errorf("FromTime(%v) = %d", ts, got)
+	}
+	if got, _ := ToTime(FromTime(ts.Add(1234500 * time.Nanosecond))); !got.Equal(ts.Add(1234500 * time.Nanosecond)) {
+		t.Errorf("expected a round trip, got %v", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := map[string]string{
+		"133519968000000000":  "2024-02-10T00:00:00Z",
+		"0":                   "",
+		"9223372036854775807": "",
+	}
+	for in, want := range cases {
+		got, err := Format(in)
+		if err != nil || got != want {
+			t.Errorf("Format(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := Format("never"); err == nil {
+		t.Error("expected an error for a non-numeric value")
+	}
+}
+
+func TestFromRFC3339(t *testing.T) {
+	got, err := FromRFC3339("2024-02-10T01:00:00+01:00")
+	if err != nil || got != "133519968000000000" {
+		t.Errorf("FromRFC3339 = %q, %v", got, err)
+	}
+	if _, err := FromRFC3339("2024-02-10"); err == nil {
+		t.Error("expected an error for a date without time")
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// BindResult is the outcome of a bind attempt (see Pool.CheckBind).
+type BindResult struct {
+	Success           bool
+	ResultCode        uint16
+	DiagnosticMessage string
+	// PasswordExpiresIn is the number of seconds before the password
+	// expires, or -1 if the server did not warn about it.
+	PasswordExpiresIn int64
+	// GraceLogins is the number of binds left with the expired password, or
+	// -1 if the server did not warn about it.
+	GraceLogins int64
+	// PolicyError is the Password Policy error reported by the server, if
+	// any (e.g. "Password expired").
+	PolicyError string
+}
+
+// CheckBind binds with the given credentials on a new connection, set up
+// with the settings of the pool, requesting the Password Policy warnings.
+// Failed binds are reported in the result; an error is only returned when
+// the server cannot be reached.
+func (p *Pool) CheckBind(dn, password string) (*BindResult, error) {
+	conn, _, err := dial(p.config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return newBindResult(conn.SimpleBind(&ldap.SimpleBindRequest{
+		Username: dn,
+		Password: password,
+		Controls: []ldap.Control{ldap.NewControlBeheraPasswordPolicy()},
+	}))
+}
+
+// newBindResult converts the response to a bind request into a BindResult.
+func newBindResult(response *ldap.SimpleBindResult, err error) (*BindResult, error) {
+	result := &BindResult{Success: err == nil, PasswordExpiresIn: -1, GraceLogins: -1}
+	if err != nil {
+		var ldapErr *ldap.Error
+		if !errors.As(err, &ldapErr) || ldapErr.ResultCode == ldap.ErrorNetwork {
+			return nil, err
+		}
+		result.ResultCode = ldapErr.ResultCode
+		if ldapErr.Err != nil {
+			result.DiagnosticMessage = ldapErr.Err.Error()
+		}
+	}
+	if response == nil {
+		return result, nil
+	}
+	if ppolicy := passwordPolicyControl(response.Controls); ppolicy != nil {
+		if ppolicy.Expire >= 0 {
+			result.PasswordExpiresIn = ppolicy.Expire
+		}
+		if ppolicy.Grace >= 0 {
+			result.GraceLogins = ppolicy.Grace
+		}
+		result.PolicyError = ppolicy.ErrorString
+	}
+	return result, nil
+}
+
+// PasswordPolicyWarning describes the Password Policy warning of a
+// successful bind, the password expiring soon or expired with grace logins
+// left, or returns an empty string when the server sent none.
+func (r *BindResult) PasswordPolicyWarning() string {
+	switch {
+	case r == nil:
+		return ""
+	case r.GraceLogins >= 0:
+		return fmt.Sprintf("the password has expired, %d grace login(s) left", r.GraceLogins)
+	case r.PasswordExpiresIn >= 0:
+		return fmt.Sprintf("the password expires in %s", time.Duration(r.PasswordExpiresIn)*time.Second)
+	}
+	return ""
+}
+
+// PasswordPolicyError returns the Password Policy error carried by the
+// response to a failed request sent with the Password Policy control, e.g.
+// "Password is in history of old passwords" for a password write, or an
+// empty string.
+func PasswordPolicyError(err error) string {
+	var ldapErr *ldap.Error
+	if !errors.As(err, &ldapErr) || ldapErr.Packet == nil || len(ldapErr.Packet.Children) < 3 {
+		return ""
+	}
+	controls := []ldap.Control{}
+	for _, child := range ldapErr.Packet.Children[2].Children {
+		if control, err := ldap.DecodeControl(child); err == nil {
+			controls = append(controls, control)
+		}
+	}
+	if ppolicy := passwordPolicyControl(controls); ppolicy != nil && ppolicy.Error >= 0 {
+		return ppolicy.ErrorString
+	}
+	return ""
+}
+
+// passwordPolicyControl returns the Password Policy control of a response,
+// or nil.
+func passwordPolicyControl(controls []ldap.Control) *ldap.ControlBeheraPasswordPolicy {
+	for _, control := range controls {
+		if ppolicy, ok := control.(*ldap.ControlBeheraPasswordPolicy); ok {
+			return ppolicy
+		}
+	}
+	return nil
+}
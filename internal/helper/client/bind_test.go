@@ -0,0 +1,85 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestNewBindResult(t *testing.T) {
+	ppolicy := ldap.NewControlBeheraPasswordPolicy()
+	ppolicy.Expire = 3600
+	result, err := newBindResult(&ldap.SimpleBindResult{Controls: []ldap.Control{ppolicy}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.PasswordExpiresIn != 3600 || result.GraceLogins != -1 {
+		t.Errorf("unexpected result for a successful bind: %+v", result)
+	}
+
+	ppolicy = ldap.NewControlBeheraPasswordPolicy()
+	ppolicy.Error = 0
+	ppolicy.ErrorString = ldap.BeheraPasswordPolicyErrorMap[0]
+	result, err = newBindResult(
+		&ldap.SimpleBindResult{Controls: []ldap.Control{ppolicy}},
+		ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || result.ResultCode != ldap.LDAPResultInvalidCredentials || result.PolicyError != "Password expired" {
+		t.Errorf("unexpected result for a failed bind: %+v", result)
+	}
+
+	if _, err := newBindResult(nil, ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset"))); err == nil {
+		t.Error("expected network errors to be returned")
+	}
+}
+
+func TestPasswordPolicyWarning(t *testing.T) {
+	for _, tc := range []struct {
+		result *BindResult
+		want   string
+	}{
+		{nil, ""},
+		{&BindResult{Success: true, PasswordExpiresIn: -1, GraceLogins: -1}, ""},
+		{&BindResult{Success: true, PasswordExpiresIn: 7200, GraceLogins: -1}, "the password expires in 2h0m0s"},
+		{&BindResult{Success: true, PasswordExpiresIn: -1, GraceLogins: 2}, "the password has expired, 2 grace login(s) left"},
+	} {
+		if got := tc.result.PasswordPolicyWarning(); got != tc.want {
+			t.Errorf("PasswordPolicyWarning() of %+v = %q, want %q", tc.result, got, tc.want)
+		}
+	}
+}
+
+func TestPasswordPolicyError(t *testing.T) {
+	// a response carrying a Password Policy control with the
+	// passwordInHistory error
+	value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "PasswordPolicyResponseValue")
+	value.AppendChild(ber.NewInteger(ber.ClassContext, ber.TypePrimitive, 1, 8, "error"))
+	control := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	control.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldap.ControlTypeBeheraPasswordPolicy, "Control Type"))
+	control.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(value.Bytes()), "Control Value"))
+	controls := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+	controls.AppendChild(control)
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 1, "MessageID"))
+	packet.AppendChild(ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationModifyResponse, nil, "Modify Response"))
+	packet.AppendChild(controls)
+
+	err := fmt.Errorf("error updating: %w", &ldap.Error{
+		ResultCode: ldap.LDAPResultConstraintViolation,
+		Err:        errors.New("password is in history"),
+		Packet:     packet,
+	})
+	if got := PasswordPolicyError(err); got != ldap.BeheraPasswordPolicyErrorMap[8] {
+		t.Errorf("PasswordPolicyError() = %q, want %q", got, ldap.BeheraPasswordPolicyErrorMap[8])
+	}
+
+	if got := PasswordPolicyError(ldap.NewError(ldap.LDAPResultConstraintViolation, errors.New("constraint violation"))); got != "" {
+		t.Errorf("PasswordPolicyError() without control = %q, want none", got)
+	}
+}
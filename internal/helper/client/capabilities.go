@@ -0,0 +1,83 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// OIDs of the optional controls whose use depends on what the server
+// advertises.
+const (
+	// OIDPagedResults is the OID of the Simple Paged Results control (RFC 2696).
+	OIDPagedResults = "1.2.840.113556.1.4.319"
+	// OIDTreeDelete is the OID of the Tree Delete control.
+	OIDTreeDelete = "1.2.840.113556.1.4.805"
+	// OIDModifyIncrement is the OID of the Modify-Increment feature (RFC 4525).
+	OIDModifyIncrement = "1.3.6.1.1.14"
+)
+
+// Capabilities are the optional features a server advertises in its root
+// DSE.
+type Capabilities struct {
+	Controls       []string
+	Extensions     []string
+	Features       []string
+	NamingContexts []string
+}
+
+// SupportsControl tells whether the server advertises the control with the
+// given OID; nil Capabilities support nothing.
+func (c *Capabilities) SupportsControl(oid string) bool {
+	return c != nil && contains(c.Controls, oid)
+}
+
+// SupportsFeature tells whether the server advertises the feature with the
+// given OID; nil Capabilities support nothing.
+func (c *Capabilities) SupportsFeature(oid string) bool {
+	return c != nil && contains(c.Features, oid)
+}
+
+// SupportsExtension tells whether the server advertises the extended
+// operation with the given OID; nil Capabilities support nothing.
+func (c *Capabilities) SupportsExtension(oid string) bool {
+	return c != nil && contains(c.Extensions, oid)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// readCapabilities reads the capabilities of the server from its root DSE.
+func readCapabilities(conn Client) (*Capabilities, error) {
+	request := ldap.NewSearchRequest(
+		"",
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		[]string{"supportedControl", "supportedExtension", "supportedFeatures", "namingContexts"},
+		nil,
+	)
+	sr, err := conn.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the root DSE: %w", err)
+	}
+	if len(sr.Entries) == 0 {
+		return nil, fmt.Errorf("error reading the root DSE: no entry returned")
+	}
+	entry := sr.Entries[0]
+	return &Capabilities{
+		Controls:       entry.GetAttributeValues("supportedControl"),
+		Extensions:     entry.GetAttributeValues("supportedExtension"),
+		Features:       entry.GetAttributeValues("supportedFeatures"),
+		NamingContexts: entry.GetAttributeValues("namingContexts"),
+	}, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import "testing"
+
+func TestCapabilities(t *testing.T) {
+	capabilities := &Capabilities{
+		Controls:   []string{OIDPagedResults, OIDPostRead},
+		Extensions: []string{OIDWhoAmI},
+	}
+	if !capabilities.SupportsControl(OIDPagedResults) || capabilities.SupportsControl(OIDTreeDelete) {
+		t.Errorf("unexpected controls support: %+v", capabilities)
+	}
+	if !capabilities.SupportsExtension(OIDWhoAmI) || capabilities.SupportsExtension(OIDStartTransaction) {
+		t.Errorf("unexpected extensions support: %+v", capabilities)
+	}
+
+	var unknown *Capabilities
+	if unknown.SupportsControl(OIDPagedResults) || unknown.SupportsExtension(OIDWhoAmI) {
+		t.Error("expected unknown capabilities to support nothing")
+	}
+
+	p := &Pool{}
+	if p.SupportsControl(OIDPostRead) {
+		t.Error("expected a pool without capabilities to support nothing")
+	}
+}
@@ -1,12 +1,160 @@
 package client
 
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Bind methods supported by DialAndBind.
+const (
+	BindMethodSimple   = "simple"
+	BindMethodExternal = "external"
+	BindMethodNTLM     = "ntlm"
+	BindMethodGSSAPI   = "gssapi"
+)
+
 type Config struct {
+	// URLs are the ldap://, ldaps:// or ldapi:// URLs of the servers, tried in
+	// order; when set, LDAPHost, LDAPPort, LDAPISocket and TLS are ignored.
+	URLs []string
+
 	LDAPHost     string
 	LDAPPort     int
+	LDAPISocket  string
+	BindMethod   string
 	BindUser     string
 	BindPassword string
 
+	// NTLMDomain is the domain of BindUser with BindMethodNTLM, which binds
+	// with NTLMHash rather than BindPassword when it is set.
+	NTLMDomain string
+	NTLMHash   string
+
+	// Kerberos settings of BindMethodGSSAPI: the credentials are read from
+	// KerberosKeytab for KerberosPrincipal (user@REALM) if set, or from the
+	// credential cache KerberosCCache otherwise. KerberosSPN defaults to
+	// ldap/<host name of the server>.
+	KerberosConfig    string
+	KerberosPrincipal string
+	KerberosKeytab    string
+	KerberosCCache    string
+	KerberosSPN       string
+
 	StartTLS    bool
 	TLS         bool
 	TLSInsecure bool
+
+	// TLSMinVersion and TLSCipherSuites restrict the TLS versions and the
+	// cipher suites (of TLS 1.2 and earlier) used; zero values leave the Go
+	// defaults.
+	TLSMinVersion   uint16
+	TLSCipherSuites []uint16
+
+	// TLSPeerFingerprints, if set, are the SHA-256 fingerprints of the
+	// certificates the server may present, in lower-case hexadecimal; the
+	// certificate is then checked against them rather than against the
+	// certificate authorities.
+	TLSPeerFingerprints []string
+
+	// TLSClientCertificate, if set, is presented to the server during the TLS
+	// handshake, e.g. for SASL EXTERNAL binds.
+	TLSClientCertificate *tls.Certificate
+
+	// Proxy is the URL of the SOCKS5 (socks5://) or HTTP (http://) proxy the
+	// connections to the server go through, if any; connections over ldapi
+	// do not.
+	Proxy string
+
+	// SSHTunnel, if set, is the SSH server the connections to the server are
+	// forwarded through (itself reached through Proxy, if set).
+	SSHTunnel *SSHTunnel
+
+	// KeepAlive, if positive, is the interval of the TCP keepalives of the
+	// connections, and of the searches run on the idle connections of a Pool
+	// so that they are not dropped by firewalls while unused.
+	KeepAlive time.Duration
+
+	// RequestTimeout, if positive, is the time after which requests without
+	// response fail with a timeout.
+	RequestTimeout time.Duration
+
+	// RetryAttempts is the number of times the operations of a Pool are
+	// attempted while they fail with a retryable error (see IsRetryable),
+	// waiting between attempts from RetryMinBackoff, doubled at each attempt,
+	// up to RetryMaxBackoff. Updates failing with network errors may have
+	// been applied before being retried.
+	RetryAttempts   int
+	RetryMinBackoff time.Duration
+	RetryMaxBackoff time.Duration
+
+	// MaxRequestsPerSecond, if positive, is the maximum rate of the
+	// operations of a Pool, which are then spaced out evenly.
+	MaxRequestsPerSecond float64
+
+	// ReadOnly makes the operations of a Pool which would update the
+	// directory fail with ErrReadOnly.
+	ReadOnly bool
+
+	// Controls are attached to every operation of a Pool, binds excepted,
+	// unless its request already carries a control of the same type.
+	Controls []ldap.Control
+}
+
+// Secrets returns the credentials of the configuration which must not be
+// logged.
+func (c *Config) Secrets() []string {
+	secrets := []string{c.BindPassword, c.NTLMHash}
+	if u, err := url.Parse(c.Proxy); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			secrets = append(secrets, password)
+		}
+	}
+	if c.SSHTunnel != nil {
+		secrets = append(secrets, c.SSHTunnel.PrivateKeyPassphrase)
+	}
+	return secrets
+}
+
+// tlsConfig returns the TLS settings of the connections to the server with
+// the given name.
+func (c *Config) tlsConfig(serverName string, insecureSkipVerify bool) *tls.Config {
+	config := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: insecureSkipVerify,
+		MinVersion:         c.TLSMinVersion,
+		CipherSuites:       c.TLSCipherSuites,
+	}
+	if c.TLSClientCertificate != nil {
+		config.Certificates = []tls.Certificate{*c.TLSClientCertificate}
+	}
+	if len(c.TLSPeerFingerprints) > 0 {
+		// the chain is not checked: the fingerprint of the certificate is
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = c.verifyPeerFingerprint
+	}
+	return config
+}
+
+// verifyPeerFingerprint checks that the certificate of the server has one of
+// the fingerprints of TLSPeerFingerprints.
+func (c *Config) verifyPeerFingerprint(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("the server presented no certificate")
+	}
+	sum := sha256.Sum256(rawCerts[0])
+	fingerprint := hex.EncodeToString(sum[:])
+	for _, expected := range c.TLSPeerFingerprints {
+		if fingerprint == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("the certificate of the server has an unexpected SHA-256 fingerprint %s", fingerprint)
 }
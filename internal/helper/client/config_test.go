@@ -0,0 +1,31 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyPeerFingerprint(t *testing.T) {
+	certificate := []byte("not really a certificate")
+	sum := sha256.Sum256(certificate)
+
+	c := &Config{TLSPeerFingerprints: []string{"00", hex.EncodeToString(sum[:])}}
+	tlsConfig := c.tlsConfig("ldap.example.com", false)
+	if !tlsConfig.InsecureSkipVerify || tlsConfig.VerifyPeerCertificate == nil {
+		t.Fatal("expected the fingerprint to replace the verification of the chain")
+	}
+	if err := tlsConfig.VerifyPeerCertificate([][]byte{certificate}, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := tlsConfig.VerifyPeerCertificate([][]byte{[]byte("another certificate")}, nil); err == nil {
+		t.Error("expected an error for an unknown certificate")
+	}
+	if err := tlsConfig.VerifyPeerCertificate(nil, nil); err == nil {
+		t.Error("expected an error without certificate")
+	}
+
+	if tlsConfig := (&Config{}).tlsConfig("ldap.example.com", false); tlsConfig.InsecureSkipVerify || tlsConfig.VerifyPeerCertificate != nil {
+		t.Error("expected the chain to be verified without fingerprints")
+	}
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"fmt"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// OIDAssertion is the OID of the Assertion control (RFC 4528).
+const OIDAssertion = "1.3.6.1.1.12"
+
+// ControlAssertion makes an update operation conditional: the server only
+// applies it if the target entry matches the filter, and fails with
+// assertionFailed otherwise.
+type ControlAssertion struct {
+	Filter string
+	filter *ber.Packet
+}
+
+// NewControlAssertion returns an Assertion control for the given filter.
+func NewControlAssertion(filter string) (*ControlAssertion, error) {
+	compiled, err := ldap.CompileFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assertion filter %q: %w", filter, err)
+	}
+	return &ControlAssertion{Filter: filter, filter: compiled}, nil
+}
+
+// GetControlType returns the OID of the control.
+func (c *ControlAssertion) GetControlType() string {
+	return OIDAssertion
+}
+
+// Encode returns the BER encoding of the control, which is always critical.
+func (c *ControlAssertion) Encode() *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, OIDAssertion, "Control Type (Assertion)"))
+	packet.AppendChild(ber.NewBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, true, "Criticality"))
+	value := ber.Encode(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, nil, "Control Value (Filter)")
+	value.AppendChild(c.filter)
+	packet.AppendChild(value)
+	return packet
+}
+
+// String returns a human-readable description of the control.
+func (c *ControlAssertion) String() string {
+	return fmt.Sprintf("Control Type: Assertion (%q)  Criticality: true  Filter: %s", OIDAssertion, c.Filter)
+}
+
+// OIDPostRead is the OID of the Post-Read control (RFC 4527).
+const OIDPostRead = "1.3.6.1.1.13.2"
+
+// ControlPostRead asks the server to return the given attributes of the entry
+// as it is after an update operation.
+type ControlPostRead struct {
+	Attributes []string
+}
+
+// GetControlType returns the OID of the control.
+func (c *ControlPostRead) GetControlType() string {
+	return OIDPostRead
+}
+
+// Encode returns the BER encoding of the control; it is not critical, so
+// servers which do not support it simply ignore it.
+func (c *ControlPostRead) Encode() *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, OIDPostRead, "Control Type (Post-Read)"))
+	value := ber.Encode(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, nil, "Control Value (Attribute Selection)")
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, attribute := range c.Attributes {
+		attributes.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute, "Attribute"))
+	}
+	value.AppendChild(attributes)
+	packet.AppendChild(value)
+	return packet
+}
+
+// String returns a human-readable description of the control.
+func (c *ControlPostRead) String() string {
+	return fmt.Sprintf("Control Type: Post-Read (%q)  Criticality: false  Attributes: %v", OIDPostRead, c.Attributes)
+}
+
+// PostReadEntry returns the entry carried by the Post-Read response control
+// among the given response controls, or nil if there is none.
+func PostReadEntry(controls []ldap.Control) (*ldap.Entry, error) {
+	for _, control := range controls {
+		c, ok := control.(*ldap.ControlString)
+		if !ok || c.ControlType != OIDPostRead {
+			continue
+		}
+
+		// SearchResultEntry ::= [APPLICATION 4] SEQUENCE {
+		//      objectName      LDAPDN,
+		//      attributes      PartialAttributeList }
+		packet, err := ber.DecodePacketErr([]byte(c.ControlValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Post-Read control value: %w", err)
+		}
+		if len(packet.Children) != 2 {
+			return nil, fmt.Errorf("invalid Post-Read control value: expected 2 children, got %d", len(packet.Children))
+		}
+
+		entry := &ldap.Entry{DN: packet.Children[0].Data.String()}
+		for _, child := range packet.Children[1].Children {
+			if len(child.Children) != 2 {
+				return nil, fmt.Errorf("invalid Post-Read control value: malformed attribute")
+			}
+			attribute := &ldap.EntryAttribute{Name: child.Children[0].Data.String()}
+			for _, value := range child.Children[1].Children {
+				attribute.Values = append(attribute.Values, value.Data.String())
+				attribute.ByteValues = append(attribute.ByteValues, value.Data.Bytes())
+			}
+			entry.Attributes = append(entry.Attributes, attribute)
+		}
+		return entry, nil
+	}
+	return nil, nil
+}
+
+// OIDNoOp is the OID of the No-Op control (draft-zeilenga-ldap-noop), with
+// which the server checks an update without applying it.
+const OIDNoOp = "1.3.6.1.4.1.4203.1.10.2"
+
+// ResultNoOperation is the result code of the updates sent with the No-Op
+// control which would have succeeded.
+const ResultNoOperation = 0x410e
+
+// NewControlNoOp returns a No-Op control; it is critical, so that servers
+// which do not support it reject the update rather than apply it.
+func NewControlNoOp() ldap.Control {
+	return ldap.NewControlString(OIDNoOp, true, "")
+}
+
+// ControlVLV is a Virtual List View request (draft-ietf-ldapext-ldapv3-vlv)
+// selecting a window of a sorted result set by offset; it must be sent along
+// with a Server Side Sorting control.
+type ControlVLV struct {
+	// Offset is the 1-based position of the first entry of the window.
+	Offset int
+	// Size is the number of entries in the window.
+	Size int
+}
+
+// GetControlType returns the OID of the control.
+func (c *ControlVLV) GetControlType() string {
+	return ldap.ControlTypeVLVRequest
+}
+
+// Encode returns the BER encoding of the control, which is always critical.
+func (c *ControlVLV) Encode() *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldap.ControlTypeVLVRequest, "Control Type (Virtual List View)"))
+	packet.AppendChild(ber.NewBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, true, "Criticality"))
+
+	request := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Virtual List View Request")
+	request.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 0, "Before Count"))
+	request.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, c.Size-1, "After Count"))
+	target := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "By Offset")
+	target.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, c.Offset, "Offset"))
+	target.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 0, "Content Count"))
+	request.AppendChild(target)
+
+	value := ber.Encode(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, nil, "Control Value (Virtual List View Request)")
+	value.AppendChild(request)
+	packet.AppendChild(value)
+	return packet
+}
+
+// String returns a human-readable description of the control.
+func (c *ControlVLV) String() string {
+	return fmt.Sprintf("Control Type: Virtual List View (%q)  Criticality: true  Offset: %d  Size: %d", ldap.ControlTypeVLVRequest, c.Offset, c.Size)
+}
+
+// VLVResponse is the content of a Virtual List View response control.
+type VLVResponse struct {
+	TargetPosition int64
+	ContentCount   int64
+	Result         int64
+}
+
+// FindVLVResponse returns the Virtual List View response among the given
+// response controls, or nil if there is none.
+func FindVLVResponse(controls []ldap.Control) (*VLVResponse, error) {
+	for _, control := range controls {
+		c, ok := control.(*ldap.ControlString)
+		if !ok || c.ControlType != ldap.ControlTypeVLVResponse {
+			continue
+		}
+
+		// VirtualListViewResponse ::= SEQUENCE {
+		//      targetPosition    INTEGER (0 .. maxInt),
+		//      contentCount      INTEGER (0 .. maxInt),
+		//      virtualListViewResult ENUMERATED { ... },
+		//      contextID         OCTET STRING OPTIONAL }
+		packet, err := ber.DecodePacketErr([]byte(c.ControlValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Virtual List View response: %w", err)
+		}
+		if len(packet.Children) < 3 {
+			return nil, fmt.Errorf("invalid Virtual List View response: expected at least 3 children, got %d", len(packet.Children))
+		}
+		response := &VLVResponse{}
+		for i, field := range []*int64{&response.TargetPosition, &response.ContentCount, &response.Result} {
+			value, ok := packet.Children[i].Value.(int64)
+			if !ok {
+				return nil, fmt.Errorf("invalid Virtual List View response: field %d is not an integer", i)
+			}
+			*field = value
+		}
+		return response, nil
+	}
+	return nil, nil
+}
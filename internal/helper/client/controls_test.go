@@ -0,0 +1,105 @@
+package client
+
+import (
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestControlAssertionEncode(t *testing.T) {
+	control, err := NewControlAssertion("(entryCSN=20240101000000.000000Z#000000#000#000000)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packet, err := ber.DecodePacketErr(control.Encode().Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error decoding the control: %v", err)
+	}
+	if len(packet.Children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(packet.Children))
+	}
+	if oid := packet.Children[0].Value.(string); oid != OIDAssertion {
+		t.Errorf("invalid control type %q", oid)
+	}
+	if critical := packet.Children[1].Value.(bool); !critical {
+		t.Error("the control should be critical")
+	}
+	filter, err := ber.DecodePacketErr(packet.Children[2].Data.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error decoding the filter: %v", err)
+	}
+	if filter.ClassType != ber.ClassContext || filter.Tag != 3 { // equalityMatch
+		t.Errorf("invalid filter encoding: class %v tag %v", filter.ClassType, filter.Tag)
+	}
+
+	if _, err := NewControlAssertion("(entryCSN="); err == nil {
+		t.Error("expected an error for an invalid filter")
+	}
+}
+
+func TestControlTransactionSpecificationEncode(t *testing.T) {
+	control := &ControlTransactionSpecification{ID: []byte{0x01, 0x02}}
+
+	packet, err := ber.DecodePacketErr(control.Encode().Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error decoding the control: %v", err)
+	}
+	if oid := packet.Children[0].Value.(string); oid != OIDTransactionSpec {
+		t.Errorf("invalid control type %q", oid)
+	}
+	if id := packet.Children[2].Data.Bytes(); string(id) != "\x01\x02" {
+		t.Errorf("invalid transaction identifier %x", id)
+	}
+}
+
+func TestPostReadEntry(t *testing.T) {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, 4, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "cn=test,dc=example,dc=com", "Object Name"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+	attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "description", "Type"))
+	values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+	values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "first", "Value"))
+	values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "second", "Value"))
+	attribute.AppendChild(values)
+	attributes.AppendChild(attribute)
+	entry.AppendChild(attributes)
+
+	controls := []ldap.Control{
+		ldap.NewControlManageDsaIT(false),
+		&ldap.ControlString{ControlType: OIDPostRead, ControlValue: string(entry.Bytes())},
+	}
+	parsed, err := PostReadEntry(controls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed == nil || parsed.DN != "cn=test,dc=example,dc=com" {
+		t.Fatalf("invalid entry: %+v", parsed)
+	}
+	if values := parsed.GetAttributeValues("description"); len(values) != 2 || values[0] != "first" || values[1] != "second" {
+		t.Errorf("invalid values: %v", values)
+	}
+
+	if parsed, err := PostReadEntry(controls[:1]); parsed != nil || err != nil {
+		t.Errorf("expected no entry, got %+v, %v", parsed, err)
+	}
+}
+
+func TestFindVLVResponse(t *testing.T) {
+	response := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Virtual List View Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 101, "Target Position"))
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 250000, "Content Count"))
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, 0, "Result"))
+
+	parsed, err := FindVLVResponse([]ldap.Control{
+		&ldap.ControlString{ControlType: ldap.ControlTypeVLVResponse, ControlValue: string(response.Bytes())},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed == nil || parsed.TargetPosition != 101 || parsed.ContentCount != 250000 || parsed.Result != 0 {
+		t.Errorf("invalid response: %+v", parsed)
+	}
+}
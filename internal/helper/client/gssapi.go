@@ -0,0 +1,58 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/go-ldap/ldap/v3/gssapi"
+)
+
+// gssapiBind binds with SASL GSSAPI (Kerberos) to the server with the given
+// host name.
+func gssapiBind(conn *ldap.Conn, c *Config, host string) error {
+	spn := c.KerberosSPN
+	if spn == "" {
+		if host == "" {
+			return fmt.Errorf("the Kerberos service principal of the server must be set to bind with GSSAPI over ldapi")
+		}
+		spn = "ldap/" + host
+	}
+
+	kerberos, err := gssapiClient(c)
+	if err != nil {
+		return err
+	}
+	defer kerberos.Close()
+
+	return conn.GSSAPIBind(kerberos, spn, "")
+}
+
+// gssapiClient returns a Kerberos client with the credentials of the
+// configuration.
+func gssapiClient(c *Config) (*gssapi.Client, error) {
+	if c.KerberosKeytab != "" {
+		if c.KerberosPrincipal == "" {
+			return nil, fmt.Errorf("the Kerberos principal must be set along with the keytab")
+		}
+		// without a realm, the default realm of the configuration is used
+		username, realm, _ := strings.Cut(c.KerberosPrincipal, "@")
+		kerberos, err := gssapi.NewClientWithKeytab(username, realm, c.KerberosKeytab, c.KerberosConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error loading the Kerberos keytab %q: %w", c.KerberosKeytab, err)
+		}
+		return kerberos, nil
+	}
+
+	if c.KerberosCCache == "" {
+		return nil, fmt.Errorf("either a Kerberos keytab or a credential cache must be set to bind with GSSAPI")
+	}
+	// KRB5CCNAME-style values may name the type of the cache, of which only
+	// files are supported
+	ccache := strings.TrimPrefix(c.KerberosCCache, "FILE:")
+	kerberos, err := gssapi.NewClientFromCCache(ccache, c.KerberosConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the Kerberos credential cache %q: %w", ccache, err)
+	}
+	return kerberos, nil
+}
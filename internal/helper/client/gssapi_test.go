@@ -0,0 +1,27 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGSSAPIClientErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   Config
+		expected string
+	}{
+		"no credentials":       {Config{}, "either a Kerberos keytab or a credential cache"},
+		"keytab without user":  {Config{KerberosKeytab: "/etc/krb5.keytab"}, "principal must be set"},
+		"missing krb5.conf":    {Config{KerberosCCache: "FILE:/nonexistent/ccache", KerberosConfig: "/nonexistent/krb5.conf"}, `"/nonexistent/ccache"`},
+		"missing keytab files": {Config{KerberosKeytab: "/nonexistent/keytab", KerberosPrincipal: "svc@EXAMPLE.COM", KerberosConfig: "/nonexistent/krb5.conf"}, `"/nonexistent/keytab"`},
+	} {
+		_, err := gssapiClient(&tc.config)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s: expected an error containing %s, got %v", name, tc.expected, err)
+		}
+	}
+
+	if err := gssapiBind(nil, &Config{}, ""); err == nil || !strings.Contains(err.Error(), "ldapi") {
+		t.Errorf("expected an error for a missing service principal over ldapi, got %v", err)
+	}
+}
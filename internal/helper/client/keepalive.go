@@ -0,0 +1,53 @@
+package client
+
+import (
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// keepAlive runs a cheap operation on the idle connections of the pool at
+// the given interval, until the pool is closed, so that firewalls do not
+// drop them while unused; connections which fail are closed, and replaced
+// on demand.
+func (p *Pool) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		// only the connections idle now: those given back meanwhile were
+		// just used
+		for n := len(p.idle); n > 0; n-- {
+			select {
+			case conn := <-p.idle:
+				if err := ping(conn); err != nil {
+					conn.Close()
+				}
+				p.Put(conn)
+			default:
+			}
+		}
+	}
+}
+
+// ping reads the root DSE, which any server allows, without attributes.
+func ping(conn *ldap.Conn) error {
+	request := ldap.NewSearchRequest(
+		"",
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		1,
+		int(ldap.DefaultTimeout/time.Second),
+		false,
+		"(objectClass=*)",
+		[]string{"1.1"},
+		nil,
+	)
+	_, err := conn.Search(request)
+	return err
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestKeepAliveDropsBrokenConnections(t *testing.T) {
+	client, server := net.Pipe()
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+
+	p := &Pool{idle: make(chan *ldap.Conn, 2), slots: make(chan struct{}, 2), done: make(chan struct{})}
+	p.slots <- struct{}{}
+	p.idle <- conn
+	go p.keepAlive(10 * time.Millisecond)
+	defer p.Close()
+
+	// e.g. a firewall dropping the connection
+	server.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(p.slots) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the broken connection to be dropped, got %d idle connections and %d slots in use", len(p.idle), len(p.slots))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,55 +2,186 @@ package client
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/go-ldap/ldap/v3"
 )
 
 func DialAndBind(c *Config) (*ldap.Conn, error) {
-	conn, err := dial(c)
+	conn, _, err := dialAndBind(c)
+	return conn, err
+}
+
+// dialAndBind is DialAndBind, also returning the outcome of simple binds,
+// which request the Password Policy warnings; it is nil for the other bind
+// methods.
+func dialAndBind(c *Config) (*ldap.Conn, *BindResult, error) {
+	conn, host, err := dial(c)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if c.RequestTimeout > 0 {
+		conn.SetTimeout(c.RequestTimeout)
 	}
 
 	// bind to current connection
 	// Use UnauthenticatedBind for anonymous access when credentials are empty
-	if c.BindUser == "" && c.BindPassword == "" {
+	var result *BindResult
+	switch {
+	case c.BindMethod == BindMethodExternal:
+		// SASL EXTERNAL: the identity comes from the transport (the peer
+		// credentials on ldapi, or the TLS client certificate)
+		err = conn.ExternalBind()
+	case c.BindMethod == BindMethodNTLM && c.NTLMHash != "":
+		err = conn.NTLMBindWithHash(c.NTLMDomain, c.BindUser, c.NTLMHash)
+	case c.BindMethod == BindMethodNTLM:
+		err = conn.NTLMBind(c.NTLMDomain, c.BindUser, c.BindPassword)
+	case c.BindMethod == BindMethodGSSAPI:
+		err = gssapiBind(conn, c, host)
+	case c.BindUser == "" && c.BindPassword == "":
 		err = conn.UnauthenticatedBind("")
-	} else {
-		err = conn.Bind(c.BindUser, c.BindPassword)
+	default:
+		var response *ldap.SimpleBindResult
+		response, err = conn.SimpleBind(&ldap.SimpleBindRequest{
+			Username: c.BindUser,
+			Password: c.BindPassword,
+			Controls: []ldap.Control{ldap.NewControlBeheraPasswordPolicy()},
+		})
+		result, _ = newBindResult(response, err)
 	}
 	if err != nil {
 		conn.Close()
-		return nil, err
+		if result != nil && result.PolicyError != "" {
+			// e.g. the account is locked, or its password expired
+			err = fmt.Errorf("%w (password policy: %s)", err, result.PolicyError)
+		}
+		return nil, nil, err
 	}
 
 	// return the LDAP connection
-	return conn, nil
+	return conn, result, nil
 }
 
-func dial(c *Config) (*ldap.Conn, error) {
-	uri := fmt.Sprintf("%s:%d", c.LDAPHost, c.LDAPPort)
+// dial connects to the server, and returns the connection along with the
+// host name of the server, which is empty over ldapi.
+func dial(c *Config) (*ldap.Conn, string, error) {
+	if len(c.URLs) > 0 {
+		return dialURLs(c)
+	}
+
+	if c.LDAPISocket != "" {
+		// the socket path is carried, percent-encoded, in the host part of
+		// the ldapi URL
+		conn, err := ldap.DialURL("ldapi://" + url.PathEscape(c.LDAPISocket))
+		return conn, "", err
+	}
+
+	address := net.JoinHostPort(c.LDAPHost, strconv.Itoa(c.LDAPPort))
 
 	if c.TLS {
-		return ldap.DialTLS("tcp", uri, &tls.Config{
-			ServerName:         c.LDAPHost,
-			InsecureSkipVerify: c.TLSInsecure,
-		})
+		conn, err := dialTCP(c, address, c.tlsConfig(c.LDAPHost, c.TLSInsecure))
+		return conn, c.LDAPHost, err
 	}
 
-	conn, err := ldap.Dial("tcp", uri)
+	conn, err := dialTCP(c, address, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if c.StartTLS {
-		err = conn.StartTLS(&tls.Config{
-			InsecureSkipVerify: true,
-		})
+		err = conn.StartTLS(c.tlsConfig("", true))
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 	}
-	return conn, err
+	return conn, c.LDAPHost, err
+}
+
+// dialURLs connects to the first of the URLs of the configuration which
+// accepts the connection, like the URI directive of ldap.conf.
+func dialURLs(c *Config) (*ldap.Conn, string, error) {
+	var errs []error
+	for _, u := range c.URLs {
+		conn, host, err := dialURL(c, u)
+		if err == nil {
+			return conn, host, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", u, err))
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+func dialURL(c *Config, u string) (*ldap.Conn, string, error) {
+	if strings.HasPrefix(strings.ToLower(u), "ldapi://") {
+		conn, err := ldap.DialURL(u)
+		return conn, "", err
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, "", err
+	}
+	tlsConfig := c.tlsConfig(parsed.Hostname(), c.TLSInsecure)
+
+	var conn *ldap.Conn
+	switch parsed.Scheme {
+	case "ldap":
+		conn, err = dialTCP(c, hostPort(parsed, ldap.DefaultLdapPort), nil)
+	case "ldaps":
+		conn, err = dialTCP(c, hostPort(parsed, ldap.DefaultLdapsPort), tlsConfig)
+	default:
+		return nil, "", fmt.Errorf("unsupported scheme %q", parsed.Scheme)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c.StartTLS && parsed.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, "", err
+		}
+	}
+	return conn, parsed.Hostname(), nil
+}
+
+// hostPort returns the address of the server of a URL, with the given
+// default port if the URL has none.
+func hostPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// dialTCP connects to the given address, through the proxy of the
+// configuration if any, and over TLS if tlsConfig is set.
+func dialTCP(c *Config, address string, tlsConfig *tls.Config) (*ldap.Conn, error) {
+	dialer, err := c.dialer()
+	if err != nil {
+		return nil, err
+	}
+	netConn, err := dialer("tcp", address)
+	if err != nil {
+		return nil, ldap.NewError(ldap.ErrorNetwork, err)
+	}
+
+	if tlsConfig != nil {
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			netConn.Close()
+			return nil, ldap.NewError(ldap.ErrorNetwork, err)
+		}
+		netConn = tlsConn
+	}
+
+	conn := ldap.NewConn(netConn, tlsConfig != nil)
+	conn.Start()
+	return conn, nil
 }
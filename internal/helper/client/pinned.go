@@ -0,0 +1,125 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Pinned is a connection taken out of a Pool for operations which must all
+// be sent on the same connection, as those of a transaction. Its operations
+// get the default controls of the pool, are subject to its rate limit and to
+// its read_only setting as those of the pool are, but are not retried, as a
+// failed operation cannot be run again on another connection. It must be
+// given back with Release.
+type Pinned struct {
+	pool *Pool
+	conn *ldap.Conn
+}
+
+var _ Client = (*Pinned)(nil)
+
+// Pin takes a connection out of the pool, waiting for one as Get does.
+func (p *Pool) Pin() (*Pinned, error) {
+	conn, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &Pinned{pool: p, conn: conn}, nil
+}
+
+// Release gives the connection back to the pool.
+func (c *Pinned) Release() {
+	if c.conn != nil {
+		c.pool.Put(c.conn)
+		c.conn = nil
+	}
+}
+
+// Search runs a search on the connection.
+func (c *Pinned) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	c.pool.limiter.wait()
+	return c.conn.Search(request)
+}
+
+// SearchWithPaging runs a paged search on the connection.
+func (c *Pinned) SearchWithPaging(request *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error) {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	c.pool.limiter.wait()
+	return c.conn.SearchWithPaging(request, pagingSize)
+}
+
+// Add adds an entry on the connection.
+func (c *Pinned) Add(request *ldap.AddRequest) error {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	if err := c.pool.checkWritable("add", request.DN); err != nil {
+		return err
+	}
+	c.pool.limiter.wait()
+	if err := c.conn.Add(request); err != nil {
+		return err
+	}
+	c.pool.written.recordAdd(request)
+	return nil
+}
+
+// Modify modifies an entry on the connection.
+func (c *Pinned) Modify(request *ldap.ModifyRequest) error {
+	_, err := c.ModifyWithResult(request)
+	return err
+}
+
+// ModifyWithResult modifies an entry on the connection and returns the
+// response controls.
+func (c *Pinned) ModifyWithResult(request *ldap.ModifyRequest) (*ldap.ModifyResult, error) {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	if err := c.pool.checkWritable("modify", request.DN); err != nil {
+		return nil, err
+	}
+	c.pool.limiter.wait()
+	result, err := c.conn.ModifyWithResult(request)
+	if err != nil {
+		return nil, err
+	}
+	c.pool.written.recordModify(request)
+	return result, nil
+}
+
+// ModifyDN renames or moves an entry on the connection.
+func (c *Pinned) ModifyDN(request *ldap.ModifyDNRequest) error {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	if err := c.pool.checkWritable("rename", request.DN); err != nil {
+		return err
+	}
+	c.pool.limiter.wait()
+	if err := c.conn.ModifyDN(request); err != nil {
+		return err
+	}
+	c.pool.written.forget(request.DN)
+	return nil
+}
+
+// Del deletes an entry on the connection.
+func (c *Pinned) Del(request *ldap.DelRequest) error {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	if err := c.pool.checkWritable("delete", request.DN); err != nil {
+		return err
+	}
+	c.pool.limiter.wait()
+	if err := c.conn.Del(request); err != nil {
+		return err
+	}
+	c.pool.written.forget(request.DN)
+	return nil
+}
+
+// Extended runs an extended operation on the connection.
+func (c *Pinned) Extended(request *ldap.ExtendedRequest) (*ldap.ExtendedResponse, error) {
+	request.Controls = c.pool.withDefaultControls(request.Controls)
+	if c.pool.ReadOnly() && request.Name != OIDWhoAmI {
+		return nil, fmt.Errorf("refusing to run extended operation %s: %w", request.Name, ErrReadOnly)
+	}
+	c.pool.limiter.wait()
+	return c.conn.Extended(request)
+}
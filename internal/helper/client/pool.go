@@ -0,0 +1,379 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// DefaultMaxConnections matches Terraform's default parallelism.
+const DefaultMaxConnections = 10
+
+// ErrReadOnly is returned by the update operations of a read-only Pool.
+var ErrReadOnly = errors.New("the provider is configured with read_only")
+
+// OIDWhoAmI is the OID of the "Who am I?" extended operation (RFC 4532),
+// which a read-only Pool still allows.
+const OIDWhoAmI = "1.3.6.1.4.1.4203.1.11.3"
+
+// Client is the subset of the operations of an *ldap.Conn used by the
+// provider; it is implemented by both *ldap.Conn and *Pool.
+type Client interface {
+	Search(request *ldap.SearchRequest) (*ldap.SearchResult, error)
+	SearchWithPaging(request *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error)
+	Add(request *ldap.AddRequest) error
+	Modify(request *ldap.ModifyRequest) error
+	ModifyWithResult(request *ldap.ModifyRequest) (*ldap.ModifyResult, error)
+	ModifyDN(request *ldap.ModifyDNRequest) error
+	Del(request *ldap.DelRequest) error
+	Extended(request *ldap.ExtendedRequest) (*ldap.ExtendedResponse, error)
+}
+
+var (
+	_ Client = (*ldap.Conn)(nil)
+	_ Client = (*Pool)(nil)
+)
+
+// Pool is a set of bound connections shared by concurrent operations. Up to
+// its size connections are opened, lazily, as operations need them; each
+// operation uses one connection for its whole duration, so that paged
+// searches and transactions stay on the connection they started on.
+type Pool struct {
+	config *Config
+	idle   chan *ldap.Conn
+	slots  chan struct{}
+
+	// limiter throttles the operations, if MaxRequestsPerSecond is set
+	limiter *rateLimiter
+
+	// done is closed by Close, stopping the keepalive
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// capabilities are read from the root DSE when the pool is created; they
+	// are nil if it could not be read
+	capabilities    *Capabilities
+	capabilitiesErr error
+
+	// bindResult is the outcome of the first bind, if it was a simple bind
+	bindResult *BindResult
+
+	// written are the values written to entries, for the reads which follow
+	written writtenLog
+}
+
+// NewPool returns a pool of at most size connections. A first connection is
+// opened right away, so that invalid settings or credentials are reported
+// when the pool is created, and the capabilities of the server are read on
+// it.
+func NewPool(config *Config, size int) (*Pool, error) {
+	if size < 1 {
+		size = 1
+	}
+	p := &Pool{
+		config:  config,
+		idle:    make(chan *ldap.Conn, size),
+		slots:   make(chan struct{}, size),
+		done:    make(chan struct{}),
+		limiter: newRateLimiter(config.MaxRequestsPerSecond),
+	}
+
+	conn, bindResult, err := dialAndBind(config)
+	if err != nil {
+		return nil, err
+	}
+	p.bindResult = bindResult
+	p.capabilities, p.capabilitiesErr = readCapabilities(conn)
+	p.slots <- struct{}{}
+	p.idle <- conn
+	if config.KeepAlive > 0 {
+		go p.keepAlive(config.KeepAlive)
+	}
+	return p, nil
+}
+
+// IsTransient tells whether an error connecting to the server may go away
+// by itself: the server cannot be reached, or is busy or unavailable, as
+// while it starts. Failed binds are not transient.
+func IsTransient(err error) bool {
+	var ldapErr *ldap.Error
+	if errors.As(err, &ldapErr) {
+		switch ldapErr.ResultCode {
+		case ldap.ErrorNetwork, ldap.LDAPResultBusy, ldap.LDAPResultUnavailable:
+			return true
+		}
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Get takes a connection from the pool, opening a new one if none is idle
+// and the pool is not full, or waiting for one to be released otherwise. The
+// connection must be given back with Put.
+func (p *Pool) Get() (*ldap.Conn, error) {
+	for {
+		// prefer idle connections over opening new ones
+		select {
+		case conn := <-p.idle:
+			if p.usable(conn) {
+				return conn, nil
+			}
+			continue
+		default:
+		}
+
+		select {
+		case conn := <-p.idle:
+			if p.usable(conn) {
+				return conn, nil
+			}
+		case p.slots <- struct{}{}:
+			conn, err := DialAndBind(p.config)
+			if err != nil {
+				<-p.slots
+				return nil, err
+			}
+			return conn, nil
+		}
+	}
+}
+
+// Put gives back a connection taken with Get.
+func (p *Pool) Put(conn *ldap.Conn) {
+	if p.usable(conn) {
+		p.idle <- conn
+	}
+}
+
+// usable tells whether a connection can still be used, releasing its slot in
+// the pool if it cannot.
+func (p *Pool) usable(conn *ldap.Conn) bool {
+	if conn.IsClosing() {
+		conn.Close()
+		<-p.slots
+		return false
+	}
+	return true
+}
+
+// Capabilities returns the capabilities of the server, or the error which
+// prevented reading them, in which case no optional feature is used.
+func (p *Pool) Capabilities() (*Capabilities, error) {
+	return p.capabilities, p.capabilitiesErr
+}
+
+// BindResult returns the outcome of the first bind of the pool, with the
+// Password Policy warnings of the server, or nil if it was not a simple bind.
+func (p *Pool) BindResult() *BindResult {
+	return p.bindResult
+}
+
+// SupportsControl tells whether the server advertises the control with the
+// given OID.
+func (p *Pool) SupportsControl(oid string) bool {
+	return p.capabilities.SupportsControl(oid)
+}
+
+// SupportsExtension tells whether the server advertises the extended
+// operation with the given OID.
+func (p *Pool) SupportsExtension(oid string) bool {
+	return p.capabilities.SupportsExtension(oid)
+}
+
+// ReadOnly tells whether the pool refuses update operations.
+func (p *Pool) ReadOnly() bool {
+	return p.config.ReadOnly
+}
+
+func (p *Pool) checkWritable(operation, dn string) error {
+	if p.ReadOnly() {
+		return fmt.Errorf("refusing to %s %q: %w", operation, dn, ErrReadOnly)
+	}
+	return nil
+}
+
+// WithConn runs fn with a connection of the pool, for operations which need
+// several requests on the same connection; the read_only setting is not
+// enforced on the connection, which fn must honor itself.
+func (p *Pool) WithConn(fn func(conn *ldap.Conn) error) error {
+	conn, err := p.Get()
+	if err != nil {
+		return err
+	}
+	defer p.Put(conn)
+	return fn(conn)
+}
+
+// Written returns the values written to an entry through the pool since
+// ForgetWritten was last called for it, or nil if none were.
+func (p *Pool) Written(dn string) *Written {
+	return p.written.get(dn)
+}
+
+// ForgetWritten forgets the values written to the given entries.
+func (p *Pool) ForgetWritten(dns ...string) {
+	p.written.forget(dns...)
+}
+
+// Close closes the idle connections of the pool, and stops the keepalive.
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+	for {
+		select {
+		case conn := <-p.idle:
+			conn.Close()
+			<-p.slots
+		default:
+			return
+		}
+	}
+}
+
+// withDefaultControls returns the controls of a request followed by the
+// default Controls of the pool it does not carry yet.
+func (p *Pool) withDefaultControls(controls []ldap.Control) []ldap.Control {
+	if p.config == nil {
+		return controls
+	}
+	for _, control := range p.config.Controls {
+		if ldap.FindControl(controls, control.GetControlType()) == nil {
+			controls = append(controls, control)
+		}
+	}
+	return controls
+}
+
+// Search runs a search on a connection of the pool.
+func (p *Pool) Search(request *ldap.SearchRequest) (sr *ldap.SearchResult, err error) {
+	request.Controls = p.withDefaultControls(request.Controls)
+	err = p.withRetry(func(conn *ldap.Conn) error {
+		sr, err = conn.Search(request)
+		return err
+	})
+	return
+}
+
+// SearchWithPaging runs a paged search, all of whose pages are read on the
+// same connection of the pool; servers known not to support the Simple Paged
+// Results control get a single search instead. A retried search starts over
+// from the first page: the paging control, whose cookie go-ldap updates in
+// place, is left out of the request of each attempt, since the cookie of a
+// page read on a failed connection means nothing to another one.
+func (p *Pool) SearchWithPaging(request *ldap.SearchRequest, pagingSize uint32) (sr *ldap.SearchResult, err error) {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if p.capabilities != nil && !p.capabilities.SupportsControl(OIDPagedResults) {
+		return p.Search(request)
+	}
+	err = p.withRetry(func(conn *ldap.Conn) error {
+		attempt := *request
+		attempt.Controls = withoutControl(request.Controls, ldap.ControlTypePaging)
+		sr, err = conn.SearchWithPaging(&attempt, pagingSize)
+		return err
+	})
+	return
+}
+
+// withoutControl returns a copy of controls without those of the given type.
+func withoutControl(controls []ldap.Control, controlType string) []ldap.Control {
+	kept := make([]ldap.Control, 0, len(controls))
+	for _, control := range controls {
+		if control.GetControlType() != controlType {
+			kept = append(kept, control)
+		}
+	}
+	return kept
+}
+
+// Add adds an entry using a connection of the pool.
+func (p *Pool) Add(request *ldap.AddRequest) error {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if err := p.checkWritable("add", request.DN); err != nil {
+		return err
+	}
+	err := p.withRetry(func(conn *ldap.Conn) error {
+		return conn.Add(request)
+	})
+	if err == nil {
+		p.written.recordAdd(request)
+	}
+	return err
+}
+
+// Modify modifies an entry using a connection of the pool.
+func (p *Pool) Modify(request *ldap.ModifyRequest) error {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if err := p.checkWritable("modify", request.DN); err != nil {
+		return err
+	}
+	err := p.withRetry(func(conn *ldap.Conn) error {
+		return conn.Modify(request)
+	})
+	if err == nil {
+		p.written.recordModify(request)
+	}
+	return err
+}
+
+// ModifyWithResult modifies an entry using a connection of the pool and
+// returns the response controls.
+func (p *Pool) ModifyWithResult(request *ldap.ModifyRequest) (result *ldap.ModifyResult, err error) {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if err := p.checkWritable("modify", request.DN); err != nil {
+		return nil, err
+	}
+	err = p.withRetry(func(conn *ldap.Conn) error {
+		result, err = conn.ModifyWithResult(request)
+		return err
+	})
+	if err == nil {
+		p.written.recordModify(request)
+	}
+	return
+}
+
+// ModifyDN renames or moves an entry using a connection of the pool.
+func (p *Pool) ModifyDN(request *ldap.ModifyDNRequest) error {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if err := p.checkWritable("rename", request.DN); err != nil {
+		return err
+	}
+	err := p.withRetry(func(conn *ldap.Conn) error {
+		return conn.ModifyDN(request)
+	})
+	if err == nil {
+		// what was written under the former DN no longer applies
+		p.written.forget(request.DN)
+	}
+	return err
+}
+
+// Del deletes an entry using a connection of the pool.
+func (p *Pool) Del(request *ldap.DelRequest) error {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if err := p.checkWritable("delete", request.DN); err != nil {
+		return err
+	}
+	err := p.withRetry(func(conn *ldap.Conn) error {
+		return conn.Del(request)
+	})
+	if err == nil {
+		p.written.forget(request.DN)
+	}
+	return err
+}
+
+// Extended runs an extended operation on a connection of the pool.
+func (p *Pool) Extended(request *ldap.ExtendedRequest) (response *ldap.ExtendedResponse, err error) {
+	request.Controls = p.withDefaultControls(request.Controls)
+	if p.ReadOnly() && request.Name != OIDWhoAmI {
+		return nil, fmt.Errorf("refusing to run extended operation %s: %w", request.Name, ErrReadOnly)
+	}
+	err = p.withRetry(func(conn *ldap.Conn) error {
+		response, err = conn.Extended(request)
+		return err
+	})
+	return
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestNewPoolReportsDialErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	if _, err := NewPool(&Config{LDAPHost: "127.0.0.1", LDAPPort: port}, 2); err == nil {
+		t.Error("expected an error for an unreachable server")
+	}
+}
+
+func TestPoolReusesIdleConnections(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+	defer conn.Close()
+
+	p := &Pool{idle: make(chan *ldap.Conn, 2), slots: make(chan struct{}, 2)}
+	p.slots <- struct{}{}
+	p.idle <- conn
+
+	got, err := p.Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != conn {
+		t.Fatal("expected the idle connection")
+	}
+	p.Put(got)
+	if len(p.idle) != 1 || len(p.slots) != 1 {
+		t.Errorf("expected 1 idle connection and 1 slot in use, got %d and %d", len(p.idle), len(p.slots))
+	}
+}
+
+func TestPoolDropsClosedConnections(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+
+	p := &Pool{idle: make(chan *ldap.Conn, 2), slots: make(chan struct{}, 2)}
+	p.slots <- struct{}{}
+	conn.Close()
+	p.Put(conn)
+
+	if len(p.idle) != 0 || len(p.slots) != 0 {
+		t.Errorf("expected the closed connection to be dropped, got %d idle connections and %d slots in use", len(p.idle), len(p.slots))
+	}
+}
+
+func TestReadOnlyPoolRejectsUpdates(t *testing.T) {
+	p := &Pool{config: &Config{ReadOnly: true}, idle: make(chan *ldap.Conn, 1), slots: make(chan struct{}, 1)}
+
+	errs := map[string]error{
+		"add":      p.Add(ldap.NewAddRequest("cn=a,dc=example,dc=com", nil)),
+		"modify":   p.Modify(ldap.NewModifyRequest("cn=a,dc=example,dc=com", nil)),
+		"modifydn": p.ModifyDN(ldap.NewModifyDNRequest("cn=a,dc=example,dc=com", "cn=b", true, "")),
+		"delete":   p.Del(ldap.NewDelRequest("cn=a,dc=example,dc=com", nil)),
+	}
+	_, errs["modify with result"] = p.ModifyWithResult(ldap.NewModifyRequest("cn=a,dc=example,dc=com", nil))
+	_, errs["extended"] = p.Extended(ldap.NewExtendedRequest(OIDStartTransaction, nil))
+	for operation, err := range errs {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", operation, err)
+		}
+	}
+}
+
+func TestPinnedConnection(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+	defer conn.Close()
+
+	sessionTracking := ldap.NewControlString("1.3.6.1.4.1.21008.108.63.1", false, "run")
+	p := &Pool{
+		config: &Config{ReadOnly: true, Controls: []ldap.Control{sessionTracking}},
+		idle:   make(chan *ldap.Conn, 1),
+		slots:  make(chan struct{}, 1),
+	}
+	p.slots <- struct{}{}
+	p.idle <- conn
+
+	pinned, err := p.Pin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p.idle) != 0 {
+		t.Error("expected the connection to be taken out of the pool")
+	}
+
+	add := ldap.NewAddRequest("cn=a,dc=example,dc=com", nil)
+	modify := ldap.NewModifyRequest("cn=a,dc=example,dc=com", nil)
+	del := ldap.NewDelRequest("cn=a,dc=example,dc=com", nil)
+	errs := map[string]error{
+		"add":      pinned.Add(add),
+		"modify":   pinned.Modify(modify),
+		"modifydn": pinned.ModifyDN(ldap.NewModifyDNRequest("cn=a,dc=example,dc=com", "cn=b", true, "")),
+		"delete":   pinned.Del(del),
+	}
+	_, errs["extended"] = pinned.Extended(ldap.NewExtendedRequest(OIDStartTransaction, nil))
+	for operation, err := range errs {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", operation, err)
+		}
+	}
+	for operation, controls := range map[string][]ldap.Control{"add": add.Controls, "modify": modify.Controls, "delete": del.Controls} {
+		if len(controls) != 1 || controls[0] != sessionTracking {
+			t.Errorf("%s: expected the default controls, got %v", operation, controls)
+		}
+	}
+
+	pinned.Release()
+	pinned.Release()
+	if len(p.idle) != 1 || len(p.slots) != 1 {
+		t.Errorf("expected the connection to be given back once, got %d idle connections and %d slots in use", len(p.idle), len(p.slots))
+	}
+}
+
+func TestPoolDefaultControls(t *testing.T) {
+	manageDsaIT := ldap.NewControlManageDsaIT(true)
+	sessionTracking := ldap.NewControlString("1.3.6.1.4.1.21008.108.63.1", false, "run")
+	p := &Pool{config: &Config{Controls: []ldap.Control{manageDsaIT, sessionTracking}}}
+
+	controls := p.withDefaultControls(nil)
+	if len(controls) != 2 || controls[0] != manageDsaIT || controls[1] != sessionTracking {
+		t.Errorf("expected the default controls, got %v", controls)
+	}
+
+	// the controls of the request take precedence
+	own := ldap.NewControlManageDsaIT(false)
+	controls = p.withDefaultControls([]ldap.Control{own})
+	if len(controls) != 2 || controls[0] != own || controls[1] != sessionTracking {
+		t.Errorf("expected the request control and the session tracking one, got %v", controls)
+	}
+
+	// retried requests do not accumulate them
+	if again := p.withDefaultControls(controls); len(again) != 2 {
+		t.Errorf("expected the default controls to be added once, got %v", again)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	_, err = NewPool(&Config{LDAPHost: "127.0.0.1", LDAPPort: port}, 1)
+	if !IsTransient(err) {
+		t.Errorf("expected an unreachable server to be transient: %v", err)
+	}
+	if !IsTransient(ldap.NewError(ldap.LDAPResultUnavailable, errors.New("starting"))) {
+		t.Error("expected an unavailable server to be transient")
+	}
+	if IsTransient(ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))) {
+		t.Error("expected a failed bind not to be transient")
+	}
+}
+
+func TestNewPoolTriesEachURL(t *testing.T) {
+	var urls []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		urls = append(urls, fmt.Sprintf("ldap://127.0.0.1:%d", listener.Addr().(*net.TCPAddr).Port))
+		listener.Close()
+	}
+
+	_, err := NewPool(&Config{URLs: urls}, 1)
+	if err == nil {
+		t.Fatal("expected an error for unreachable servers")
+	}
+	for _, u := range urls {
+		if !strings.Contains(err.Error(), u) {
+			t.Errorf("expected the error to report %s: %v", u, err)
+		}
+	}
+	if !IsTransient(err) {
+		t.Errorf("expected unreachable servers to be transient: %v", err)
+	}
+}
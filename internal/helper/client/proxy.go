@@ -0,0 +1,123 @@
+package client
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/net/proxy"
+)
+
+// dialFunc opens network connections, like net.Dial.
+type dialFunc func(network, address string) (net.Conn, error)
+
+// dialer returns the function opening the TCP connections to the server:
+// through the SSH tunnel and the proxy of the configuration if any, or
+// directly otherwise.
+func (c *Config) dialer() (dialFunc, error) {
+	direct := &net.Dialer{Timeout: ldap.DefaultTimeout, KeepAlive: c.KeepAlive}
+	dial := direct.Dial
+	if c.Proxy != "" {
+		var err error
+		if dial, err = proxyDialer(c.Proxy, dial); err != nil {
+			return nil, err
+		}
+	}
+	if c.SSHTunnel != nil {
+		dial = c.SSHTunnel.dialer(dial)
+	}
+	return dial, nil
+}
+
+// proxyDialer returns the function opening connections through the proxy
+// with the given URL: socks5:// (or socks5h://) for a SOCKS5 proxy, http://
+// for an HTTP proxy, with CONNECT requests. Credentials may be given in the
+// user information of the URL.
+func proxyDialer(proxyURL string, forward dialFunc) (dialFunc, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if u.Port() == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing port", u.Redacted())
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if u.User != nil {
+			password, _ := u.User.Password()
+			auth = &proxy.Auth{User: u.User.Username(), Password: password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", u.Host, auth, forwardDialer(forward))
+		if err != nil {
+			return nil, err
+		}
+		return dialer.Dial, nil
+	case "http":
+		return func(network, address string) (net.Conn, error) {
+			return httpConnect(u, forward, address)
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid proxy URL %q: unsupported scheme %q", u.Redacted(), u.Scheme)
+}
+
+// forwardDialer adapts a dialFunc to proxy.Dialer.
+type forwardDialer dialFunc
+
+func (d forwardDialer) Dial(network, address string) (net.Conn, error) {
+	return d(network, address)
+}
+
+// httpConnect opens a connection to address through the HTTP proxy at u,
+// with a CONNECT request.
+func httpConnect(u *url.URL, forward dialFunc, address string) (net.Conn, error) {
+	conn, err := forward("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+	// the whole exchange with the proxy must fit in the dial timeout
+	if err := conn.SetDeadline(time.Now().Add(ldap.DefaultTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := request.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error connecting through proxy %s: %w", u.Host, err)
+	}
+
+	// LDAP servers do not speak first: nothing follows the response
+	response, err := http.ReadResponse(bufio.NewReader(conn), request)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error connecting through proxy %s: %w", u.Host, err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", u.Host, address, response.Status)
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
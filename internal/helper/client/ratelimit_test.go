@@ -0,0 +1,25 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	if newRateLimiter(0) != nil {
+		t.Error("expected no limiter without a rate")
+	}
+	// a nil limiter does not wait
+	var unlimited *rateLimiter
+	unlimited.wait()
+
+	l := newRateLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		l.wait()
+	}
+	// the first operation is not delayed, the others are 10ms apart
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("5 operations at 100 per second took %s, expected at least 40ms", elapsed)
+	}
+}
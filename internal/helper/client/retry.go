@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// IsRetryable tells whether an operation which failed with err may succeed
+// if run again: besides transient errors, servers refusing operations while
+// in maintenance, e.g. a replica being resynchronised, are retried.
+func IsRetryable(err error) bool {
+	if IsTransient(err) {
+		return true
+	}
+	var ldapErr *ldap.Error
+	return errors.As(err, &ldapErr) &&
+		ldapErr.ResultCode == ldap.LDAPResultUnwillingToPerform &&
+		strings.Contains(strings.ToLower(ldapErr.Error()), "maintenance")
+}
+
+// backoff returns the time to wait after the given failed attempt: it
+// doubles at each attempt from RetryMinBackoff up to RetryMaxBackoff, with
+// a random jitter of up to half of it so that concurrent operations do not
+// retry all at once.
+func (c *Config) backoff(attempt int) time.Duration {
+	d := c.RetryMinBackoff
+	for i := 1; i < attempt && d < c.RetryMaxBackoff; i++ {
+		d *= 2
+	}
+	if c.RetryMaxBackoff > 0 && d > c.RetryMaxBackoff {
+		d = c.RetryMaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// withRetry runs fn with a connection of the pool, again on another
+// connection while it fails with a retryable error, up to RetryAttempts
+// times; each attempt is subject to the rate limit of the pool.
+func (p *Pool) withRetry(fn func(conn *ldap.Conn) error) error {
+	for attempt := 1; ; attempt++ {
+		p.limiter.wait()
+		err := p.WithConn(fn)
+		if err == nil || p.config == nil || attempt >= p.config.RetryAttempts || !IsRetryable(err) {
+			return err
+		}
+		time.Sleep(p.config.backoff(attempt))
+	}
+}
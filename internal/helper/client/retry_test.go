@@ -0,0 +1,222 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldaptest"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for name, test := range map[string]struct {
+		err       error
+		retryable bool
+	}{
+		"busy":        {ldap.NewError(ldap.LDAPResultBusy, errors.New("busy")), true},
+		"unavailable": {ldap.NewError(ldap.LDAPResultUnavailable, errors.New("shutting down")), true},
+		"network":     {ldap.NewError(ldap.ErrorNetwork, errors.New("ldap: connection timed out")), true},
+		"maintenance": {ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("server in maintenance mode")), true},
+		"unwilling":   {ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("no global superior knowledge")), false},
+		"no such":     {ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object")), false},
+		"other":       {errors.New("other"), false},
+	} {
+		if got := IsRetryable(test.err); got != test.retryable {
+			t.Errorf("%s: got %t, expected %t", name, got, test.retryable)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Config{RetryMinBackoff: time.Second, RetryMaxBackoff: 5 * time.Second}
+	for attempt, max := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if d := c.backoff(attempt); d < max/2 || d > max {
+			t.Errorf("attempt %d: got %s, expected between %s and %s", attempt, d, max/2, max)
+		}
+	}
+}
+
+func TestPoolRetriesRetryableErrors(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+	defer conn.Close()
+
+	p := &Pool{
+		config: &Config{RetryAttempts: 3, RetryMinBackoff: time.Millisecond},
+		idle:   make(chan *ldap.Conn, 1),
+		slots:  make(chan struct{}, 1),
+	}
+	p.slots <- struct{}{}
+	p.idle <- conn
+
+	attempts := 0
+	err := p.withRetry(func(conn *ldap.Conn) error {
+		attempts++
+		return ldap.NewError(ldap.LDAPResultBusy, errors.New("busy"))
+	})
+	if !IsRetryable(err) || attempts != 3 {
+		t.Errorf("expected 3 attempts failing with busy, got %d and %v", attempts, err)
+	}
+
+	attempts = 0
+	err = p.withRetry(func(conn *ldap.Conn) error {
+		attempts++
+		return ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("expected a single attempt, got %d and %v", attempts, err)
+	}
+}
+
+func TestPoolRetriesPagedSearchesFromTheFirstPage(t *testing.T) {
+	var seed strings.Builder
+	seed.WriteString("dn: dc=example,dc=com\nobjectClass: dcObject\nobjectClass: organization\ndc: example\no: Example Inc.\n")
+	var expected []string
+	for i := 1; i <= 5; i++ {
+		dn := fmt.Sprintf("ou=unit%d,dc=example,dc=com", i)
+		fmt.Fprintf(&seed, "\ndn: %s\nobjectClass: organizationalUnit\nou: unit%d\n", dn, i)
+		expected = append(expected, dn)
+	}
+	server, err := ldaptest.NewServer(ldaptest.Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF:         seed.String(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error starting the server: %v", err)
+	}
+	defer server.Close()
+
+	// the server is busy when the second page is first asked for, through a
+	// proxy which answers that request itself
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	var busy sync.Once
+	go func() {
+		for {
+			client, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", net.JoinHostPort(server.Host(), strconv.Itoa(server.Port())))
+			if err != nil {
+				client.Close()
+				return
+			}
+			var writes sync.Mutex
+			go func() {
+				defer client.Close()
+				for {
+					packet, err := ber.ReadPacket(upstream)
+					if err != nil {
+						return
+					}
+					writes.Lock()
+					_, err = client.Write(packet.Bytes())
+					writes.Unlock()
+					if err != nil {
+						return
+					}
+				}
+			}()
+			go func() {
+				defer upstream.Close()
+				for {
+					packet, err := ber.ReadPacket(client)
+					if err != nil {
+						return
+					}
+					answered := false
+					if pagingCookie(packet) != "" {
+						busy.Do(func() {
+							writes.Lock()
+							client.Write(busyResult(packet.Children[0].Value.(int64)).Bytes())
+							writes.Unlock()
+							answered = true
+						})
+					}
+					if answered {
+						continue
+					}
+					if _, err := upstream.Write(packet.Bytes()); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	p, err := NewPool(&Config{
+		LDAPHost:        "127.0.0.1",
+		LDAPPort:        listener.Addr().(*net.TCPAddr).Port,
+		BindUser:        "cn=admin,dc=example,dc=com",
+		BindPassword:    "admin",
+		RetryAttempts:   2,
+		RetryMinBackoff: time.Millisecond,
+	}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error creating the pool: %v", err)
+	}
+	defer p.Close()
+
+	request := ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeSingleLevel, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=organizationalUnit)", []string{"ou"}, nil)
+	sr, err := p.SearchWithPaging(request, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var dns []string
+	for _, entry := range sr.Entries {
+		dns = append(dns, entry.DN)
+	}
+	sort.Strings(dns)
+	if strings.Join(dns, ";") != strings.Join(expected, ";") {
+		t.Errorf("expected all the entries once the search is retried, got %v", dns)
+	}
+}
+
+// busyResult returns the search result done message of a server too busy to
+// answer the given search request.
+func busyResult(messageID int64) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	done := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultDone, nil, "Search Result Done")
+	done.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(ldap.LDAPResultBusy), "Result Code"))
+	done.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	done.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "busy", "Diagnostic Message"))
+	packet.AppendChild(done)
+	return packet
+}
+
+// pagingCookie returns the cookie of the Simple Paged Results control of a
+// request, if any.
+func pagingCookie(packet *ber.Packet) string {
+	if len(packet.Children) < 3 {
+		return ""
+	}
+	for _, control := range packet.Children[2].Children {
+		if len(control.Children) < 2 || control.Children[0].Value != ldap.ControlTypePaging {
+			continue
+		}
+		value, err := ber.DecodePacketErr(control.Children[len(control.Children)-1].ByteValue)
+		if err != nil || len(value.Children) != 2 {
+			return ""
+		}
+		return string(value.Children[1].ByteValue)
+	}
+	return ""
+}
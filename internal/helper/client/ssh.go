@@ -0,0 +1,170 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHTunnel is an SSH server the connections to the LDAP server are
+// forwarded through, like ssh -L, e.g. a jump host in front of a directory
+// which cannot be reached directly.
+type SSHTunnel struct {
+	// Host is the address of the SSH server, as host:port.
+	Host string
+	User string
+
+	// PrivateKey is the PEM-encoded private key to authenticate with,
+	// encrypted with PrivateKeyPassphrase if set; with UseAgent, the keys of
+	// the agent at SSH_AUTH_SOCK are tried too.
+	PrivateKey           string
+	PrivateKeyPassphrase string
+	UseAgent             bool
+
+	// HostKey is the public key of the SSH server, in the authorized_keys
+	// format; when empty, the key is looked up in KnownHostsFile.
+	HostKey        string
+	KnownHostsFile string
+
+	// the SSH connection is opened on the first dial, and shared by all the
+	// connections to the LDAP server
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// dialer returns the function opening connections through the tunnel; the
+// SSH server itself is reached with forward.
+func (t *SSHTunnel) dialer(forward dialFunc) dialFunc {
+	return func(network, address string) (net.Conn, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.client != nil {
+			conn, err := t.client.Dial(network, address)
+			if err == nil {
+				return conn, nil
+			}
+			// the SSH connection may have been dropped: open a new one
+			t.client.Close()
+			t.client = nil
+		}
+
+		client, err := t.connect(forward)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to the SSH server %s: %w", t.Host, err)
+		}
+		t.client = client
+		return t.client.Dial(network, address)
+	}
+}
+
+func (t *SSHTunnel) connect(forward dialFunc) (*ssh.Client, error) {
+	config, closeAgent, err := t.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	conn, err := forward("tcp", t.Host)
+	if err != nil {
+		return nil, err
+	}
+	// the handshake must fit in the dial timeout
+	if err := conn.SetDeadline(time.Now().Add(ldap.DefaultTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, t.Host, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		sshConn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, channels, requests), nil
+}
+
+// clientConfig returns the settings of the SSH connection, along with the
+// function closing the connection to the agent once authenticated.
+func (t *SSHTunnel) clientConfig() (*ssh.ClientConfig, func(), error) {
+	closeAgent := func() {}
+
+	var methods []ssh.AuthMethod
+	if t.PrivateKey != "" {
+		signer, err := t.signer()
+		if err != nil {
+			return nil, nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if t.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, nil, errors.New("SSH_AUTH_SOCK is not set, no SSH agent to use")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error connecting to the SSH agent: %w", err)
+		}
+		closeAgent = func() { conn.Close() }
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+	if len(methods) == 0 {
+		return nil, nil, errors.New("no private key nor agent to authenticate with")
+	}
+
+	hostKeyCallback, err := t.hostKeyCallback()
+	if err != nil {
+		closeAgent()
+		return nil, nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            methods,
+		HostKeyCallback: hostKeyCallback,
+	}, closeAgent, nil
+}
+
+func (t *SSHTunnel) signer() (ssh.Signer, error) {
+	if t.PrivateKeyPassphrase != "" {
+		signer, err := ssh.ParsePrivateKeyWithPassphrase([]byte(t.PrivateKey), []byte(t.PrivateKeyPassphrase))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		return signer, nil
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(t.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return signer, nil
+}
+
+func (t *SSHTunnel) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if t.HostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(t.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid host key: %w", err)
+		}
+		return ssh.FixedHostKey(key), nil
+	}
+	if t.KnownHostsFile == "" {
+		return nil, errors.New("the host key of the SSH server cannot be checked: no host key nor known_hosts file")
+	}
+	callback, err := knownhosts.New(t.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the known hosts: %w", err)
+	}
+	return callback, nil
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startSSHServer starts an SSH server accepting the given client key and
+// forwarding direct-tcpip channels, and returns its address and host key.
+func startSSHServer(t *testing.T, clientKey ssh.PublicKey) (string, ssh.PublicKey) {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "tunnel" && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, channels, requests, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for newChannel := range channels {
+					var target struct {
+						Host       string
+						Port       uint32
+						OriginHost string
+						OriginPort uint32
+					}
+					if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+						newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+						continue
+					}
+					upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.FormatUint(uint64(target.Port), 10)))
+					if err != nil {
+						newChannel.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, channelRequests, err := newChannel.Accept()
+					if err != nil {
+						upstream.Close()
+						continue
+					}
+					go ssh.DiscardRequests(channelRequests)
+					go func() {
+						defer channel.Close()
+						defer upstream.Close()
+						go io.Copy(upstream, channel)
+						io.Copy(channel, upstream)
+					}()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), hostSigner.PublicKey()
+}
+
+func TestSSHTunnel(t *testing.T) {
+	// the target echoes what it receives
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	_, clientPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientPrivateKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, hostKey := startSSHServer(t, clientSigner.PublicKey())
+
+	c := &Config{SSHTunnel: &SSHTunnel{
+		Host:       host,
+		User:       "tunnel",
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		HostKey:    string(ssh.MarshalAuthorizedKey(hostKey)),
+	}}
+	dialer, err := c.dialer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both connections go through the same SSH connection
+	for i := 0; i < 2; i++ {
+		conn, err := dialer("tcp", target.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(conn, "ping"); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+			t.Errorf("read %q, %v through the tunnel, expected ping", buf, err)
+		}
+		conn.Close()
+	}
+
+	// another host key is rejected
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(otherKey)
+	c.SSHTunnel = &SSHTunnel{
+		Host:       host,
+		User:       "tunnel",
+		PrivateKey: c.SSHTunnel.PrivateKey,
+		HostKey:    string(ssh.MarshalAuthorizedKey(otherSigner.PublicKey())),
+	}
+	dialer, _ = c.dialer()
+	if _, err := dialer("tcp", target.Addr().String()); err == nil {
+		t.Error("expected an error for an unknown host key")
+	}
+}
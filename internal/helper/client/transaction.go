@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LDAP transactions (RFC 5805).
+const (
+	OIDStartTransaction = "1.3.6.1.1.21.1"
+	OIDTransactionSpec  = "1.3.6.1.1.21.2"
+	OIDEndTransaction   = "1.3.6.1.1.21.3"
+)
+
+// ControlTransactionSpecification marks an update operation as part of the
+// transaction with the given identifier.
+type ControlTransactionSpecification struct {
+	ID []byte
+}
+
+// GetControlType returns the OID of the control.
+func (c *ControlTransactionSpecification) GetControlType() string {
+	return OIDTransactionSpec
+}
+
+// Encode returns the BER encoding of the control, which is always critical.
+func (c *ControlTransactionSpecification) Encode() *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, OIDTransactionSpec, "Control Type (Transaction Specification)"))
+	packet.AppendChild(ber.NewBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, true, "Criticality"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(c.ID), "Control Value (Transaction Identifier)"))
+	return packet
+}
+
+// String returns a human-readable description of the control.
+func (c *ControlTransactionSpecification) String() string {
+	return fmt.Sprintf("Control Type: Transaction Specification (%q)  Criticality: true  Transaction Identifier: %x", OIDTransactionSpec, c.ID)
+}
+
+// StartTransaction starts a transaction and returns its identifier.
+func StartTransaction(conn Client) ([]byte, error) {
+	response, err := conn.Extended(ldap.NewExtendedRequest(OIDStartTransaction, nil))
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	if response.Value == nil || response.Value.Data == nil || response.Value.Data.Len() == 0 {
+		return nil, fmt.Errorf("error starting transaction: no transaction identifier returned")
+	}
+	return response.Value.Data.Bytes(), nil
+}
+
+// EndTransaction commits (or aborts) the transaction with the given
+// identifier. The error of a failed commit is the one of the update operation
+// which could not be applied.
+func EndTransaction(conn Client, id []byte, commit bool) error {
+	// txnEndReq ::= SEQUENCE { commit BOOLEAN DEFAULT TRUE, identifier OCTET STRING }
+	request := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Transaction End Request")
+	if !commit {
+		request.AppendChild(ber.NewBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, false, "Commit"))
+	}
+	request.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(id), "Identifier"))
+
+	value := ber.Encode(ber.ClassContext, ber.TypePrimitive, 1, nil, "Extended Request Value: Transaction End Request")
+	value.AppendChild(request)
+
+	if _, err := conn.Extended(ldap.NewExtendedRequest(OIDEndTransaction, value)); err != nil {
+		if commit {
+			return fmt.Errorf("error committing transaction: %w", err)
+		}
+		return fmt.Errorf("error aborting transaction: %w", err)
+	}
+	return nil
+}
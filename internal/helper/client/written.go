@@ -0,0 +1,156 @@
+package client
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Written are the values written to an entry since the pool was last told to
+// forget them (see Pool.ForgetWritten), so that the reads following the
+// writes can tell whether they see them yet, e.g. on a replica lagging behind
+// the server written to. Attributes are keyed by their lower-cased names.
+type Written struct {
+	// Present are the values added to each attribute, or the values it was
+	// replaced with.
+	Present map[string][]string
+	// Absent are the values deleted from each attribute.
+	Absent map[string][]string
+	// Cleared are the attributes deleted or replaced with no values.
+	Cleared map[string]bool
+}
+
+func newWritten() *Written {
+	return &Written{Present: map[string][]string{}, Absent: map[string][]string{}, Cleared: map[string]bool{}}
+}
+
+// forget drops what is known of an attribute, e.g. incremented.
+func (w *Written) forget(attribute string) {
+	delete(w.Present, attribute)
+	delete(w.Absent, attribute)
+	delete(w.Cleared, attribute)
+}
+
+func (w *Written) add(attribute string, values []string) {
+	delete(w.Cleared, attribute)
+	w.Present[attribute] = append(w.Present[attribute], values...)
+	w.Absent[attribute] = without(w.Absent[attribute], values)
+}
+
+func (w *Written) delete(attribute string, values []string) {
+	if len(values) == 0 {
+		w.forget(attribute)
+		w.Cleared[attribute] = true
+		return
+	}
+	w.Absent[attribute] = append(w.Absent[attribute], values...)
+	w.Present[attribute] = without(w.Present[attribute], values)
+}
+
+func (w *Written) replace(attribute string, values []string) {
+	w.forget(attribute)
+	if len(values) == 0 {
+		w.Cleared[attribute] = true
+		return
+	}
+	w.Present[attribute] = append([]string(nil), values...)
+}
+
+// without returns the values but those given, compared as they are.
+func without(values, removed []string) []string {
+	var kept []string
+	for _, v := range values {
+		found := false
+		for _, r := range removed {
+			found = found || v == r
+		}
+		if !found {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// writtenLog is the values written to entries, by normalized DN.
+type writtenLog struct {
+	mu      sync.Mutex
+	entries map[string]*Written
+}
+
+// writtenKey returns the key of an entry in the log, which is its lower-cased
+// DN in its canonical form.
+func writtenKey(dn string) string {
+	if parsed, err := ldap.ParseDN(dn); err == nil {
+		dn = parsed.String()
+	}
+	return strings.ToLower(dn)
+}
+
+func (l *writtenLog) entry(dn string) *Written {
+	key := writtenKey(dn)
+	if l.entries == nil {
+		l.entries = map[string]*Written{}
+	}
+	if l.entries[key] == nil {
+		l.entries[key] = newWritten()
+	}
+	return l.entries[key]
+}
+
+func (l *writtenLog) recordAdd(request *ldap.AddRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, writtenKey(request.DN))
+	w := l.entry(request.DN)
+	for _, attribute := range request.Attributes {
+		w.add(strings.ToLower(attribute.Type), attribute.Vals)
+	}
+}
+
+func (l *writtenLog) recordModify(request *ldap.ModifyRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := l.entry(request.DN)
+	for _, change := range request.Changes {
+		attribute := strings.ToLower(change.Modification.Type)
+		switch change.Operation {
+		case ldap.AddAttribute:
+			w.add(attribute, change.Modification.Vals)
+		case ldap.DeleteAttribute:
+			w.delete(attribute, change.Modification.Vals)
+		case ldap.ReplaceAttribute:
+			w.replace(attribute, change.Modification.Vals)
+		default:
+			w.forget(attribute)
+		}
+	}
+}
+
+func (l *writtenLog) forget(dns ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, dn := range dns {
+		delete(l.entries, writtenKey(dn))
+	}
+}
+
+func (l *writtenLog) get(dn string) *Written {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w, ok := l.entries[writtenKey(dn)]
+	if !ok {
+		return nil
+	}
+	c := newWritten()
+	for attribute, values := range w.Present {
+		c.Present[attribute] = append([]string(nil), values...)
+	}
+	for attribute, values := range w.Absent {
+		c.Absent[attribute] = append([]string(nil), values...)
+	}
+	for attribute := range w.Cleared {
+		c.Cleared[attribute] = true
+	}
+	return c
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestWrittenLog(t *testing.T) {
+	var log writtenLog
+	if w := log.get("cn=test,dc=example,dc=com"); w != nil {
+		t.Fatalf("expected nothing written yet, got %+v", w)
+	}
+
+	add := ldap.NewAddRequest("cn=test,dc=example,dc=com", nil)
+	add.Attribute("objectClass", []string{"groupOfNames"})
+	add.Attribute("member", []string{"uid=a,dc=example,dc=com", "uid=b,dc=example,dc=com"})
+	log.recordAdd(add)
+
+	modify := ldap.NewModifyRequest("CN=Test, DC=example,DC=com", nil)
+	modify.Delete("member", []string{"uid=a,dc=example,dc=com"})
+	modify.Add("member", []string{"uid=c,dc=example,dc=com"})
+	modify.Replace("description", []string{"updated"})
+	modify.Delete("seeAlso", nil)
+	modify.Increment("serialNumber", "1")
+	log.recordModify(modify)
+
+	w := log.get("cn=test,dc=example,dc=com")
+	if w == nil {
+		t.Fatal("expected the writes under the same DN in another form")
+	}
+	if got := fmt.Sprint(w.Present["member"]); got != "[uid=b,dc=example,dc=com uid=c,dc=example,dc=com]" {
+		t.Errorf("present members = %s", got)
+	}
+	if got := fmt.Sprint(w.Absent["member"]); got != "[uid=a,dc=example,dc=com]" {
+		t.Errorf("absent members = %s", got)
+	}
+	if got := fmt.Sprint(w.Present["description"]); got != "[updated]" {
+		t.Errorf("present descriptions = %s", got)
+	}
+	if !w.Cleared["seealso"] {
+		t.Error("expected seeAlso cleared")
+	}
+	if _, ok := w.Present["serialnumber"]; ok {
+		t.Error("expected nothing known of the incremented serialNumber")
+	}
+
+	log.forget("cn=test,dc=example,dc=com")
+	if w := log.get("cn=test,dc=example,dc=com"); w != nil {
+		t.Errorf("expected the writes forgotten, got %+v", w)
+	}
+}
@@ -0,0 +1,381 @@
+// Package ldapschema parses the object class and attribute type descriptions
+// published in an LDAP server's subschema subentry (RFC 4512, section 4.1).
+package ldapschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectClass is a parsed object class description.
+type ObjectClass struct {
+	OID         string
+	Names       []string
+	Description string
+	Obsolete    bool
+	Superior    []string
+	Kind        string // STRUCTURAL, AUXILIARY or ABSTRACT
+	Must        []string
+	May         []string
+}
+
+// Name returns the primary name of the object class, or its OID if it has no
+// name.
+func (oc *ObjectClass) Name() string {
+	if len(oc.Names) > 0 {
+		return oc.Names[0]
+	}
+	return oc.OID
+}
+
+// AttributeType is a parsed attribute type description.
+type AttributeType struct {
+	OID                string
+	Names              []string
+	Description        string
+	Obsolete           bool
+	Superior           string
+	Equality           string
+	Ordering           string
+	Substring          string
+	Syntax             string
+	SingleValue        bool
+	Collective         bool
+	NoUserModification bool
+	Usage              string
+}
+
+// Name returns the primary name of the attribute type, or its OID if it has
+// no name.
+func (at *AttributeType) Name() string {
+	if len(at.Names) > 0 {
+		return at.Names[0]
+	}
+	return at.OID
+}
+
+// Schema indexes object classes and attribute types by OID and by each of
+// their names, case-insensitively.
+type Schema struct {
+	ObjectClasses  []*ObjectClass
+	AttributeTypes []*AttributeType
+
+	objectClasses  map[string]*ObjectClass
+	attributeTypes map[string]*AttributeType
+}
+
+// New parses the values of the objectClasses and attributeTypes attributes of
+// a subschema subentry.
+func New(objectClasses, attributeTypes []string) (*Schema, error) {
+	s := &Schema{
+		objectClasses:  map[string]*ObjectClass{},
+		attributeTypes: map[string]*AttributeType{},
+	}
+	for _, value := range objectClasses {
+		oc, err := ParseObjectClass(value)
+		if err != nil {
+			return nil, err
+		}
+		s.ObjectClasses = append(s.ObjectClasses, oc)
+		s.objectClasses[strings.ToLower(oc.OID)] = oc
+		for _, name := range oc.Names {
+			s.objectClasses[strings.ToLower(name)] = oc
+		}
+	}
+	for _, value := range attributeTypes {
+		at, err := ParseAttributeType(value)
+		if err != nil {
+			return nil, err
+		}
+		s.AttributeTypes = append(s.AttributeTypes, at)
+		s.attributeTypes[strings.ToLower(at.OID)] = at
+		for _, name := range at.Names {
+			s.attributeTypes[strings.ToLower(name)] = at
+		}
+	}
+	return s, nil
+}
+
+// ObjectClass returns the object class with the given name or OID, or nil.
+func (s *Schema) ObjectClass(name string) *ObjectClass {
+	return s.objectClasses[strings.ToLower(name)]
+}
+
+// AttributeType returns the attribute type with the given name or OID, or nil.
+func (s *Schema) AttributeType(name string) *AttributeType {
+	return s.attributeTypes[strings.ToLower(name)]
+}
+
+// Equality returns the equality matching rule of the attribute type with the
+// given name or OID, inherited from its superior types if it has none, or ""
+// if the attribute type is not defined or has no equality matching rule.
+func (s *Schema) Equality(name string) string {
+	seen := map[*AttributeType]bool{}
+	for at := s.AttributeType(name); at != nil && !seen[at]; at = s.AttributeType(at.Superior) {
+		if at.Equality != "" {
+			return at.Equality
+		}
+		seen[at] = true
+	}
+	return ""
+}
+
+// ParseObjectClass parses an ObjectClassDescription.
+func ParseObjectClass(value string) (*ObjectClass, error) {
+	p, err := newParser(value)
+	if err != nil {
+		return nil, err
+	}
+	oc := &ObjectClass{Kind: "STRUCTURAL"}
+	oc.OID = p.next()
+	for !p.done() {
+		switch keyword := strings.ToUpper(p.next()); keyword {
+		case "NAME":
+			oc.Names = p.list()
+		case "DESC":
+			oc.Description = p.next()
+		case "OBSOLETE":
+			oc.Obsolete = true
+		case "SUP":
+			oc.Superior = p.list()
+		case "STRUCTURAL", "AUXILIARY", "ABSTRACT":
+			oc.Kind = keyword
+		case "MUST":
+			oc.Must = p.list()
+		case "MAY":
+			oc.May = p.list()
+		default:
+			p.skipExtension(keyword)
+		}
+	}
+	if p.err != nil {
+		return nil, fmt.Errorf("invalid object class description %q: %w", value, p.err)
+	}
+	return oc, nil
+}
+
+// ParseAttributeType parses an AttributeTypeDescription.
+func ParseAttributeType(value string) (*AttributeType, error) {
+	p, err := newParser(value)
+	if err != nil {
+		return nil, err
+	}
+	at := &AttributeType{Usage: "userApplications"}
+	at.OID = p.next()
+	for !p.done() {
+		switch keyword := strings.ToUpper(p.next()); keyword {
+		case "NAME":
+			at.Names = p.list()
+		case "DESC":
+			at.Description = p.next()
+		case "OBSOLETE":
+			at.Obsolete = true
+		case "SUP":
+			at.Superior = p.next()
+		case "EQUALITY":
+			at.Equality = p.next()
+		case "ORDERING":
+			at.Ordering = p.next()
+		case "SUBSTR":
+			at.Substring = p.next()
+		case "SYNTAX":
+			// drop the optional length bound, e.g. "...121.1.15{256}"
+			at.Syntax = strings.SplitN(p.next(), "{", 2)[0]
+		case "SINGLE-VALUE":
+			at.SingleValue = true
+		case "COLLECTIVE":
+			at.Collective = true
+		case "NO-USER-MODIFICATION":
+			at.NoUserModification = true
+		case "USAGE":
+			at.Usage = p.next()
+		default:
+			p.skipExtension(keyword)
+		}
+	}
+	if p.err != nil {
+		return nil, fmt.Errorf("invalid attribute type description %q: %w", value, p.err)
+	}
+	return at, nil
+}
+
+// parser walks the tokens of a description, which have already been stripped
+// of the enclosing parentheses.
+type parser struct {
+	tokens []string
+	pos    int
+	err    error
+}
+
+func newParser(value string) (*parser, error) {
+	tokens, err := tokenize(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema description %q: %w", value, err)
+	}
+	if len(tokens) < 3 || tokens[0] != "(" || tokens[len(tokens)-1] != ")" {
+		return nil, fmt.Errorf("invalid schema description %q: not enclosed in parentheses", value)
+	}
+	return &parser{tokens: tokens[1 : len(tokens)-1]}, nil
+}
+
+func (p *parser) done() bool {
+	return p.err != nil || p.pos >= len(p.tokens)
+}
+
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		p.err = fmt.Errorf("unexpected end of description")
+		return ""
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token
+}
+
+// list reads either a single value or a parenthesised list of values
+// separated by "$" (or by spaces, as some servers do).
+func (p *parser) list() []string {
+	token := p.next()
+	if token != "(" {
+		return []string{token}
+	}
+	values := []string{}
+	for {
+		token := p.next()
+		if p.err != nil {
+			return values
+		}
+		switch token {
+		case ")":
+			return values
+		case "$":
+			continue
+		default:
+			values = append(values, token)
+		}
+	}
+}
+
+// skipExtension skips the value of an X-* extension or of an unknown keyword.
+func (p *parser) skipExtension(keyword string) {
+	if !strings.HasPrefix(keyword, "X-") {
+		return
+	}
+	p.list()
+}
+
+// tokenize splits a description into parentheses, "$" separators, quoted
+// strings (returned without quotes) and bare words.
+func tokenize(value string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(value); {
+		switch c := value[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '$':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'':
+			end := strings.IndexByte(value[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			tokens = append(tokens, value[i+1:i+1+end])
+			i += end + 2
+		default:
+			start := i
+			for i < len(value) && !strings.ContainsRune(" \t\n\r()$'", rune(value[i])) {
+				i++
+			}
+			tokens = append(tokens, value[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+// RequiredAttributes returns the attribute types listed as MUST by the given
+// object classes and all their superclasses; it fails if any of the classes
+// is not defined in the schema.
+func (s *Schema) RequiredAttributes(objectClasses []string) ([]*AttributeType, error) {
+	required := []*AttributeType{}
+	seenClasses := map[*ObjectClass]bool{}
+	seenAttributes := map[string]bool{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		oc := s.ObjectClass(name)
+		if oc == nil {
+			return fmt.Errorf("object class %q is not defined in the server schema", name)
+		}
+		if seenClasses[oc] {
+			return nil
+		}
+		seenClasses[oc] = true
+		for _, attribute := range oc.Must {
+			at := s.AttributeType(attribute)
+			if at == nil {
+				// unknown attribute type: keep it under its own name
+				at = &AttributeType{OID: attribute, Names: []string{attribute}}
+			}
+			if !seenAttributes[strings.ToLower(at.OID)] {
+				seenAttributes[strings.ToLower(at.OID)] = true
+				required = append(required, at)
+			}
+		}
+		for _, superior := range oc.Superior {
+			if err := visit(superior); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range objectClasses {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return required, nil
+}
+
+// StructuralClass returns the structural object class of an entry with the
+// given object classes, i.e. the most specific of their structural classes,
+// or nil if there is none; it fails if any of the classes is not defined in
+// the schema, or if the structural classes do not form a single chain.
+func (s *Schema) StructuralClass(objectClasses []string) (*ObjectClass, error) {
+	var structural *ObjectClass
+	for _, name := range objectClasses {
+		oc := s.ObjectClass(name)
+		if oc == nil {
+			return nil, fmt.Errorf("object class %q is not defined in the server schema", name)
+		}
+		switch {
+		case oc.Kind != "STRUCTURAL" || oc == structural:
+		case structural == nil || s.inherits(oc, structural):
+			structural = oc
+		case !s.inherits(structural, oc):
+			return nil, fmt.Errorf("object classes %q and %q are both structural", structural.Name(), oc.Name())
+		}
+	}
+	return structural, nil
+}
+
+// inherits tells whether an object class derives from the given superclass,
+// directly or not.
+func (s *Schema) inherits(oc, superior *ObjectClass) bool {
+	seen := map[*ObjectClass]bool{}
+	var visit func(oc *ObjectClass) bool
+	visit = func(oc *ObjectClass) bool {
+		if seen[oc] {
+			return false
+		}
+		seen[oc] = true
+		for _, name := range oc.Superior {
+			if sup := s.ObjectClass(name); sup != nil && (sup == superior || visit(sup)) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(oc)
+}
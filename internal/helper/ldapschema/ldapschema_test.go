@@ -0,0 +1,209 @@
+package ldapschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseObjectClass(t *testing.T) {
+	oc, err := ParseObjectClass("( 2.5.6.6 NAME 'person' DESC 'RFC2256: a person' SUP top STRUCTURAL MUST ( sn $ cn ) MAY ( userPassword $ telephoneNumber $ seeAlso $ description ) )")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oc.OID != "2.5.6.6" || oc.Name() != "person" {
+		t.Errorf("invalid OID/name: %q/%q", oc.OID, oc.Name())
+	}
+	if oc.Description != "RFC2256: a person" {
+		t.Errorf("invalid description: %q", oc.Description)
+	}
+	if oc.Kind != "STRUCTURAL" {
+		t.Errorf("invalid kind: %q", oc.Kind)
+	}
+	if !reflect.DeepEqual(oc.Superior, []string{"top"}) {
+		t.Errorf("invalid superior: %v", oc.Superior)
+	}
+	if !reflect.DeepEqual(oc.Must, []string{"sn", "cn"}) {
+		t.Errorf("invalid MUST: %v", oc.Must)
+	}
+	if len(oc.May) != 4 {
+		t.Errorf("invalid MAY: %v", oc.May)
+	}
+}
+
+func TestParseObjectClassAuxiliaryWithExtensions(t *testing.T) {
+	oc, err := ParseObjectClass("( 1.3.6.1.4.1.42.2.27.8.2.1 NAME ( 'pwdPolicy' 'passwordPolicy' ) SUP top AUXILIARY MUST pwdAttribute MAY pwdMinAge X-ORIGIN ( 'draft' 'behera' ) )")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(oc.Names, []string{"pwdPolicy", "passwordPolicy"}) {
+		t.Errorf("invalid names: %v", oc.Names)
+	}
+	if oc.Kind != "AUXILIARY" {
+		t.Errorf("invalid kind: %q", oc.Kind)
+	}
+	if !reflect.DeepEqual(oc.Must, []string{"pwdAttribute"}) || !reflect.DeepEqual(oc.May, []string{"pwdMinAge"}) {
+		t.Errorf("invalid MUST/MAY: %v/%v", oc.Must, oc.May)
+	}
+}
+
+func TestParseAttributeType(t *testing.T) {
+	at, err := ParseAttributeType("( 0.9.2342.19200300.100.1.3 NAME ( 'mail' 'rfc822Mailbox' ) DESC 'RFC1274: RFC822 Mailbox' EQUALITY caseIgnoreIA5Match SUBSTR caseIgnoreIA5SubstringsMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.26{256} )")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if at.Name() != "mail" || at.OID != "0.9.2342.19200300.100.1.3" {
+		t.Errorf("invalid OID/name: %q/%q", at.OID, at.Name())
+	}
+	if at.Equality != "caseIgnoreIA5Match" || at.Substring != "caseIgnoreIA5SubstringsMatch" {
+		t.Errorf("invalid matching rules: %q/%q", at.Equality, at.Substring)
+	}
+	if at.Syntax != "1.3.6.1.4.1.1466.115.121.1.26" {
+		t.Errorf("invalid syntax: %q", at.Syntax)
+	}
+	if at.SingleValue || at.Usage != "userApplications" {
+		t.Errorf("invalid flags: single-value=%v usage=%q", at.SingleValue, at.Usage)
+	}
+}
+
+func TestParseAttributeTypeOperational(t *testing.T) {
+	at, err := ParseAttributeType("( 2.5.18.2 NAME 'modifyTimestamp' EQUALITY generalizedTimeMatch ORDERING generalizedTimeOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.24 SINGLE-VALUE NO-USER-MODIFICATION USAGE directoryOperation )")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !at.SingleValue || !at.NoUserModification || at.Usage != "directoryOperation" {
+		t.Errorf("invalid flags: %+v", at)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"2.5.6.6 NAME 'person'",
+		"( 2.5.6.6 NAME 'person )",
+		"( 2.5.6.6 NAME )",
+	} {
+		if _, err := ParseObjectClass(value); err == nil {
+			t.Errorf("expected an error parsing %q", value)
+		}
+	}
+}
+
+func TestSchemaLookup(t *testing.T) {
+	s, err := New(
+		[]string{"( 2.5.6.6 NAME 'person' SUP top STRUCTURAL MUST ( sn $ cn ) )"},
+		[]string{"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.ObjectClass("PERSON") == nil || s.ObjectClass("2.5.6.6") == nil {
+		t.Error("object class lookup failed")
+	}
+	if s.AttributeType("commonname") == nil || s.AttributeType("CN").Superior != "name" {
+		t.Error("attribute type lookup failed")
+	}
+	if s.ObjectClass("device") != nil {
+		t.Error("unexpected object class")
+	}
+}
+
+func TestRequiredAttributes(t *testing.T) {
+	s, err := New(
+		[]string{
+			"( 2.5.6.0 NAME 'top' ABSTRACT MUST objectClass )",
+			"( 2.5.6.6 NAME 'person' SUP top STRUCTURAL MUST ( sn $ cn ) )",
+			"( 2.16.840.1.113730.3.2.2 NAME 'inetOrgPerson' SUP organizationalPerson STRUCTURAL )",
+			"( 2.5.6.7 NAME 'organizationalPerson' SUP person STRUCTURAL )",
+			"( 1.3.6.1.1.1.2.0 NAME 'posixAccount' SUP top AUXILIARY MUST ( cn $ uid $ uidNumber $ gidNumber $ homeDirectory ) )",
+		},
+		[]string{
+			"( 2.5.4.0 NAME 'objectClass' )",
+			"( 2.5.4.3 NAME ( 'cn' 'commonName' ) )",
+			"( 2.5.4.4 NAME ( 'sn' 'surname' ) )",
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	required, err := s.RequiredAttributes([]string{"inetOrgPerson", "posixAccount"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, at := range required {
+		names = append(names, at.Name())
+	}
+	expected := []string{"sn", "cn", "objectClass", "uid", "uidNumber", "gidNumber", "homeDirectory"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("invalid required attributes: %v, expected %v", names, expected)
+	}
+
+	if _, err := s.RequiredAttributes([]string{"groupOfNames"}); err == nil {
+		t.Error("expected an error for an undefined object class")
+	}
+}
+
+func TestEquality(t *testing.T) {
+	s, err := New(nil, []string{
+		"( 2.5.4.41 NAME 'name' EQUALITY caseIgnoreMatch SUBSTR caseIgnoreSubstringsMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.15{32768} )",
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 2.5.4.35 NAME 'userPassword' SYNTAX 1.3.6.1.4.1.1466.115.121.1.40 )",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"name":         "caseIgnoreMatch",
+		"commonName":   "caseIgnoreMatch",
+		"userPassword": "",
+		"unknown":      "",
+	} {
+		if equality := s.Equality(name); equality != expected {
+			t.Errorf("Equality(%q) = %q, expected %q", name, equality, expected)
+		}
+	}
+}
+
+func TestStructuralClass(t *testing.T) {
+	s, err := New(
+		[]string{
+			"( 2.5.6.0 NAME 'top' ABSTRACT MUST objectClass )",
+			"( 2.5.6.6 NAME 'person' SUP top STRUCTURAL MUST ( sn $ cn ) )",
+			"( 2.16.840.1.113730.3.2.2 NAME 'inetOrgPerson' SUP organizationalPerson STRUCTURAL )",
+			"( 2.5.6.7 NAME 'organizationalPerson' SUP person STRUCTURAL )",
+			"( 2.5.6.9 NAME 'groupOfNames' SUP top STRUCTURAL MUST ( member $ cn ) )",
+			"( 1.3.6.1.1.1.2.0 NAME 'posixAccount' SUP top AUXILIARY MUST ( cn $ uid $ uidNumber $ gidNumber $ homeDirectory ) )",
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for classes, expected := range map[string]string{
+		"top,person,organizationalPerson,inetOrgPerson,posixAccount": "inetOrgPerson",
+		"posixAccount,INETORGPERSON,person":                          "inetOrgPerson",
+		"person":                                                     "person",
+		"top,posixAccount":                                           "",
+	} {
+		oc, err := s.StructuralClass(strings.Split(classes, ","))
+		if err != nil {
+			t.Fatalf("StructuralClass(%s): unexpected error: %v", classes, err)
+		}
+		name := ""
+		if oc != nil {
+			name = oc.Name()
+		}
+		if name != expected {
+			t.Errorf("StructuralClass(%s) = %q, expected %q", classes, name, expected)
+		}
+	}
+
+	for _, classes := range [][]string{{"person", "groupOfNames"}, {"device"}} {
+		if _, err := s.StructuralClass(classes); err == nil {
+			t.Errorf("StructuralClass(%v): expected an error", classes)
+		}
+	}
+}
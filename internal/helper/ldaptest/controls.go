@@ -0,0 +1,157 @@
+package ldaptest
+
+import (
+	"fmt"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// OIDs of the controls the server supports besides those go-ldap defines.
+const (
+	oidAssertion     = "1.3.6.1.1.12"
+	oidPagedResults  = ldap.ControlTypePaging
+	oidSortRequest   = ldap.ControlTypeServerSideSorting
+	oidSortResponse  = ldap.ControlTypeServerSideSortingResult
+	oidManageDsaIT   = ldap.ControlTypeManageDsaIT
+	oidPermissive    = ldap.ControlTypeMicrosoftPermissiveModify
+	oidTreeDelete    = ldap.ControlTypeSubtreeDelete
+	oidAllOperations = "1.3.6.1.4.1.4203.1.5.1"
+	oidNoOp          = "1.3.6.1.4.1.4203.1.10.2"
+)
+
+// resultNoOperation is the result of the updates sent with the No-Op control
+// which would have succeeded.
+const resultNoOperation = 0x410e
+
+// supportedControls are the controls advertised in the root DSE.
+var supportedControls = []string{oidAssertion, oidManageDsaIT, oidNoOp, oidPagedResults, oidPermissive, oidSortRequest, oidTreeDelete}
+
+// supportedControl tells whether a control is supported with an operation.
+func supportedControl(tag ber.Tag, oid string) bool {
+	switch oid {
+	case oidAssertion, oidManageDsaIT:
+		return true
+	case oidPagedResults, oidSortRequest:
+		return tag == ldap.ApplicationSearchRequest
+	case oidPermissive, oidNoOp:
+		return tag == ldap.ApplicationModifyRequest
+	case oidTreeDelete:
+		return tag == ldap.ApplicationDelRequest
+	}
+	return false
+}
+
+// control is a control of a request.
+type control struct {
+	oid      string
+	critical bool
+	value    []byte
+}
+
+// parseControls parses the controls of a request.
+func parseControls(packet *ber.Packet) ([]*control, error) {
+	controls := make([]*control, 0, len(packet.Children))
+	for _, child := range packet.Children {
+		if len(child.Children) == 0 {
+			return nil, fmt.Errorf("malformed control")
+		}
+		c := &control{oid: child.Children[0].Data.String()}
+		for _, field := range child.Children[1:] {
+			switch field.Tag {
+			case ber.TagBoolean:
+				c.critical, _ = field.Value.(bool)
+			case ber.TagOctetString:
+				c.value = field.Data.Bytes()
+			}
+		}
+		controls = append(controls, c)
+	}
+	return controls, nil
+}
+
+// findControl returns the control with the given OID, or nil.
+func findControl(controls []*control, oid string) *control {
+	for _, c := range controls {
+		if c.oid == oid {
+			return c
+		}
+	}
+	return nil
+}
+
+// assertion evaluates the filter of the Assertion control (RFC 4528), if
+// any, against an entry.
+func (s *Server) assertion(controls []*control, e *entry) error {
+	c := findControl(controls, oidAssertion)
+	if c == nil {
+		return nil
+	}
+	filter, err := ber.DecodePacketErr(c.value)
+	if err != nil {
+		return errorf(ldap.LDAPResultProtocolError, "malformed assertion control: %v", err)
+	}
+	if !s.match(filter, s.attributes(e)) {
+		return errorf(ldap.LDAPResultAssertionFailed, "assertion failed")
+	}
+	return nil
+}
+
+// paging returns the page size and cookie of the Simple Paged Results
+// control (RFC 2696); the cookie is the offset of the page.
+func paging(c *control) (int, int, error) {
+	packet, err := ber.DecodePacketErr(c.value)
+	if err != nil || len(packet.Children) != 2 {
+		return 0, 0, errorf(ldap.LDAPResultProtocolError, "malformed paged results control")
+	}
+	size, _ := packet.Children[0].Value.(int64)
+	offset := 0
+	if cookie := packet.Children[1].Data.String(); cookie != "" {
+		if _, err := fmt.Sscanf(cookie, "%d", &offset); err != nil {
+			return 0, 0, errorf(ldap.LDAPResultProtocolError, "invalid paged results cookie %q", cookie)
+		}
+	}
+	return int(size), offset, nil
+}
+
+// pagingResponse returns the Simple Paged Results control of a page, with
+// the offset of the next page as cookie, if any.
+func pagingResponse(next int) ldap.Control {
+	response := ldap.NewControlPaging(0)
+	if next > 0 {
+		response.SetCookie([]byte(fmt.Sprint(next)))
+	}
+	return response
+}
+
+// sortKeys parses the keys of the Server Side Sorting control (RFC 2891).
+func sortKeys(c *control) ([]*ldap.SortKey, error) {
+	packet, err := ber.DecodePacketErr(c.value)
+	if err != nil {
+		return nil, errorf(ldap.LDAPResultProtocolError, "malformed sort control")
+	}
+	keys := make([]*ldap.SortKey, 0, len(packet.Children))
+	for _, child := range packet.Children {
+		if len(child.Children) == 0 {
+			return nil, errorf(ldap.LDAPResultProtocolError, "malformed sort key")
+		}
+		key := &ldap.SortKey{AttributeType: child.Children[0].Data.String()}
+		for _, field := range child.Children[1:] {
+			switch field.Tag {
+			case 0:
+				key.MatchingRule = field.Data.String()
+			case 1:
+				key.Reverse = len(field.Data.Bytes()) > 0 && field.Data.Bytes()[0] != 0
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortResponse returns the successful Server Side Sorting response control.
+func sortResponse() ldap.Control {
+	value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Sort Result")
+	value.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(ldap.LDAPResultSuccess), "Result"))
+	return &ldap.ControlString{ControlType: oidSortResponse, ControlValue: string(value.Bytes())}
+}
@@ -0,0 +1,539 @@
+package ldaptest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Well-known DNs of the server.
+const (
+	rootDSE      = ""
+	subschemaDN  = "cn=Subschema"
+	generalized  = "20060102150405Z"
+	csnTimestamp = "20060102150405.000000Z"
+)
+
+// entry is an entry of the directory along with its operational attributes.
+type entry struct {
+	dn         string
+	key        string // normalized DN
+	parent     string // normalized DN of the parent
+	attributes []*ldap.EntryAttribute
+	sequence   int
+
+	uuid       string
+	csn        string
+	creator    string
+	modifier   string
+	createdAt  time.Time
+	modifiedAt time.Time
+}
+
+// operationalAttributes are the attributes the server maintains, which may
+// not be written by clients.
+var operationalAttributes = []string{
+	"createTimestamp", "creatorsName", "entryCSN", "entryDN", "entryUUID", "hasSubordinates",
+	"memberOf", "modifiersName", "modifyTimestamp", "structuralObjectClass", "subschemaSubentry",
+}
+
+func isOperational(name string) bool {
+	for _, attribute := range operationalAttributes {
+		if strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDN returns the DN with its attribute types and values lower-cased,
+// to compare DNs.
+func normalizeDN(dn string) (string, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return "", err
+	}
+	return normalizeRDNs(parsed.RDNs), nil
+}
+
+func normalizeRDNs(rdns []*ldap.RelativeDN) string {
+	normalized := make([]string, len(rdns))
+	for i, rdn := range rdns {
+		parts := make([]string, len(rdn.Attributes))
+		for j, attribute := range rdn.Attributes {
+			parts[j] = strings.ToLower(attribute.Type) + "=" + ldap.EscapeDN(strings.ToLower(attribute.Value))
+		}
+		sort.Strings(parts)
+		normalized[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(normalized, ",")
+}
+
+// parseDN parses the DN of a request, returning its normalized form and the
+// normalized DN of its parent.
+func parseDN(dn string) (*ldap.DN, string, string, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return nil, "", "", errorf(ldap.LDAPResultInvalidDNSyntax, "invalid DN %q", dn)
+	}
+	return parsed, normalizeRDNs(parsed.RDNs), normalizeRDNs(parsed.RDNs[1:]), nil
+}
+
+// noSuchObject returns the noSuchObject error of a DN, along with the DN of
+// its closest existing ancestor; the caller must hold the lock.
+func (s *Server) noSuchObject(dn string) error {
+	err := &resultError{code: ldap.LDAPResultNoSuchObject, message: fmt.Sprintf("no such object %q", dn)}
+	if parsed, perr := ldap.ParseDN(dn); perr == nil {
+		for i := 1; i < len(parsed.RDNs); i++ {
+			if e, ok := s.entries[normalizeRDNs(parsed.RDNs[i:])]; ok {
+				err.matchedDN = e.dn
+				break
+			}
+		}
+	}
+	return err
+}
+
+// lookup returns the entry with the given DN; the caller must hold the lock.
+func (s *Server) lookup(dn string) (*entry, error) {
+	_, key, _, err := parseDN(dn)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, s.noSuchObject(dn)
+	}
+	return e, nil
+}
+
+// authenticate checks the credentials of a simple bind.
+func (s *Server) authenticate(dn, password string) bool {
+	key, err := normalizeDN(dn)
+	if err != nil {
+		return false
+	}
+	if admin, err := normalizeDN(s.config.BindDN); err == nil && s.config.BindDN != "" && key == admin {
+		return password == s.config.BindPassword
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return false
+	}
+	for _, value := range values(s.attribute(e.attributes, "userPassword")) {
+		if value == password {
+			return true
+		}
+	}
+	return false
+}
+
+// touch updates the operational attributes of an entry written by a client;
+// the caller must hold the lock.
+func (s *Server) touch(e *entry, bindDN string) {
+	s.sequence++
+	now := time.Now().UTC()
+	e.csn = fmt.Sprintf("%s#%06x#000#000000", now.Format(csnTimestamp), s.sequence)
+	e.modifier = bindDN
+	e.modifiedAt = now
+	if e.sequence == 0 {
+		e.sequence = s.sequence
+		e.uuid = newUUID()
+		e.creator = bindDN
+		e.createdAt = now
+	}
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// addRequest handles an add request.
+func (s *Server) addRequest(op *ber.Packet, bindDN string) error {
+	if len(op.Children) != 2 {
+		return errorf(ldap.LDAPResultProtocolError, "malformed add request")
+	}
+	attributes, err := parseAttributes(op.Children[1])
+	if err != nil {
+		return err
+	}
+	return s.add(op.Children[0].Data.String(), attributes, bindDN)
+}
+
+func (s *Server) add(dn string, attributes []*ldap.EntryAttribute, bindDN string) error {
+	parsed, key, parent, err := parseDN(dn)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[key]; ok {
+		return errorf(ldap.LDAPResultEntryAlreadyExists, "entry %q already exists", dn)
+	}
+	if _, ok := s.entries[parent]; !ok && key != s.suffix {
+		return s.noSuchObject(dn)
+	}
+
+	e := &entry{dn: dn, key: key, parent: parent}
+	for _, a := range attributes {
+		if isOperational(a.Name) {
+			return errorf(ldap.LDAPResultConstraintViolation, "%s: no user modification allowed", a.Name)
+		}
+		if existing := s.attribute(e.attributes, a.Name); existing != nil {
+			existing.Values = append(existing.Values, a.Values...)
+		} else {
+			e.attributes = append(e.attributes, ldap.NewEntryAttribute(a.Name, a.Values))
+		}
+	}
+	// like OpenLDAP, add the values of the RDN which are missing
+	for _, rdn := range parsed.RDNs[0].Attributes {
+		if s.hasValue(e.attributes, rdn.Type, rdn.Value) {
+			continue
+		}
+		if existing := s.attribute(e.attributes, rdn.Type); existing != nil {
+			existing.Values = append(existing.Values, rdn.Value)
+		} else {
+			e.attributes = append(e.attributes, ldap.NewEntryAttribute(rdn.Type, []string{rdn.Value}))
+		}
+	}
+	if err := s.check(e); err != nil {
+		return err
+	}
+	s.touch(e, bindDN)
+	s.entries[key] = e
+	return nil
+}
+
+// check checks the attributes of an entry: it must have object classes, no
+// duplicate values, and a single value for the single-valued attribute types.
+func (s *Server) check(e *entry) error {
+	if len(values(s.attribute(e.attributes, "objectClass"))) == 0 {
+		return errorf(ldap.LDAPResultObjectClassViolation, "no objectClass attribute")
+	}
+	for _, a := range e.attributes {
+		for i, value := range a.Values {
+			for _, other := range a.Values[:i] {
+				if s.equal(a.Name, value, other) {
+					return errorf(ldap.LDAPResultAttributeOrValueExists, "%s: value #%d provided more than once", a.Name, i)
+				}
+			}
+		}
+		if at := s.schema.AttributeType(a.Name); at != nil && at.SingleValue && len(a.Values) > 1 {
+			return errorf(ldap.LDAPResultConstraintViolation, "attribute %q cannot have multiple values", a.Name)
+		}
+	}
+	return nil
+}
+
+// deleteRequest handles a delete request.
+func (s *Server) deleteRequest(op *ber.Packet, controls []*control) error {
+	dn := op.Data.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.lookup(dn)
+	if err != nil {
+		return err
+	}
+	if err := s.assertion(controls, e); err != nil {
+		return err
+	}
+	subtree := s.subtree(e)
+	if len(subtree) > 1 && findControl(controls, oidTreeDelete) == nil {
+		return errorf(ldap.LDAPResultNotAllowedOnNonLeaf, "subordinate objects must be deleted first")
+	}
+	for _, child := range subtree {
+		delete(s.entries, child.key)
+	}
+	return nil
+}
+
+// subtree returns an entry along with all its subordinates; the caller must
+// hold the lock.
+func (s *Server) subtree(e *entry) []*entry {
+	subtree := []*entry{e}
+	for _, other := range s.entries {
+		if strings.HasSuffix(other.key, ","+e.key) {
+			subtree = append(subtree, other)
+		}
+	}
+	return subtree
+}
+
+// modifyRequest handles a modify request; the changes are applied all at
+// once, or not at all.
+func (s *Server) modifyRequest(op *ber.Packet, controls []*control, bindDN string) error {
+	if len(op.Children) != 2 {
+		return errorf(ldap.LDAPResultProtocolError, "malformed modify request")
+	}
+	dn := op.Children[0].Data.String()
+	permissive := findControl(controls, oidPermissive) != nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.lookup(dn)
+	if err != nil {
+		return err
+	}
+	if err := s.assertion(controls, e); err != nil {
+		return err
+	}
+
+	attributes := copyAttributes(e.attributes)
+	for _, change := range op.Children[1].Children {
+		if len(change.Children) != 2 {
+			return errorf(ldap.LDAPResultProtocolError, "malformed change")
+		}
+		operation, _ := change.Children[0].Value.(int64)
+		modification, err := parseAttribute(change.Children[1])
+		if err != nil {
+			return err
+		}
+		if isOperational(modification.Name) {
+			return errorf(ldap.LDAPResultConstraintViolation, "%s: no user modification allowed", modification.Name)
+		}
+		if attributes, err = s.apply(attributes, operation, modification, permissive); err != nil {
+			return err
+		}
+	}
+
+	parsed, _ := ldap.ParseDN(e.dn)
+	for _, rdn := range parsed.RDNs[0].Attributes {
+		if !s.hasValue(attributes, rdn.Type, rdn.Value) {
+			return errorf(ldap.LDAPResultNotAllowedOnRDN, "cannot delete the value of naming attribute %q", rdn.Type)
+		}
+	}
+	if err := s.check(&entry{attributes: attributes}); err != nil {
+		return err
+	}
+	before, err := s.schema.StructuralClass(values(s.attribute(e.attributes, "objectClass")))
+	if err == nil && before != nil {
+		after, err := s.schema.StructuralClass(values(s.attribute(attributes, "objectClass")))
+		if err == nil && after != before {
+			return errorf(ldap.LDAPResultObjectClassModsProhibited, "structural object class modification from %q to %q not allowed", before.Name(), after.Name())
+		}
+	}
+	if findControl(controls, oidNoOp) != nil {
+		return errorf(resultNoOperation, "no operation")
+	}
+
+	e.attributes = attributes
+	s.touch(e, bindDN)
+	return nil
+}
+
+// apply applies a change to the attributes of an entry.
+func (s *Server) apply(attributes []*ldap.EntryAttribute, operation int64, modification *ldap.EntryAttribute, permissive bool) ([]*ldap.EntryAttribute, error) {
+	name := modification.Name
+	existing := s.attribute(attributes, name)
+
+	switch operation {
+	case ldap.AddAttribute:
+		if existing == nil {
+			existing = &ldap.EntryAttribute{Name: name}
+			attributes = append(attributes, existing)
+		}
+		for _, value := range modification.Values {
+			if s.hasValue(attributes, name, value) {
+				if permissive {
+					continue
+				}
+				return nil, errorf(ldap.LDAPResultAttributeOrValueExists, "%s: value %q already exists", name, value)
+			}
+			existing.Values = append(existing.Values, value)
+		}
+
+	case ldap.DeleteAttribute:
+		if existing == nil {
+			if permissive {
+				return attributes, nil
+			}
+			return nil, errorf(ldap.LDAPResultNoSuchAttribute, "%s: no such attribute", name)
+		}
+		if len(modification.Values) == 0 {
+			existing.Values = nil
+			break
+		}
+		for _, value := range modification.Values {
+			i := s.indexValue(existing, value)
+			if i < 0 {
+				if permissive {
+					continue
+				}
+				return nil, errorf(ldap.LDAPResultNoSuchAttribute, "%s: no such value %q", name, value)
+			}
+			existing.Values = append(existing.Values[:i], existing.Values[i+1:]...)
+		}
+
+	case ldap.ReplaceAttribute:
+		if existing == nil {
+			existing = &ldap.EntryAttribute{Name: name}
+			attributes = append(attributes, existing)
+		}
+		existing.Values = append([]string(nil), modification.Values...)
+
+	default:
+		return nil, errorf(ldap.LDAPResultUnwillingToPerform, "unsupported modification %d", operation)
+	}
+
+	kept := attributes[:0]
+	for _, a := range attributes {
+		if len(a.Values) > 0 {
+			kept = append(kept, a)
+		}
+	}
+	return kept, nil
+}
+
+// modifyDNRequest handles a modify DN request, moving the subordinates of
+// the entry along with it.
+func (s *Server) modifyDNRequest(op *ber.Packet, controls []*control, bindDN string) error {
+	if len(op.Children) < 3 {
+		return errorf(ldap.LDAPResultProtocolError, "malformed modify DN request")
+	}
+	dn := op.Children[0].Data.String()
+	newRDN, err := ldap.ParseDN(op.Children[1].Data.String())
+	if err != nil || len(newRDN.RDNs) != 1 {
+		return errorf(ldap.LDAPResultInvalidDNSyntax, "invalid RDN %q", op.Children[1].Data.String())
+	}
+	deleteOldRDN, _ := op.Children[2].Value.(bool)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.lookup(dn)
+	if err != nil {
+		return err
+	}
+	if err := s.assertion(controls, e); err != nil {
+		return err
+	}
+	old, _ := ldap.ParseDN(e.dn)
+	superior := &ldap.DN{RDNs: old.RDNs[1:]}
+	if len(op.Children) > 3 {
+		newSuperior := op.Children[3].Data.String()
+		if superior, err = ldap.ParseDN(newSuperior); err != nil {
+			return errorf(ldap.LDAPResultInvalidDNSyntax, "invalid new superior %q", newSuperior)
+		}
+		if _, ok := s.entries[normalizeRDNs(superior.RDNs)]; !ok {
+			return s.noSuchObject(newSuperior)
+		}
+	}
+
+	renamed := &ldap.DN{RDNs: append([]*ldap.RelativeDN{newRDN.RDNs[0]}, superior.RDNs...)}
+	key := normalizeRDNs(renamed.RDNs)
+	if _, ok := s.entries[key]; ok && key != e.key {
+		return errorf(ldap.LDAPResultEntryAlreadyExists, "entry %q already exists", renamed.String())
+	}
+	if strings.HasSuffix(key, ","+e.key) {
+		return errorf(ldap.LDAPResultUnwillingToPerform, "cannot move an entry below itself")
+	}
+
+	attributes := copyAttributes(e.attributes)
+	if deleteOldRDN {
+		for _, old := range old.RDNs[0].Attributes {
+			if a := s.attribute(attributes, old.Type); a != nil {
+				if i := s.indexValue(a, old.Value); i >= 0 {
+					a.Values = append(a.Values[:i], a.Values[i+1:]...)
+				}
+			}
+		}
+	}
+	for _, rdn := range newRDN.RDNs[0].Attributes {
+		if s.hasValue(attributes, rdn.Type, rdn.Value) {
+			continue
+		}
+		if a := s.attribute(attributes, rdn.Type); a != nil {
+			a.Values = append(a.Values, rdn.Value)
+		} else {
+			attributes = append(attributes, ldap.NewEntryAttribute(rdn.Type, []string{rdn.Value}))
+		}
+	}
+	if err := s.check(&entry{attributes: attributes}); err != nil {
+		return err
+	}
+
+	for _, child := range s.subtree(e) {
+		delete(s.entries, child.key)
+		childDN, _ := ldap.ParseDN(child.dn)
+		moved := &ldap.DN{RDNs: append(childDN.RDNs[:len(childDN.RDNs)-len(old.RDNs)], renamed.RDNs...)}
+		child.dn = moved.String()
+		child.key = normalizeRDNs(moved.RDNs)
+		child.parent = normalizeRDNs(moved.RDNs[1:])
+		s.entries[child.key] = child
+	}
+	e.attributes = attributes
+	s.touch(e, bindDN)
+	return nil
+}
+
+// compareRequest handles a compare request.
+func (s *Server) compareRequest(op *ber.Packet, controls []*control) error {
+	if len(op.Children) != 2 || len(op.Children[1].Children) != 2 {
+		return errorf(ldap.LDAPResultProtocolError, "malformed compare request")
+	}
+	name := op.Children[1].Children[0].Data.String()
+	value := op.Children[1].Children[1].Data.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.lookup(op.Children[0].Data.String())
+	if err != nil {
+		return err
+	}
+	if err := s.assertion(controls, e); err != nil {
+		return err
+	}
+	attributes := s.attributes(e)
+	if s.attribute(attributes, name) == nil {
+		return errorf(ldap.LDAPResultNoSuchAttribute, "%s: no such attribute", name)
+	}
+	if s.hasValue(attributes, name, value) {
+		return &resultError{code: ldap.LDAPResultCompareTrue}
+	}
+	return &resultError{code: ldap.LDAPResultCompareFalse}
+}
+
+// parseAttributes parses a list of attributes with their values.
+func parseAttributes(packet *ber.Packet) ([]*ldap.EntryAttribute, error) {
+	attributes := make([]*ldap.EntryAttribute, 0, len(packet.Children))
+	for _, child := range packet.Children {
+		a, err := parseAttribute(child)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, a)
+	}
+	return attributes, nil
+}
+
+func parseAttribute(packet *ber.Packet) (*ldap.EntryAttribute, error) {
+	if len(packet.Children) != 2 {
+		return nil, errorf(ldap.LDAPResultProtocolError, "malformed attribute")
+	}
+	a := &ldap.EntryAttribute{Name: packet.Children[0].Data.String()}
+	for _, value := range packet.Children[1].Children {
+		a.Values = append(a.Values, value.Data.String())
+	}
+	return a, nil
+}
+
+func copyAttributes(attributes []*ldap.EntryAttribute) []*ldap.EntryAttribute {
+	copied := make([]*ldap.EntryAttribute, len(attributes))
+	for i, a := range attributes {
+		copied[i] = ldap.NewEntryAttribute(a.Name, append([]string(nil), a.Values...))
+	}
+	return copied
+}
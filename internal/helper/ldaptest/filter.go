@@ -0,0 +1,298 @@
+package ldaptest
+
+import (
+	"strconv"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Matching rules of the extensible match filters the server supports, besides
+// the equality of the attribute type.
+const (
+	ruleBitAnd  = "1.2.840.113556.1.4.803"
+	ruleBitOr   = "1.2.840.113556.1.4.804"
+	ruleInChain = "1.2.840.113556.1.4.1941"
+)
+
+// attributeKey identifies an attribute type: its OID if it is defined in the
+// schema, its lower-cased name otherwise; options such as ";binary" are
+// ignored.
+func (s *Server) attributeKey(name string) string {
+	if i := strings.IndexByte(name, ';'); i >= 0 {
+		name = name[:i]
+	}
+	if at := s.schema.AttributeType(name); at != nil {
+		return strings.ToLower(at.OID)
+	}
+	return strings.ToLower(name)
+}
+
+// attribute returns the attribute with the given name, or one of its other
+// names, or nil.
+func (s *Server) attribute(attributes []*ldap.EntryAttribute, name string) *ldap.EntryAttribute {
+	key := s.attributeKey(name)
+	for _, a := range attributes {
+		if s.attributeKey(a.Name) == key {
+			return a
+		}
+	}
+	return nil
+}
+
+// values returns the values of an attribute, which may be nil.
+func values(a *ldap.EntryAttribute) []string {
+	if a == nil {
+		return nil
+	}
+	return a.Values
+}
+
+// equal tells whether two values of an attribute are equal according to the
+// equality matching rule of its type; values of attribute types which are
+// not defined in the schema are compared ignoring case.
+func (s *Server) equal(name, a, b string) bool {
+	rule := strings.ToLower(s.schema.Equality(strings.SplitN(name, ";", 2)[0]))
+	switch {
+	case rule == "distinguishednamematch" || rule == "uniquemembermatch":
+		x, errX := normalizeDN(a)
+		y, errY := normalizeDN(b)
+		if errX == nil && errY == nil {
+			return x == y
+		}
+		return strings.EqualFold(a, b)
+	case s.exact(name):
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+// exact tells whether the values of an attribute are compared exactly, rather
+// than ignoring case.
+func (s *Server) exact(name string) bool {
+	rule := strings.ToLower(s.schema.Equality(strings.SplitN(name, ";", 2)[0]))
+	for _, prefix := range []string{"caseexact", "octetstring", "integer", "bitstring", "boolean", "generalizedtime", "uuid", "csn"} {
+		if strings.HasPrefix(rule, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexValue returns the index of a value of an attribute, or -1.
+func (s *Server) indexValue(a *ldap.EntryAttribute, value string) int {
+	for i, v := range values(a) {
+		if s.equal(a.Name, v, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasValue tells whether an attribute has the given value.
+func (s *Server) hasValue(attributes []*ldap.EntryAttribute, name, value string) bool {
+	return s.indexValue(s.attribute(attributes, name), value) >= 0
+}
+
+// match evaluates a filter (RFC 4511, section 4.5.1.7) against the
+// attributes of an entry; undefined filters, such as extensible matches with
+// unsupported rules, do not match. The caller must hold the lock.
+func (s *Server) match(filter *ber.Packet, attributes []*ldap.EntryAttribute) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !s.match(child, attributes) {
+				return false
+			}
+		}
+		return true
+
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if s.match(child, attributes) {
+				return true
+			}
+		}
+		return false
+
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !s.match(filter.Children[0], attributes)
+
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, value := filter.Children[0].Data.String(), filter.Children[1].Data.String()
+		if s.attributeKey(name) == s.attributeKey("objectClass") {
+			return s.hasObjectClass(attributes, value)
+		}
+		return s.hasValue(attributes, name, value)
+
+	case ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, value := filter.Children[0].Data.String(), filter.Children[1].Data.String()
+		for _, v := range values(s.attribute(attributes, name)) {
+			c := s.compare(name, v, value)
+			if c == 0 || (c > 0) == (filter.Tag == ldap.FilterGreaterOrEqual) {
+				return true
+			}
+		}
+		return false
+
+	case ldap.FilterPresent:
+		name := filter.Data.String()
+		return strings.EqualFold(name, "objectClass") || s.attribute(attributes, name) != nil
+
+	case ldap.FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name := filter.Children[0].Data.String()
+		for _, v := range values(s.attribute(attributes, name)) {
+			if s.substrings(name, v, filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+
+	case ldap.FilterExtensibleMatch:
+		var rule, name, value string
+		for _, child := range filter.Children {
+			switch child.Tag {
+			case 1:
+				rule = child.Data.String()
+			case 2:
+				name = child.Data.String()
+			case 3:
+				value = child.Data.String()
+			}
+		}
+		return s.extensibleMatch(rule, name, value, attributes)
+	}
+	return false
+}
+
+// hasObjectClass tells whether an entry has the given object class, or one
+// of its subclasses.
+func (s *Server) hasObjectClass(attributes []*ldap.EntryAttribute, name string) bool {
+	for _, value := range values(s.attribute(attributes, "objectClass")) {
+		seen := map[string]bool{}
+		for queue := []string{value}; len(queue) > 0; queue = queue[1:] {
+			if strings.EqualFold(queue[0], name) {
+				return true
+			}
+			oc := s.schema.ObjectClass(queue[0])
+			if oc == nil || seen[oc.OID] {
+				continue
+			}
+			seen[oc.OID] = true
+			if s.schema.ObjectClass(name) == oc {
+				return true
+			}
+			queue = append(queue, oc.Superior...)
+		}
+	}
+	return false
+}
+
+// compare orders two values of an attribute, numerically if both are
+// integers.
+func (s *Server) compare(name, a, b string) int {
+	x, errX := strconv.ParseInt(a, 10, 64)
+	y, errY := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errX == nil && errY == nil && x < y:
+		return -1
+	case errX == nil && errY == nil:
+		if x > y {
+			return 1
+		}
+		return 0
+	case !s.exact(name):
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// substrings tells whether a value matches the initial, any and final
+// substrings of a filter.
+func (s *Server) substrings(name, value string, substrings []*ber.Packet) bool {
+	if !s.exact(name) {
+		value = strings.ToLower(value)
+	}
+	for _, substring := range substrings {
+		part := substring.Data.String()
+		if !s.exact(name) {
+			part = strings.ToLower(part)
+		}
+		switch substring.Tag {
+		case 0: // initial
+			if !strings.HasPrefix(value, part) {
+				return false
+			}
+			value = value[len(part):]
+		case 1: // any
+			i := strings.Index(value, part)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(part):]
+		case 2: // final
+			if !strings.HasSuffix(value, part) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// extensibleMatch evaluates an extensible match with the equality matching
+// rule of the attribute type, the bitwise rules of Active Directory, or its
+// LDAP_MATCHING_RULE_IN_CHAIN rule, which follows the DN values of the
+// attribute through the entries they refer to.
+func (s *Server) extensibleMatch(rule, name, value string, attributes []*ldap.EntryAttribute) bool {
+	switch rule {
+	case "":
+		return s.hasValue(attributes, name, value)
+
+	case ruleBitAnd, ruleBitOr:
+		mask, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		for _, v := range values(s.attribute(attributes, name)) {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err == nil && ((rule == ruleBitAnd && n&mask == mask) || (rule == ruleBitOr && n&mask != 0)) {
+				return true
+			}
+		}
+		return false
+
+	case ruleInChain:
+		target, err := normalizeDN(value)
+		if err != nil {
+			return false
+		}
+		seen := map[string]bool{}
+		queue := append([]string(nil), values(s.attribute(attributes, name))...)
+		for len(queue) > 0 {
+			key, err := normalizeDN(queue[0])
+			queue = queue[1:]
+			if err != nil || seen[key] {
+				continue
+			}
+			if key == target {
+				return true
+			}
+			seen[key] = true
+			if e, ok := s.entries[key]; ok {
+				queue = append(queue, values(s.attribute(s.attributes(e), name))...)
+			}
+		}
+		return false
+	}
+	return false
+}
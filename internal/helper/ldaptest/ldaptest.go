@@ -0,0 +1,384 @@
+// Package ldaptest runs an in-memory LDAP server seeded with LDIF entries and
+// a schema, so that the provider can be tested with plain `go test`, without
+// an external directory.
+//
+// The server implements the operations the provider uses: simple bind,
+// search, add, modify, modify DN, delete, compare and the "Who am I?"
+// extended operation, along with the Simple Paged Results, Server Side
+// Sorting, Assertion, ManageDsaIT, Permissive Modify and Tree Delete
+// controls. It is not a faithful directory: the schema is published and used
+// to match values and to keep the structural object class of entries, but
+// entries are not otherwise checked against it; anonymous clients may read
+// everything and bound ones may write everything; aliases and referrals are
+// plain entries.
+package ldaptest
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldapschema"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldif"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Config configures a Server.
+type Config struct {
+	// Suffix is the naming context of the directory, e.g.
+	// "dc=example,dc=com"; the suffix entry itself must be seeded.
+	Suffix string
+	// BindDN and BindPassword are the credentials of the administrator,
+	// which needs not be an entry of the directory. Entries may also bind
+	// with their clear text userPassword.
+	BindDN       string
+	BindPassword string
+	// ObjectClasses and AttributeTypes are the object class and attribute
+	// type descriptions of the schema; DefaultObjectClasses and
+	// DefaultAttributeTypes if both are empty.
+	ObjectClasses  []string
+	AttributeTypes []string
+	// LDIF holds the entries the directory is seeded with, parents first.
+	LDIF string
+}
+
+// Server is an in-memory LDAP server listening on a local port.
+type Server struct {
+	config   Config
+	schema   *ldapschema.Schema
+	suffix   string
+	listener net.Listener
+
+	mu       sync.Mutex
+	entries  map[string]*entry
+	sequence int
+	conns    map[net.Conn]bool
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewServer seeds a directory and starts serving it on a random port of the
+// loopback interface; the server must be closed once done with.
+func NewServer(config Config) (*Server, error) {
+	if config.Suffix == "" {
+		return nil, fmt.Errorf("the suffix of the directory is required")
+	}
+	suffix, err := normalizeDN(config.Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suffix %q: %w", config.Suffix, err)
+	}
+	if len(config.ObjectClasses) == 0 && len(config.AttributeTypes) == 0 {
+		config.ObjectClasses = DefaultObjectClasses
+		config.AttributeTypes = DefaultAttributeTypes
+	}
+	schema, err := ldapschema.New(config.ObjectClasses, config.AttributeTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	s := &Server{
+		config:  config,
+		schema:  schema,
+		suffix:  suffix,
+		entries: map[string]*entry{},
+		conns:   map[net.Conn]bool{},
+	}
+	if err := s.Seed(config.LDIF); err != nil {
+		return nil, err
+	}
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error listening: %w", err)
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Seed adds the entries of LDIF content to the directory, parents first.
+func (s *Server) Seed(content string) error {
+	entries, err := ldif.Parse(content)
+	if err != nil {
+		return fmt.Errorf("invalid LDIF: %w", err)
+	}
+	for _, e := range entries {
+		attributes := make([]*ldap.EntryAttribute, len(e.Attributes))
+		for i, attribute := range e.Attributes {
+			attributes[i] = ldap.NewEntryAttribute(attribute.Name, attribute.Values)
+		}
+		if err := s.add(e.DN, attributes, s.config.BindDN); err != nil {
+			return fmt.Errorf("error seeding %q: %w", e.DN, err)
+		}
+	}
+	return nil
+}
+
+// Entry returns the user attributes of the entry with the given DN, or nil
+// if there is no such entry.
+func (s *Server) Entry(dn string) *ldap.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, err := normalizeDN(dn)
+	if err != nil {
+		return nil
+	}
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	return &ldap.Entry{DN: e.dn, Attributes: copyAttributes(e.attributes)}
+}
+
+// Host returns the IP address the server listens on.
+func (s *Server) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server listens on.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// URL returns the ldap:// URL of the server.
+func (s *Server) URL() string {
+	return "ldap://" + net.JoinHostPort(s.Host(), strconv.Itoa(s.Port()))
+}
+
+// Close stops the server, closing the connections of its clients.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			(&session{server: s, conn: conn}).serve()
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// session is the connection of a client, along with the DN it is bound as.
+type session struct {
+	server *Server
+	conn   net.Conn
+	bindDN string
+}
+
+// serve handles the requests of the client one at a time, until it unbinds
+// or the connection is closed.
+func (c *session) serve() {
+	for {
+		packet, err := ber.ReadPacket(c.conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		id, ok := packet.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		var controls []*control
+		if len(packet.Children) > 2 {
+			if controls, err = parseControls(packet.Children[2]); err != nil {
+				return
+			}
+		}
+		if !c.handle(id, packet.Children[1], controls) {
+			return
+		}
+	}
+}
+
+// handle handles a request, telling whether to keep the connection open.
+func (c *session) handle(id int64, op *ber.Packet, controls []*control) bool {
+	s := c.server
+	if op.ClassType != ber.ClassApplication {
+		return false
+	}
+
+	switch op.Tag {
+	case ldap.ApplicationUnbindRequest:
+		return false
+	case ldap.ApplicationAbandonRequest:
+		return true
+	case ldap.ApplicationBindRequest:
+		c.write(id, result(ldap.ApplicationBindResponse, c.bind(op)))
+		return true
+	case ldap.ApplicationExtendedRequest:
+		c.write(id, c.extended(op))
+		return true
+	}
+
+	var responseTag ber.Tag
+	switch op.Tag {
+	case ldap.ApplicationSearchRequest:
+		responseTag = ldap.ApplicationSearchResultDone
+	case ldap.ApplicationAddRequest:
+		responseTag = ldap.ApplicationAddResponse
+	case ldap.ApplicationModifyRequest:
+		responseTag = ldap.ApplicationModifyResponse
+	case ldap.ApplicationDelRequest:
+		responseTag = ldap.ApplicationDelResponse
+	case ldap.ApplicationModifyDNRequest:
+		responseTag = ldap.ApplicationModifyDNResponse
+	case ldap.ApplicationCompareRequest:
+		responseTag = ldap.ApplicationCompareResponse
+	default:
+		c.write(id, result(ldap.ApplicationExtendedResponse, errorf(ldap.LDAPResultProtocolError, "unsupported operation %d", op.Tag)))
+		return false
+	}
+
+	for _, control := range controls {
+		if control.critical && !supportedControl(op.Tag, control.oid) {
+			c.write(id, result(responseTag, errorf(ldap.LDAPResultUnavailableCriticalExtension, "unsupported critical control %s", control.oid)))
+			return true
+		}
+	}
+	if op.Tag != ldap.ApplicationSearchRequest && op.Tag != ldap.ApplicationCompareRequest && c.bindDN == "" {
+		c.write(id, result(responseTag, errorf(ldap.LDAPResultInsufficientAccessRights, "anonymous clients may not write")))
+		return true
+	}
+
+	var err error
+	switch op.Tag {
+	case ldap.ApplicationSearchRequest:
+		c.search(id, op, controls)
+		return true
+	case ldap.ApplicationAddRequest:
+		err = s.addRequest(op, c.bindDN)
+	case ldap.ApplicationModifyRequest:
+		err = s.modifyRequest(op, controls, c.bindDN)
+	case ldap.ApplicationDelRequest:
+		err = s.deleteRequest(op, controls)
+	case ldap.ApplicationModifyDNRequest:
+		err = s.modifyDNRequest(op, controls, c.bindDN)
+	case ldap.ApplicationCompareRequest:
+		err = s.compareRequest(op, controls)
+	}
+	c.write(id, result(responseTag, err))
+	return true
+}
+
+// bind handles a simple bind request.
+func (c *session) bind(op *ber.Packet) error {
+	if len(op.Children) < 3 {
+		return errorf(ldap.LDAPResultProtocolError, "malformed bind request")
+	}
+	name := op.Children[1].Data.String()
+	authentication := op.Children[2]
+	if authentication.ClassType != ber.ClassContext || authentication.Tag != 0 {
+		return errorf(ldap.LDAPResultAuthMethodNotSupported, "only simple binds are supported")
+	}
+	password := authentication.Data.String()
+
+	c.bindDN = ""
+	switch {
+	case name == "" && password == "":
+		return nil
+	case password == "":
+		return errorf(ldap.LDAPResultUnwillingToPerform, "unauthenticated binds are not allowed")
+	case !c.server.authenticate(name, password):
+		return errorf(ldap.LDAPResultInvalidCredentials, "invalid credentials")
+	}
+	c.bindDN = name
+	return nil
+}
+
+// extended handles an extended request; only "Who am I?" is supported.
+func (c *session) extended(op *ber.Packet) *ber.Packet {
+	name := ""
+	if len(op.Children) > 0 {
+		name = op.Children[0].Data.String()
+	}
+	if name != ldap.ControlTypeWhoAmI {
+		return result(ldap.ApplicationExtendedResponse, errorf(ldap.LDAPResultProtocolError, "unsupported extended operation %s", name))
+	}
+	response := result(ldap.ApplicationExtendedResponse, nil)
+	authzID := ""
+	if c.bindDN != "" {
+		authzID = "dn:" + c.bindDN
+	}
+	response.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 11, authzID, "Response Value"))
+	return response
+}
+
+// write sends a response to the client, along with controls.
+func (c *session) write(id int64, op *ber.Packet, controls ...ldap.Control) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	packet.AppendChild(op)
+	if len(controls) > 0 {
+		encoded := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		for _, control := range controls {
+			encoded.AppendChild(control.Encode())
+		}
+		packet.AppendChild(encoded)
+	}
+	c.conn.Write(packet.Bytes())
+}
+
+// resultError is an LDAP result other than success.
+type resultError struct {
+	code      uint16
+	matchedDN string
+	message   string
+}
+
+func (e *resultError) Error() string {
+	return fmt.Sprintf("%s: %s", ldap.LDAPResultCodeMap[e.code], e.message)
+}
+
+func errorf(code uint16, format string, args ...interface{}) error {
+	return &resultError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// result encodes the result of an operation, successful if err is nil.
+func result(tag ber.Tag, err error) *ber.Packet {
+	var code uint16
+	var matchedDN, message string
+	if err != nil {
+		code, message = ldap.LDAPResultOther, err.Error()
+		if e, ok := err.(*resultError); ok {
+			code, matchedDN, message = e.code, e.matchedDN, e.message
+		}
+	}
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, matchedDN, "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return packet
+}
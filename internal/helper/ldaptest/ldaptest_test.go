@@ -0,0 +1,327 @@
+package ldaptest
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const testLDIF = `dn: dc=example,dc=com
+objectClass: top
+objectClass: dcObject
+objectClass: organization
+dc: example
+o: Example Inc.
+
+dn: ou=users,dc=example,dc=com
+objectClass: organizationalUnit
+ou: users
+
+dn: uid=alice,ou=users,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice
+sn: Smith
+mail: alice@example.com
+userPassword: secret
+
+dn: uid=bob,ou=users,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: bob
+cn: Bob
+sn: Jones
+
+dn: cn=staff,dc=example,dc=com
+objectClass: groupOfNames
+cn: staff
+member: uid=alice,ou=users,dc=example,dc=com
+
+dn: cn=all,dc=example,dc=com
+objectClass: groupOfNames
+cn: all
+member: cn=staff,dc=example,dc=com
+`
+
+func testServer(t *testing.T) (*Server, *ldap.Conn) {
+	t.Helper()
+	s, err := NewServer(Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF:         testLDIF,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error starting the server: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	conn, err := ldap.DialURL(s.URL())
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := conn.Bind("cn=admin,dc=example,dc=com", "admin"); err != nil {
+		t.Fatalf("unexpected error binding: %v", err)
+	}
+	return s, conn
+}
+
+func search(t *testing.T, conn *ldap.Conn, base string, scope int, filter string, attributes ...string) []*ldap.Entry {
+	t.Helper()
+	sr, err := conn.Search(ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases, 0, 0, false, filter, attributes, nil))
+	if err != nil {
+		t.Fatalf("unexpected error searching %q: %v", filter, err)
+	}
+	return sr.Entries
+}
+
+func dns(entries []*ldap.Entry) []string {
+	dns := make([]string, len(entries))
+	for i, entry := range entries {
+		dns[i] = entry.DN
+	}
+	return dns
+}
+
+func assertResultCode(t *testing.T, err error, code uint16) {
+	t.Helper()
+	var ldapErr *ldap.Error
+	if !errors.As(err, &ldapErr) || ldapErr.ResultCode != code {
+		t.Errorf("expected %s, got %v", ldap.LDAPResultCodeMap[code], err)
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	if _, err := NewServer(Config{}); err == nil {
+		t.Error("expected an error without a suffix")
+	}
+	if _, err := NewServer(Config{Suffix: "dc=example,dc=com", LDIF: "dn: ou=orphan,dc=example,dc=com\nobjectClass: organizationalUnit\nou: orphan\n"}); err == nil {
+		t.Error("expected an error seeding an entry without parent")
+	}
+}
+
+func TestBind(t *testing.T) {
+	s, _ := testServer(t)
+	conn, err := ldap.DialURL(s.URL())
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind("uid=alice,ou=users,dc=example,dc=com", "secret"); err != nil {
+		t.Errorf("unexpected error binding with the userPassword of an entry: %v", err)
+	}
+	result, err := conn.WhoAmI(nil)
+	if err != nil || result.AuthzID != "dn:uid=alice,ou=users,dc=example,dc=com" {
+		t.Errorf("unexpected identity %+v: %v", result, err)
+	}
+	assertResultCode(t, conn.Bind("uid=alice,ou=users,dc=example,dc=com", "wrong"), ldap.LDAPResultInvalidCredentials)
+	assertResultCode(t, conn.Bind("cn=admin,dc=example,dc=com", "wrong"), ldap.LDAPResultInvalidCredentials)
+
+	if err := conn.UnauthenticatedBind(""); err != nil {
+		t.Fatalf("unexpected error binding anonymously: %v", err)
+	}
+	if entries := search(t, conn, "dc=example,dc=com", ldap.ScopeWholeSubtree, "(uid=alice)"); len(entries) != 1 {
+		t.Errorf("anonymous clients should read entries, got %v", dns(entries))
+	}
+	err = conn.Add(&ldap.AddRequest{DN: "ou=groups,dc=example,dc=com", Attributes: []ldap.Attribute{
+		{Type: "objectClass", Vals: []string{"organizationalUnit"}},
+		{Type: "ou", Vals: []string{"groups"}},
+	}})
+	assertResultCode(t, err, ldap.LDAPResultInsufficientAccessRights)
+}
+
+func TestSearch(t *testing.T) {
+	_, conn := testServer(t)
+
+	for filter, expected := range map[string][]string{
+		"(uid=ALICE)":                                                  {"uid=alice,ou=users,dc=example,dc=com"},
+		"(&(objectClass=person)(!(mail=*)))":                           {"uid=bob,ou=users,dc=example,dc=com"},
+		"(|(cn=B*)(mail=*@example.com))":                               {"uid=alice,ou=users,dc=example,dc=com", "uid=bob,ou=users,dc=example,dc=com"},
+		"(member=UID=Alice,OU=Users,DC=Example,DC=Com)":                {"cn=staff,dc=example,dc=com"},
+		"(memberOf=cn=staff,dc=example,dc=com)":                        {"uid=alice,ou=users,dc=example,dc=com"},
+		"(memberOf:1.2.840.113556.1.4.1941:=cn=all,dc=example,dc=com)": {"uid=alice,ou=users,dc=example,dc=com", "cn=staff,dc=example,dc=com"},
+		"(commonName=bob)":                                             {"uid=bob,ou=users,dc=example,dc=com"},
+		"(sn>=k)":                                                      {"uid=alice,ou=users,dc=example,dc=com"},
+	} {
+		if actual := dns(search(t, conn, "dc=example,dc=com", ldap.ScopeWholeSubtree, filter)); !equalSets(actual, expected) {
+			t.Errorf("search %q: expected %v, got %v", filter, expected, actual)
+		}
+	}
+
+	if actual := dns(search(t, conn, "dc=example,dc=com", ldap.ScopeSingleLevel, "(objectClass=*)")); !equalSets(actual, []string{"ou=users,dc=example,dc=com", "cn=staff,dc=example,dc=com", "cn=all,dc=example,dc=com"}) {
+		t.Errorf("unexpected one-level search result %v", actual)
+	}
+
+	entries := search(t, conn, "uid=alice,ou=users,dc=example,dc=com", ldap.ScopeBaseObject, "(objectClass=*)", "cn", "entryUUID")
+	if len(entries) != 1 || len(entries[0].Attributes) != 2 || entries[0].GetAttributeValue("entryUUID") == "" {
+		t.Errorf("unexpected attributes %+v", entries)
+	}
+	entries = search(t, conn, "uid=alice,ou=users,dc=example,dc=com", ldap.ScopeBaseObject, "(objectClass=*)")
+	if entries[0].GetAttributeValue("entryUUID") != "" || entries[0].GetAttributeValue("mail") != "alice@example.com" {
+		t.Errorf("operational attributes should only be returned when requested: %+v", entries[0].Attributes)
+	}
+	entries = search(t, conn, "uid=alice,ou=users,dc=example,dc=com", ldap.ScopeBaseObject, "(objectClass=*)", "+")
+	if entries[0].GetAttributeValue("structuralObjectClass") != "inetOrgPerson" || entries[0].GetAttributeValue("mail") != "" {
+		t.Errorf("unexpected operational attributes %+v", entries[0].Attributes)
+	}
+
+	_, err := conn.Search(ldap.NewSearchRequest("ou=missing,dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", nil, nil))
+	assertResultCode(t, err, ldap.LDAPResultNoSuchObject)
+}
+
+func TestSearchControls(t *testing.T) {
+	s, conn := testServer(t)
+	for _, uid := range []string{"carol", "dave", "erin"} {
+		if err := s.Seed("dn: uid=" + uid + ",ou=users,dc=example,dc=com\nobjectClass: account\nuid: " + uid + "\n"); err != nil {
+			t.Fatalf("unexpected error seeding: %v", err)
+		}
+	}
+
+	request := ldap.NewSearchRequest("ou=users,dc=example,dc=com", ldap.ScopeSingleLevel, ldap.NeverDerefAliases, 0, 0, false, "(uid=*)", []string{"uid"}, nil)
+	sr, err := conn.SearchWithPaging(request, 2)
+	if err != nil || len(sr.Entries) != 5 {
+		t.Errorf("unexpected paged search result %v: %v", sr, err)
+	}
+
+	request = ldap.NewSearchRequest("ou=users,dc=example,dc=com", ldap.ScopeSingleLevel, ldap.NeverDerefAliases, 0, 0, false, "(uid=*)", []string{"uid"},
+		[]ldap.Control{ldap.NewControlServerSideSortingWithSortKeys([]*ldap.SortKey{{AttributeType: "uid", Reverse: true}})})
+	sr, err = conn.Search(request)
+	if err != nil {
+		t.Fatalf("unexpected error sorting: %v", err)
+	}
+	uids := []string{}
+	for _, entry := range sr.Entries {
+		uids = append(uids, entry.GetAttributeValue("uid"))
+	}
+	if !sort.IsSorted(sort.Reverse(sort.StringSlice(uids))) || len(uids) != 5 {
+		t.Errorf("unexpected sorted search result %v", uids)
+	}
+
+	request = ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", nil,
+		[]ldap.Control{&ldap.ControlString{ControlType: "1.2.3.4", Criticality: true}})
+	_, err = conn.Search(request)
+	assertResultCode(t, err, ldap.LDAPResultUnavailableCriticalExtension)
+
+	rootDSE := search(t, conn, "", ldap.ScopeBaseObject, "(objectClass=*)", "namingContexts", "subschemaSubentry")
+	if rootDSE[0].GetAttributeValue("namingContexts") != "dc=example,dc=com" || rootDSE[0].GetAttributeValue("subschemaSubentry") != "cn=Subschema" {
+		t.Errorf("unexpected root DSE %+v", rootDSE[0].Attributes)
+	}
+	subschema := search(t, conn, "cn=Subschema", ldap.ScopeBaseObject, "(objectClass=subschema)", "objectClasses", "attributeTypes")
+	if len(subschema[0].GetAttributeValues("objectClasses")) != len(DefaultObjectClasses) {
+		t.Errorf("unexpected subschema subentry %+v", subschema[0].Attributes)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s, conn := testServer(t)
+	dn := "uid=carol,ou=users,dc=example,dc=com"
+
+	add := ldap.NewAddRequest(dn, nil)
+	add.Attribute("objectClass", []string{"inetOrgPerson"})
+	add.Attribute("cn", []string{"Carol"})
+	add.Attribute("sn", []string{"White"})
+	if err := conn.Add(add); err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+	if uid := s.Entry(dn).GetAttributeValue("uid"); uid != "carol" {
+		t.Errorf("the value of the RDN should be added, got %q", uid)
+	}
+	assertResultCode(t, conn.Add(add), ldap.LDAPResultEntryAlreadyExists)
+
+	orphan := ldap.NewAddRequest("uid=carol,ou=missing,dc=example,dc=com", nil)
+	orphan.Attribute("objectClass", []string{"account"})
+	orphan.Attribute("uid", []string{"carol"})
+	assertResultCode(t, conn.Add(orphan), ldap.LDAPResultNoSuchObject)
+
+	modify := ldap.NewModifyRequest(dn, nil)
+	modify.Add("mail", []string{"carol@example.com"})
+	modify.Replace("sn", []string{"Black"})
+	if err := conn.Modify(modify); err != nil {
+		t.Fatalf("unexpected error modifying: %v", err)
+	}
+	entry := s.Entry(dn)
+	if entry.GetAttributeValue("mail") != "carol@example.com" || entry.GetAttributeValue("sn") != "Black" {
+		t.Errorf("unexpected entry after modification %+v", entry.Attributes)
+	}
+
+	modify = ldap.NewModifyRequest(dn, nil)
+	modify.Add("mail", []string{"CAROL@example.com"})
+	assertResultCode(t, conn.Modify(modify), ldap.LDAPResultAttributeOrValueExists)
+	modify.Controls = []ldap.Control{&ldap.ControlString{ControlType: ldap.ControlTypeMicrosoftPermissiveModify, Criticality: true}}
+	if err := conn.Modify(modify); err != nil {
+		t.Errorf("unexpected error with the permissive modify control: %v", err)
+	}
+
+	modify = ldap.NewModifyRequest(dn, nil)
+	modify.Delete("uid", nil)
+	assertResultCode(t, conn.Modify(modify), ldap.LDAPResultNotAllowedOnRDN)
+
+	modify = ldap.NewModifyRequest(dn, nil)
+	modify.Replace("objectClass", []string{"account"})
+	assertResultCode(t, conn.Modify(modify), ldap.LDAPResultObjectClassModsProhibited)
+
+	noOp := &ldap.ControlString{ControlType: oidNoOp, Criticality: true}
+	modify = ldap.NewModifyRequest(dn, []ldap.Control{noOp})
+	modify.Replace("sn", []string{"Grey"})
+	assertResultCode(t, conn.Modify(modify), resultNoOperation)
+	if sn := s.Entry(dn).GetAttributeValue("sn"); sn != "Black" {
+		t.Errorf("the No-Op control should leave the entry untouched, got sn %q", sn)
+	}
+	modify = ldap.NewModifyRequest(dn, []ldap.Control{noOp})
+	modify.Delete("uid", nil)
+	assertResultCode(t, conn.Modify(modify), ldap.LDAPResultNotAllowedOnRDN)
+
+	modify = ldap.NewModifyRequest(dn, []ldap.Control{assertionControl(t, "(sn=White)")})
+	modify.Replace("description", []string{"guarded"})
+	assertResultCode(t, conn.Modify(modify), ldap.LDAPResultAssertionFailed)
+
+	if err := conn.ModifyDN(ldap.NewModifyDNRequest("ou=users,dc=example,dc=com", "ou=people", true, "")); err != nil {
+		t.Fatalf("unexpected error renaming: %v", err)
+	}
+	if s.Entry(dn) != nil || s.Entry("uid=carol,ou=people,dc=example,dc=com") == nil {
+		t.Error("the subordinates should be moved along with their parent")
+	}
+	if ou := s.Entry("ou=people,dc=example,dc=com").GetAttributeValues("ou"); len(ou) != 1 || ou[0] != "people" {
+		t.Errorf("unexpected RDN values after renaming %v", ou)
+	}
+
+	assertResultCode(t, conn.Del(ldap.NewDelRequest("ou=people,dc=example,dc=com", nil)), ldap.LDAPResultNotAllowedOnNonLeaf)
+	if err := conn.Del(ldap.NewDelRequest("ou=people,dc=example,dc=com", []ldap.Control{ldap.NewControlSubtreeDelete()})); err != nil {
+		t.Fatalf("unexpected error deleting the subtree: %v", err)
+	}
+	if s.Entry("uid=alice,ou=people,dc=example,dc=com") != nil {
+		t.Error("the subtree should be deleted")
+	}
+
+	matched, err := conn.Compare("cn=staff,dc=example,dc=com", "cn", "STAFF")
+	if err != nil || !matched {
+		t.Errorf("unexpected comparison %t: %v", matched, err)
+	}
+}
+
+func assertionControl(t *testing.T, filter string) ldap.Control {
+	t.Helper()
+	packet, err := ldap.CompileFilter(filter)
+	if err != nil {
+		t.Fatalf("unexpected error compiling %q: %v", filter, err)
+	}
+	return &ldap.ControlString{ControlType: oidAssertion, Criticality: true, ControlValue: string(packet.Bytes())}
+}
+
+func equalSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
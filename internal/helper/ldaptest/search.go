@@ -0,0 +1,310 @@
+package ldaptest
+
+import (
+	"sort"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// view is an entry as returned by a search: its user attributes and its
+// operational ones, which are only returned when requested.
+type view struct {
+	dn          string
+	user        []*ldap.EntryAttribute
+	operational []*ldap.EntryAttribute
+}
+
+func (v *view) attributes() []*ldap.EntryAttribute {
+	return append(append([]*ldap.EntryAttribute{}, v.user...), v.operational...)
+}
+
+// attributes returns the user and operational attributes of an entry; the
+// caller must hold the lock.
+func (s *Server) attributes(e *entry) []*ldap.EntryAttribute {
+	return s.view(e).attributes()
+}
+
+// view returns the attributes of an entry; the caller must hold the lock.
+func (s *Server) view(e *entry) *view {
+	v := &view{dn: e.dn, user: copyAttributes(e.attributes)}
+	operational := func(name string, values ...string) {
+		v.operational = append(v.operational, ldap.NewEntryAttribute(name, values))
+	}
+	if structural, err := s.schema.StructuralClass(values(s.attribute(e.attributes, "objectClass"))); err == nil && structural != nil {
+		operational("structuralObjectClass", structural.Name())
+	}
+	operational("entryUUID", e.uuid)
+	operational("entryCSN", e.csn)
+	operational("creatorsName", e.creator)
+	operational("createTimestamp", e.createdAt.Format(generalized))
+	operational("modifiersName", e.modifier)
+	operational("modifyTimestamp", e.modifiedAt.Format(generalized))
+	operational("entryDN", e.dn)
+	operational("subschemaSubentry", subschemaDN)
+
+	subordinates := "FALSE"
+	var memberOf []string
+	for _, other := range s.entries {
+		if other.parent == e.key {
+			subordinates = "TRUE"
+		}
+		if s.hasValue(other.attributes, "member", e.dn) || s.hasValue(other.attributes, "uniqueMember", e.dn) {
+			memberOf = append(memberOf, other.dn)
+		}
+	}
+	operational("hasSubordinates", subordinates)
+	if len(memberOf) > 0 {
+		sort.Strings(memberOf)
+		operational("memberOf", memberOf...)
+	}
+	return v
+}
+
+// rootDSE returns the root DSE of the server.
+func (s *Server) rootDSE() *view {
+	return &view{
+		user: []*ldap.EntryAttribute{ldap.NewEntryAttribute("objectClass", []string{"top"})},
+		operational: []*ldap.EntryAttribute{
+			ldap.NewEntryAttribute("namingContexts", []string{s.config.Suffix}),
+			ldap.NewEntryAttribute("subschemaSubentry", []string{subschemaDN}),
+			ldap.NewEntryAttribute("supportedControl", supportedControls),
+			ldap.NewEntryAttribute("supportedExtension", []string{ldap.ControlTypeWhoAmI}),
+			ldap.NewEntryAttribute("supportedFeatures", []string{oidAllOperations}),
+			ldap.NewEntryAttribute("supportedLDAPVersion", []string{"3"}),
+			ldap.NewEntryAttribute("vendorName", []string{"ldaptest"}),
+		},
+	}
+}
+
+// subschema returns the subschema subentry of the server.
+func (s *Server) subschema() *view {
+	return &view{
+		dn: subschemaDN,
+		user: []*ldap.EntryAttribute{
+			ldap.NewEntryAttribute("objectClass", []string{"top", "subentry", "subschema", "extensibleObject"}),
+			ldap.NewEntryAttribute("cn", []string{"Subschema"}),
+		},
+		operational: []*ldap.EntryAttribute{
+			ldap.NewEntryAttribute("objectClasses", s.config.ObjectClasses),
+			ldap.NewEntryAttribute("attributeTypes", s.config.AttributeTypes),
+		},
+	}
+}
+
+// search handles a search request; entries are returned in the order they
+// were added, unless sorted with the Server Side Sorting control.
+func (c *session) search(id int64, op *ber.Packet, controls []*control) {
+	s := c.server
+	var responseControls []ldap.Control
+	done := func(err error) {
+		c.write(id, result(ldap.ApplicationSearchResultDone, err), responseControls...)
+	}
+	if len(op.Children) != 8 {
+		done(errorf(ldap.LDAPResultProtocolError, "malformed search request"))
+		return
+	}
+	base := op.Children[0].Data.String()
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	typesOnly, _ := op.Children[5].Value.(bool)
+	filter := op.Children[6]
+	requested := []string{}
+	for _, child := range op.Children[7].Children {
+		requested = append(requested, child.Data.String())
+	}
+
+	views, err := s.search(base, int(scope), filter, controls)
+	if err != nil {
+		done(err)
+		return
+	}
+
+	if control := findControl(controls, oidSortRequest); control != nil {
+		keys, err := sortKeys(control)
+		if err != nil {
+			done(err)
+			return
+		}
+		s.sort(views, keys)
+		responseControls = append(responseControls, sortResponse())
+	}
+	if control := findControl(controls, oidPagedResults); control != nil {
+		size, offset, err := paging(control)
+		if err != nil {
+			done(err)
+			return
+		}
+		if offset > len(views) {
+			offset = len(views)
+		}
+		end, next := offset+size, 0
+		if end < len(views) && size > 0 {
+			next = end
+		} else {
+			end = len(views)
+		}
+		if size == 0 {
+			// a size of 0 abandons the paged search
+			end = offset
+		}
+		views = views[offset:end]
+		responseControls = append(responseControls, pagingResponse(next))
+	}
+
+	for i, v := range views {
+		if sizeLimit > 0 && int64(i) >= sizeLimit {
+			done(errorf(ldap.LDAPResultSizeLimitExceeded, "size limit exceeded"))
+			return
+		}
+		c.write(id, s.entryPacket(v, requested, typesOnly))
+	}
+	done(nil)
+}
+
+// search returns the entries in the scope of a search which match its
+// filter.
+func (s *Server) search(base string, scope int, filter *ber.Packet, controls []*control) ([]*view, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := ""
+	if base != rootDSE {
+		_, k, _, err := parseDN(base)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	}
+
+	switch {
+	case key == "" && scope == ldap.ScopeBaseObject:
+		return s.filter([]*view{s.rootDSE()}, filter), nil
+	case key == strings.ToLower(subschemaDN) && scope == ldap.ScopeBaseObject:
+		return s.filter([]*view{s.subschema()}, filter), nil
+	}
+
+	if key != "" {
+		e, ok := s.entries[key]
+		if !ok {
+			return nil, s.noSuchObject(base)
+		}
+		if err := s.assertion(controls, e); err != nil {
+			return nil, err
+		}
+	}
+
+	candidates := []*entry{}
+	for _, e := range s.entries {
+		switch {
+		case scope == ldap.ScopeBaseObject && e.key == key,
+			scope == ldap.ScopeSingleLevel && key == "" && e.key == s.suffix,
+			scope == ldap.ScopeSingleLevel && key != "" && e.parent == key,
+			scope == ldap.ScopeWholeSubtree && (key == "" || e.key == key || strings.HasSuffix(e.key, ","+key)):
+			candidates = append(candidates, e)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].sequence < candidates[j].sequence
+	})
+
+	views := make([]*view, len(candidates))
+	for i, e := range candidates {
+		views[i] = s.view(e)
+	}
+	return s.filter(views, filter), nil
+}
+
+// filter returns the entries matching a filter; the caller must hold the
+// lock.
+func (s *Server) filter(views []*view, filter *ber.Packet) []*view {
+	matching := []*view{}
+	for _, v := range views {
+		if s.match(filter, v.attributes()) {
+			matching = append(matching, v)
+		}
+	}
+	return matching
+}
+
+// sort sorts entries by the lowest values of the keys, the entries which
+// have no value for a key coming last.
+func (s *Server) sort(views []*view, keys []*ldap.SortKey) {
+	lowest := func(v *view, name string) (string, bool) {
+		values := values(s.attribute(v.attributes(), name))
+		if len(values) == 0 {
+			return "", false
+		}
+		min := values[0]
+		for _, value := range values[1:] {
+			if s.compare(name, value, min) < 0 {
+				min = value
+			}
+		}
+		return min, true
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		for _, key := range keys {
+			a, okA := lowest(views[i], key.AttributeType)
+			b, okB := lowest(views[j], key.AttributeType)
+			if !okA || !okB {
+				if okA != okB {
+					return okA
+				}
+				continue
+			}
+			if c := s.compare(key.AttributeType, a, b); c != 0 {
+				return (c < 0) != key.Reverse
+			}
+		}
+		return false
+	})
+}
+
+// entryPacket encodes an entry as a search result, with the requested
+// attributes: all user attributes if none or "*" is, all operational ones if
+// "+" is, and no attributes if only "1.1" is.
+func (s *Server) entryPacket(v *view, requested []string, typesOnly bool) *ber.Packet {
+	all := len(requested) == 0
+	operational := false
+	keys := map[string]bool{}
+	for _, name := range requested {
+		switch name {
+		case "*":
+			all = true
+		case "+":
+			operational = true
+		default:
+			keys[s.attributeKey(name)] = true
+		}
+	}
+
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v.dn, "Object Name"))
+	encoded := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	add := func(a *ldap.EntryAttribute) {
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a.Name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		if !typesOnly {
+			for _, value := range a.Values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+		}
+		attribute.AppendChild(set)
+		encoded.AppendChild(attribute)
+	}
+	for _, a := range v.user {
+		if all || keys[s.attributeKey(a.Name)] {
+			add(a)
+		}
+	}
+	for _, a := range v.operational {
+		if operational || keys[s.attributeKey(a.Name)] {
+			add(a)
+		}
+	}
+	packet.AppendChild(encoded)
+	return packet
+}
@@ -0,0 +1,165 @@
+// Package ldapurl parses LDAP URLs (RFC 4516), such as the memberURL values
+// of dynamic groups.
+package ldapurl
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// URL is a parsed LDAP URL; the fields left out of the URL hold their
+// default values.
+type URL struct {
+	Scheme     string // ldap, ldaps or ldapi
+	Host       string // host[:port], empty for the default server
+	DN         string
+	Attributes []string
+	Scope      string // base, one or sub; base by default
+	Filter     string // (objectClass=*) by default
+	Extensions []Extension
+}
+
+// Extension is an extension of an LDAP URL.
+type Extension struct {
+	Critical bool
+	Type     string
+	Value    string
+}
+
+// Parse parses an LDAP URL, checking that its DN and filter are valid.
+func Parse(s string) (*URL, error) {
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		return nil, fmt.Errorf("invalid LDAP URL %q: missing scheme", s)
+	}
+	u := &URL{Scheme: strings.ToLower(scheme), Scope: "base", Filter: "(objectClass=*)"}
+	switch u.Scheme {
+	case "ldap", "ldaps", "ldapi":
+	default:
+		return nil, fmt.Errorf("invalid LDAP URL %q: unsupported scheme %q", s, scheme)
+	}
+
+	hostport, rest, _ := strings.Cut(rest, "/")
+	if err := u.setHost(hostport); err != nil {
+		return nil, fmt.Errorf("invalid LDAP URL %q: %w", s, err)
+	}
+
+	parts := strings.Split(rest, "?")
+	if len(parts) > 5 {
+		return nil, fmt.Errorf("invalid LDAP URL %q: too many components", s)
+	}
+	for i, part := range parts {
+		value, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LDAP URL %q: %w", s, err)
+		}
+		switch i {
+		case 0:
+			err = u.setDN(value)
+		case 1:
+			err = u.setAttributes(value)
+		case 2:
+			err = u.setScope(value)
+		case 3:
+			err = u.setFilter(value)
+		case 4:
+			// extensions are split before being unescaped, since their
+			// values may hold escaped commas
+			err = u.setExtensions(part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid LDAP URL %q: %w", s, err)
+		}
+	}
+	return u, nil
+}
+
+func (u *URL) setHost(hostport string) error {
+	if u.Scheme == "ldapi" {
+		// the host of ldapi URLs is the escaped path of a socket
+		host, err := url.PathUnescape(hostport)
+		u.Host = host
+		return err
+	}
+	u.Host = hostport
+	if i := strings.LastIndex(hostport, ":"); i >= 0 && !strings.HasSuffix(hostport, "]") {
+		port, err := strconv.Atoi(hostport[i+1:])
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port %q", hostport[i+1:])
+		}
+	}
+	return nil
+}
+
+func (u *URL) setDN(dn string) error {
+	if _, err := ldap.ParseDN(dn); err != nil {
+		return fmt.Errorf("invalid DN %q: %w", dn, err)
+	}
+	u.DN = dn
+	return nil
+}
+
+func (u *URL) setAttributes(attributes string) error {
+	if attributes == "" {
+		return nil
+	}
+	for _, attribute := range strings.Split(attributes, ",") {
+		if attribute == "" {
+			return fmt.Errorf("empty attribute in %q", attributes)
+		}
+		u.Attributes = append(u.Attributes, attribute)
+	}
+	return nil
+}
+
+func (u *URL) setScope(scope string) error {
+	switch strings.ToLower(scope) {
+	case "":
+	case "base", "one", "sub":
+		u.Scope = strings.ToLower(scope)
+	default:
+		return fmt.Errorf("invalid scope %q: expected base, one or sub", scope)
+	}
+	return nil
+}
+
+func (u *URL) setFilter(filter string) error {
+	if filter == "" {
+		return nil
+	}
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return fmt.Errorf("invalid filter %q: %w", filter, err)
+	}
+	u.Filter = filter
+	return nil
+}
+
+func (u *URL) setExtensions(extensions string) error {
+	if extensions == "" {
+		return nil
+	}
+	for _, extension := range strings.Split(extensions, ",") {
+		var e Extension
+		if strings.HasPrefix(extension, "!") {
+			e.Critical = true
+			extension = extension[1:]
+		}
+		extensionType, value, _ := strings.Cut(extension, "=")
+		var err error
+		if e.Type, err = url.PathUnescape(extensionType); err != nil {
+			return err
+		}
+		if e.Value, err = url.PathUnescape(value); err != nil {
+			return err
+		}
+		if e.Type == "" {
+			return fmt.Errorf("empty extension type in %q", extensions)
+		}
+		u.Extensions = append(u.Extensions, e)
+	}
+	return nil
+}
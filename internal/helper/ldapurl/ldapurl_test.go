@@ -0,0 +1,64 @@
+package ldapurl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	u, err := Parse("ldap://ldap.example.com:389/ou=people,dc=example,dc=com?cn,mail?sub?(&(objectClass=person)(departmentNumber=42))?!x-ext=a%2Cb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &URL{
+		Scheme:     "ldap",
+		Host:       "ldap.example.com:389",
+		DN:         "ou=people,dc=example,dc=com",
+		Attributes: []string{"cn", "mail"},
+		Scope:      "sub",
+		Filter:     "(&(objectClass=person)(departmentNumber=42))",
+		Extensions: []Extension{{Critical: true, Type: "x-ext", Value: "a,b"}},
+	}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("Parse() = %+v, expected %+v", u, expected)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	u, err := Parse("ldap:///dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Host != "" || u.Scope != "base" || u.Filter != "(objectClass=*)" || u.Attributes != nil {
+		t.Errorf("unexpected defaults: %+v", u)
+	}
+}
+
+func TestParseEscaped(t *testing.T) {
+	u, err := Parse("ldap:///ou=Sales%5C%2C%20EMEA,dc=example,dc=com??one?(cn=J%3F*)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.DN != `ou=Sales\, EMEA,dc=example,dc=com` || u.Filter != "(cn=J?*)" {
+		t.Errorf("unexpected DN or filter: %q, %q", u.DN, u.Filter)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"ou=people,dc=example,dc=com",
+		"http://example.com/",
+		"ldap://example.com:port/dc=example,dc=com",
+		"ldap:///not a dn",
+		"ldap:///dc=example,dc=com??subtree",
+		"ldap:///dc=example,dc=com??sub?(objectClass=person",
+		"ldap:///dc=example,dc=com??sub?objectClass=person)",
+		"ldap:///dc=example,dc=com?cn,,mail",
+		"ldap:///dc=example,dc=com????x?y",
+		"ldap:///dc=example,dc=com??sub?(cn=%zz)",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package ldif parses content records of the LDAP Data Interchange Format
+// (RFC 2849); change records are not supported.
+package ldif
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Attribute is an attribute of an LDIF entry with all its values, in the
+// order they appear.
+type Attribute struct {
+	Name   string
+	Values []string
+}
+
+// Entry is an LDIF content record.
+type Entry struct {
+	DN         string
+	Attributes []*Attribute
+}
+
+// Attribute returns the attribute with the given name (case-insensitively),
+// or nil.
+func (e *Entry) Attribute(name string) *Attribute {
+	for _, attribute := range e.Attributes {
+		if strings.EqualFold(attribute.Name, name) {
+			return attribute
+		}
+	}
+	return nil
+}
+
+// Parse parses LDIF content into its entries, in the order they appear.
+func Parse(content string) ([]*Entry, error) {
+	lines, err := unfold(content)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{}
+	seen := map[string]bool{}
+	var entry *Entry
+	for i, line := range lines {
+		if line.text == "" {
+			entry = nil
+			continue
+		}
+
+		name, value, err := parseLine(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+
+		if entry == nil {
+			switch {
+			case strings.EqualFold(name, "version") && i == 0:
+				if value != "1" {
+					return nil, fmt.Errorf("line %d: unsupported LDIF version %q", line.number, value)
+				}
+				continue
+			case !strings.EqualFold(name, "dn"):
+				return nil, fmt.Errorf("line %d: expected a dn, got %q", line.number, name)
+			}
+			if value == "" {
+				return nil, fmt.Errorf("line %d: empty dn", line.number)
+			}
+			if seen[strings.ToLower(value)] {
+				return nil, fmt.Errorf("line %d: duplicate entry %q", line.number, value)
+			}
+			seen[strings.ToLower(value)] = true
+			entry = &Entry{DN: value}
+			entries = append(entries, entry)
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(name, "changetype"):
+			return nil, fmt.Errorf("line %d: change records are not supported", line.number)
+		case strings.EqualFold(name, "control"):
+			return nil, fmt.Errorf("line %d: controls are not supported", line.number)
+		}
+
+		// the ";binary" transfer option is not part of the attribute name
+		name = strings.TrimSuffix(name, ";binary")
+		if attribute := entry.Attribute(name); attribute != nil {
+			attribute.Values = append(attribute.Values, value)
+		} else {
+			entry.Attributes = append(entry.Attributes, &Attribute{Name: name, Values: []string{value}})
+		}
+	}
+
+	for _, entry := range entries {
+		if len(entry.Attributes) == 0 {
+			return nil, fmt.Errorf("entry %q has no attributes", entry.DN)
+		}
+	}
+	return entries, nil
+}
+
+type line struct {
+	number int
+	text   string
+}
+
+// unfold joins continuation lines (starting with a single space) and drops
+// comments; blank lines are kept as record separators.
+func unfold(content string) ([]line, error) {
+	lines := []line{}
+	comment := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case strings.HasPrefix(text, " "):
+			if comment {
+				continue
+			}
+			if len(lines) == 0 || lines[len(lines)-1].text == "" {
+				return nil, fmt.Errorf("line %d: unexpected continuation line", number)
+			}
+			lines[len(lines)-1].text += text[1:]
+		case strings.HasPrefix(text, "#"):
+			comment = true
+		default:
+			comment = false
+			if strings.TrimSpace(text) == "" {
+				text = ""
+			}
+			lines = append(lines, line{number: number, text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseLine splits an "attribute: value" line, decoding base64 values
+// ("attribute:: value"); URL values ("attribute:< url") are not supported.
+func parseLine(text string) (string, string, error) {
+	i := strings.IndexByte(text, ':')
+	if i <= 0 {
+		return "", "", fmt.Errorf("invalid line %q", text)
+	}
+	name, value := text[:i], text[i+1:]
+	switch {
+	case strings.HasPrefix(value, ":"):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+		if err != nil {
+			return "", "", fmt.Errorf("invalid base64 value for %q: %w", name, err)
+		}
+		return name, string(decoded), nil
+	case strings.HasPrefix(value, "<"):
+		return "", "", fmt.Errorf("URL values are not supported (attribute %q)", name)
+	default:
+		return name, strings.TrimLeft(value, " "), nil
+	}
+}
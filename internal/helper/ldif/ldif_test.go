@@ -0,0 +1,83 @@
+package ldif
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `version: 1
+
+# the organisational unit
+dn: ou=people,dc=example,dc=com
+objectClass: organizationalUnit
+ou: people
+
+dn: uid=jdoe,ou=people,dc=example,dc=com
+objectClass: inetOrgPerson
+objectClass: posixAccount
+cn: John
+  Doe
+sn:: RG9l
+description: a long
+ er line
+# comments are ignored,
+ even when folded
+uid: jdoe
+`
+	entries, err := Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].DN != "ou=people,dc=example,dc=com" || len(entries[0].Attributes) != 2 {
+		t.Errorf("invalid first entry: %+v", entries[0])
+	}
+
+	jdoe := entries[1]
+	if jdoe.DN != "uid=jdoe,ou=people,dc=example,dc=com" {
+		t.Errorf("invalid DN: %q", jdoe.DN)
+	}
+	expected := map[string][]string{
+		"objectClass": {"inetOrgPerson", "posixAccount"},
+		"cn":          {"John Doe"},
+		"sn":          {"Doe"},
+		"description": {"a longer line"},
+		"uid":         {"jdoe"},
+	}
+	for name, values := range expected {
+		attribute := jdoe.Attribute(name)
+		if attribute == nil {
+			t.Errorf("missing attribute %q", name)
+			continue
+		}
+		if !reflect.DeepEqual(attribute.Values, values) {
+			t.Errorf("invalid values for %q: %q, expected %q", name, attribute.Values, values)
+		}
+	}
+	if jdoe.Attribute("OBJECTCLASS") == nil {
+		t.Error("attribute lookup should be case-insensitive")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"no dn":          "cn: foo\n",
+		"changetype":     "dn: cn=foo,dc=example,dc=com\nchangetype: delete\n",
+		"no attributes":  "dn: cn=foo,dc=example,dc=com\n",
+		"duplicate":      "dn: cn=foo,dc=example,dc=com\ncn: foo\n\ndn: CN=foo,dc=example,dc=com\ncn: foo\n",
+		"bad base64":     "dn: cn=foo,dc=example,dc=com\ncn:: !!!\n",
+		"url value":      "dn: cn=foo,dc=example,dc=com\njpegPhoto:< file:///tmp/photo.jpg\n",
+		"no separator":   "dn: cn=foo,dc=example,dc=com\ncn foo\n",
+		"continuation":   " cn: foo\n",
+		"version":        "version: 2\n\ndn: cn=foo,dc=example,dc=com\ncn: foo\n",
+		"missing record": "dn: cn=foo,dc=example,dc=com\ncn: foo\n\ncn: bar\n",
+	} {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPBind() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPBindRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Required:     true,
+				Description:  "The DN to bind as.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password to bind with.",
+			},
+			"success": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the bind succeeded.",
+			},
+			"result_code": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The LDAP result code of the bind (0 on success, 49 for invalid credentials).",
+			},
+			"diagnostic_message": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The message returned by the server along with a failed bind.",
+			},
+			"password_expires_in": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of seconds before the password expires, as warned by the Password Policy control; -1 if there was no such warning.",
+			},
+			"grace_logins_remaining": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of binds left with the expired password, as warned by the Password Policy control; -1 if there was no such warning.",
+			},
+			"password_policy_error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Password Policy error reported by the server, e.g. \"Password expired\" or \"Account locked\".",
+			},
+		},
+
+		Description: "Checks that credentials work by binding with them on a new connection, reporting the outcome along " +
+			"with the Password Policy (ppolicy) warnings of the server, e.g. to verify the credentials of provisioned " +
+			"service accounts.\n\n" +
+			"A failed bind does not fail the data source. The bind is attempted on every refresh, and failed binds " +
+			"count towards the lockout of the account when the server enforces a password policy.\n\n" +
+			"~> The password is stored in cleartext in the state, as are all the arguments of data sources. Prefer the " +
+			"`ldap_bind` ephemeral resource, which checks credentials without persisting them, with Terraform 1.10 and later.",
+	}
+}
+
+func dataSourceLDAPBindRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+	ctx = tflog.MaskLogStrings(ctx, d.Get("password").(string))
+
+	tflog.SubsystemDebug(ctx, subsystemConnection, "checking bind", map[string]interface{}{"dn": dn})
+
+	result, err := providerConfig.Connection.CheckBind(dn, d.Get("password").(string))
+	if err != nil {
+		return diag.Errorf("error binding as %q: %v", dn, err)
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemConnection, "bind checked", map[string]interface{}{
+		"dn":          dn,
+		"success":     result.Success,
+		"result_code": result.ResultCode,
+	})
+
+	d.SetId(dn)
+	d.Set("success", result.Success)
+	d.Set("result_code", int(result.ResultCode))
+	d.Set("diagnostic_message", result.DiagnosticMessage)
+	d.Set("password_expires_in", int(result.PasswordExpiresIn))
+	d.Set("grace_logins_remaining", int(result.GraceLogins))
+	d.Set("password_policy_error", result.PolicyError)
+	return nil
+}
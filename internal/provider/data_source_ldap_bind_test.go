@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPBind(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPBindConfig(os.Getenv("LDAP_BIND_PASSWORD")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_bind.test", "success", "true"),
+					resource.TestCheckResourceAttr("data.ldap_bind.test", "result_code", "0"),
+				),
+			},
+			{
+				Config: testAccDataSourceLDAPBindConfig("wrong password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_bind.test", "success", "false"),
+					resource.TestCheckResourceAttr("data.ldap_bind.test", "result_code", "49"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceLDAPBindConfig(password string) string {
+	return fmt.Sprintf(`
+data "ldap_bind" "test" {
+  dn       = %q
+  password = %q
+}
+`, os.Getenv("LDAP_BIND_USER"), password)
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLDAPCount() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPCountRead,
+
+		Schema: map[string]*schema.Schema{
+			"base_dn": searchBaseSchema("The base DN to start the search from."),
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "(objectClass=*)",
+				Description: "LDAP filter string (e.g., \"(memberOf=cn=admins,ou=groups,dc=example,dc=com)\"). Default: (objectClass=*).",
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "sub",
+				Description:  "Search scope: base, one, or sub. Default: sub.",
+				ValidateFunc: validation.StringInSlice([]string{"base", "one", "sub"}, false),
+			},
+			"member_of_in_chain": memberOfInChainSchema(),
+			"deref_aliases":      derefAliasesSchema(),
+			"time_limit":         timeLimitSchema(),
+			"paged_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "LDAP paged search size. Set to 0 to disable pagination and use a single search request.",
+			},
+			"entry_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of entries matching the search criteria.",
+			},
+		},
+
+		Description: "Counts the entries matching a filter, without reading their attributes, e.g. for preconditions " +
+			"such as \"no more than 5 administrators\".",
+	}
+}
+
+func dataSourceLDAPCountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	baseDN, err := searchBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := searchFilter(d)
+	scopeStr := d.Get("scope").(string)
+	pagedSize := d.Get("paged_size").(int)
+
+	scope := ldap.ScopeWholeSubtree
+	switch scopeStr {
+	case "base":
+		scope = ldap.ScopeBaseObject
+	case "one":
+		scope = ldap.ScopeSingleLevel
+	}
+
+	// 1.1 asks for no attributes (RFC 4511, section 4.5.1.8)
+	request := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		derefAliases(d, meta),
+		0,
+		timeLimit(d, meta),
+		false,
+		filter,
+		[]string{"1.1"},
+		nil,
+	)
+
+	tflog.Debug(ctx, "counting entries", map[string]interface{}{
+		"base_dn": baseDN,
+		"filter":  filter,
+		"scope":   scopeStr,
+	})
+
+	var sr *ldap.SearchResult
+	if pagedSize > 0 {
+		sr, err = conn.SearchWithPaging(request, uint32(pagedSize))
+	} else {
+		sr, err = conn.Search(request)
+	}
+	if err != nil {
+		return diag.Errorf("LDAP search failed: %v", err)
+	}
+
+	tflog.Debug(ctx, "counted entries", map[string]interface{}{"entries": len(sr.Entries)})
+
+	if err := d.Set("entry_count", len(sr.Entries)); err != nil {
+		return diag.Errorf("error setting entry_count: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", baseDN, filter, scopeStr)))))
+	return nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPCount_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPCountConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_count.users", "entry_count", "2"),
+					resource.TestCheckResourceAttr("data.ldap_count.none", "entry_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceLDAPCountConfig_basic = `
+resource "ldap_object" "users_ou" {
+  dn             = "ou=users,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "test_user1" {
+  dn             = "uid=testuser1,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User1" },
+    { cn = "Test User 1" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_object" "test_user2" {
+  dn             = "uid=testuser2,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User2" },
+    { cn = "Test User 2" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+data "ldap_count" "users" {
+  base_dn = "ou=users,dc=example,dc=com"
+  filter  = "(objectClass=inetOrgPerson)"
+
+  depends_on = [ldap_object.test_user1, ldap_object.test_user2]
+}
+
+data "ldap_count" "none" {
+  base_dn = "ou=users,dc=example,dc=com"
+  filter  = "(uid=nonexistentuser)"
+
+  depends_on = [ldap_object.users_ou]
+}
+`
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// deletedObjectsWellKnownGUID is the well-known GUID of the Deleted Objects
+// container of an Active Directory domain, which is bound to through
+// <WKGUID=guid,domain DN>.
+const deletedObjectsWellKnownGUID = "18e2ea80684f11d2b9aa00c04f79f805"
+
+// deletedObjectsAttributes are the attributes of the tombstones always read.
+var deletedObjectsAttributes = []string{"objectGUID", "msDS-LastKnownRDN", "lastKnownParent", "whenChanged"}
+
+func dataSourceLDAPDeletedObjects() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPDeletedObjectsRead,
+
+		Schema: map[string]*schema.Schema{
+			"base_dn": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				Description: "The DN of the container searched. Default: the Deleted Objects container of the provider's " +
+					"`base_dn`, bound to as `<WKGUID=" + deletedObjectsWellKnownGUID + ",base_dn>`.",
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "(objectClass=*)",
+				Description: "An LDAP filter the tombstones must match too, e.g. \"(sAMAccountName=jdoe)\". Default: (objectClass=*).",
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "one",
+				Description:  "Search scope: one, or sub. Default: one.",
+				ValidateFunc: validation.StringInSlice([]string{"one", "sub"}, false),
+			},
+			"time_limit": timeLimitSchema(),
+			"paged_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "LDAP paged search size. Set to 0 to disable pagination and use a single search request.",
+			},
+			"attributes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The attributes of the tombstones to read besides those of `entries`, e.g. sAMAccountName; most attributes are stripped from the tombstones.",
+			},
+			"entries": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The tombstones matching the filter.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The DN of the tombstone, e.g. CN=jdoe\\0ADEL:<GUID>,CN=Deleted Objects,DC=example,DC=com.",
+						},
+						"object_guid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The objectGUID of the deleted object, e.g. to restore it.",
+						},
+						"last_known_rdn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The RDN value of the object before it was deleted (`msDS-LastKnownRDN`).",
+						},
+						"last_known_parent": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The DN of the container of the object before it was deleted (`lastKnownParent`).",
+						},
+						"deleted_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the object was deleted (`whenChanged` of the tombstone), as an RFC 3339 timestamp.",
+						},
+						"attributes": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The values of the requested attributes (sorted and comma-separated for multi-valued).",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+
+		Description: "Searches the tombstones of the objects deleted from Active Directory with the Show Deleted control " +
+			"(LDAP_SERVER_SHOW_DELETED_OID), e.g. to verify that decommissioned accounts are gone, or to find the " +
+			"objectGUID of an object to restore. Only the filter on `isDeleted` is added: the filter may match any " +
+			"attribute kept on the tombstones.",
+	}
+}
+
+// deletedObjectsBase returns the base DN of the searches of
+// ldap_deleted_objects, setting base_dn to the Deleted Objects container of
+// the provider's base_dn if it is not set.
+func deletedObjectsBase(d *schema.ResourceData, meta interface{}) (string, error) {
+	if v, ok := d.GetOk("base_dn"); ok {
+		return v.(string), nil
+	}
+	baseDN := meta.(*ProviderConfig).BaseDN
+	if baseDN == "" {
+		return "", fmt.Errorf("base_dn must be set, as the provider has no base_dn")
+	}
+	base := "<WKGUID=" + deletedObjectsWellKnownGUID + "," + baseDN + ">"
+	return base, d.Set("base_dn", base)
+}
+
+// formatObjectGUID returns the string form of an objectGUID, whose first
+// three fields are little-endian, or an empty string if it is not a GUID.
+func formatObjectGUID(b []byte) string {
+	if len(b) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:4]),
+		binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]),
+		b[8:10],
+		b[10:16],
+	)
+}
+
+// deletedObject returns the fields of a tombstone in the entries of
+// ldap_deleted_objects.
+func deletedObject(entry *ldap.Entry, attributes []string) (map[string]interface{}, error) {
+	deletedTime, err := formatGeneralizedTime(entry.GetAttributeValue("whenChanged"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid whenChanged of %q: %w", entry.DN, err)
+	}
+	values := map[string]interface{}{}
+	for _, attribute := range attributes {
+		if v := entry.GetEqualFoldAttributeValues(attribute); len(v) > 0 {
+			values[attribute] = strings.Join(sortValues(v), ",")
+		}
+	}
+	return map[string]interface{}{
+		"dn":                entry.DN,
+		"object_guid":       formatObjectGUID(entry.GetRawAttributeValue("objectGUID")),
+		"last_known_rdn":    entry.GetAttributeValue("msDS-LastKnownRDN"),
+		"last_known_parent": entry.GetAttributeValue("lastKnownParent"),
+		"deleted_time":      deletedTime,
+		"attributes":        values,
+	}, nil
+}
+
+func dataSourceLDAPDeletedObjectsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	baseDN, err := deletedObjectsBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := "(&" + d.Get("filter").(string) + "(isDeleted=TRUE))"
+	scopeStr := d.Get("scope").(string)
+	pagedSize := d.Get("paged_size").(int)
+	attributes := convertToStringSlice(d.Get("attributes").([]interface{}))
+
+	scope := ldap.ScopeSingleLevel
+	if scopeStr == "sub" {
+		scope = ldap.ScopeWholeSubtree
+	}
+
+	request := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		ldap.NeverDerefAliases,
+		0,
+		timeLimit(d, meta),
+		false,
+		filter,
+		append(append([]string{}, deletedObjectsAttributes...), attributes...),
+		[]ldap.Control{&ldap.ControlMicrosoftShowDeleted{}},
+	)
+
+	tflog.Debug(ctx, "searching deleted objects", map[string]interface{}{
+		"base_dn": baseDN,
+		"filter":  filter,
+		"scope":   scopeStr,
+	})
+
+	var sr *ldap.SearchResult
+	if pagedSize > 0 {
+		sr, err = conn.SearchWithPaging(request, uint32(pagedSize))
+	} else {
+		sr, err = conn.Search(request)
+	}
+	if err != nil {
+		return diag.Errorf("LDAP search failed: %v", err)
+	}
+
+	tflog.Debug(ctx, "found deleted objects", map[string]interface{}{"entries": len(sr.Entries)})
+
+	entries := make([]interface{}, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		object, err := deletedObject(entry, attributes)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		entries = append(entries, object)
+	}
+	if err := d.Set("entries", entries); err != nil {
+		return diag.Errorf("error setting entries: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", baseDN, filter, scopeStr)))))
+	return nil
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFormatObjectGUID(t *testing.T) {
+	guid := []byte{0x78, 0x56, 0x34, 0x12, 0x34, 0x12, 0x78, 0x56, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+	if got, want := formatObjectGUID(guid), "12345678-1234-5678-1234-56789abcdef0"; got != want {
+		t.Errorf("formatObjectGUID() = %q, want %q", got, want)
+	}
+	if got := formatObjectGUID([]byte("short")); got != "" {
+		t.Errorf("formatObjectGUID() of an invalid GUID = %q, want none", got)
+	}
+}
+
+func TestDeletedObject(t *testing.T) {
+	entry := ldap.NewEntry("CN=jdoe\\0ADEL:12345678-1234-5678-1234-56789abcdef0,CN=Deleted Objects,DC=example,DC=com", map[string][]string{
+		"msDS-LastKnownRDN": {"jdoe"},
+		"lastKnownParent":   {"OU=Users,DC=example,DC=com"},
+		"whenChanged":       {"20240101120000.0Z"},
+		"sAMAccountName":    {"jdoe"},
+	})
+	object, err := deletedObject(entry, []string{"samaccountname"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object["last_known_rdn"] != "jdoe" || object["last_known_parent"] != "OU=Users,DC=example,DC=com" || object["deleted_time"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("deletedObject() = %v", object)
+	}
+	if attributes := object["attributes"].(map[string]interface{}); attributes["samaccountname"] != "jdoe" {
+		t.Errorf("deletedObject() attributes = %v", attributes)
+	}
+}
+
+func TestAccDataSourceLDAPDeletedObjects(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPDeletedObjectsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_deleted_objects.test", "entries.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_deleted_objects.test", "entries.0.last_known_rdn", "tombstone"),
+					resource.TestCheckResourceAttr("data.ldap_deleted_objects.test", "entries.0.deleted_time", "2024-01-01T12:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+// the test server has no tombstones: they are faked with isDeleted
+const testAccDataSourceLDAPDeletedObjectsConfig = `
+resource "ldap_object" "deleted" {
+  dn             = "ou=deleted,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "tombstone" {
+  dn             = "cn=tombstone,${ldap_object.deleted.dn}"
+  object_classes = ["device"]
+  attributes = [
+    { isDeleted = "TRUE" },
+    { "msDS-LastKnownRDN" = "tombstone" },
+    { whenChanged = "20240101120000.0Z" },
+  ]
+}
+
+resource "ldap_object" "live" {
+  dn             = "cn=live,${ldap_object.deleted.dn}"
+  object_classes = ["device"]
+}
+
+data "ldap_deleted_objects" "test" {
+  base_dn = ldap_object.deleted.dn
+
+  depends_on = [ldap_object.tombstone, ldap_object.live]
+}
+`
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPDNExists() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPDNExistsRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Required:     true,
+				Description:  "The DN of the entry to look for.",
+			},
+			"exists": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the entry exists.",
+			},
+		},
+
+		Description: "Tells whether an entry exists, with a base search which does not fail when it does not, e.g. for " +
+			"the `count` of resources or the preconditions depending on the contents of the directory. Entries hidden by " +
+			"the access controls of the server are reported as missing.",
+	}
+}
+
+func dataSourceLDAPDNExistsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for entry", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntry(providerConfig.Connection, dn, []string{"1.1"}, providerConfig.DerefAliases)
+	if err != nil {
+		return diag.Errorf("error looking for %q: %v", dn, err)
+	}
+
+	d.SetId(dn)
+	d.Set("exists", entry != nil)
+	return nil
+}
@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPDNExists(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPDNExistsConfig("cn=admin,dc=example,dc=com"),
+				Check:  resource.TestCheckResourceAttr("data.ldap_dn_exists.test", "exists", "true"),
+			},
+			{
+				Config: testAccDataSourceLDAPDNExistsConfig("cn=missing,dc=example,dc=com"),
+				Check:  resource.TestCheckResourceAttr("data.ldap_dn_exists.test", "exists", "false"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceLDAPDNExistsConfig(dn string) string {
+	return fmt.Sprintf(`
+data "ldap_dn_exists" "test" {
+  dn = %q
+}
+`, dn)
+}
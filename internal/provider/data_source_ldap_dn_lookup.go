@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dnLookupPageSize is the page size of the searches of ldap_dn_lookup, which
+// may return more entries than the size limit of the server.
+const dnLookupPageSize = 500
+
+func dataSourceLDAPDNLookup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPDNLookupRead,
+
+		Schema: map[string]*schema.Schema{
+			"base_dn": searchBaseSchema("The DN under which the entries are looked up."),
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "sub",
+				Description:  "Search scope: base, one, or sub. Default: sub.",
+				ValidateFunc: validation.StringInSlice([]string{"base", "one", "sub"}, false),
+			},
+			"deref_aliases": derefAliasesSchema(),
+			"time_limit":    timeLimitSchema(),
+			"attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "uid",
+				Description: "The attribute holding the names, e.g. uid or sAMAccountName. Default: uid.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names to look up, e.g. user names.",
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An additional filter the entries must match, e.g. `(objectClass=person)`.",
+			},
+			"ignore_missing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Do not fail when names match no entry; they are listed in `missing` instead. Default: false.",
+			},
+			"dns": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DN of the entry of each name found, keyed by name.",
+			},
+			"dn_list": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DNs of the entries found, in the order of `names`.",
+			},
+			"missing": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names which match no entry.",
+			},
+		},
+
+		Description: "Looks up the DNs of entries by name (e.g. by uid or sAMAccountName) in a single search, so that " +
+			"modules can take user names rather than DNs, e.g. for the members of an `ldap_group`.\n\n" +
+			"Names are matched case-insensitively; a name matching several entries is an error.",
+	}
+}
+
+// dnLookupFilter returns the filter matching the entries whose attribute
+// holds any of the names, and the additional filter if any.
+func dnLookupFilter(attribute string, names []string, filter string) string {
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "(%s=%s)", attribute, ldap.EscapeFilter(name))
+	}
+	lookup := b.String()
+	if len(names) > 1 {
+		lookup = "(|" + lookup + ")"
+	}
+	if filter != "" {
+		lookup = "(&" + lookup + filter + ")"
+	}
+	return lookup
+}
+
+func dataSourceLDAPDNLookupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	baseDN, err := searchBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	attribute := d.Get("attribute").(string)
+	names := convertToStringSlice(d.Get("names").([]interface{}))
+
+	scope := ldap.ScopeWholeSubtree
+	switch d.Get("scope").(string) {
+	case "base":
+		scope = ldap.ScopeBaseObject
+	case "one":
+		scope = ldap.ScopeSingleLevel
+	}
+
+	// each name is looked up once, whatever its case
+	dns := map[string]string{}
+	byKey := map[string]string{}
+	for _, name := range names {
+		byKey[strings.ToLower(name)] = name
+	}
+
+	if len(byKey) > 0 {
+		unique := make([]string, 0, len(byKey))
+		for _, name := range byKey {
+			unique = append(unique, name)
+		}
+		filter := dnLookupFilter(attribute, sortValues(unique), d.Get("filter").(string))
+
+		tflog.Debug(ctx, "looking up DNs", map[string]interface{}{
+			"base_dn": baseDN,
+			"filter":  filter,
+		})
+
+		request := ldap.NewSearchRequest(baseDN, scope, derefAliases(d, meta), 0, timeLimit(d, meta), false, filter, []string{attribute}, nil)
+		sr, err := conn.SearchWithPaging(request, dnLookupPageSize)
+		if err != nil {
+			return diag.Errorf("error looking up DNs under %q: %v", baseDN, err)
+		}
+
+		for _, entry := range sr.Entries {
+			for _, value := range entry.GetEqualFoldAttributeValues(attribute) {
+				name, ok := byKey[strings.ToLower(value)]
+				if !ok {
+					continue
+				}
+				if dn, found := dns[name]; found && normalizeDN(dn) != normalizeDN(entry.DN) {
+					return diag.Errorf("%s %q matches several entries: %q and %q", attribute, name, dn, entry.DN)
+				}
+				dns[name] = entry.DN
+			}
+		}
+	}
+
+	dnList := []string{}
+	missing := []string{}
+	for _, name := range names {
+		if dn, ok := dns[byKey[strings.ToLower(name)]]; ok {
+			dnList = append(dnList, dn)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 && !d.Get("ignore_missing").(bool) {
+		return diag.Errorf("no entry found under %q for %s %s", baseDN, attribute, strings.Join(missing, ", "))
+	}
+
+	// report the DNs under the names as they were given
+	result := map[string]interface{}{}
+	for _, name := range names {
+		if dn, ok := dns[byKey[strings.ToLower(name)]]; ok {
+			result[name] = dn
+		}
+	}
+	if err := d.Set("dns", result); err != nil {
+		return diag.Errorf("error setting dns: %v", err)
+	}
+	if err := d.Set("dn_list", dnList); err != nil {
+		return diag.Errorf("error setting dn_list: %v", err)
+	}
+	if err := d.Set("missing", missing); err != nil {
+		return diag.Errorf("error setting missing: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(baseDN+attribute+strings.Join(names, ",")))))
+	return nil
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDNLookupFilter(t *testing.T) {
+	for _, tc := range []struct {
+		attribute string
+		names     []string
+		filter    string
+		expected  string
+	}{
+		{"uid", []string{"jdoe"}, "", "(uid=jdoe)"},
+		{"uid", []string{"jdoe", "asmith"}, "", "(|(uid=jdoe)(uid=asmith))"},
+		{"sAMAccountName", []string{"j*doe"}, "(objectClass=user)", `(&(sAMAccountName=j\2adoe)(objectClass=user))`},
+	} {
+		if filter := dnLookupFilter(tc.attribute, tc.names, tc.filter); filter != tc.expected {
+			t.Errorf("dnLookupFilter(%q, %q, %q) = %q, expected %q", tc.attribute, tc.names, tc.filter, filter, tc.expected)
+		}
+	}
+}
+
+func TestAccDataSourceLDAPDNLookup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPDNLookupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_dn_lookup.test", "dns.JDoe", "uid=jdoe,ou=lookup,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_dn_lookup.test", "dn_list.#", "2"),
+					resource.TestCheckResourceAttr("data.ldap_dn_lookup.test", "dn_list.1", "uid=asmith,ou=lookup,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_dn_lookup.test", "missing.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_dn_lookup.test", "missing.0", "nobody"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceLDAPDNLookupConfig = `
+resource "ldap_object" "lookup" {
+  dn             = "ou=lookup,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "jdoe" {
+  dn             = "uid=jdoe,${ldap_object.lookup.dn}"
+  object_classes = ["inetOrgPerson"]
+  attributes     = [{ cn = "John Doe" }, { sn = "Doe" }]
+}
+
+resource "ldap_object" "asmith" {
+  dn             = "uid=asmith,${ldap_object.lookup.dn}"
+  object_classes = ["inetOrgPerson"]
+  attributes     = [{ cn = "Alice Smith" }, { sn = "Smith" }]
+}
+
+data "ldap_dn_lookup" "test" {
+  base_dn        = ldap_object.lookup.dn
+  names          = ["JDoe", "asmith", "nobody"]
+  filter         = "(objectClass=inetOrgPerson)"
+  ignore_missing = true
+
+  depends_on = [ldap_object.jdoe, ldap_object.asmith]
+}
+`
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPGroupMemberDetails() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPGroupMemberDetailsRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Required:     true,
+				Description:  "The DN of the group.",
+			},
+			"member_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "member",
+				Description: "The attribute listing the DNs of the members, e.g. uniqueMember or roleOccupant. Default: member.",
+			},
+			"attributes": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The attributes of the members to read, e.g. uid, mail and displayName.",
+			},
+			"deref_aliases": derefAliasesSchema(),
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The members which exist, sorted by DN.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The DN of the member.",
+						},
+						"attributes": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The requested attributes of the member, multi-valued ones being sorted and comma-separated.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"missing": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DNs of the members which do not exist, e.g. deleted users still listed in the group, sorted.",
+			},
+		},
+
+		Description: "Reads the members of a group along with some of their attributes, e.g. the mail addresses of " +
+			"the members to notify, rather than looking each of them up with a data source of its own.\n\n" +
+			"The members are read like with `ldap_group_members`, then looked up with a few searches, by batches " +
+			"of members with the same parent.",
+	}
+}
+
+func dataSourceLDAPGroupMemberDetailsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	attribute := d.Get("member_attribute").(string)
+	attributes := convertToStringSlice(d.Get("attributes").([]interface{}))
+	deref := derefAliases(d, meta)
+
+	tflog.Debug(ctx, "reading group member details", map[string]interface{}{
+		"dn":         dn,
+		"attribute":  attribute,
+		"attributes": attributes,
+	})
+
+	members, err := readRangedValues(conn, dn, attribute, deref)
+	if err != nil {
+		return diag.Errorf("error reading the members of %q: %v", dn, err)
+	}
+	sort.Strings(members)
+
+	details := []interface{}{}
+	missing := []string{}
+	if len(members) > 0 {
+		entries, err := batchReadEntries(ctx, conn, members, attributes, deref)
+		if err != nil {
+			return diag.Errorf("error reading the members of %q: %v", dn, err)
+		}
+		for _, member := range members {
+			entry, ok := entries[normalizeDN(member)]
+			if !ok {
+				missing = append(missing, member)
+				continue
+			}
+			attrs := make(map[string]interface{}, len(entry.Attributes))
+			for _, attr := range entry.Attributes {
+				attrs[attr.Name] = strings.Join(sortValues(attr.Values), ",")
+			}
+			details = append(details, map[string]interface{}{
+				"dn":         member,
+				"attributes": attrs,
+			})
+		}
+	}
+
+	tflog.Debug(ctx, "read group member details", map[string]interface{}{
+		"dn":      dn,
+		"members": len(details),
+		"missing": len(missing),
+	})
+
+	if err := d.Set("members", details); err != nil {
+		return diag.Errorf("error setting members: %v", err)
+	}
+	if err := d.Set("missing", missing); err != nil {
+		return diag.Errorf("error setting missing: %v", err)
+	}
+	d.SetId(dn)
+	return nil
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPGroupMemberDetails_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPGroupMemberDetailsConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "members.#", "2"),
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "members.0.dn", "uid=testuser1,ou=users,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "members.0.attributes.mail", "testuser1@example.com"),
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "members.1.attributes.uid", "testuser2"),
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "missing.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_group_member_details.admins", "missing.0", "uid=gone,ou=users,dc=example,dc=com"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceLDAPGroupMemberDetailsConfig_basic = `
+resource "ldap_object" "users_ou" {
+  dn             = "ou=users,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "test_user1" {
+  dn             = "uid=testuser1,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User1" },
+    { cn = "Test User 1" },
+    { mail = "testuser1@example.com" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_object" "test_user2" {
+  dn             = "uid=testuser2,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User2" },
+    { cn = "Test User 2" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_group" "admins" {
+  dn             = "cn=admins,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  member = [
+    ldap_object.test_user1.dn,
+    ldap_object.test_user2.dn,
+    "uid=gone,ou=users,dc=example,dc=com",
+  ]
+}
+
+data "ldap_group_member_details" "admins" {
+  dn         = ldap_group.admins.dn
+  attributes = ["uid", "mail"]
+}
+`
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPGroupMembersRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Required:     true,
+				Description:  "The DN of the group.",
+			},
+			"member_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "member",
+				Description: "The attribute listing the DNs of the members, e.g. uniqueMember or roleOccupant. Default: member.",
+			},
+			"resolve_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An attribute of the members to read, e.g. uid, cn or mail, returned in `resolved`.",
+			},
+			"deref_aliases": derefAliasesSchema(),
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DNs of the members, sorted.",
+			},
+			"resolved": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The value of `resolve_attribute` of each member, keyed by DN; members which do not exist or have no value are missing.",
+			},
+		},
+
+		Description: "Reads the members of a group, e.g. to iterate over them with for_each.\n\n" +
+			"Groups too large for the server to return all their members at once, such as Active Directory groups " +
+			"of more than 1500 members, are read in ranges (`member;range=0-1499`...). The members are resolved with " +
+			"a few searches, by batches of members with the same parent.",
+	}
+}
+
+func dataSourceLDAPGroupMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	attribute := d.Get("member_attribute").(string)
+	deref := derefAliases(d, meta)
+
+	tflog.Debug(ctx, "reading group members", map[string]interface{}{"dn": dn, "attribute": attribute})
+
+	members, err := readRangedValues(conn, dn, attribute, deref)
+	if err != nil {
+		return diag.Errorf("error reading the members of %q: %v", dn, err)
+	}
+	sort.Strings(members)
+
+	resolved := map[string]interface{}{}
+	if resolveAttribute := d.Get("resolve_attribute").(string); resolveAttribute != "" && len(members) > 0 {
+		entries, err := batchReadEntries(ctx, conn, members, []string{resolveAttribute}, deref)
+		if err != nil {
+			return diag.Errorf("error resolving the members of %q: %v", dn, err)
+		}
+		for _, member := range members {
+			entry, ok := entries[normalizeDN(member)]
+			if !ok {
+				continue
+			}
+			if value := entry.GetEqualFoldAttributeValue(resolveAttribute); value != "" {
+				resolved[member] = value
+			}
+		}
+	}
+
+	tflog.Debug(ctx, "read group members", map[string]interface{}{
+		"dn":       dn,
+		"members":  len(members),
+		"resolved": len(resolved),
+	})
+
+	if err := d.Set("members", members); err != nil {
+		return diag.Errorf("error setting members: %v", err)
+	}
+	if err := d.Set("resolved", resolved); err != nil {
+		return diag.Errorf("error setting resolved: %v", err)
+	}
+	d.SetId(dn)
+	return nil
+}
+
+// rangePattern matches the range option of the attributes of Active
+// Directory, e.g. member;range=1500-2999, or member;range=3000-* for the
+// last range.
+var rangePattern = regexp.MustCompile(`(?i)^([^;]+);range=(\d+)-(\d+|\*)$`)
+
+// readRangedValues reads all the values of an attribute of an entry, in
+// several searches if the server returns them in ranges.
+func readRangedValues(conn client.Client, dn, attribute string, derefAliases int) ([]string, error) {
+	var values []string
+	requested := attribute
+	for {
+		entry, err := searchEntry(conn, dn, []string{requested}, derefAliases)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("no such entry")
+		}
+		vals, next, more := rangedValues(entry, attribute)
+		values = append(values, vals...)
+		if !more {
+			return values, nil
+		}
+		requested = fmt.Sprintf("%s;range=%d-*", attribute, next)
+	}
+}
+
+// rangedValues returns the values of an attribute of an entry, and, if they
+// are only a range of them, the start of the next range.
+func rangedValues(entry *ldap.Entry, attribute string) ([]string, int, bool) {
+	for _, attr := range entry.Attributes {
+		m := rangePattern.FindStringSubmatch(attr.Name)
+		if m == nil || !strings.EqualFold(m[1], attribute) {
+			continue
+		}
+		if m[3] == "*" {
+			return attr.Values, 0, false
+		}
+		end, _ := strconv.Atoi(m[3])
+		return attr.Values, end + 1, true
+	}
+	return entry.GetEqualFoldAttributeValues(attribute), 0, false
+}
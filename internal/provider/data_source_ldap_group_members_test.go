@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestRangedValues(t *testing.T) {
+	for name, test := range map[string]struct {
+		attribute string
+		values    []string
+		next      int
+		more      bool
+	}{
+		"member":                 {"member", []string{"a", "b"}, 0, false},
+		"member;range=0-1499":    {"member;range=0-1499", []string{"a", "b"}, 1500, true},
+		"Member;Range=1500-*":    {"Member;Range=1500-*", []string{"a", "b"}, 0, false},
+		"uniqueMember;range=0-9": {"uniqueMember;range=0-9", nil, 0, false},
+	} {
+		entry := ldap.NewEntry("cn=group,dc=example,dc=com", map[string][]string{test.attribute: {"a", "b"}})
+		values, next, more := rangedValues(entry, "member")
+		if len(values) != len(test.values) || next != test.next || more != test.more {
+			t.Errorf("%s: got %v, %d, %t, expected %v, %d, %t", name, values, next, more, test.values, test.next, test.more)
+		}
+	}
+}
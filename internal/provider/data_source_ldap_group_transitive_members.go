@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLDAPGroupTransitiveMembers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPGroupTransitiveMembersRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Required:     true,
+				Description:  "The DN of the group.",
+			},
+			"member_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "member",
+				Description: "The attribute listing the DNs of the members of the group and of the nested groups, e.g. uniqueMember. Default: member.",
+			},
+			"group_object_classes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The object classes telling nested groups apart from the other members. Default: group, groupOfNames and groupOfUniqueNames.",
+			},
+			"max_depth": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum nesting depth of the groups; the read fails if groups are nested deeper. Default: 10.",
+			},
+			"deref_aliases": derefAliasesSchema(),
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DNs of the members of the group and of its nested groups which are not groups themselves, e.g. users, sorted. Members which do not exist are left out.",
+			},
+			"groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The DNs of the nested groups, sorted.",
+			},
+		},
+
+		Description: "Reads the effective members of a group, expanding its nested groups recursively, e.g. to grant " +
+			"access to every user of a group of groups.\n\n" +
+			"Each level of nesting is read with a few searches, by batches of members with the same parent; " +
+			"groups which are members of themselves, directly or not, are only expanded once. " +
+			"On Active Directory, the `member_of_in_chain` argument of `ldap_search` lets the server expand them instead.",
+	}
+}
+
+// defaultGroupObjectClassNames are the object classes of nested groups by
+// default.
+var defaultGroupObjectClassNames = []string{"group", "groupOfNames", "groupOfUniqueNames"}
+
+func dataSourceLDAPGroupTransitiveMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	attribute := d.Get("member_attribute").(string)
+	maxDepth := d.Get("max_depth").(int)
+	deref := derefAliases(d, meta)
+
+	groupClasses := defaultGroupObjectClassNames
+	if v, ok := d.GetOk("group_object_classes"); ok {
+		groupClasses = convertToStringSlice(v.([]interface{}))
+	}
+
+	tflog.Debug(ctx, "reading transitive group members", map[string]interface{}{
+		"dn":        dn,
+		"attribute": attribute,
+		"max_depth": maxDepth,
+	})
+
+	direct, err := readRangedValues(conn, dn, attribute, deref)
+	if err != nil {
+		return diag.Errorf("error reading the members of %q: %v", dn, err)
+	}
+
+	seen := map[string]bool{normalizeDN(dn): true}
+	members := []string{}
+	groups := []string{}
+	candidates := direct
+	for depth := 1; len(candidates) > 0; depth++ {
+		var unseen []string
+		for _, candidate := range candidates {
+			if !seen[normalizeDN(candidate)] {
+				seen[normalizeDN(candidate)] = true
+				unseen = append(unseen, candidate)
+			}
+		}
+		if len(unseen) == 0 {
+			break
+		}
+
+		entries, err := batchReadEntries(ctx, conn, unseen, []string{"objectClass", attribute}, deref)
+		if err != nil {
+			return diag.Errorf("error reading the members of %q: %v", dn, err)
+		}
+
+		var nested []string
+		for _, member := range unseen {
+			entry, ok := entries[normalizeDN(member)]
+			if !ok {
+				tflog.Debug(ctx, "skipping missing member", map[string]interface{}{"dn": member})
+				continue
+			}
+			if !hasObjectClass(entry.GetEqualFoldAttributeValues("objectClass"), groupClasses) {
+				members = append(members, member)
+				continue
+			}
+			if depth > maxDepth {
+				return diag.Errorf("the groups of %q are nested deeper than max_depth (%d), e.g. %q", dn, maxDepth, member)
+			}
+			groups = append(groups, member)
+			values, _, more := rangedValues(entry, attribute)
+			if more {
+				// the members of large groups are read in ranges
+				if values, err = readRangedValues(conn, member, attribute, deref); err != nil {
+					return diag.Errorf("error reading the members of %q: %v", member, err)
+				}
+			}
+			nested = append(nested, values...)
+		}
+		candidates = nested
+	}
+	sort.Strings(members)
+	sort.Strings(groups)
+
+	tflog.Debug(ctx, "read transitive group members", map[string]interface{}{
+		"dn":      dn,
+		"members": len(members),
+		"groups":  len(groups),
+	})
+
+	if err := d.Set("members", members); err != nil {
+		return diag.Errorf("error setting members: %v", err)
+	}
+	if err := d.Set("groups", groups); err != nil {
+		return diag.Errorf("error setting groups: %v", err)
+	}
+	d.SetId(dn)
+	return nil
+}
+
+// hasObjectClass tells whether any of the object classes of an entry is one
+// of the given ones, ignoring case.
+func hasObjectClass(objectClasses, classes []string) bool {
+	for _, oc := range objectClasses {
+		if containsFold(classes, oc) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPGroupTransitiveMembers_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPGroupTransitiveMembersConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_group_transitive_members.all", "members.#", "2"),
+					resource.TestCheckResourceAttr("data.ldap_group_transitive_members.all", "members.0", "uid=testuser1,ou=users,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_group_transitive_members.all", "members.1", "uid=testuser2,ou=users,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_group_transitive_members.all", "groups.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_group_transitive_members.all", "groups.0", "cn=nested,dc=example,dc=com"),
+				),
+			},
+		},
+	})
+}
+
+// the nested group is a member of the top group and the other way around
+const testAccDataSourceLDAPGroupTransitiveMembersConfig_basic = `
+resource "ldap_object" "users_ou" {
+  dn             = "ou=users,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "test_user1" {
+  dn             = "uid=testuser1,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User1" },
+    { cn = "Test User 1" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_object" "test_user2" {
+  dn             = "uid=testuser2,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User2" },
+    { cn = "Test User 2" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_group" "nested" {
+  dn             = "cn=nested,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  member = [
+    ldap_object.test_user2.dn,
+    "cn=top,dc=example,dc=com",
+  ]
+}
+
+resource "ldap_group" "top" {
+  dn             = "cn=top,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  member = [
+    ldap_object.test_user1.dn,
+    ldap_group.nested.dn,
+  ]
+}
+
+data "ldap_group_transitive_members" "all" {
+  dn = ldap_group.top.dn
+}
+`
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/adtime"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// lockedAccountsFilter matches the accounts locked by the ppolicy overlay of
+// OpenLDAP or locked out by Active Directory; each directory ignores the
+// attribute of the other.
+var lockedAccountsFilter = fmt.Sprintf("(|(%s=*)(%s>=1))", accountLockedTimeAttribute, lockoutTimeAttribute)
+
+func dataSourceLDAPLockedAccounts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPLockedAccountsRead,
+
+		Schema: map[string]*schema.Schema{
+			"base_dn": searchBaseSchema("The base DN to look for the locked accounts under."),
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "(objectClass=*)",
+				Description: "An LDAP filter the locked accounts must match too, e.g. \"(objectClass=person)\". Default: (objectClass=*).",
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "sub",
+				Description:  "Search scope: one, or sub. Default: sub.",
+				ValidateFunc: validation.StringInSlice([]string{"one", "sub"}, false),
+			},
+			"deref_aliases": derefAliasesSchema(),
+			"time_limit":    timeLimitSchema(),
+			"paged_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "LDAP paged search size. Set to 0 to disable pagination and use a single search request.",
+			},
+			"dns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The DNs of the locked accounts.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"accounts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The locked accounts, in the order of dns.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The DN of the account.",
+						},
+						"locked_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Description: "When the account was locked (`pwdAccountLockedTime`) or locked out (`lockoutTime`), " +
+								"as an RFC 3339 timestamp; empty for the accounts locked until an administrator unlocks them.",
+						},
+						"permanent": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the account is locked until an administrator unlocks it (`pwdAccountLockedTime` 000001010000Z), rather than for the lockout duration of the policy.",
+						},
+						"failure_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of failed binds recorded in `pwdFailureTime`; always 0 on Active Directory.",
+						},
+					},
+				},
+			},
+		},
+
+		Description: "Lists the accounts currently locked under a base DN, either by the ppolicy overlay of OpenLDAP " +
+			"(`pwdAccountLockedTime` set) or by Active Directory (`lockoutTime` other than 0), e.g. to drive alerting " +
+			"or `ldap_account_lock` and `ldap_ad_account` resources unlocking them.",
+	}
+}
+
+// lockedAccount returns the fields of an entry of the accounts of
+// ldap_locked_accounts.
+func lockedAccount(entry *ldap.Entry) (map[string]interface{}, error) {
+	account := map[string]interface{}{
+		"dn":            entry.DN,
+		"locked_time":   "",
+		"permanent":     false,
+		"failure_count": len(entry.GetAttributeValues(accountFailureTimeAttribute)),
+	}
+	var err error
+	switch lockedTime := entry.GetAttributeValue(accountLockedTimeAttribute); {
+	case lockedTime == accountLockedPermanently:
+		account["permanent"] = true
+	case lockedTime != "":
+		account["locked_time"], err = formatGeneralizedTime(lockedTime)
+	default:
+		account["locked_time"], err = adtime.Format(entry.GetAttributeValue(lockoutTimeAttribute))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid lock time of %q: %w", entry.DN, err)
+	}
+	return account, nil
+}
+
+func dataSourceLDAPLockedAccountsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	baseDN, err := searchBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := "(&" + d.Get("filter").(string) + lockedAccountsFilter + ")"
+	scopeStr := d.Get("scope").(string)
+	pagedSize := d.Get("paged_size").(int)
+
+	scope := ldap.ScopeWholeSubtree
+	if scopeStr == "one" {
+		scope = ldap.ScopeSingleLevel
+	}
+
+	request := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		derefAliases(d, meta),
+		0,
+		timeLimit(d, meta),
+		false,
+		filter,
+		[]string{accountLockedTimeAttribute, accountFailureTimeAttribute, lockoutTimeAttribute},
+		nil,
+	)
+
+	tflog.Debug(ctx, "looking for locked accounts", map[string]interface{}{
+		"base_dn": baseDN,
+		"filter":  filter,
+		"scope":   scopeStr,
+	})
+
+	var sr *ldap.SearchResult
+	if pagedSize > 0 {
+		sr, err = conn.SearchWithPaging(request, uint32(pagedSize))
+	} else {
+		sr, err = conn.Search(request)
+	}
+	if err != nil {
+		return diag.Errorf("LDAP search failed: %v", err)
+	}
+
+	tflog.Debug(ctx, "found locked accounts", map[string]interface{}{"entries": len(sr.Entries)})
+
+	dns := make([]string, 0, len(sr.Entries))
+	accounts := make([]interface{}, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		account, err := lockedAccount(entry)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		dns = append(dns, entry.DN)
+		accounts = append(accounts, account)
+	}
+	if err := d.Set("dns", dns); err != nil {
+		return diag.Errorf("error setting dns: %v", err)
+	}
+	if err := d.Set("accounts", accounts); err != nil {
+		return diag.Errorf("error setting accounts: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", baseDN, filter, scopeStr)))))
+	return nil
+}
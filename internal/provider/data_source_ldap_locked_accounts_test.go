@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestLockedAccount(t *testing.T) {
+	for _, tc := range []struct {
+		attributes map[string][]string
+		lockedTime string
+		permanent  bool
+		failures   int
+	}{
+		{map[string][]string{"pwdAccountLockedTime": {"20240101120000Z"}, "pwdFailureTime": {"20240101115900Z", "20240101120000Z"}}, "2024-01-01T12:00:00Z", false, 2},
+		{map[string][]string{"pwdAccountLockedTime": {"000001010000Z"}}, "", true, 0},
+		{map[string][]string{"lockoutTime": {"133485408000000000"}}, "2024-01-01T00:00:00Z", false, 0},
+	} {
+		account, err := lockedAccount(ldap.NewEntry("uid=user,dc=example,dc=com", tc.attributes))
+		if err != nil {
+			t.Fatalf("lockedAccount(%v) error = %v", tc.attributes, err)
+		}
+		if account["locked_time"] != tc.lockedTime || account["permanent"] != tc.permanent || account["failure_count"] != tc.failures {
+			t.Errorf("lockedAccount(%v) = %v", tc.attributes, account)
+		}
+	}
+}
+
+func TestAccDataSourceLDAPLockedAccounts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPLockedAccountsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_locked_accounts.test", "dns.#", "2"),
+					resource.TestCheckTypeSetElemAttr("data.ldap_locked_accounts.test", "dns.*", "uid=ppolicylocked,dc=example,dc=com"),
+					resource.TestCheckTypeSetElemAttr("data.ldap_locked_accounts.test", "dns.*", "uid=adlocked,dc=example,dc=com"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.ldap_locked_accounts.test", "accounts.*", map[string]string{
+						"dn":          "uid=ppolicylocked,dc=example,dc=com",
+						"locked_time": "",
+						"permanent":   "true",
+					}),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceLDAPLockedAccountsConfig = `
+resource "ldap_object" "ppolicy_locked" {
+  dn             = "uid=ppolicylocked,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "Locked" },
+    { cn = "PPolicy Locked" },
+    { pwdAccountLockedTime = "000001010000Z" },
+  ]
+}
+
+resource "ldap_object" "ad_locked" {
+  dn             = "uid=adlocked,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "Locked" },
+    { cn = "AD Locked" },
+    { lockoutTime = "133485408000000000" },
+  ]
+}
+
+resource "ldap_object" "unlocked" {
+  dn             = "uid=unlocked,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "Unlocked" },
+    { cn = "Unlocked" },
+    { lockoutTime = "0" },
+  ]
+}
+
+data "ldap_locked_accounts" "test" {
+  base_dn = "dc=example,dc=com"
+  filter  = "(objectClass=inetOrgPerson)"
+
+  depends_on = [ldap_object.ppolicy_locked, ldap_object.ad_locked, ldap_object.unlocked]
+}
+`
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// monitorMetric binds a field of ldap_monitor to the attribute of the
+// cn=monitor entry holding it; RDN is the DN of the entry relative to the
+// base of the monitor backend.
+type monitorMetric struct {
+	Field     string
+	RDN       string
+	Attribute string
+}
+
+// monitorMetrics are the metrics of the OpenLDAP monitor backend exposed by
+// ldap_monitor.
+var monitorMetrics = []monitorMetric{
+	{Field: "current_connections", RDN: "cn=Current,cn=Connections", Attribute: "monitorCounter"},
+	{Field: "total_connections", RDN: "cn=Total,cn=Connections", Attribute: "monitorCounter"},
+	{Field: "operations_initiated", RDN: "cn=Operations", Attribute: "monitorOpInitiated"},
+	{Field: "operations_completed", RDN: "cn=Operations", Attribute: "monitorOpCompleted"},
+	{Field: "max_threads", RDN: "cn=Max,cn=Threads", Attribute: "monitoredInfo"},
+	{Field: "active_threads", RDN: "cn=Active,cn=Threads", Attribute: "monitoredInfo"},
+	{Field: "uptime", RDN: "cn=Uptime,cn=Time", Attribute: "monitoredInfo"},
+}
+
+func dataSourceLDAPMonitor() *schema.Resource {
+	metric := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: description,
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPMonitorRead,
+
+		Schema: map[string]*schema.Schema{
+			"base_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "cn=Monitor",
+				Description: "The DN of the monitor backend. Default: cn=Monitor.",
+			},
+			"version":              {Type: schema.TypeString, Computed: true, Description: "The version of the server."},
+			"current_connections":  metric("The number of open connections."),
+			"total_connections":    metric("The number of connections opened since the server started."),
+			"operations_initiated": metric("The number of operations initiated since the server started."),
+			"operations_completed": metric("The number of operations completed since the server started."),
+			"max_threads":          metric("The maximum number of worker threads."),
+			"active_threads":       metric("The number of busy worker threads."),
+			"uptime":               metric("The number of seconds since the server started."),
+		},
+
+		Description: "Reads the key metrics of the OpenLDAP monitor backend (`cn=Monitor`), so that health checks " +
+			"and capacity dashboards can use them, e.g. in outputs or preconditions.\n\n" +
+			"The monitor backend must be enabled, and readable by the bind DN of the provider. Metrics the server " +
+			"does not publish are 0.",
+	}
+}
+
+// readMonitorMetrics returns the value of each metric found in the entries of
+// the monitor backend, keyed by field.
+func readMonitorMetrics(baseDN string, entries []*ldap.Entry) (map[string]int, error) {
+	byDN := map[string]*ldap.Entry{}
+	for _, entry := range entries {
+		byDN[normalizeDN(entry.DN)] = entry
+	}
+
+	metrics := map[string]int{}
+	for _, metric := range monitorMetrics {
+		entry, ok := byDN[normalizeDN(metric.RDN+","+baseDN)]
+		if !ok {
+			continue
+		}
+		value := entry.GetEqualFoldAttributeValue(metric.Attribute)
+		if value == "" {
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert %s value %q of %q to int: %w", metric.Attribute, value, entry.DN, err)
+		}
+		metrics[metric.Field] = i
+	}
+	return metrics, nil
+}
+
+func dataSourceLDAPMonitorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	conn := meta.(*ProviderConfig).Connection
+	baseDN := d.Get("base_dn").(string)
+
+	tflog.Debug(ctx, "reading monitor metrics", map[string]interface{}{"base_dn": baseDN})
+
+	// the metrics are operational attributes, which must be requested by name
+	request := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		[]string{"monitoredInfo", "monitorCounter", "monitorOpInitiated", "monitorOpCompleted"},
+		nil,
+	)
+	sr, err := conn.Search(request)
+	if err != nil {
+		return diag.Errorf("error reading the monitor backend %q: %v", baseDN, err)
+	}
+
+	metrics, err := readMonitorMetrics(baseDN, sr.Entries)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, metric := range monitorMetrics {
+		if err := d.Set(metric.Field, metrics[metric.Field]); err != nil {
+			return diag.Errorf("error setting %s: %v", metric.Field, err)
+		}
+	}
+
+	version := ""
+	for _, entry := range sr.Entries {
+		if normalizeDN(entry.DN) == normalizeDN(baseDN) {
+			version = entry.GetEqualFoldAttributeValue("monitoredInfo")
+		}
+	}
+	d.Set("version", version)
+
+	d.SetId(baseDN)
+	return nil
+}
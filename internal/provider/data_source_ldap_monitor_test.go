@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestReadMonitorMetrics(t *testing.T) {
+	entries := []*ldap.Entry{
+		ldap.NewEntry("cn=Monitor", map[string][]string{"monitoredInfo": {"OpenLDAP: slapd 2.6.7"}}),
+		ldap.NewEntry("cn=Current,cn=Connections,cn=Monitor", map[string][]string{"monitorCounter": {"12"}}),
+		ldap.NewEntry("CN=Total, CN=Connections, CN=Monitor", map[string][]string{"monitorCounter": {"3456"}}),
+		ldap.NewEntry("cn=Operations,cn=Monitor", map[string][]string{
+			"monitorOpInitiated": {"1002"},
+			"monitorOpCompleted": {"1001"},
+		}),
+		ldap.NewEntry("cn=Max,cn=Threads,cn=Monitor", map[string][]string{"monitoredInfo": {"16"}}),
+	}
+
+	metrics, err := readMonitorMetrics("cn=Monitor", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{
+		"current_connections":  12,
+		"total_connections":    3456,
+		"operations_initiated": 1002,
+		"operations_completed": 1001,
+		"max_threads":          16,
+	}
+	if !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("readMonitorMetrics() = %v, expected %v", metrics, expected)
+	}
+
+	invalid := []*ldap.Entry{
+		ldap.NewEntry("cn=Uptime,cn=Time,cn=Monitor", map[string][]string{"monitoredInfo": {"a while"}}),
+	}
+	if _, err := readMonitorMetrics("cn=Monitor", invalid); err == nil {
+		t.Error("expected an error for a non-numeric metric")
+	}
+}
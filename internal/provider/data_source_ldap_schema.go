@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPSchema() *schema.Resource {
+	stringList := &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPSchemaRead,
+
+		Schema: map[string]*schema.Schema{
+			"include_object_classes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only return these object classes (by name or OID) and the attribute types they reference. Default: the whole schema.",
+			},
+			"subschema_dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The DN of the subschema subentry the schema was read from.",
+			},
+			"object_classes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The object classes defined by the server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":        {Type: schema.TypeString, Computed: true, Description: "The primary name of the object class."},
+						"names":       stringList,
+						"oid":         {Type: schema.TypeString, Computed: true, Description: "The OID of the object class."},
+						"description": {Type: schema.TypeString, Computed: true, Description: "The description of the object class."},
+						"kind":        {Type: schema.TypeString, Computed: true, Description: "STRUCTURAL, AUXILIARY or ABSTRACT."},
+						"obsolete":    {Type: schema.TypeBool, Computed: true, Description: "Whether the object class is obsolete."},
+						"superior":    stringList,
+						"must":        stringList,
+						"may":         stringList,
+					},
+				},
+			},
+			"attribute_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The attribute types defined by the server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":                 {Type: schema.TypeString, Computed: true, Description: "The primary name of the attribute type."},
+						"names":                stringList,
+						"oid":                  {Type: schema.TypeString, Computed: true, Description: "The OID of the attribute type."},
+						"description":          {Type: schema.TypeString, Computed: true, Description: "The description of the attribute type."},
+						"superior":             {Type: schema.TypeString, Computed: true, Description: "The attribute type this one derives from."},
+						"syntax":               {Type: schema.TypeString, Computed: true, Description: "The OID of the attribute syntax."},
+						"equality":             {Type: schema.TypeString, Computed: true, Description: "The equality matching rule."},
+						"ordering":             {Type: schema.TypeString, Computed: true, Description: "The ordering matching rule."},
+						"substring":            {Type: schema.TypeString, Computed: true, Description: "The substrings matching rule."},
+						"single_value":         {Type: schema.TypeBool, Computed: true, Description: "Whether the attribute is single-valued."},
+						"no_user_modification": {Type: schema.TypeBool, Computed: true, Description: "Whether the attribute is maintained by the server only."},
+						"usage":                {Type: schema.TypeString, Computed: true, Description: "userApplications, or one of the operational usages."},
+					},
+				},
+			},
+		},
+
+		Description: "Reads the server schema from its subschema subentry, exposing object classes with their " +
+			"MUST/MAY attributes and attribute types with their syntaxes and matching rules.",
+	}
+}
+
+func dataSourceLDAPSchemaRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	conn := providerConfig.Connection
+
+	s, dn, err := readSubschema(ctx, conn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	include := convertToStringSlice(d.Get("include_object_classes").([]interface{}))
+
+	tflog.Debug(ctx, "read object classes and attribute types", map[string]interface{}{
+		"object_classes":  len(s.ObjectClasses),
+		"attribute_types": len(s.AttributeTypes),
+		"dn":              dn,
+	})
+
+	objectClasses := s.ObjectClasses
+	attributeTypes := s.AttributeTypes
+	if len(include) > 0 {
+		objectClasses = objectClasses[:0:0]
+		attributeTypes = attributeTypes[:0:0]
+		seen := map[string]bool{}
+		for _, name := range include {
+			oc := s.ObjectClass(name)
+			if oc == nil {
+				return diag.Errorf("object class %q is not defined in the server schema", name)
+			}
+			objectClasses = append(objectClasses, oc)
+			for _, attribute := range append(append([]string{}, oc.Must...), oc.May...) {
+				at := s.AttributeType(attribute)
+				if at == nil || seen[at.OID] {
+					continue
+				}
+				seen[at.OID] = true
+				attributeTypes = append(attributeTypes, at)
+			}
+		}
+	}
+
+	ocs := make([]interface{}, len(objectClasses))
+	for i, oc := range objectClasses {
+		ocs[i] = map[string]interface{}{
+			"name":        oc.Name(),
+			"names":       oc.Names,
+			"oid":         oc.OID,
+			"description": oc.Description,
+			"kind":        oc.Kind,
+			"obsolete":    oc.Obsolete,
+			"superior":    oc.Superior,
+			"must":        oc.Must,
+			"may":         oc.May,
+		}
+	}
+	ats := make([]interface{}, len(attributeTypes))
+	for i, at := range attributeTypes {
+		ats[i] = map[string]interface{}{
+			"name":                 at.Name(),
+			"names":                at.Names,
+			"oid":                  at.OID,
+			"description":          at.Description,
+			"superior":             at.Superior,
+			"syntax":               at.Syntax,
+			"equality":             at.Equality,
+			"ordering":             at.Ordering,
+			"substring":            at.Substring,
+			"single_value":         at.SingleValue,
+			"no_user_modification": at.NoUserModification,
+			"usage":                at.Usage,
+		}
+	}
+
+	if err := d.Set("subschema_dn", dn); err != nil {
+		return diag.Errorf("error setting subschema_dn: %v", err)
+	}
+	if err := d.Set("object_classes", ocs); err != nil {
+		return diag.Errorf("error setting object_classes: %v", err)
+	}
+	if err := d.Set("attribute_types", ats); err != nil {
+		return diag.Errorf("error setting attribute_types: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(dn+"|"+strings.Join(include, ",")))))
+	return nil
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLDAPSchema_includeObjectClasses(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPSchemaConfig_person,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.ldap_schema.test", "subschema_dn"),
+					resource.TestCheckResourceAttr("data.ldap_schema.test", "object_classes.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_schema.test", "object_classes.0.name", "person"),
+					resource.TestCheckResourceAttr("data.ldap_schema.test", "object_classes.0.kind", "STRUCTURAL"),
+					resource.TestCheckTypeSetElemAttr("data.ldap_schema.test", "object_classes.0.must.*", "sn"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceLDAPSchemaConfig_person = `
+data "ldap_schema" "test" {
+  include_object_classes = ["person"]
+}
+`
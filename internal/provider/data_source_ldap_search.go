@@ -1,26 +1,26 @@
 package provider
 
 import (
+	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceLDAPSearch() *schema.Resource {
-	return &schema.Resource{
-		Read: dataSourceLDAPSearchRead,
+	r := &schema.Resource{
+		ReadContext: dataSourceLDAPSearchRead,
 
 		Schema: map[string]*schema.Schema{
-			"base_dn": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The base DN to start the search from.",
-			},
+			"base_dn": searchBaseSchema("The base DN to start the search from."),
 			"filter": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -33,12 +33,32 @@ func dataSourceLDAPSearch() *schema.Resource {
 				Description:  "Search scope: base, one, or sub. Default: sub.",
 				ValidateFunc: validation.StringInSlice([]string{"base", "one", "sub"}, false),
 			},
+			"member_of_in_chain": memberOfInChainSchema(),
+			"deref_aliases":      derefAliasesSchema(),
+			"time_limit":         timeLimitSchema(),
 			"attributes": {
 				Type:        schema.TypeList,
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "Specific attributes to retrieve. Default: all attributes.",
 			},
+			"key_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An attribute whose first value identifies the entries, e.g. uid, cn or mail, to return them in `dns_by_key` and `attributes_by_key` too; the search fails if two entries have the same value. Entries without value are left out of the maps.",
+			},
+			"dns_by_key": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The DNs of the entries, keyed by the value of `key_attribute`, e.g. for for_each.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"attributes_by_key": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The attributes of the entries as JSON objects of lists of values, keyed by the value of `key_attribute`; use jsondecode to read them.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"entries": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -53,7 +73,7 @@ func dataSourceLDAPSearch() *schema.Resource {
 						"attributes": {
 							Type:        schema.TypeMap,
 							Computed:    true,
-							Description: "Map of attribute names to their values (comma-separated for multi-valued).",
+							Description: "Map of attribute names to their values (sorted and comma-separated for multi-valued).",
 							Elem:        &schema.Schema{Type: schema.TypeString},
 						},
 					},
@@ -64,14 +84,23 @@ func dataSourceLDAPSearch() *schema.Resource {
 		Description: "Searches for LDAP objects and returns an ordered list of entries.\n\n" +
 			"Prefer this data source for simple iteration or when no unique key attribute can be guaranteed. " +
 			"Note that multi-valued attributes are joined as comma-separated strings.\n\n" +
-			"Use `ldap_search_map` instead when you need keyed lookups, pagination, or lossless multi-valued attributes.",
+			"Set `key_attribute` to get the entries keyed by an attribute too, e.g. for for_each.\n\n" +
+			"Use `ldap_search_map` instead when you need pagination.",
+	}
+	for name, s := range searchWindowSchema() {
+		r.Schema[name] = s
 	}
+	return r
 }
 
-func dataSourceLDAPSearchRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceLDAPSearchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
 	// 1. Extract parameters
-	baseDN := d.Get("base_dn").(string)
-	filter := d.Get("filter").(string)
+	baseDN, err := searchBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := searchFilter(d)
 	scopeStr := d.Get("scope").(string)
 
 	// 2. Convert scope string to LDAP scope constant
@@ -94,40 +123,53 @@ func dataSourceLDAPSearchRead(d *schema.ResourceData, meta interface{}) error {
 			attributes[i] = attr.(string)
 		}
 	}
+	// the key attribute is needed even if not requested
+	if key := d.Get("key_attribute").(string); key != "" && !containsFold(attributes, key) && !containsFold(attributes, "*") {
+		attributes = append(attributes, key)
+	}
 
 	// 4. Get LDAP connection
 	providerConfig := meta.(*ProviderConfig)
 	conn := providerConfig.Connection
 
+	controls, _, err := searchWindowControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	// 5. Build and execute search request
 	request := ldap.NewSearchRequest(
 		baseDN,
 		scope,
-		ldap.NeverDerefAliases,
-		0,     // no size limit
-		0,     // no time limit
+		derefAliases(d, meta),
+		0, // no size limit
+		timeLimit(d, meta),
 		false, // return attribute values, not just names
 		filter,
 		attributes,
-		nil, // no controls
+		controls,
 	)
 
-	log.Printf("[DEBUG] ldap_search::read - searching base_dn=%q, filter=%q, scope=%d", baseDN, filter, scope)
+	tflog.Debug(ctx, "searching", map[string]interface{}{
+		"base_dn": baseDN,
+		"filter":  filter,
+		"scope":   scope,
+	})
 
 	sr, err := conn.Search(request)
 	if err != nil {
-		return fmt.Errorf("LDAP search failed: %w", err)
+		return diag.Errorf("LDAP search failed: %v", err)
 	}
 
-	log.Printf("[DEBUG] ldap_search::read - found %d entries", len(sr.Entries))
+	tflog.Debug(ctx, "found entries", map[string]interface{}{"entries": len(sr.Entries)})
 
 	// 6. Convert results to Terraform schema format
 	entries := make([]interface{}, len(sr.Entries))
 	for i, entry := range sr.Entries {
 		attrs := make(map[string]interface{})
 		for _, attr := range entry.Attributes {
-			// Join multi-valued attributes with comma
-			attrs[attr.Name] = strings.Join(attr.Values, ",")
+			// Join multi-valued attributes with comma, in a stable order
+			attrs[attr.Name] = strings.Join(sortValues(attr.Values), ",")
 		}
 
 		entries[i] = map[string]interface{}{
@@ -138,7 +180,20 @@ func dataSourceLDAPSearchRead(d *schema.ResourceData, meta interface{}) error {
 
 	// 7. Set computed values
 	if err := d.Set("entries", entries); err != nil {
-		return fmt.Errorf("error setting entries: %w", err)
+		return diag.Errorf("error setting entries: %v", err)
+	}
+	dnsByKey, attributesByKey, err := searchEntriesByKey(sr.Entries, d.Get("key_attribute").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("dns_by_key", dnsByKey); err != nil {
+		return diag.Errorf("error setting dns_by_key: %v", err)
+	}
+	if err := d.Set("attributes_by_key", attributesByKey); err != nil {
+		return diag.Errorf("error setting attributes_by_key: %v", err)
+	}
+	if err := setSearchWindowResult(ctx, d, sr); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// 8. Generate stable ID for state tracking
@@ -147,3 +202,55 @@ func dataSourceLDAPSearchRead(d *schema.ResourceData, meta interface{}) error {
 
 	return nil
 }
+
+// searchEntriesByKey returns the DNs and the attributes, as JSON, of the
+// entries keyed by the first value of their key attribute; without key
+// attribute, the maps are empty.
+func searchEntriesByKey(entries []*ldap.Entry, keyAttribute string) (map[string]interface{}, map[string]interface{}, error) {
+	dns := map[string]interface{}{}
+	attributesJSON := map[string]interface{}{}
+	if keyAttribute == "" {
+		return dns, attributesJSON, nil
+	}
+
+	var duplicateKeys []string
+	for _, entry := range entries {
+		key := entry.GetEqualFoldAttributeValue(keyAttribute)
+		if key == "" {
+			continue
+		}
+		if _, exists := dns[key]; exists {
+			duplicateKeys = append(duplicateKeys, key)
+			continue
+		}
+
+		attrs := make(map[string][]string, len(entry.Attributes))
+		for _, attr := range entry.Attributes {
+			attrs[attr.Name] = sortValues(attr.Values)
+		}
+		attrsJSON, err := json.Marshal(attrs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error marshalling attributes for key %q: %w", key, err)
+		}
+
+		dns[key] = entry.DN
+		attributesJSON[key] = string(attrsJSON)
+	}
+
+	if len(duplicateKeys) > 0 {
+		sort.Strings(duplicateKeys)
+		return nil, nil, fmt.Errorf("duplicate values found for key_attribute %q: %v", keyAttribute, duplicateKeys)
+	}
+	return dns, attributesJSON, nil
+}
+
+// containsFold tells whether the values contain the given one, ignoring
+// case, like attribute names.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
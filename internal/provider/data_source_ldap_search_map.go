@@ -1,27 +1,25 @@
 package provider
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"log"
 	"sort"
 
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceLDAPSearchMap() *schema.Resource {
-	return &schema.Resource{
-		Read: dataSourceLDAPSearchMapRead,
+	r := &schema.Resource{
+		ReadContext: dataSourceLDAPSearchMapRead,
 
 		Schema: map[string]*schema.Schema{
-			"base_dn": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The base DN to start the search from.",
-			},
+			"base_dn": searchBaseSchema("The base DN to start the search from."),
 			"filter": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -34,6 +32,9 @@ func dataSourceLDAPSearchMap() *schema.Resource {
 				Description:  "Search scope: base, one, or sub. Default: sub.",
 				ValidateFunc: validation.StringInSlice([]string{"base", "one", "sub"}, false),
 			},
+			"member_of_in_chain": memberOfInChainSchema(),
+			"deref_aliases":      derefAliasesSchema(),
+			"time_limit":         timeLimitSchema(),
 			"requested_attributes": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -75,11 +76,19 @@ func dataSourceLDAPSearchMap() *schema.Resource {
 			"or when multi-valued attributes must be preserved without information loss.\n\n" +
 			"Use `ldap_search` instead for simple ordered iteration or when the key attribute is not guaranteed to be unique.",
 	}
+	for name, s := range searchWindowSchema() {
+		r.Schema[name] = s
+	}
+	return r
 }
 
-func dataSourceLDAPSearchMapRead(d *schema.ResourceData, meta interface{}) error {
-	baseDN := d.Get("base_dn").(string)
-	filter := d.Get("filter").(string)
+func dataSourceLDAPSearchMapRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	baseDN, err := searchBase(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := searchFilter(d)
 	scopeStr := d.Get("scope").(string)
 	keyAttribute := d.Get("key_attribute").(string)
 	pagedSize := d.Get("paged_size").(int)
@@ -117,32 +126,42 @@ func dataSourceLDAPSearchMapRead(d *schema.ResourceData, meta interface{}) error
 	providerConfig := meta.(*ProviderConfig)
 	conn := providerConfig.Connection
 
+	controls, windowed, err := searchWindowControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	request := ldap.NewSearchRequest(
 		baseDN,
 		scope,
-		ldap.NeverDerefAliases,
-		0,
+		derefAliases(d, meta),
 		0,
+		timeLimit(d, meta),
 		false,
 		filter,
 		attributes,
-		nil,
+		controls,
 	)
 
-	log.Printf("[DEBUG] ldap_search_map::read - searching base_dn=%q, filter=%q, scope=%d, key_attribute=%q, paged_size=%d", baseDN, filter, scope, keyAttribute, pagedSize)
+	tflog.Debug(ctx, "searching", map[string]interface{}{
+		"base_dn":       baseDN,
+		"filter":        filter,
+		"scope":         scope,
+		"key_attribute": keyAttribute,
+		"paged_size":    pagedSize,
+	})
 
 	var sr *ldap.SearchResult
-	var err error
-	if pagedSize > 0 {
+	if pagedSize > 0 && !windowed {
 		sr, err = conn.SearchWithPaging(request, uint32(pagedSize))
 	} else {
 		sr, err = conn.Search(request)
 	}
 	if err != nil {
-		return fmt.Errorf("LDAP search failed: %w", err)
+		return diag.Errorf("LDAP search failed: %v", err)
 	}
 
-	log.Printf("[DEBUG] ldap_search_map::read - found %d entries", len(sr.Entries))
+	tflog.Debug(ctx, "found entries", map[string]interface{}{"entries": len(sr.Entries)})
 
 	dns := make(map[string]interface{}, len(sr.Entries))
 	attributesJSONByKey := make(map[string]interface{}, len(sr.Entries))
@@ -161,14 +180,12 @@ func dataSourceLDAPSearchMapRead(d *schema.ResourceData, meta interface{}) error
 
 		attrs := make(map[string][]string, len(entry.Attributes))
 		for _, attr := range entry.Attributes {
-			values := make([]string, len(attr.Values))
-			copy(values, attr.Values)
-			attrs[attr.Name] = values
+			attrs[attr.Name] = sortValues(attr.Values)
 		}
 
 		attrsJSON, err := json.Marshal(attrs)
 		if err != nil {
-			return fmt.Errorf("error marshalling attributes for key %q: %w", key, err)
+			return diag.Errorf("error marshalling attributes for key %q: %v", key, err)
 		}
 
 		dns[key] = entry.DN
@@ -177,17 +194,20 @@ func dataSourceLDAPSearchMapRead(d *schema.ResourceData, meta interface{}) error
 
 	if len(duplicateKeys) > 0 {
 		sort.Strings(duplicateKeys)
-		return fmt.Errorf("duplicate values found for key_attribute %q: %v", keyAttribute, duplicateKeys)
+		return diag.Errorf("duplicate values found for key_attribute %q: %v", keyAttribute, duplicateKeys)
 	}
 
 	if err := d.Set("entry_count", len(sr.Entries)); err != nil {
-		return fmt.Errorf("error setting entry_count: %w", err)
+		return diag.Errorf("error setting entry_count: %v", err)
 	}
 	if err := d.Set("dns", dns); err != nil {
-		return fmt.Errorf("error setting dns: %w", err)
+		return diag.Errorf("error setting dns: %v", err)
 	}
 	if err := d.Set("attributes_json_by_key", attributesJSONByKey); err != nil {
-		return fmt.Errorf("error setting attributes_json_by_key: %w", err)
+		return diag.Errorf("error setting attributes_json_by_key: %v", err)
+	}
+	if err := setSearchWindowResult(ctx, d, sr); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%d", baseDN, filter, scopeStr, keyAttribute, pagedSize))))
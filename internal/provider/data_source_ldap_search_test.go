@@ -106,6 +106,23 @@ func TestAccDataSourceLDAPSearch_emptyResults(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceLDAPSearch_keyAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceLDAPSearchConfig_keyAttribute,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_search.test", "dns_by_key.%", "2"),
+					resource.TestCheckResourceAttr("data.ldap_search.test", "dns_by_key.testuser1", "uid=testuser1,ou=users,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("data.ldap_search.test", "attributes_by_key.testuser2", `{"cn":["Test User 2"],"uid":["testuser2"]}`),
+				),
+			},
+		},
+	})
+}
+
 const testAccDataSourceLDAPSearchConfig_basic = `
 resource "ldap_object" "users_ou" {
   dn             = "ou=users,dc=example,dc=com"
@@ -266,3 +283,48 @@ data "ldap_search" "test" {
   depends_on = [ldap_object.users_ou]
 }
 `
+
+const testAccDataSourceLDAPSearchConfig_keyAttribute = `
+resource "ldap_object" "users_ou" {
+  dn             = "ou=users,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "test_user1" {
+  dn             = "uid=testuser1,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson", "posixAccount"]
+  attributes = [
+    { sn = "User1" },
+    { cn = "Test User 1" },
+    { uidNumber = "5001" },
+    { gidNumber = "5001" },
+    { homeDirectory = "/home/testuser1" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+resource "ldap_object" "test_user2" {
+  dn             = "uid=testuser2,ou=users,dc=example,dc=com"
+  object_classes = ["inetOrgPerson", "posixAccount"]
+  attributes = [
+    { sn = "User2" },
+    { cn = "Test User 2" },
+    { uidNumber = "5002" },
+    { gidNumber = "5002" },
+    { homeDirectory = "/home/testuser2" },
+  ]
+
+  depends_on = [ldap_object.users_ou]
+}
+
+data "ldap_search" "test" {
+  base_dn       = "ou=users,dc=example,dc=com"
+  filter        = "(objectClass=inetOrgPerson)"
+  scope         = "sub"
+  attributes    = ["cn"]
+  key_attribute = "uid"
+
+  depends_on = [ldap_object.test_user1, ldap_object.test_user2]
+}
+`
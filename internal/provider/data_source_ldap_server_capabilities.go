@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverCapability binds a boolean field of ldap_server_capabilities to the
+// controls, extended operations and features the server must all advertise.
+type serverCapability struct {
+	Field       string
+	Description string
+	Controls    []string
+	Extensions  []string
+	Features    []string
+}
+
+// serverCapabilities are the optional features ldap_server_capabilities
+// tells about.
+var serverCapabilities = []serverCapability{
+	{Field: "paged_results", Description: "the Simple Paged Results control (RFC 2696)", Controls: []string{client.OIDPagedResults}},
+	{Field: "modify_increment", Description: "the Modify-Increment extension (RFC 4525)", Features: []string{client.OIDModifyIncrement}},
+	{
+		Field:       "transactions",
+		Description: "the LDAP transactions (RFC 5805)",
+		Controls:    []string{client.OIDTransactionSpec},
+		Extensions:  []string{client.OIDStartTransaction, client.OIDEndTransaction},
+	},
+	{Field: "assertion", Description: "the Assertion control (RFC 4528)", Controls: []string{client.OIDAssertion}},
+	{Field: "post_read", Description: "the Post-Read control (RFC 4527)", Controls: []string{client.OIDPostRead}},
+	{Field: "tree_delete", Description: "the Tree Delete control", Controls: []string{client.OIDTreeDelete}},
+	{Field: "no_op", Description: "the No-Op control", Controls: []string{client.OIDNoOp}},
+	{Field: "who_am_i", Description: "the \"Who am I?\" extended operation (RFC 4532)", Extensions: []string{client.OIDWhoAmI}},
+}
+
+// supported tells whether the server advertises all what the capability
+// needs.
+func (c serverCapability) supported(capabilities *client.Capabilities) bool {
+	for _, oid := range c.Controls {
+		if !capabilities.SupportsControl(oid) {
+			return false
+		}
+	}
+	for _, oid := range c.Extensions {
+		if !capabilities.SupportsExtension(oid) {
+			return false
+		}
+	}
+	for _, oid := range c.Features {
+		if !capabilities.SupportsFeature(oid) {
+			return false
+		}
+	}
+	return true
+}
+
+func dataSourceLDAPServerCapabilities() *schema.Resource {
+	list := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: description,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+
+	s := map[string]*schema.Schema{
+		"vendor_name":     {Type: schema.TypeString, Computed: true, Description: "The name of the vendor of the server (`vendorName`), if it tells."},
+		"vendor_version":  {Type: schema.TypeString, Computed: true, Description: "The version of the server (`vendorVersion`), if it tells."},
+		"naming_contexts": list("The naming contexts held by the server (`namingContexts`)."),
+		"sasl_mechanisms": list("The SASL mechanisms supported by the server (`supportedSASLMechanisms`), e.g. EXTERNAL or GSSAPI."),
+		"ldap_versions": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The LDAP versions supported by the server (`supportedLDAPVersion`).",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"controls":   list("The OIDs of the controls supported by the server (`supportedControl`)."),
+		"extensions": list("The OIDs of the extended operations supported by the server (`supportedExtension`)."),
+		"features":   list("The OIDs of the features supported by the server (`supportedFeatures`)."),
+	}
+	for _, capability := range serverCapabilities {
+		s["supports_"+capability.Field] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the server advertises " + capability.Description + ".",
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceLDAPServerCapabilitiesRead,
+		Schema:      s,
+		Description: "Reads what the server advertises in its root DSE: SASL mechanisms, LDAP versions, controls, " +
+			"extended operations and features, e.g. for modules branching on the optional features of the server.",
+	}
+}
+
+func dataSourceLDAPServerCapabilitiesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	rootDSE, err := readRootDSE(ctx, meta.(*ProviderConfig).Connection,
+		"vendorName",
+		"vendorVersion",
+		"namingContexts",
+		"supportedSASLMechanisms",
+		"supportedLDAPVersion",
+		"supportedControl",
+		"supportedExtension",
+		"supportedFeatures",
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	versions := []int{}
+	for _, v := range rootDSE.GetAttributeValues("supportedLDAPVersion") {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return diag.Errorf("invalid supportedLDAPVersion %q: %v", v, err)
+		}
+		versions = append(versions, version)
+	}
+	capabilities := &client.Capabilities{
+		Controls:       rootDSE.GetAttributeValues("supportedControl"),
+		Extensions:     rootDSE.GetAttributeValues("supportedExtension"),
+		Features:       rootDSE.GetAttributeValues("supportedFeatures"),
+		NamingContexts: rootDSE.GetAttributeValues("namingContexts"),
+	}
+
+	d.Set("vendor_name", rootDSE.GetAttributeValue("vendorName"))
+	d.Set("vendor_version", rootDSE.GetAttributeValue("vendorVersion"))
+	d.Set("naming_contexts", capabilities.NamingContexts)
+	d.Set("sasl_mechanisms", rootDSE.GetAttributeValues("supportedSASLMechanisms"))
+	d.Set("ldap_versions", versions)
+	d.Set("controls", capabilities.Controls)
+	d.Set("extensions", capabilities.Extensions)
+	d.Set("features", capabilities.Features)
+	for _, capability := range serverCapabilities {
+		d.Set("supports_"+capability.Field, capability.supported(capabilities))
+	}
+	d.SetId("rootDSE")
+	return nil
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestServerCapabilitySupported(t *testing.T) {
+	transactions := serverCapability{
+		Controls:   []string{client.OIDTransactionSpec},
+		Extensions: []string{client.OIDStartTransaction, client.OIDEndTransaction},
+	}
+	if transactions.supported(nil) {
+		t.Error("supported() with no capabilities = true")
+	}
+	if transactions.supported(&client.Capabilities{Extensions: []string{client.OIDStartTransaction, client.OIDEndTransaction}}) {
+		t.Error("supported() without the control = true")
+	}
+	if !transactions.supported(&client.Capabilities{
+		Controls:   []string{client.OIDTransactionSpec},
+		Extensions: []string{client.OIDStartTransaction, client.OIDEndTransaction},
+	}) {
+		t.Error("supported() with all the OIDs = false")
+	}
+}
+
+func TestAccDataSourceLDAPServerCapabilities(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "ldap_server_capabilities" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ldap_server_capabilities.test", "ldap_versions.#", "1"),
+					resource.TestCheckResourceAttr("data.ldap_server_capabilities.test", "ldap_versions.0", "3"),
+					resource.TestCheckResourceAttr("data.ldap_server_capabilities.test", "supports_paged_results", "true"),
+					resource.TestCheckResourceAttr("data.ldap_server_capabilities.test", "supports_who_am_i", "true"),
+					resource.TestCheckResourceAttr("data.ldap_server_capabilities.test", "supports_transactions", "false"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func ephemeralResourceLDAPBind() *ephemeralResource {
+	return &ephemeralResource{
+		Schema: &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "dn",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The DN to bind as.",
+					},
+					{
+						Name:        "password",
+						Type:        tftypes.String,
+						Required:    true,
+						Sensitive:   true,
+						Description: "The password to bind with.",
+					},
+					{
+						Name:        "success",
+						Type:        tftypes.Bool,
+						Computed:    true,
+						Description: "Whether the bind succeeded.",
+					},
+					{
+						Name:        "result_code",
+						Type:        tftypes.Number,
+						Computed:    true,
+						Description: "The LDAP result code of the bind (0 on success, 49 for invalid credentials).",
+					},
+					{
+						Name:        "diagnostic_message",
+						Type:        tftypes.String,
+						Computed:    true,
+						Description: "The message returned by the server along with a failed bind.",
+					},
+					{
+						Name:        "password_expires_in",
+						Type:        tftypes.Number,
+						Computed:    true,
+						Description: "The number of seconds before the password expires, as warned by the Password Policy control; -1 if there was no such warning.",
+					},
+					{
+						Name:        "grace_logins_remaining",
+						Type:        tftypes.Number,
+						Computed:    true,
+						Description: "The number of binds left with the expired password, as warned by the Password Policy control; -1 if there was no such warning.",
+					},
+					{
+						Name:        "password_policy_error",
+						Type:        tftypes.String,
+						Computed:    true,
+						Description: "The Password Policy error reported by the server, e.g. \"Password expired\" or \"Account locked\".",
+					},
+				},
+				Description: "Checks that credentials work by binding with them on a new connection, reporting the outcome along " +
+					"with the Password Policy (ppolicy) warnings of the server, as the `ldap_bind` data source does but " +
+					"without persisting the password to the plan nor the state.\n\n" +
+					"A failed bind does not fail the ephemeral resource. The bind is attempted each time it is opened, and " +
+					"failed binds count towards the lockout of the account when the server enforces a password policy.",
+				DescriptionKind: tfprotov5.StringKindMarkdown,
+			},
+		},
+		Validate: validateEphemeralDN,
+		Open:     openBind,
+	}
+}
+
+func openBind(ctx context.Context, config map[string]tftypes.Value, meta interface{}) (map[string]tftypes.Value, error) {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	var dn, password string
+	if err := config["dn"].As(&dn); err != nil {
+		return nil, err
+	}
+	if err := config["password"].As(&password); err != nil {
+		return nil, err
+	}
+	ctx = tflog.MaskLogStrings(ctx, password)
+
+	tflog.SubsystemDebug(ctx, subsystemConnection, "checking bind", map[string]interface{}{"dn": dn})
+
+	result, err := providerConfig.Connection.CheckBind(dn, password)
+	if err != nil {
+		return nil, fmt.Errorf("error binding as %q: %w", dn, err)
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemConnection, "bind checked", map[string]interface{}{
+		"dn":          dn,
+		"success":     result.Success,
+		"result_code": result.ResultCode,
+	})
+
+	return map[string]tftypes.Value{
+		"dn":                     config["dn"],
+		"password":               config["password"],
+		"success":                tftypes.NewValue(tftypes.Bool, result.Success),
+		"result_code":            tftypes.NewValue(tftypes.Number, int64(result.ResultCode)),
+		"diagnostic_message":     tftypes.NewValue(tftypes.String, result.DiagnosticMessage),
+		"password_expires_in":    tftypes.NewValue(tftypes.Number, result.PasswordExpiresIn),
+		"grace_logins_remaining": tftypes.NewValue(tftypes.Number, result.GraceLogins),
+		"password_policy_error":  tftypes.NewValue(tftypes.String, result.PolicyError),
+	}, nil
+}
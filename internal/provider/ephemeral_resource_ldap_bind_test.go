@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldaptest"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestOpenBind(t *testing.T) {
+	server, err := ldaptest.NewServer(ldaptest.Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF:         testAccLDIF,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	pool, err := client.NewPool(&client.Config{
+		LDAPHost:     server.Host(),
+		LDAPPort:     server.Port(),
+		BindUser:     "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	meta := &ProviderConfig{Connection: pool}
+
+	for password, expected := range map[string]struct {
+		success    bool
+		resultCode int64
+	}{
+		"admin": {true, 0},
+		"wrong": {false, 49},
+	} {
+		values, err := openBind(context.Background(), map[string]tftypes.Value{
+			"dn":       tftypes.NewValue(tftypes.String, "cn=admin,dc=example,dc=com"),
+			"password": tftypes.NewValue(tftypes.String, password),
+		}, meta)
+		if err != nil {
+			t.Fatalf("unexpected error binding with %q: %v", password, err)
+		}
+		var success bool
+		var resultCode big.Float
+		if err := values["success"].As(&success); err != nil {
+			t.Fatal(err)
+		}
+		if err := values["result_code"].As(&resultCode); err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := resultCode.Int64(); success != expected.success || code != expected.resultCode {
+			t.Errorf("bind with %q: got success=%t and result code %d, expected %t and %d", password, success, code, expected.success, expected.resultCode)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/adtime"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The attributes of the computers holding their local administrator password,
+// for the legacy Microsoft LAPS and for Windows LAPS.
+const (
+	legacyLAPSPasswordAttribute           = "ms-Mcs-AdmPwd"
+	legacyLAPSExpirationTimeAttribute     = "ms-Mcs-AdmPwdExpirationTime"
+	windowsLAPSPasswordAttribute          = "msLAPS-Password"
+	windowsLAPSEncryptedPasswordAttribute = "msLAPS-EncryptedPassword"
+	windowsLAPSExpirationTimeAttribute    = "msLAPS-PasswordExpirationTime"
+)
+
+var lapsPasswordAttributes = []string{
+	legacyLAPSPasswordAttribute,
+	legacyLAPSExpirationTimeAttribute,
+	windowsLAPSPasswordAttribute,
+	windowsLAPSEncryptedPasswordAttribute,
+	windowsLAPSExpirationTimeAttribute,
+}
+
+func ephemeralResourceLDAPLAPSPassword() *ephemeralResource {
+	return &ephemeralResource{
+		Schema: &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "dn",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The DN of the computer, e.g. CN=WS01,OU=Workstations,DC=example,DC=com.",
+					},
+					{
+						Name:        "password",
+						Type:        tftypes.String,
+						Computed:    true,
+						Sensitive:   true,
+						Description: "The password of the local administrator account of the computer.",
+					},
+					{
+						Name:        "account_name",
+						Type:        tftypes.String,
+						Computed:    true,
+						Description: "The name of the local administrator account; empty with the legacy Microsoft LAPS, which does not record it.",
+					},
+					{
+						Name:     "updated_time",
+						Type:     tftypes.String,
+						Computed: true,
+						Description: "When the password was last set, as an RFC 3339 timestamp; empty with the legacy Microsoft LAPS, " +
+							"which does not record it.",
+					},
+					{
+						Name:        "expiration_time",
+						Type:        tftypes.String,
+						Computed:    true,
+						Description: "When the password expires and is rotated, as an RFC 3339 timestamp; empty if it is not set.",
+					},
+					{
+						Name:     "source",
+						Type:     tftypes.String,
+						Computed: true,
+						Description: "The attribute the password was read from: `msLAPS-Password` (Windows LAPS), or `ms-Mcs-AdmPwd` " +
+							"(legacy Microsoft LAPS).",
+					},
+				},
+				Description: "Reads the password of the local administrator account of a computer managed by LAPS from Active " +
+					"Directory, without persisting it to the plan nor the state, e.g. for runbooks consuming it. The " +
+					"cleartext password of Windows LAPS (`msLAPS-Password`) is read if set, else that of the legacy " +
+					"Microsoft LAPS (`ms-Mcs-AdmPwd`); encrypted passwords (`msLAPS-EncryptedPassword`) are not supported. " +
+					"These attributes are confidential: the bind user must be granted to read them.",
+				DescriptionKind: tfprotov5.StringKindMarkdown,
+			},
+		},
+		Validate: validateEphemeralDN,
+		Open:     openLAPSPassword,
+	}
+}
+
+// lapsPassword is the local administrator password of a computer.
+type lapsPassword struct {
+	Password       string
+	AccountName    string
+	UpdatedTime    string
+	ExpirationTime string
+	Source         string
+}
+
+// windowsLAPSPassword is the JSON value of msLAPS-Password.
+type windowsLAPSPassword struct {
+	AccountName string `json:"n"`
+	// UpdatedTime is the Active Directory timestamp, in hexadecimal, of when
+	// the password was set.
+	UpdatedTime string `json:"t"`
+	Password    string `json:"p"`
+}
+
+// readLAPSPassword returns the LAPS password of a computer from its entry,
+// preferring that of Windows LAPS.
+func readLAPSPassword(entry *ldap.Entry) (*lapsPassword, error) {
+	if v := entry.GetAttributeValue(windowsLAPSPasswordAttribute); v != "" {
+		var value windowsLAPSPassword
+		if err := json.Unmarshal([]byte(v), &value); err != nil {
+			return nil, fmt.Errorf("invalid %s of %q: %w", windowsLAPSPasswordAttribute, entry.DN, err)
+		}
+		updatedTime := ""
+		if value.UpdatedTime != "" {
+			timestamp, err := strconv.ParseInt(value.UpdatedTime, 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid update time of the %s of %q: %w", windowsLAPSPasswordAttribute, entry.DN, err)
+			}
+			if t, ok := adtime.ToTime(timestamp); ok {
+				updatedTime = t.Format(time.RFC3339)
+			}
+		}
+		expirationTime, err := lapsExpirationTime(entry, windowsLAPSExpirationTimeAttribute)
+		if err != nil {
+			return nil, err
+		}
+		return &lapsPassword{
+			Password:       value.Password,
+			AccountName:    value.AccountName,
+			UpdatedTime:    updatedTime,
+			ExpirationTime: expirationTime,
+			Source:         windowsLAPSPasswordAttribute,
+		}, nil
+	}
+
+	if v := entry.GetAttributeValue(legacyLAPSPasswordAttribute); v != "" {
+		expirationTime, err := lapsExpirationTime(entry, legacyLAPSExpirationTimeAttribute)
+		if err != nil {
+			return nil, err
+		}
+		return &lapsPassword{
+			Password:       v,
+			ExpirationTime: expirationTime,
+			Source:         legacyLAPSPasswordAttribute,
+		}, nil
+	}
+
+	if len(entry.GetRawAttributeValue(windowsLAPSEncryptedPasswordAttribute)) > 0 {
+		return nil, fmt.Errorf("the LAPS password of %q is encrypted (%s), which is not supported", entry.DN, windowsLAPSEncryptedPasswordAttribute)
+	}
+	return nil, fmt.Errorf("no LAPS password found on %q: either LAPS does not manage the computer, or the bind user is not granted to read it", entry.DN)
+}
+
+// lapsExpirationTime returns the RFC 3339 string of an expiration time
+// attribute of LAPS, or the empty string if it is not set.
+func lapsExpirationTime(entry *ldap.Entry, attribute string) (string, error) {
+	v := entry.GetAttributeValue(attribute)
+	if v == "" {
+		return "", nil
+	}
+	expirationTime, err := adtime.Format(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s of %q: %w", attribute, entry.DN, err)
+	}
+	return expirationTime, nil
+}
+
+func openLAPSPassword(ctx context.Context, config map[string]tftypes.Value, meta interface{}) (map[string]tftypes.Value, error) {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	var dn string
+	if err := config["dn"].As(&dn); err != nil {
+		return nil, err
+	}
+
+	tflog.Debug(ctx, "reading LAPS password", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntry(providerConfig.Connection, dn, lapsPasswordAttributes, providerConfig.DerefAliases)
+	if err != nil {
+		return nil, fmt.Errorf("error looking for %q: %w", dn, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("computer %q not found", dn)
+	}
+	password, err := readLAPSPassword(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	tflog.Debug(ctx, "read LAPS password", map[string]interface{}{
+		"dn":              dn,
+		"source":          password.Source,
+		"expiration_time": password.ExpirationTime,
+	})
+
+	return map[string]tftypes.Value{
+		"dn":              config["dn"],
+		"password":        tftypes.NewValue(tftypes.String, password.Password),
+		"account_name":    tftypes.NewValue(tftypes.String, password.AccountName),
+		"updated_time":    tftypes.NewValue(tftypes.String, password.UpdatedTime),
+		"expiration_time": tftypes.NewValue(tftypes.String, password.ExpirationTime),
+		"source":          tftypes.NewValue(tftypes.String, password.Source),
+	}, nil
+}
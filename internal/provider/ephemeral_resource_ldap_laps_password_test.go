@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestReadLAPSPassword(t *testing.T) {
+	const dn = "CN=WS01,OU=Workstations,DC=example,DC=com"
+	tests := []struct {
+		name       string
+		attributes map[string][]string
+		want       lapsPassword
+		wantErr    bool
+	}{
+		{
+			name: "windows LAPS",
+			attributes: map[string][]string{
+				"msLAPS-Password":               {`{"n":"Administrator","t":"1da3caa0bbea000","p":"s3cr3t"}`},
+				"msLAPS-PasswordExpirationTime": {"133512624000000000"},
+				"ms-Mcs-AdmPwd":                 {"legacy"},
+			},
+			want: lapsPassword{
+				Password:       "s3cr3t",
+				AccountName:    "Administrator",
+				UpdatedTime:    "2024-01-01T12:00:00Z",
+				ExpirationTime: "2024-02-01T12:00:00Z",
+				Source:         "msLAPS-Password",
+			},
+		},
+		{
+			name: "legacy LAPS",
+			attributes: map[string][]string{
+				"ms-Mcs-AdmPwd":               {"legacy"},
+				"ms-Mcs-AdmPwdExpirationTime": {"133512624000000000"},
+			},
+			want: lapsPassword{
+				Password:       "legacy",
+				ExpirationTime: "2024-02-01T12:00:00Z",
+				Source:         "ms-Mcs-AdmPwd",
+			},
+		},
+		{
+			name:       "invalid JSON",
+			attributes: map[string][]string{"msLAPS-Password": {"s3cr3t"}},
+			wantErr:    true,
+		},
+		{
+			name:       "encrypted",
+			attributes: map[string][]string{"msLAPS-EncryptedPassword": {"\x01\x02"}},
+			wantErr:    true,
+		},
+		{
+			name:       "not readable",
+			attributes: map[string][]string{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readLAPSPassword(ldap.NewEntry(dn, tt.attributes))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readLAPSPassword() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tt.want {
+				t.Errorf("readLAPSPassword() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtocolProviderLAPSPassword(t *testing.T) {
+	ctx := context.Background()
+	server := ProtocolProvider()
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := schemas.EphemeralResourceSchemas["ldap_laps_password"]
+	if !ok {
+		t.Fatalf("ldap_laps_password missing from the ephemeral resource schemas")
+	}
+	if _, ok := schemas.ResourceSchemas["ldap_object"]; !ok {
+		t.Errorf("ldap_object missing from the resource schemas")
+	}
+
+	config := func(dn string) *tfprotov5.DynamicValue {
+		t.Helper()
+		values := configValues(s.ValueType(), map[string]tftypes.Value{"dn": tftypes.NewValue(tftypes.String, dn)})
+		dv, err := tfprotov5.NewDynamicValue(s.ValueType(), tftypes.NewValue(s.ValueType(), values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &dv
+	}
+
+	validation, err := server.ValidateEphemeralResourceConfig(ctx, &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: "ldap_laps_password",
+		Config:   config("not a DN"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validation.Diagnostics) == 0 {
+		t.Errorf("ValidateEphemeralResourceConfig() of an invalid DN returned no diagnostics")
+	}
+
+	open, err := server.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "ldap_laps_password",
+		Config:   config("CN=WS01,OU=Workstations,DC=example,DC=com"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open.Diagnostics) == 0 || open.Result != nil {
+		t.Errorf("OpenEphemeralResource() of an unconfigured provider = %+v, want an error", open)
+	}
+}
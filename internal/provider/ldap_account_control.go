@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// accountControlAttribute is the Active Directory attribute holding the
+// account flags set from the user_account_control block.
+const accountControlAttribute = "userAccountControl"
+
+// accountControlNormal is the NORMAL_ACCOUNT flag AD requires on the user
+// accounts it creates.
+const accountControlNormal = 0x0200
+
+// accountControlFlag binds a boolean field of the user_account_control block
+// to a bit of userAccountControl; Inverted fields are true when the bit is
+// clear.
+type accountControlFlag struct {
+	Field    string
+	Bit      int64
+	Inverted bool
+}
+
+// accountControlFlags are the flags of userAccountControl the provider
+// manages; the other bits are preserved as they are on the server.
+// PASSWD_CANT_CHANGE (0x0040) is not one of them: AD neither enforces it nor
+// reports it in userAccountControl, whether the user may change their password
+// being granted by the "Change Password" ACEs of the security descriptor.
+var accountControlFlags = []accountControlFlag{
+	{Field: "enabled", Bit: 0x0002, Inverted: true}, // ACCOUNTDISABLE
+	{Field: "password_never_expires", Bit: 0x10000}, // DONT_EXPIRE_PASSWORD
+	{Field: "smartcard_required", Bit: 0x40000},     // SMARTCARD_REQUIRED
+}
+
+func accountControlSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "The flags of the Active Directory `userAccountControl` of the entry, managed as booleans; " +
+			"the bits of `userAccountControl` they do not cover are left as they are. `userAccountControl` cannot " +
+			"be set in `attributes` along with this block. Whether the user may change their password is not among " +
+			"them: AD ignores the PASSWD_CANT_CHANGE bit, and grants it with the \"Change Password\" access control " +
+			"entries of `nTSecurityDescriptor` instead.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enabled": {
+					Type:        schema.TypeBool,
+					Description: "Whether the account is enabled (ACCOUNTDISABLE clear). Default: true.",
+					Optional:    true,
+					Default:     true,
+				},
+				"password_never_expires": {
+					Type:        schema.TypeBool,
+					Description: "Whether the password never expires (DONT_EXPIRE_PASSWORD). Default: false.",
+					Optional:    true,
+					Default:     false,
+				},
+				"smartcard_required": {
+					Type:        schema.TypeBool,
+					Description: "Whether a smart card is required to log on (SMARTCARD_REQUIRED). Default: false.",
+					Optional:    true,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// managesAccountControl tells whether userAccountControl is managed through
+// the user_account_control block, in which case it is not read back as an
+// ordinary attribute.
+func managesAccountControl(d attributeGetter, name string) bool {
+	_, ok := accountControlConfig(d)
+	return ok && strings.EqualFold(name, accountControlAttribute)
+}
+
+// applyAccountControl returns the value of userAccountControl with the bits
+// of the flags set as configured in the user_account_control block.
+func applyAccountControl(value int64, flags map[string]interface{}) int64 {
+	for _, flag := range accountControlFlags {
+		set, _ := flags[flag.Field].(bool)
+		if set != flag.Inverted {
+			value |= flag.Bit
+		} else {
+			value &^= flag.Bit
+		}
+	}
+	return value
+}
+
+// accountControlValues returns the fields of the user_account_control block
+// matching the value of userAccountControl.
+func accountControlValues(value int64) map[string]interface{} {
+	flags := map[string]interface{}{}
+	for _, flag := range accountControlFlags {
+		flags[flag.Field] = (value&flag.Bit != 0) != flag.Inverted
+	}
+	return flags
+}
+
+// parseAccountControl parses a value of userAccountControl, a 32-bit integer
+// which AD writes as a signed decimal number.
+func parseAccountControl(s string) (int64, error) {
+	value, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", accountControlAttribute, s, err)
+	}
+	return value, nil
+}
+
+// accountControlConfig returns the fields of the user_account_control block,
+// if it is set.
+func accountControlConfig(d attributeGetter) (map[string]interface{}, bool) {
+	v, ok := d.GetOk("user_account_control")
+	if !ok {
+		return nil, false
+	}
+	blocks := v.([]interface{})
+	if len(blocks) == 0 {
+		return nil, false
+	}
+	// a block with only default values is nil
+	flags, _ := blocks[0].(map[string]interface{})
+	if flags == nil {
+		flags = map[string]interface{}{"enabled": true}
+	}
+	return flags, true
+}
+
+// addAccountControl sets userAccountControl in a request creating an entry,
+// starting from a normal account.
+func addAccountControl(request *ldap.AddRequest, d *schema.ResourceData) {
+	flags, ok := accountControlConfig(d)
+	if !ok {
+		return
+	}
+	value := applyAccountControl(accountControlNormal, flags)
+	request.Attribute(accountControlAttribute, []string{strconv.FormatInt(value, 10)})
+}
+
+// modifyAccountControl replaces userAccountControl in a request updating an
+// entry when the user_account_control block changes, keeping the bits it does
+// not cover as they currently are on the server.
+func modifyAccountControl(request *ldap.ModifyRequest, d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("user_account_control") {
+		return nil
+	}
+	flags, ok := accountControlConfig(d)
+	if !ok {
+		// the block was removed: the flags are left as they are
+		return nil
+	}
+	providerConfig := meta.(*ProviderConfig)
+	entry, err := searchEntry(providerConfig.Connection, request.DN, []string{accountControlAttribute}, providerConfig.DerefAliases)
+	if err != nil {
+		return fmt.Errorf("error reading %s of %q: %w", accountControlAttribute, request.DN, err)
+	}
+	value := int64(accountControlNormal)
+	if entry != nil {
+		if current := entry.GetAttributeValue(accountControlAttribute); current != "" {
+			if value, err = parseAccountControl(current); err != nil {
+				return err
+			}
+		}
+	}
+	request.Replace(accountControlAttribute, []string{strconv.FormatInt(applyAccountControl(value, flags), 10)})
+	return nil
+}
+
+// readAccountControl sets the user_account_control block from the value of
+// userAccountControl, when the block is set.
+func readAccountControl(d *schema.ResourceData, entry *ldap.Entry) error {
+	if _, ok := accountControlConfig(d); !ok {
+		return nil
+	}
+	current := entry.GetAttributeValue(accountControlAttribute)
+	if current == "" {
+		return d.Set("user_account_control", nil)
+	}
+	value, err := parseAccountControl(current)
+	if err != nil {
+		return err
+	}
+	return d.Set("user_account_control", []interface{}{accountControlValues(value)})
+}
+
+// customizeDiffAccountControl forbids setting userAccountControl both as an
+// attribute and through the user_account_control block.
+func customizeDiffAccountControl(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := accountControlConfig(d); !ok {
+		return nil
+	}
+	for name := range configuredAttributes(d) {
+		if strings.EqualFold(name, accountControlAttribute) {
+			return fmt.Errorf("%s cannot be set in attributes along with user_account_control", accountControlAttribute)
+		}
+	}
+	return nil
+}
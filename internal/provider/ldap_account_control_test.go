@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyAccountControl(t *testing.T) {
+	cases := []struct {
+		value int64
+		flags map[string]interface{}
+		want  int64
+	}{
+		// a new, enabled account
+		{accountControlNormal, map[string]interface{}{"enabled": true}, 0x0200},
+		// disabling an account keeps the bits which are not managed
+		{0x0220, map[string]interface{}{"enabled": false}, 0x0222},
+		{0x0222, map[string]interface{}{"enabled": true, "password_never_expires": true}, 0x10220},
+		{0x50242, map[string]interface{}{"enabled": true}, 0x0240},
+		{0x0200, map[string]interface{}{
+			"enabled":                true,
+			"password_never_expires": true,
+			"smartcard_required":     true,
+		}, 0x50200},
+	}
+	for _, c := range cases {
+		if got := applyAccountControl(c.value, c.flags); got != c.want {
+			t.Errorf("applyAccountControl(%#x, %v) = %#x, want %#x", c.value, c.flags, got, c.want)
+		}
+	}
+}
+
+func TestAccountControlValues(t *testing.T) {
+	want := map[string]interface{}{
+		"enabled":                false,
+		"password_never_expires": true,
+		"smartcard_required":     false,
+	}
+	if got := accountControlValues(0x10222); !reflect.DeepEqual(got, want) {
+		t.Errorf("accountControlValues(0x10222) = %v, want %v", got, want)
+	}
+	if got := applyAccountControl(0x10222, accountControlValues(0x10222)); got != 0x10222 {
+		t.Errorf("expected the flags read from a value to give it back, got %#x", got)
+	}
+}
+
+func TestParseAccountControl(t *testing.T) {
+	if value, err := parseAccountControl("66048"); err != nil || value != 0x10200 {
+		t.Errorf("parseAccountControl(66048) = %d, %v", value, err)
+	}
+	if _, err := parseAccountControl("enabled"); err == nil {
+		t.Error("expected an error for a non-numeric value")
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/adtime"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// accountExpiresNever is the value of account_expires for accounts which
+// never expire.
+const accountExpiresNever = "never"
+
+// adTimeAttributes maps the timestamp fields of ldap_object onto the Active
+// Directory attributes holding them as 100-nanosecond intervals; only
+// account_expires can be set, the others are read-only.
+var adTimeAttributes = []typedAttribute{
+	{Field: "account_expires", Attribute: "accountExpires"},
+	{Field: "password_last_set", Attribute: "pwdLastSet"},
+	{Field: "lockout_time", Attribute: "lockoutTime"},
+}
+
+// adTimeSchema returns the fields holding the Active Directory timestamps of
+// an account as RFC 3339 strings.
+func adTimeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"account_expires": {
+			Type: schema.TypeString,
+			Description: "When the Active Directory account expires (`accountExpires`), as an RFC 3339 timestamp, " +
+				"or `never`. `accountExpires` cannot be set in `attributes` along with it.",
+			Optional:         true,
+			ValidateFunc:     validateAccountExpires,
+			DiffSuppressFunc: suppressEquivalentTime,
+		},
+		"password_last_set": {
+			Type: schema.TypeString,
+			Description: "When the password of the Active Directory account was last set (`pwdLastSet`), as an " +
+				"RFC 3339 timestamp; empty when the user must change it at their next logon. Only read when " +
+				"`account_expires` or `user_account_control` is set.",
+			Computed: true,
+		},
+		"lockout_time": {
+			Type: schema.TypeString,
+			Description: "When the Active Directory account was locked out (`lockoutTime`), as an RFC 3339 " +
+				"timestamp; empty when it is not locked. Only read when `account_expires` or `user_account_control` is set.",
+			Computed: true,
+		},
+	}
+}
+
+func validateAccountExpires(v interface{}, k string) (ws []string, errs []error) {
+	s := v.(string)
+	if s == accountExpiresNever {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, s); err != nil {
+		errs = append(errs, fmt.Errorf("%s: expected an RFC 3339 timestamp or %q: %w", k, accountExpiresNever, err))
+	}
+	return
+}
+
+// suppressEquivalentTime ignores the changes between RFC 3339 timestamps
+// standing for the same instant, e.g. in different time zones.
+func suppressEquivalentTime(k, old, new string, d *schema.ResourceData) bool {
+	o, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return o.Equal(n)
+}
+
+// isADAccount tells whether the entry is managed as an Active Directory
+// account, whose timestamps are then read into their fields.
+func isADAccount(d attributeGetter) bool {
+	if _, ok := accountControlConfig(d); ok {
+		return true
+	}
+	_, ok := d.GetOk("account_expires")
+	return ok
+}
+
+// managesADTime tells whether an attribute is one of the timestamps of an
+// Active Directory account, which are not read back as ordinary attributes.
+func managesADTime(d attributeGetter, name string) bool {
+	if !isADAccount(d) {
+		return false
+	}
+	for _, attribute := range adTimeAttributes {
+		if strings.EqualFold(name, attribute.Attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// accountExpiresValue returns the value of accountExpires for a value of
+// account_expires.
+func accountExpiresValue(s string) (string, error) {
+	if s == accountExpiresNever {
+		return strconv.FormatInt(adtime.Never, 10), nil
+	}
+	return adtime.FromRFC3339(s)
+}
+
+// addADTimes sets accountExpires in a request creating an entry.
+func addADTimes(request *ldap.AddRequest, d *schema.ResourceData) error {
+	v, ok := d.GetOk("account_expires")
+	if !ok {
+		return nil
+	}
+	value, err := accountExpiresValue(v.(string))
+	if err != nil {
+		return err
+	}
+	request.Attribute("accountExpires", []string{value})
+	return nil
+}
+
+// modifyADTimes replaces accountExpires in a request updating an entry when
+// account_expires changes; it is left as it is when account_expires is
+// removed.
+func modifyADTimes(request *ldap.ModifyRequest, d *schema.ResourceData) error {
+	v, ok := d.GetOk("account_expires")
+	if !ok || !d.HasChange("account_expires") {
+		return nil
+	}
+	value, err := accountExpiresValue(v.(string))
+	if err != nil {
+		return err
+	}
+	request.Replace("accountExpires", []string{value})
+	return nil
+}
+
+// readADTimes sets the timestamp fields of an Active Directory account from
+// its entry; account_expires is only read when it is set.
+func readADTimes(d *schema.ResourceData, entry *ldap.Entry) error {
+	if !isADAccount(d) {
+		return nil
+	}
+	for _, attribute := range adTimeAttributes {
+		if attribute.Field == "account_expires" && d.Get("account_expires").(string) == "" {
+			continue
+		}
+		value := entry.GetAttributeValue(attribute.Attribute)
+		if value == "" {
+			d.Set(attribute.Field, "")
+			continue
+		}
+		formatted, err := adtime.Format(value)
+		if err != nil {
+			return err
+		}
+		if formatted == "" && attribute.Field == "account_expires" {
+			formatted = accountExpiresNever
+		}
+		d.Set(attribute.Field, formatted)
+	}
+	return nil
+}
+
+// customizeDiffADTimes forbids setting accountExpires both as an attribute
+// and through account_expires.
+func customizeDiffADTimes(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := d.GetOk("account_expires"); !ok {
+		return nil
+	}
+	for name := range configuredAttributes(d) {
+		if strings.EqualFold(name, "accountExpires") {
+			return fmt.Errorf("accountExpires cannot be set in attributes along with account_expires")
+		}
+	}
+	return nil
+}
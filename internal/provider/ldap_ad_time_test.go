@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestAccountExpiresValue(t *testing.T) {
+	cases := map[string]string{
+		"never":                "9223372036854775807",
+		"2024-02-10T00:00:00Z": "133519968000000000",
+	}
+	for in, want := range cases {
+		if got, err := accountExpiresValue(in); err != nil || got != want {
+			t.Errorf("accountExpiresValue(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestValidateAccountExpires(t *testing.T) {
+	for _, v := range []string{"never", "2024-02-10T00:00:00Z", "2024-02-10T01:00:00+01:00"} {
+		if _, errs := validateAccountExpires(v, "account_expires"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range []string{"Never", "2024-02-10", "0"} {
+		if _, errs := validateAccountExpires(v, "account_expires"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestSuppressEquivalentTime(t *testing.T) {
+	if !suppressEquivalentTime("account_expires", "2024-02-10T00:00:00Z", "2024-02-10T01:00:00+01:00", nil) {
+		t.Error("expected the same instant in different zones to be suppressed")
+	}
+	if suppressEquivalentTime("account_expires", "2024-02-10T00:00:00Z", "never", nil) {
+		t.Error("expected a change to never not to be suppressed")
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// assertionSchema returns the fields guarding updates with an Assertion
+// control (RFC 4528); they are shared by the resources managing entries.
+func assertionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"assertion_filter": {
+			Type:         schema.TypeString,
+			Description:  "An LDAP filter the entry must match for updates to be applied, e.g. \"(description=managed)\"; updates of an entry which does not match fail instead of overwriting it.",
+			Optional:     true,
+			ValidateFunc: validateLDAPFilter,
+		},
+		"assert_unchanged": {
+			Type:        schema.TypeBool,
+			Description: "Only apply updates if the entry has not changed since it was last read, as told by its entryCSN (OpenLDAP and 389-ds).",
+			Optional:    true,
+			Default:     false,
+		},
+		"entry_csn": {
+			Type:        schema.TypeString,
+			Description: "The change sequence number (entryCSN) of the entry when it was last read, if the server maintains it.",
+			Computed:    true,
+		},
+	}
+}
+
+func validateLDAPFilter(v interface{}, key string) (warnings []string, errors []error) {
+	if _, err := ldap.CompileFilter(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: invalid LDAP filter %q: %v", key, v, err))
+	}
+	return
+}
+
+// assertionControls returns the Assertion control to attach to an update
+// request, if the resource asks for one.
+func assertionControls(ctx context.Context, d *schema.ResourceData) ([]ldap.Control, error) {
+	return newAssertionControls(ctx, d, d.Get("assert_unchanged").(bool))
+}
+
+// renamedAssertionControls returns the Assertion control to attach to the
+// requests updating an entry the update renamed: the rename, guarded by
+// assertionControls, changed its entryCSN, so they only assert
+// assertion_filter.
+func renamedAssertionControls(ctx context.Context, d *schema.ResourceData) ([]ldap.Control, error) {
+	return newAssertionControls(ctx, d, false)
+}
+
+func newAssertionControls(ctx context.Context, d *schema.ResourceData, unchanged bool) ([]ldap.Control, error) {
+	filter := d.Get("assertion_filter").(string)
+	if unchanged {
+		if csn := d.Get("entry_csn").(string); csn != "" {
+			filter = fmt.Sprintf("(&%s(entryCSN=%s))", filter, ldap.EscapeFilter(csn))
+		} else {
+			tflog.Warn(ctx, "no entryCSN known, cannot assert that the entry is unchanged", map[string]interface{}{
+				"id": d.Id(),
+			})
+		}
+	}
+	if filter == "" {
+		return []ldap.Control{}, nil
+	}
+
+	control, err := client.NewControlAssertion(filter)
+	if err != nil {
+		return nil, err
+	}
+	return []ldap.Control{control}, nil
+}
+
+// assertionError turns the assertionFailed error of a guarded update into a
+// more helpful one.
+func assertionError(dn string, err error) error {
+	if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultAssertionFailed {
+		return fmt.Errorf("%q was not updated because it changed outside of Terraform or no longer matches assertion_filter; refresh and plan again: %w", dn, err)
+	}
+	return err
+}
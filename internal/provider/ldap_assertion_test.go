@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRenameWithAssertUnchanged(t *testing.T) {
+	server, err := ldaptest.NewServer(ldaptest.Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF: testAccLDIF + `
+dn: ou=guarded,dc=example,dc=com
+objectClass: organizationalUnit
+ou: guarded
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	pool, err := client.NewPool(&client.Config{
+		LDAPHost:     server.Host(),
+		LDAPPort:     server.Port(),
+		BindUser:     "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	meta := &ProviderConfig{Connection: pool}
+	ctx := context.Background()
+
+	const oldDN, newDN = "ou=guarded,dc=example,dc=com", "ou=renamed,dc=example,dc=com"
+	entry, err := searchEntry(pool, oldDN, []string{"entryCSN"}, ldap.NeverDerefAliases)
+	if err != nil || entry == nil {
+		t.Fatalf("error reading %s: %v", oldDN, err)
+	}
+	d := schema.TestResourceDataRaw(t, resourceLDAPObject().Schema, map[string]interface{}{
+		"dn":               newDN,
+		"object_classes":   []interface{}{"organizationalUnit"},
+		"assertion_filter": "(objectClass=organizationalUnit)",
+		"assert_unchanged": true,
+	})
+	if err := d.Set("entry_csn", entry.GetAttributeValue("entryCSN")); err != nil {
+		t.Fatal(err)
+	}
+
+	controls, err := assertionControls(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renameLDAPEntry(ctx, meta, oldDN, newDN, controls); err != nil {
+		t.Fatalf("unexpected error renaming %s: %v", oldDN, err)
+	}
+
+	// the rename changed the entryCSN: the rename is guarded, but the
+	// updates which follow cannot assert it any longer
+	if err := renameLDAPEntry(ctx, meta, newDN, oldDN, controls); !ldap.IsErrorWithCode(err, ldap.LDAPResultAssertionFailed) {
+		t.Errorf("expected the rename of a changed entry to fail the assertion, got %v", err)
+	}
+	controls, err = renamedAssertionControls(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := ldap.NewModifyRequest(newDN, controls)
+	request.Replace("description", []string{"renamed"})
+	if err := pool.Modify(request); err != nil {
+		t.Errorf("unexpected error updating the renamed entry: %v", err)
+	}
+
+	// assertion_filter still guards them
+	if err := d.Set("assertion_filter", "(objectClass=groupOfNames)"); err != nil {
+		t.Fatal(err)
+	}
+	controls, err = renamedAssertionControls(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request = ldap.NewModifyRequest(newDN, controls)
+	request.Replace("description", []string{"unguarded"})
+	if err := pool.Modify(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultAssertionFailed) {
+		t.Errorf("expected the update to fail assertion_filter, got %v", err)
+	}
+}
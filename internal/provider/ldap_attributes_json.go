@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func attributesJSONSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeString,
+		Description: "The attributes of this object as a JSON object mapping each attribute name to the list of its " +
+			"values, e.g. `jsonencode({ mail = [\"jdoe@example.com\"], sn = [\"Doe\"] })`; an alternative to `attributes`, " +
+			"which is easier to build programmatically. The order of the attributes and of their values is not significant.",
+		Optional:         true,
+		ConflictsWith:    []string{"attributes"},
+		ValidateFunc:     validateAttributesJSON,
+		DiffSuppressFunc: suppressEquivalentAttributesJSON,
+	}
+}
+
+// parseAttributesJSON parses the value of attributes_json.
+func parseAttributesJSON(s string) (map[string][]string, error) {
+	attributes := map[string][]string{}
+	if err := json.Unmarshal([]byte(s), &attributes); err != nil {
+		return nil, fmt.Errorf("expected a JSON object mapping attribute names to lists of strings: %w", err)
+	}
+	return attributes, nil
+}
+
+func validateAttributesJSON(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := parseAttributesJSON(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", k, err))
+	}
+	return
+}
+
+// normalizeAttributesJSON returns the attributes of a JSON value keyed by
+// their lower-cased name, with their values normalized and sorted, for
+// comparisons.
+func normalizeAttributesJSON(s string) (map[string][]string, error) {
+	attributes, err := parseAttributesJSON(s)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string][]string{}
+	for name, values := range attributes {
+		for _, value := range values {
+			normalized[strings.ToLower(name)] = append(normalized[strings.ToLower(name)], standardMatching.normalizeValue(name, value))
+		}
+	}
+	for name, values := range normalized {
+		if len(values) == 0 {
+			delete(normalized, name)
+			continue
+		}
+		normalized[name] = sortValues(values)
+	}
+	return normalized, nil
+}
+
+// suppressEquivalentAttributesJSON ignores differences in the order and in
+// the case of the names of the attributes, in the order of their values, and
+// between values the server considers equal (see normalizeValue).
+func suppressEquivalentAttributesJSON(k, old, new string, d *schema.ResourceData) bool {
+	o, err := normalizeAttributesJSON(old)
+	if err != nil {
+		return false
+	}
+	n, err := normalizeAttributesJSON(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
+
+// attributeSet returns the free-form attributes held by one of attributeKeys
+// as a set of 1-element maps, converting them from attributes_json if needed;
+// invalid JSON, reported by validateAttributesJSON, is ignored.
+func attributeSet(v interface{}) *schema.Set {
+	switch v := v.(type) {
+	case *schema.Set:
+		return v
+	case string:
+		set := &schema.Set{F: attributeHash}
+		if v == "" {
+			return set
+		}
+		attributes, err := parseAttributesJSON(v)
+		if err != nil {
+			return set
+		}
+		for name, values := range attributes {
+			for _, value := range values {
+				set.Add(map[string]interface{}{name: value})
+			}
+		}
+		return set
+	}
+	return &schema.Set{F: attributeHash}
+}
+
+// encodeAttributesJSON returns the value of attributes_json holding the given
+// set of 1-element maps, with sorted values.
+func encodeAttributesJSON(set *schema.Set) string {
+	attributes := map[string][]string{}
+	for _, attribute := range set.List() {
+		for name, value := range attribute.(map[string]interface{}) {
+			attributes[name] = append(attributes[name], value.(string))
+		}
+	}
+	for name, values := range attributes {
+		attributes[name] = sortValues(values)
+	}
+	// map keys are sorted by encoding/json
+	b, _ := json.Marshal(attributes)
+	return string(b)
+}
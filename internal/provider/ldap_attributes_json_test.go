@@ -0,0 +1,30 @@
+package provider
+
+import "testing"
+
+func TestSuppressEquivalentAttributesJSON(t *testing.T) {
+	for _, tc := range []struct {
+		old, new   string
+		equivalent bool
+	}{
+		{`{"mail":["a@example.com","b@example.com"],"sn":["Doe"]}`, `{"sn":["Doe"],"mail":["b@example.com","a@example.com"]}`, true},
+		{`{"givenName":["John"]}`, `{"givenname":["John"]}`, true},
+		{`{"sn":["Doe"]}`, `{"sn":["Roe"]}`, false},
+		{`{"sn":["Doe"]}`, `{"sn":["Doe"],"cn":["John Doe"]}`, false},
+		{`{"sn":["Doe"]}`, `not json`, false},
+	} {
+		if equivalent := suppressEquivalentAttributesJSON("attributes_json", tc.old, tc.new, nil); equivalent != tc.equivalent {
+			t.Errorf("suppressEquivalentAttributesJSON(%s, %s) = %t, expected %t", tc.old, tc.new, equivalent, tc.equivalent)
+		}
+	}
+}
+
+func TestAttributesJSONRoundTrip(t *testing.T) {
+	set := attributeSet(`{"sn":["Doe"],"mail":["b@example.com","a@example.com"]}`)
+	if set.Len() != 3 {
+		t.Fatalf("expected 3 attribute values, got %d", set.Len())
+	}
+	if s, expected := encodeAttributesJSON(set), `{"mail":["a@example.com","b@example.com"],"sn":["Doe"]}`; s != expected {
+		t.Errorf("encodeAttributesJSON() = %s, expected %s", s, expected)
+	}
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// batchReadSize is the maximum number of entries looked up by a single search.
+const batchReadSize = 100
+
+// batchReadDelay is how long a read waits for reads of other entries to join
+// its batch.
+const batchReadDelay = 10 * time.Millisecond
+
+// normalizeDN returns a form of the DN suitable for comparisons: attribute
+// types and values are lower-cased and the spacing around separators is
+// dropped. DNs which cannot be parsed are only lower-cased.
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	rdns := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		parts := make([]string, len(rdn.Attributes))
+		for j, attribute := range rdn.Attributes {
+			parts[j] = strings.ToLower(attribute.Type) + "=" + ldap.EscapeDN(strings.ToLower(attribute.Value))
+		}
+		rdns[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(rdns, ",")
+}
+
+// batchReadEntries looks up many entries with few searches: entries are
+// grouped by parent, and the children of each parent are fetched with a
+// one-level search matching their RDNs. The result maps the normalized DN
+// (see normalizeDN) of each entry found to the entry; entries which do not
+// exist are simply missing from it.
+func batchReadEntries(ctx context.Context, conn client.Client, dns []string, attributes []string, derefAliases int) (map[string]*ldap.Entry, error) {
+	parents := []string{}
+	filters := map[string][]string{}
+	roots := []string{}
+	for _, dn := range dns {
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DN %q: %w", dn, err)
+		}
+		if len(parsed.RDNs) == 0 {
+			return nil, fmt.Errorf("invalid DN %q: empty", dn)
+		}
+		if len(parsed.RDNs) == 1 {
+			// naming contexts have no parent to search under
+			roots = append(roots, dn)
+			continue
+		}
+		filter := ""
+		for _, attribute := range parsed.RDNs[0].Attributes {
+			filter += fmt.Sprintf("(%s=%s)", attribute.Type, ldap.EscapeFilter(attribute.Value))
+		}
+		if len(parsed.RDNs[0].Attributes) > 1 {
+			filter = "(&" + filter + ")"
+		}
+		parent := (&ldap.DN{RDNs: parsed.RDNs[1:]}).String()
+		key := normalizeDN(parent)
+		if _, ok := filters[key]; !ok {
+			parents = append(parents, parent)
+		}
+		filters[key] = append(filters[key], filter)
+	}
+
+	entries := map[string]*ldap.Entry{}
+	search := func(base string, scope int, filter string) error {
+		request := ldap.NewSearchRequest(
+			base,
+			scope,
+			derefAliases,
+			0,
+			0,
+			false,
+			filter,
+			attributes,
+			nil,
+		)
+		sr, err := conn.Search(request)
+		if err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				return nil
+			}
+			return err
+		}
+		for _, entry := range sr.Entries {
+			entries[normalizeDN(entry.DN)] = entry
+		}
+		return nil
+	}
+
+	for _, dn := range roots {
+		if err := search(dn, ldap.ScopeBaseObject, "(objectClass=*)"); err != nil {
+			return nil, err
+		}
+	}
+	for _, parent := range parents {
+		children := filters[normalizeDN(parent)]
+		for start := 0; start < len(children); start += batchReadSize {
+			end := start + batchReadSize
+			if end > len(children) {
+				end = len(children)
+			}
+			batch := children[start:end]
+			filter := "(|" + strings.Join(batch, "") + ")"
+
+			tflog.SubsystemDebug(ctx, subsystemConnection, "batch reading entries", map[string]interface{}{
+				"parent":  parent,
+				"entries": len(batch),
+			})
+
+			if err := search(parent, ldap.ScopeSingleLevel, filter); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entries, nil
+}
+
+// entryReader coalesces the reads of single entries issued concurrently, such
+// as those of the resources Terraform refreshes in parallel, into calls to
+// batchReadEntries.
+type entryReader struct {
+	conn         client.Client
+	attributes   []string
+	derefAliases int
+
+	mu      sync.Mutex
+	pending map[string][]chan entryReadResult
+	ctx     context.Context
+}
+
+type entryReadResult struct {
+	entry *ldap.Entry
+	err   error
+}
+
+// Read returns the entry with the given DN, or nil if it does not exist,
+// once the batch it is part of has been read; the batch is logged with the
+// context of the read which started it.
+func (r *entryReader) Read(ctx context.Context, dn string) (*ldap.Entry, error) {
+	result := make(chan entryReadResult, 1)
+
+	r.mu.Lock()
+	if r.pending == nil {
+		r.pending = map[string][]chan entryReadResult{}
+		r.ctx = ctx
+		time.AfterFunc(batchReadDelay, r.flush)
+	}
+	r.pending[dn] = append(r.pending[dn], result)
+	r.mu.Unlock()
+
+	res := <-result
+	return res.entry, res.err
+}
+
+func (r *entryReader) flush() {
+	r.mu.Lock()
+	pending, ctx := r.pending, r.ctx
+	r.pending, r.ctx = nil, nil
+	r.mu.Unlock()
+
+	dns := make([]string, 0, len(pending))
+	for dn := range pending {
+		dns = append(dns, dn)
+	}
+	entries, err := batchReadEntries(ctx, r.conn, dns, r.attributes, r.derefAliases)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, subsystemConnection, "batch read failed, reading the entries one by one", map[string]interface{}{
+			"entries": len(dns),
+			"error":   err.Error(),
+		})
+	}
+	for dn, waiters := range pending {
+		res := entryReadResult{}
+		if err != nil {
+			// read the entry alone, so that an entry which cannot be read
+			// does not fail the reads of the whole batch
+			res.entry, res.err = searchEntry(r.conn, dn, r.attributes, r.derefAliases)
+		} else if res.entry = entries[normalizeDN(dn)]; res.entry == nil {
+			// the batch filter may miss entries which exist, e.g. with
+			// multi-valued RDNs, RDN values the server normalizes otherwise,
+			// or ACLs granting base reads but not one-level searches: make
+			// sure the entry is gone before it is dropped from the state
+			res.entry, res.err = searchEntry(r.conn, dn, r.attributes, r.derefAliases)
+		}
+		for _, waiter := range waiters {
+			waiter <- res
+		}
+	}
+}
+
+// searchEntry reads a single entry with a base search, returning nil if it
+// does not exist.
+func searchEntry(conn client.Client, dn string, attributes []string, derefAliases int) (*ldap.Entry, error) {
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		derefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		attributes,
+		nil,
+	)
+	sr, err := conn.Search(request)
+	if err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(sr.Entries) == 0 {
+		return nil, nil
+	}
+	return sr.Entries[0], nil
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// searchRecorder answers one-level searches with an entry for each of the
+// given DNs under the base, base searches with the entry of the base if it is
+// one of them or one of the hidden ones (which one-level searches miss), and
+// records the searches it receives.
+type searchRecorder struct {
+	client.Client
+	mu       sync.Mutex
+	entries  []string
+	hidden   []string
+	searches []*ldap.SearchRequest
+}
+
+func (s *searchRecorder) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.mu.Lock()
+	s.searches = append(s.searches, request)
+	s.mu.Unlock()
+
+	sr := &ldap.SearchResult{}
+	if request.Scope == ldap.ScopeBaseObject {
+		for _, dn := range append(append([]string{}, s.entries...), s.hidden...) {
+			if normalizeDN(dn) == normalizeDN(request.BaseDN) {
+				sr.Entries = append(sr.Entries, ldap.NewEntry(dn, map[string][]string{"cn": {"x"}}))
+			}
+		}
+		return sr, nil
+	}
+	for _, dn := range s.entries {
+		sr.Entries = append(sr.Entries, ldap.NewEntry(dn, map[string][]string{"cn": {"x"}}))
+	}
+	return sr, nil
+}
+
+func TestEntryReaderBatchesConcurrentReads(t *testing.T) {
+	conn := &searchRecorder{entries: []string{"cn=a,ou=people,dc=example,dc=com", "cn=b,ou=people,dc=example,dc=com"}}
+	reader := &entryReader{conn: conn, attributes: []string{"*"}}
+
+	dns := []string{"cn=a,ou=people,dc=example,dc=com", "CN=B, ou=People,dc=example,dc=com", "cn=c,ou=people,dc=example,dc=com"}
+	found := make([]bool, len(dns))
+	var wg sync.WaitGroup
+	for i, dn := range dns {
+		wg.Add(1)
+		go func(i int, dn string) {
+			defer wg.Done()
+			entry, err := reader.Read(context.Background(), dn)
+			if err != nil {
+				t.Errorf("unexpected error reading %q: %v", dn, err)
+			}
+			found[i] = entry != nil
+		}(i, dn)
+	}
+	wg.Wait()
+
+	// the entry missing from the batch is confirmed with a base search
+	if len(conn.searches) != 2 {
+		t.Fatalf("expected a batch search and a base search, got %d searches", len(conn.searches))
+	}
+	if conn.searches[0].Scope != ldap.ScopeSingleLevel {
+		t.Errorf("expected a one-level search, got scope %d", conn.searches[0].Scope)
+	}
+	if conn.searches[1].Scope != ldap.ScopeBaseObject || conn.searches[1].BaseDN != dns[2] {
+		t.Errorf("expected a base search of %q, got scope %d of %q", dns[2], conn.searches[1].Scope, conn.searches[1].BaseDN)
+	}
+	for i, expected := range []bool{true, true, false} {
+		if found[i] != expected {
+			t.Errorf("read of %q: expected found=%v, got %v", dns[i], expected, found[i])
+		}
+	}
+}
+
+func TestEntryReaderConfirmsMissingEntries(t *testing.T) {
+	conn := &searchRecorder{
+		entries: []string{"cn=a,ou=people,dc=example,dc=com"},
+		// e.g. a multi-valued RDN, or ACLs only granting base reads
+		hidden: []string{"cn=b+uid=b,ou=people,dc=example,dc=com"},
+	}
+	reader := &entryReader{conn: conn, attributes: []string{"*"}}
+
+	entry, err := reader.Read(context.Background(), "cn=b+uid=b,ou=people,dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry == nil {
+		t.Error("expected the entry missed by the batch search to be found by a base search")
+	}
+}
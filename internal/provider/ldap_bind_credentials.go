@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// readBindPasswordFile returns the bind password held by a file, such as a
+// mounted Kubernetes secret; the trailing newline most such files end with
+// is not part of the password.
+func readBindPasswordFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading bind_password_file: %w", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// bindCredentials are the credentials printed, as a JSON object, by the
+// program of bind_credentials_command; fields left empty are not set.
+type bindCredentials struct {
+	BindUser     string `json:"bind_user"`
+	BindPassword string `json:"bind_password"`
+	// TLSClientCertificate and TLSClientKey are PEM-encoded.
+	TLSClientCertificate string `json:"tls_client_certificate"`
+	TLSClientKey         string `json:"tls_client_key"`
+}
+
+// runBindCredentialsCommand runs the program of bind_credentials_command,
+// without a shell, and parses the credentials it prints.
+func runBindCredentialsCommand(ctx context.Context, command []string) (*bindCredentials, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, fmt.Errorf("bind_credentials_command: no program given")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("bind_credentials_command: %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	credentials := &bindCredentials{}
+	if err := json.Unmarshal(stdout.Bytes(), credentials); err != nil {
+		// the output holds secrets: it is not included in the error
+		return nil, fmt.Errorf("bind_credentials_command: %s did not print a JSON object: %w", command[0], err)
+	}
+	if (credentials.TLSClientCertificate == "") != (credentials.TLSClientKey == "") {
+		return nil, fmt.Errorf("bind_credentials_command: tls_client_certificate and tls_client_key must be printed together")
+	}
+	return credentials, nil
+}
+
+// clientCertificate returns the TLS client certificate of the credentials,
+// or nil if they have none.
+func (c *bindCredentials) clientCertificate() (*tls.Certificate, error) {
+	if c.TLSClientCertificate == "" {
+		return nil, nil
+	}
+	certificate, err := tls.X509KeyPair([]byte(c.TLSClientCertificate), []byte(c.TLSClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("bind_credentials_command: invalid TLS client certificate: %w", err)
+	}
+	return &certificate, nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newComputedAttributes returns the provider's computed_attributes keyed by
+// their lower-cased name: site-specific attributes maintained by the server,
+// which are read into the all_attributes of ldap_object resources for
+// reference, but never into their attributes, and cannot be set.
+func newComputedAttributes(names []string) map[string]string {
+	computed := make(map[string]string, len(names))
+	for _, name := range names {
+		computed[strings.ToLower(name)] = name
+	}
+	return computed
+}
+
+// isComputedAttribute tells whether an attribute is one of the provider's
+// computed_attributes.
+func isComputedAttribute(meta interface{}, name string) bool {
+	c, ok := meta.(*ProviderConfig)
+	if !ok {
+		return false
+	}
+	_, computed := c.computedAttributes[strings.ToLower(name)]
+	return computed
+}
+
+// computedAttributeNames returns the names of the provider's computed
+// attributes, sorted, to request them explicitly as they may be operational.
+func computedAttributeNames(meta interface{}) []string {
+	names := []string{}
+	if c, ok := meta.(*ProviderConfig); ok {
+		for _, name := range c.computedAttributes {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customizeDiffComputedAttributes makes sure that the computed attributes are
+// not set, as they are never written.
+func customizeDiffComputedAttributes(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range attributeKeys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	names := []string{}
+	for name := range configuredAttributes(d) {
+		if isComputedAttribute(meta, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return fmt.Errorf("attributes %s are in the provider's computed_attributes and cannot be set", strings.Join(names, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputedAttributes(t *testing.T) {
+	configured := &ProviderConfig{computedAttributes: newComputedAttributes([]string{"siteQuota", "siteLastSync"})}
+	for name, expected := range map[string]bool{
+		"siteQuota":    true,
+		"SITELASTSYNC": true,
+		"mail":         false,
+	} {
+		if isComputedAttribute(configured, name) != expected {
+			t.Errorf("isComputedAttribute(%q) = %t, expected %t", name, !expected, expected)
+		}
+	}
+	if names := computedAttributeNames(configured); !reflect.DeepEqual(names, []string{"siteLastSync", "siteQuota"}) {
+		t.Errorf("unexpected computed attributes %v", names)
+	}
+
+	if isComputedAttribute(&ProviderConfig{}, "siteQuota") {
+		t.Error("siteQuota should only be computed by the provider configuring it")
+	}
+	if names := computedAttributeNames(nil); len(names) != 0 {
+		t.Errorf("unexpected computed attributes %v without a provider configuration", names)
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func configureRetrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "Retry connecting to the server while it is not ready, e.g. when it is brought up in the same " +
+			"apply as the resources using it. Only unreachable or unavailable servers are retried: failed binds " +
+			"are not.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"attempts": {
+					Type:         schema.TypeInt,
+					Description:  "The maximum number of connection attempts (default: 10).",
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"interval": {
+					Type:         schema.TypeString,
+					Description:  "The time to wait between attempts, as a duration, e.g. \"5s\" or \"1m\" (default: 5s).",
+					Optional:     true,
+					Default:      "5s",
+					ValidateFunc: validateDuration,
+				},
+			},
+		},
+	}
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errs []error) {
+	d, err := time.ParseDuration(v.(string))
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("%s: expected a duration (e.g. 5s): %w", k, err))
+	case d < 0:
+		errs = append(errs, fmt.Errorf("%s: expected a positive duration, got %s", k, v))
+	}
+	return
+}
+
+// configureRetry returns the number of connection attempts and the interval
+// between them set in the configure_retry block; without the block, the
+// connection is attempted once.
+func configureRetry(d *schema.ResourceData) (int, time.Duration) {
+	blocks := d.Get("configure_retry").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return 1, 0
+	}
+	block := blocks[0].(map[string]interface{})
+	// the value was validated by validateDuration
+	interval, _ := time.ParseDuration(block["interval"].(string))
+	return block["attempts"].(int), interval
+}
+
+// newPoolWithRetry creates the connection pool, retrying while the server is
+// unreachable or unavailable, up to the given number of attempts.
+func newPoolWithRetry(ctx context.Context, config *client.Config, size, attempts int, interval time.Duration) (*client.Pool, error) {
+	for attempt := 1; ; attempt++ {
+		pool, err := client.NewPool(config, size)
+		if err == nil || attempt >= attempts || !client.IsTransient(err) {
+			return pool, err
+		}
+
+		tflog.SubsystemWarn(ctx, subsystemConnection, "the LDAP server is not ready, retrying", map[string]interface{}{
+			"attempt":  attempt,
+			"attempts": attempts,
+			"interval": interval.String(),
+			"error":    err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w (gave up after %d attempts: %v)", err, attempt, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func createParentsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeBool,
+		Description: "Create the missing parents of the entry as organizationalUnit entries when the server reports that " +
+			"its parent does not exist; they are left in place when the entry is destroyed (see the `ldap_ou_tree` resource " +
+			"to remove them). Default: false.",
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// addWithParents adds an entry, creating its missing parents and adding it
+// again when its resource sets create_parents and its parent does not exist.
+func addWithParents(ctx context.Context, d *schema.ResourceData, meta interface{}, request *ldap.AddRequest) error {
+	client := meta.(*ProviderConfig).Connection
+	err := client.Add(request)
+	if err == nil || !d.Get("create_parents").(bool) {
+		return err
+	}
+	if ldapErr, ok := err.(*ldap.Error); !ok || ldapErr.ResultCode != ldap.LDAPResultNoSuchObject {
+		return err
+	}
+	_, _, parent, splitErr := splitDN(request.DN)
+	if splitErr != nil || parent == "" {
+		return err
+	}
+
+	tflog.Debug(ctx, "creating the missing parents of the entry", map[string]interface{}{"dn": request.DN})
+	if _, err := createContainers(ctx, meta, parent, nil); err != nil {
+		return err
+	}
+	return client.Add(request)
+}
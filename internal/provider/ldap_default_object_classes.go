@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultObjectClassesFunc returns the object classes of the entries of a
+// resource which do not set object_classes, according to the provider
+// configuration, which may be nil.
+type defaultObjectClassesFunc func(c *ProviderConfig) []string
+
+// defaultGroupObjectClasses returns the default_group_object_classes of the
+// provider, posixGroup by default.
+func defaultGroupObjectClasses(c *ProviderConfig) []string {
+	if c == nil || len(c.DefaultGroupObjectClasses) == 0 {
+		return []string{"posixGroup"}
+	}
+	return c.DefaultGroupObjectClasses
+}
+
+// defaultUserObjectClasses returns the default_user_object_classes of the
+// provider, if any.
+func defaultUserObjectClasses(c *ProviderConfig) []string {
+	if c == nil {
+		return nil
+	}
+	return c.DefaultUserObjectClasses
+}
+
+// customizeDiffDefaultObjectClasses plans the default object classes of the
+// new entries which do not set object_classes, so that they are shown in
+// the plan and checked by the other CustomizeDiff functions.
+func customizeDiffDefaultObjectClasses(defaults defaultObjectClassesFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() != "" {
+			return nil
+		}
+		config := d.GetRawConfig()
+		if config.IsNull() || !config.IsKnown() || !config.GetAttr("object_classes").IsNull() {
+			return nil
+		}
+
+		providerConfig, _ := meta.(*ProviderConfig)
+		objectClasses := defaults(providerConfig)
+		if len(objectClasses) == 0 {
+			return fmt.Errorf("object_classes must be set, the provider has no default object classes for %q", d.Get("dn").(string))
+		}
+		return d.SetNew("object_classes", convertToInterfaceSlice(objectClasses))
+	}
+}
+
+// objectClasses returns the object classes of a new entry: its
+// object_classes, or the default ones.
+func objectClasses(d *schema.ResourceData, meta interface{}, defaults defaultObjectClassesFunc) []string {
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		return convertToStringSlice(v.(*schema.Set).List())
+	}
+	providerConfig, _ := meta.(*ProviderConfig)
+	return defaults(providerConfig)
+}
@@ -1,43 +1,48 @@
 package provider
 
 import (
-	"log"
+	"context"
 	"strings"
 
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // deleteLDAPEntry deletes an LDAP entry and retries referrals with the ManageDsaIT
 // control. Some directory servers return LDAPResultReferral when deleting referral
 // entries or entries below a referral; LDAP admin clients commonly send ManageDsaIT
 // for these operations. Terraform should be able to do the same while keeping the
-// normal delete path unchanged for regular entries.
-func deleteLDAPEntry(conn *ldap.Conn, dn string, logPrefix string) error {
-	request := ldap.NewDelRequest(dn, []ldap.Control{})
+// normal delete path unchanged for regular entries. The given controls are
+// attached to the requests.
+func deleteLDAPEntry(ctx context.Context, conn client.Client, dn string, logPrefix string, controls ...ldap.Control) error {
+	fields := map[string]interface{}{"operation": logPrefix, "dn": dn}
+	request := ldap.NewDelRequest(dn, append([]ldap.Control{}, controls...))
 
 	if err := conn.Del(request); err != nil {
+		fields["error"] = err.Error()
 		ldapErr, ok := err.(*ldap.Error)
 		if !ok {
-			log.Printf("[ERROR] %s - error removing %q: %v", logPrefix, dn, err)
+			tflog.Error(ctx, "error removing entry", fields)
 			return err
 		}
 
 		switch ldapErr.ResultCode {
 		case ldap.LDAPResultNoSuchObject:
-			log.Printf("[WARN] %s - %q does not exist in LDAP, considering delete successful", logPrefix, dn)
+			tflog.Warn(ctx, "entry does not exist in LDAP, considering delete successful", fields)
 			return nil
 		case ldap.LDAPResultReferral:
-			log.Printf("[WARN] %s - delete of %q returned referral, retrying with ManageDsaIT control", logPrefix, dn)
-			return deleteLDAPEntryWithManageDsaIT(conn, dn, logPrefix)
+			tflog.Warn(ctx, "delete returned referral, retrying with ManageDsaIT control", fields)
+			return deleteLDAPEntryWithManageDsaIT(ctx, conn, dn, logPrefix, controls)
 		case ldap.LDAPResultUnwillingToPerform:
 			if isCannotDeleteReferralError(err) {
-				log.Printf("[WARN] %s - delete of %q returned unwillingToPerform/cannot delete referral, retrying with ManageDsaIT control", logPrefix, dn)
-				return deleteLDAPEntryWithManageDsaIT(conn, dn, logPrefix)
+				tflog.Warn(ctx, "delete returned unwillingToPerform/cannot delete referral, retrying with ManageDsaIT control", fields)
+				return deleteLDAPEntryWithManageDsaIT(ctx, conn, dn, logPrefix, controls)
 			}
-			log.Printf("[ERROR] %s - error removing %q: %v", logPrefix, dn, err)
+			tflog.Error(ctx, "error removing entry", fields)
 			return err
 		default:
-			log.Printf("[ERROR] %s - error removing %q: %v", logPrefix, dn, err)
+			tflog.Error(ctx, "error removing entry", fields)
 			return err
 		}
 	}
@@ -49,16 +54,18 @@ func isCannotDeleteReferralError(err error) bool {
 	return strings.Contains(strings.ToLower(err.Error()), "cannot delete referral")
 }
 
-func deleteLDAPEntryWithManageDsaIT(conn *ldap.Conn, dn string, logPrefix string) error {
-	request := ldap.NewDelRequest(dn, []ldap.Control{ldap.NewControlManageDsaIT(false)})
+func deleteLDAPEntryWithManageDsaIT(ctx context.Context, conn client.Client, dn string, logPrefix string, controls []ldap.Control) error {
+	fields := map[string]interface{}{"operation": logPrefix, "dn": dn}
+	request := ldap.NewDelRequest(dn, append([]ldap.Control{ldap.NewControlManageDsaIT(false)}, controls...))
 
 	if err := conn.Del(request); err != nil {
 		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
-			log.Printf("[WARN] %s - %q does not exist in LDAP after ManageDsaIT retry, considering delete successful", logPrefix, dn)
+			tflog.Warn(ctx, "entry does not exist in LDAP after ManageDsaIT retry, considering delete successful", fields)
 			return nil
 		}
 
-		log.Printf("[ERROR] %s - error removing %q with ManageDsaIT control: %v", logPrefix, dn, err)
+		fields["error"] = err.Error()
+		tflog.Error(ctx, "error removing entry with ManageDsaIT control", fields)
 		return err
 	}
 
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// derefAliasesValues maps the values of deref_aliases to the derefAliases
+// values of search requests (RFC 4511, section 4.5.1.3).
+var derefAliasesValues = map[string]int{
+	"never":     ldap.NeverDerefAliases,
+	"searching": ldap.DerefInSearching,
+	"finding":   ldap.DerefFindingBaseObj,
+	"always":    ldap.DerefAlways,
+}
+
+func validateDerefAliases() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{"never", "searching", "finding", "always"}, false)
+}
+
+// derefAliasesSchema returns the deref_aliases argument of data sources,
+// which defaults to the provider's.
+func derefAliasesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Description: "How aliases are dereferenced by the search: `never`, `searching` (the entries below the base), " +
+			"`finding` (the base) or `always`. Default: the provider's `deref_aliases`.",
+		ValidateFunc: validateDerefAliases(),
+	}
+}
+
+// derefAliases returns how a data source dereferences aliases.
+func derefAliases(d *schema.ResourceData, meta interface{}) int {
+	if v, ok := d.GetOk("deref_aliases"); ok {
+		return derefAliasesValues[v.(string)]
+	}
+	return meta.(*ProviderConfig).DerefAliases
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dnLocks holds a mutex per entry, keyed by normalized DN (see normalizeDN),
+// so that the resources writing the same entry, e.g. an ldap_group and
+// resources adding values to its attributes, do not interleave their reads
+// and modifications during parallel applies.
+var dnLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: map[string]*sync.Mutex{}}
+
+// lockDNs locks the entries with the given DNs, ignoring empty ones, and
+// returns the function unlocking them; entries are locked in the same order
+// whatever the order of the DNs, not to deadlock.
+func lockDNs(dns ...string) func() {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, dn := range dns {
+		if key := normalizeDN(dn); dn != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	locks := make([]*sync.Mutex, len(keys))
+	dnLocks.Lock()
+	for i, key := range keys {
+		if dnLocks.locks[key] == nil {
+			dnLocks.locks[key] = &sync.Mutex{}
+		}
+		locks[i] = dnLocks.locks[key]
+	}
+	dnLocks.Unlock()
+
+	for _, lock := range locks {
+		lock.Lock()
+	}
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
+
+// withDNLock wraps the create, update or delete function of a resource with a
+// dn so that it holds the lock of its entry, under both its former and new
+// DNs when it is renamed. The entry is read back after the write (see
+// afterWrite), until it carries the values written by f.
+func withDNLock(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		old, dn := d.GetChange("dn")
+		unlock := lockDNs(old.(string), dn.(string))
+		defer unlock()
+		if providerConfig, ok := meta.(*ProviderConfig); ok && providerConfig.Connection != nil {
+			providerConfig.Connection.ForgetWritten(old.(string), dn.(string))
+			defer providerConfig.Connection.ForgetWritten(old.(string), dn.(string))
+		}
+		return f(afterWrite(ctx), d, meta)
+	}
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockDNs(t *testing.T) {
+	unlock := lockDNs("cn=staff,dc=example,dc=com")
+	locked := make(chan struct{})
+	go func() {
+		defer lockDNs("CN=Staff, DC=example,DC=com")()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("equivalent DNs should share their lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+
+	// renames lock two entries, whatever their order
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			lockDNs("cn=a,dc=example,dc=com", "cn=b,dc=example,dc=com")()
+		}()
+		go func() {
+			defer wg.Done()
+			lockDNs("cn=b,dc=example,dc=com", "cn=a,dc=example,dc=com", "")()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// entryMetadataSchema returns the computed fields exposing when the entry of
+// a resource was created and last modified, from its operational attributes;
+// entry_csn is part of assertionSchema.
+func entryMetadataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"create_timestamp": {
+			Type:        schema.TypeString,
+			Description: "When the entry was created (createTimestamp), as an RFC 3339 timestamp.",
+			Computed:    true,
+		},
+		"modify_timestamp": {
+			Type:        schema.TypeString,
+			Description: "When the entry was last modified (modifyTimestamp), as an RFC 3339 timestamp.",
+			Computed:    true,
+		},
+	}
+}
+
+// readEntryMetadata sets the fields of entryMetadataSchema from the entry.
+func readEntryMetadata(d *schema.ResourceData, entry *ldap.Entry) error {
+	for field, attribute := range map[string]string{
+		"create_timestamp": "createTimestamp",
+		"modify_timestamp": "modifyTimestamp",
+	} {
+		value, err := formatGeneralizedTime(entry.GetEqualFoldAttributeValue(attribute))
+		if err != nil {
+			return fmt.Errorf("error reading %s of %q: %w", attribute, entry.DN, err)
+		}
+		if err := d.Set(field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// isEntryUUID tells whether a resource ID is an entryUUID (RFC 4530) rather
+// than a DN, as IDs of resources created before use_entry_uuid was set are.
+func isEntryUUID(id string) bool {
+	return id != "" && !strings.Contains(id, "=")
+}
+
+// readTrackedEntry reads the entry of a resource by its DN; when the resource
+// is tracked by entryUUID and the entry is no longer at that DN, it is looked
+// up by its entryUUID under the naming contexts of the server, so that
+// entries renamed or moved outside of Terraform are still found. The
+// attributes must include entryUUID. After a write, an entry missing or not
+// carrying the values written yet is read again (see readAfterWrite).
+func readTrackedEntry(ctx context.Context, d *schema.ResourceData, meta interface{}, attributes []string) (*ldap.Entry, error) {
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	var entry *ldap.Entry
+	err := readAfterWrite(ctx, meta, func() (found bool, err error) {
+		entry, err = providerConfig.ReadEntry(ctx, dn, attributes)
+		return entry != nil && writesVisible(meta, dn, entry, attributes), err
+	})
+	if err != nil || !providerConfig.UseEntryUUID || !isEntryUUID(d.Id()) {
+		return entry, err
+	}
+	if entry != nil && strings.EqualFold(entry.GetAttributeValue("entryUUID"), d.Id()) {
+		return entry, nil
+	}
+
+	tflog.Debug(ctx, "entry no longer found at its DN, looking it up by entryUUID", map[string]interface{}{
+		"dn":        dn,
+		"entryUUID": d.Id(),
+	})
+	filter := fmt.Sprintf("(entryUUID=%s)", ldap.EscapeFilter(d.Id()))
+	for _, base := range providerConfig.NamingContexts(ctx) {
+		request := ldap.NewSearchRequest(
+			base,
+			ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			filter,
+			attributes,
+			nil,
+		)
+		sr, err := providerConfig.Connection.Search(request)
+		if err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				continue
+			}
+			return nil, err
+		}
+		if len(sr.Entries) > 0 {
+			tflog.Warn(ctx, "entry was moved outside of Terraform", map[string]interface{}{
+				"entryUUID": d.Id(),
+				"old_dn":    dn,
+				"new_dn":    sr.Entries[0].DN,
+			})
+			return sr.Entries[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// setTrackedEntryID sets the ID of a resource from its entry: its entryUUID
+// when use_entry_uuid is set, its DN otherwise. The dn field, and the fields
+// of its parts, follow the entry if it was moved.
+func setTrackedEntryID(ctx context.Context, d *schema.ResourceData, meta interface{}, entry *ldap.Entry) {
+	if normalizeDN(entry.DN) != normalizeDN(d.Get("dn").(string)) {
+		d.Set("dn", entry.DN)
+	}
+	if err := setRDN(d); err != nil {
+		tflog.Warn(ctx, "cannot split the DN of the entry", map[string]interface{}{
+			"dn":    entry.DN,
+			"error": err.Error(),
+		})
+	}
+	if meta.(*ProviderConfig).UseEntryUUID {
+		if uuid := entry.GetAttributeValue("entryUUID"); uuid != "" {
+			d.SetId(uuid)
+			return
+		}
+		tflog.Warn(ctx, "entry has no entryUUID, tracking it by DN", map[string]interface{}{
+			"dn": entry.DN,
+		})
+	}
+	d.SetId(d.Get("dn").(string))
+}
+
+// customizeDiffRenameDN replaces the resource when its DN changes, unless it
+// is tracked by entryUUID or its DN is declared by its parts (see
+// customizeDiffRDN), in which case the entry is renamed in place (see
+// renameLDAPEntry).
+func customizeDiffRenameDN(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("dn") {
+		return nil
+	}
+	if providerConfig, ok := meta.(*ProviderConfig); ok && providerConfig.UseEntryUUID && isEntryUUID(d.Id()) {
+		return nil
+	}
+	if declaresRDN(d) {
+		return nil
+	}
+	return d.ForceNew("dn")
+}
+
+// renameLDAPEntry renames an entry, moving it under its new parent if it
+// changed; controls are attached to the request, e.g. the Assertion control
+// guarding the update of the resource.
+func renameLDAPEntry(ctx context.Context, meta interface{}, oldDN, newDN string, controls []ldap.Control) error {
+	providerConfig := meta.(*ProviderConfig)
+
+	parsedOld, err := ldap.ParseDN(oldDN)
+	if err != nil {
+		return fmt.Errorf("invalid DN %q: %w", oldDN, err)
+	}
+	parsedNew, err := ldap.ParseDN(newDN)
+	if err != nil {
+		return fmt.Errorf("invalid DN %q: %w", newDN, err)
+	}
+	if len(parsedNew.RDNs) == 0 {
+		return fmt.Errorf("invalid DN %q: empty", newDN)
+	}
+
+	rdn := (&ldap.DN{RDNs: parsedNew.RDNs[:1]}).String()
+	newSuperior := ""
+	if parent := (&ldap.DN{RDNs: parsedNew.RDNs[1:]}).String(); len(parsedOld.RDNs) == 0 ||
+		normalizeDN(parent) != normalizeDN((&ldap.DN{RDNs: parsedOld.RDNs[1:]}).String()) {
+		newSuperior = parent
+	}
+
+	tflog.Debug(ctx, "renaming entry", map[string]interface{}{
+		"old_dn": oldDN,
+		"new_dn": newDN,
+	})
+	return providerConfig.Connection.ModifyDN(ldap.NewModifyDNWithControlsRequest(oldDN, rdn, true, newSuperior, controls))
+}
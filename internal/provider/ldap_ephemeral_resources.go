@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ephemeralResource is an ephemeral resource of the provider, whose values
+// are never persisted in the plan nor in the state. The SDK does not support
+// them, hence they are served on top of it at the protocol level (see
+// protocolServer).
+type ephemeralResource struct {
+	Schema *tfprotov5.Schema
+
+	// Validate checks the configuration, whose values may be unknown.
+	Validate func(config map[string]tftypes.Value) []error
+
+	// Open returns the values of the ephemeral resource from the known
+	// configuration.
+	Open func(ctx context.Context, config map[string]tftypes.Value, meta interface{}) (map[string]tftypes.Value, error)
+}
+
+// ephemeralResources are the ephemeral resources of the provider, by type
+// name.
+var ephemeralResources = map[string]*ephemeralResource{
+	"ldap_bind":          ephemeralResourceLDAPBind(),
+	"ldap_laps_password": ephemeralResourceLDAPLAPSPassword(),
+}
+
+// protocolServer serves the provider: the SDK serves the resources and data
+// sources, and the ephemeral resources are served on top of it.
+type protocolServer struct {
+	tfprotov5.ProviderServer
+	provider *schema.Provider
+}
+
+// ProtocolProvider returns the server of the provider, serving its ephemeral
+// resources along with those of the SDK.
+func ProtocolProvider() tfprotov5.ProviderServer {
+	p := Provider()
+	return &protocolServer{ProviderServer: schema.NewGRPCProviderServer(p), provider: p}
+}
+
+func (s *protocolServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	for typeName := range ephemeralResources {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov5.EphemeralResourceMetadata{TypeName: typeName})
+	}
+	return resp, nil
+}
+
+func (s *protocolServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.EphemeralResourceSchemas == nil {
+		resp.EphemeralResourceSchemas = map[string]*tfprotov5.Schema{}
+	}
+	for typeName, r := range ephemeralResources {
+		resp.EphemeralResourceSchemas[typeName] = r.Schema
+	}
+	return resp, nil
+}
+
+func (s *protocolServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	r, ok := ephemeralResources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.ValidateEphemeralResourceConfig(ctx, req)
+	}
+	resp := &tfprotov5.ValidateEphemeralResourceConfigResponse{}
+	config, err := ephemeralConfig(r, req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic("Invalid configuration", err))
+		return resp, nil
+	}
+	for _, err := range r.Validate(config) {
+		resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic("Invalid configuration", err))
+	}
+	return resp, nil
+}
+
+func (s *protocolServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	r, ok := ephemeralResources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.OpenEphemeralResource(ctx, req)
+	}
+	resp := &tfprotov5.OpenEphemeralResourceResponse{}
+	config, err := ephemeralConfig(r, req.Config)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic("Invalid configuration", err))
+		return resp, nil
+	}
+
+	valueType := r.Schema.ValueType()
+	var result tftypes.Value
+	switch {
+	case !tftypes.NewValue(valueType, configValues(valueType, config)).IsFullyKnown():
+		// opened again once the configuration is known
+		result = tftypes.NewValue(valueType, tftypes.UnknownValue)
+	case s.provider.Meta() == nil:
+		resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic("Provider not configured", fmt.Errorf("the provider must be configured before %s is opened", req.TypeName)))
+		return resp, nil
+	default:
+		values, err := r.Open(ctx, config, s.provider.Meta())
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic(fmt.Sprintf("Error opening %s", req.TypeName), err))
+			return resp, nil
+		}
+		result = tftypes.NewValue(valueType, configValues(valueType, values))
+	}
+
+	dv, err := tfprotov5.NewDynamicValue(valueType, result)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, ephemeralDiagnostic(fmt.Sprintf("Error opening %s", req.TypeName), err))
+		return resp, nil
+	}
+	resp.Result = &dv
+	return resp, nil
+}
+
+// RenewEphemeralResource has nothing to do: the ephemeral resources of the
+// provider hold no lease.
+func (s *protocolServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	if _, ok := ephemeralResources[req.TypeName]; !ok {
+		return s.ProviderServer.RenewEphemeralResource(ctx, req)
+	}
+	return &tfprotov5.RenewEphemeralResourceResponse{}, nil
+}
+
+// CloseEphemeralResource has nothing to do either.
+func (s *protocolServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	if _, ok := ephemeralResources[req.TypeName]; !ok {
+		return s.ProviderServer.CloseEphemeralResource(ctx, req)
+	}
+	return &tfprotov5.CloseEphemeralResourceResponse{}, nil
+}
+
+// ephemeralConfig decodes the configuration of an ephemeral resource into
+// the values of its attributes.
+func ephemeralConfig(r *ephemeralResource, dv *tfprotov5.DynamicValue) (map[string]tftypes.Value, error) {
+	config := map[string]tftypes.Value{}
+	if dv == nil {
+		return config, nil
+	}
+	value, err := dv.Unmarshal(r.Schema.ValueType())
+	if err != nil {
+		return nil, err
+	}
+	if !value.IsKnown() || value.IsNull() {
+		return config, nil
+	}
+	if err := value.As(&config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateEphemeralDN checks the dn of the configuration of an ephemeral
+// resource, once it is known.
+func validateEphemeralDN(config map[string]tftypes.Value) []error {
+	v, ok := config["dn"]
+	if !ok || !v.IsKnown() || v.IsNull() {
+		return nil
+	}
+	var dn string
+	if err := v.As(&dn); err != nil {
+		return []error{err}
+	}
+	_, errs := validateDN(dn, "dn")
+	return errs
+}
+
+// configValues returns the values of all the attributes of an object type,
+// null when they are missing.
+func configValues(valueType tftypes.Type, values map[string]tftypes.Value) map[string]tftypes.Value {
+	all := map[string]tftypes.Value{}
+	for name, attributeType := range valueType.(tftypes.Object).AttributeTypes {
+		if v, ok := values[name]; ok {
+			all[name] = v
+		} else {
+			all[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	return all
+}
+
+func ephemeralDiagnostic(summary string, err error) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  summary,
+		Detail:   err.Error(),
+	}
+}
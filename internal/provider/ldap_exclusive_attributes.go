@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// exclusiveAttributesSchema returns the schema of exclusive_attributes.
+func exclusiveAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeBool,
+		Description: "Whether the configuration is authoritative for the attributes of the entry: the attributes found on the " +
+			"server but not in `attributes` or `sensitive_attributes` are removed on the next apply. Otherwise, they are left " +
+			"untouched and only the configured attributes are read and updated, replacing the values they may already have, " +
+			"and removed once they are removed from the configuration. Switching to false, e.g. after an import, leaves the " +
+			"attributes that are not configured untouched. Partial objects are never exclusive (see `mode`). Default: true.",
+		Optional: true,
+		Default:  true,
+	}
+}
+
+// exclusiveAttributes tells whether an ldap_object manages all the attributes
+// of its entry; imported objects are until their configuration is applied.
+func exclusiveAttributes(d *schema.ResourceData) bool {
+	if isPartialObject(d) {
+		return false
+	}
+	// unlike GetOk, GetOkExists tells an explicit false apart from an unset
+	// argument
+	if v, ok := d.GetOkExists("exclusive_attributes"); ok {
+		return v.(bool)
+	}
+	return true
+}
+
+// configuredAttributeNames returns the names of the attributes read back by
+// the objects which do not manage all the attributes of their entry: the
+// configured ones and the managed_attributes.
+func configuredAttributeNames(d *schema.ResourceData) []string {
+	names := convertToStringSlice(d.Get("managed_attributes").(*schema.Set).List())
+	for name := range configuredAttributes(d) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// configuredAttributesChange returns the old and new free-form attributes of
+// an object which does not manage all the attributes of its entry; the old
+// ones are limited to the configured names when the object just stopped
+// managing them all, so that the others are left untouched.
+func configuredAttributesChange(d *schema.ResourceData) (o, n *schema.Set) {
+	o, n = attributesChange(d)
+	if !d.HasChanges("mode", "exclusive_attributes") {
+		return o, n
+	}
+	names := []string{}
+	for name := range configuredAttributes(d) {
+		names = append(names, name)
+	}
+	kept := &schema.Set{F: attributeHash}
+	for _, attribute := range o.List() {
+		for name := range attribute.(map[string]interface{}) {
+			if containsFold(names, name) {
+				kept.Add(attribute)
+			}
+		}
+	}
+	return kept, n
+}
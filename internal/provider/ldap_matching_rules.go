@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldapschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// matching tells how the values of an attribute are compared by the server.
+type matching int
+
+const (
+	matchExact      matching = iota
+	matchCaseIgnore          // case and insignificant spaces are ignored
+	matchDN                  // values are DNs
+)
+
+// equalityMatching maps the equality matching rules of the server schema
+// onto the way values are compared; other rules compare values exactly.
+var equalityMatching = map[string]matching{
+	"caseignorematch":        matchCaseIgnore,
+	"caseignoreia5match":     matchCaseIgnore,
+	"caseignorelistmatch":    matchCaseIgnore,
+	"numericstringmatch":     matchCaseIgnore,
+	"distinguishednamematch": matchDN,
+	"uniquemembermatch":      matchDN,
+}
+
+// attributeMatching holds how the values of attributes are compared, keyed by
+// their lower-cased name; attributes it does not hold compare values exactly.
+type attributeMatching map[string]matching
+
+// standardMatching is how the attributes of the standard schemas (RFC 4519,
+// RFC 2798) are compared. It is completed with the server schema on the
+// provider configuration when use_schema_matching_rules is set (see
+// matchingOf).
+var standardMatching = attributeMatching{
+	"businesscategory":           matchCaseIgnore,
+	"c":                          matchCaseIgnore,
+	"cn":                         matchCaseIgnore,
+	"dc":                         matchCaseIgnore,
+	"departmentnumber":           matchCaseIgnore,
+	"description":                matchCaseIgnore,
+	"displayname":                matchCaseIgnore,
+	"employeenumber":             matchCaseIgnore,
+	"employeetype":               matchCaseIgnore,
+	"givenname":                  matchCaseIgnore,
+	"initials":                   matchCaseIgnore,
+	"l":                          matchCaseIgnore,
+	"mail":                       matchCaseIgnore,
+	"mailalternateaddress":       matchCaseIgnore,
+	"o":                          matchCaseIgnore,
+	"ou":                         matchCaseIgnore,
+	"physicaldeliveryofficename": matchCaseIgnore,
+	"postalcode":                 matchCaseIgnore,
+	"sn":                         matchCaseIgnore,
+	"st":                         matchCaseIgnore,
+	"street":                     matchCaseIgnore,
+	"title":                      matchCaseIgnore,
+	"uid":                        matchCaseIgnore,
+	"manager":                    matchDN,
+	"member":                     matchDN,
+	"owner":                      matchDN,
+	"roleoccupant":               matchDN,
+	"secretary":                  matchDN,
+	"seealso":                    matchDN,
+	"uniquemember":               matchDN,
+}
+
+// schemaMatching returns standardMatching completed with how the values of
+// the attributes defined in the server schema are compared, according to
+// their equality matching rule.
+func schemaMatching(s *ldapschema.Schema) attributeMatching {
+	rules := make(attributeMatching, len(standardMatching))
+	for name, m := range standardMatching {
+		rules[name] = m
+	}
+	for _, at := range s.AttributeTypes {
+		m := equalityMatching[strings.ToLower(s.Equality(at.OID))]
+		for _, name := range at.Names {
+			rules[strings.ToLower(name)] = m
+		}
+	}
+	return rules
+}
+
+// matchingOf returns how the provider compares the values of attributes:
+// with the server schema if it was configured so, else with standardMatching.
+func matchingOf(meta interface{}) attributeMatching {
+	if c, ok := meta.(*ProviderConfig); ok && c.matching != nil {
+		return c.matching
+	}
+	return standardMatching
+}
+
+// normalizeValue returns the value of an attribute as it is compared by the
+// server: lower-cased and with insignificant spaces removed for
+// case-insensitive attributes, normalized for DN-valued ones, as is otherwise.
+func (a attributeMatching) normalizeValue(attribute, value string) string {
+	switch a[strings.ToLower(attribute)] {
+	case matchCaseIgnore:
+		return strings.ToLower(strings.Join(strings.Fields(value), " "))
+	case matchDN:
+		return normalizeDN(value)
+	}
+	return value
+}
+
+// equivalentValues tells whether two sets of values of an attribute are the
+// same as compared by the server (see normalizeValue).
+func (a attributeMatching) equivalentValues(attribute string, x, y []string) bool {
+	normalized := func(values []string) map[string]bool {
+		set := make(map[string]bool, len(values))
+		for _, value := range values {
+			set[a.normalizeValue(attribute, value)] = true
+		}
+		return set
+	}
+	nx, ny := normalized(x), normalized(y)
+	if len(nx) != len(ny) {
+		return false
+	}
+	for value := range nx {
+		if !ny[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalValue returns the value of an attribute as it is written to the
+// server and to the state: DN-valued attributes are written with canonicalDN,
+// so that DNs written by other tools do not show up as drift.
+func (a attributeMatching) canonicalValue(attribute, value string) string {
+	if a[strings.ToLower(attribute)] == matchDN {
+		return canonicalDN(value)
+	}
+	return value
+}
+
+// canonicalValues applies canonicalValue to each value of an attribute.
+func (a attributeMatching) canonicalValues(attribute string, values []string) []string {
+	canonical := make([]string, len(values))
+	for i, value := range values {
+		canonical[i] = a.canonicalValue(attribute, value)
+	}
+	return canonical
+}
+
+// stateSpellings returns the values of the free-form attributes of a resource
+// in its state, by lower-cased attribute name and normalized value. The
+// values read which the server considers equal keep the spelling of the state
+// (see spelling): the hash of the attributes set only knows standardMatching,
+// so that values equal by the server schema would otherwise show up as drift.
+func (a attributeMatching) stateSpellings(d *schema.ResourceData) map[string]map[string]string {
+	spellings := map[string]map[string]string{}
+	set, ok := d.Get("attributes").(*schema.Set)
+	if !ok {
+		return spellings
+	}
+	for _, element := range set.List() {
+		for name, value := range element.(map[string]interface{}) {
+			name = strings.ToLower(name)
+			if spellings[name] == nil {
+				spellings[name] = map[string]string{}
+			}
+			spellings[name][a.normalizeValue(name, value.(string))] = value.(string)
+		}
+	}
+	return spellings
+}
+
+// spelling returns the value of an attribute as it is spelled in the state if
+// it holds an equivalent one (see stateSpellings), else the value as it is.
+func (a attributeMatching) spelling(spellings map[string]map[string]string, attribute, value string) string {
+	if spelled, ok := spellings[strings.ToLower(attribute)][a.normalizeValue(attribute, value)]; ok {
+		return spelled
+	}
+	return value
+}
+
+// suppressEquivalentAttributeValue ignores the changes to the values of the
+// free-form attributes which the server considers equal (e.g. a different case
+// in a mail address); k is of the form attributes.<hash>.<name>.
+func suppressEquivalentAttributeValue(k, old, new string, d *schema.ResourceData) bool {
+	parts := strings.Split(k, ".")
+	if len(parts) != 3 || parts[2] == "%" {
+		return false
+	}
+	return standardMatching.normalizeValue(parts[2], old) == standardMatching.normalizeValue(parts[2], new)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldapschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	for _, tc := range []struct {
+		attribute, value, expected string
+	}{
+		{"mail", "JDoe@Example.COM", "jdoe@example.com"},
+		{"CN", "  John   Doe ", "john doe"},
+		{"member", "CN=John Doe, OU=People,DC=example,DC=com", "cn=john doe,ou=people,dc=example,dc=com"},
+		{"memberUid", "JDoe", "JDoe"},
+	} {
+		if value := standardMatching.normalizeValue(tc.attribute, tc.value); value != tc.expected {
+			t.Errorf("normalizeValue(%q, %q) = %q, expected %q", tc.attribute, tc.value, value, tc.expected)
+		}
+	}
+}
+
+func TestEquivalentValues(t *testing.T) {
+	for _, tc := range []struct {
+		attribute string
+		a, b      []string
+		expected  bool
+	}{
+		{"mail", []string{"JDoe@example.com", "x@example.com"}, []string{"X@example.com", "jdoe@example.com"}, true},
+		{"member", []string{"CN=John Doe, OU=People,DC=example,DC=com"}, []string{"cn=john doe,ou=people,dc=example,dc=com"}, true},
+		{"homeDirectory", []string{"/home/JDoe"}, []string{"/home/jdoe"}, false},
+		{"mail", []string{"jdoe@example.com"}, []string{"jdoe@example.com", "x@example.com"}, false},
+	} {
+		if equivalent := standardMatching.equivalentValues(tc.attribute, tc.a, tc.b); equivalent != tc.expected {
+			t.Errorf("equivalentValues(%q, %q, %q) = %t, expected %t", tc.attribute, tc.a, tc.b, equivalent, tc.expected)
+		}
+	}
+}
+
+func TestSuppressEquivalentAttributeValue(t *testing.T) {
+	if !suppressEquivalentAttributeValue("attributes.1234.mail", "JDoe@example.com", "jdoe@example.com", nil) {
+		t.Error("expected a change of case in mail to be suppressed")
+	}
+	if suppressEquivalentAttributeValue("attributes.1234.homeDirectory", "/home/JDoe", "/home/jdoe", nil) {
+		t.Error("expected a change of case in homeDirectory not to be suppressed")
+	}
+}
+
+func TestSchemaMatching(t *testing.T) {
+	s, err := ldapschema.New(nil, []string{
+		"( 2.5.4.41 NAME 'name' EQUALITY caseIgnoreMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+		"( 1.3.6.1.4.1.99999.1 NAME 'exampleNickname' SUP name )",
+		"( 1.3.6.1.4.1.99999.2 NAME 'exampleToken' EQUALITY caseExactMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	configured := &ProviderConfig{matching: schemaMatching(s)}
+	if value := matchingOf(configured).normalizeValue("exampleNickname", "JD"); value != "jd" {
+		t.Errorf("expected exampleNickname to be case-insensitive, got %q", value)
+	}
+	if value := matchingOf(configured).normalizeValue("exampleToken", "JD"); value != "JD" {
+		t.Errorf("expected exampleToken to be case-sensitive, got %q", value)
+	}
+	if value := matchingOf(configured).normalizeValue("mail", "JD@example.com"); value != "jd@example.com" {
+		t.Errorf("expected mail to remain case-insensitive, got %q", value)
+	}
+	if value := matchingOf(&ProviderConfig{}).normalizeValue("exampleNickname", "JD"); value != "JD" {
+		t.Errorf("expected exampleNickname to be case-sensitive without the server schema, got %q", value)
+	}
+	if _, ok := standardMatching["examplenickname"]; ok {
+		t.Error("expected the standard matching rules to be left untouched")
+	}
+}
+
+func TestStateSpellings(t *testing.T) {
+	s, err := ldapschema.New(nil, []string{
+		"( 1.3.6.1.4.1.99999.1 NAME 'exampleNickname' EQUALITY caseIgnoreMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	matching := schemaMatching(s)
+	d := schema.TestResourceDataRaw(t, resourceLDAPObject().Schema, map[string]interface{}{
+		"dn":             "uid=jdoe,ou=People,dc=example,dc=com",
+		"object_classes": []interface{}{"inetOrgPerson"},
+		"attributes": []interface{}{
+			map[string]interface{}{"exampleNickname": "JD"},
+		},
+	})
+	spellings := matching.stateSpellings(d)
+	if value := matching.spelling(spellings, "examplenickname", "jd"); value != "JD" {
+		t.Errorf("expected the spelling of the state to be kept, got %q", value)
+	}
+	if value := matching.spelling(spellings, "exampleNickname", "johnny"); value != "johnny" {
+		t.Errorf("expected a different value to be read as it is, got %q", value)
+	}
+}
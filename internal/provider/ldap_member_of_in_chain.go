@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// matchingRuleInChain is the Active Directory matching rule comparing DN
+// values along the chain of ancestry, e.g. the groups of a user along with
+// the groups these groups are members of.
+const matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+// memberOfInChainSchema returns the member_of_in_chain argument of search data
+// sources.
+func memberOfInChainSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateDN,
+		Description: "Active Directory only: restrict the search to the entries which are members of this group, " +
+			"directly or through nested groups, by adding `(memberOf:1.2.840.113556.1.4.1941:=<group DN>)` to `filter`; " +
+			"the server expands the nested groups.",
+	}
+}
+
+// searchFilter returns the filter of a search data source, restricted to the
+// members of its member_of_in_chain group if it is set.
+func searchFilter(d *schema.ResourceData) string {
+	filter := d.Get("filter").(string)
+	group := d.Get("member_of_in_chain").(string)
+	if group == "" {
+		return filter
+	}
+	return "(&" + filter + "(memberOf:" + matchingRuleInChain + ":=" + ldap.EscapeFilter(group) + "))"
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSearchFilter(t *testing.T) {
+	s := dataSourceLDAPSearch().Schema
+
+	for name, test := range map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"filter only": {
+			map[string]interface{}{"filter": "(objectClass=user)"},
+			"(objectClass=user)",
+		},
+		"member_of_in_chain": {
+			map[string]interface{}{"filter": "(objectClass=user)", "member_of_in_chain": "CN=Admins (EU),OU=Groups,DC=example,DC=com"},
+			`(&(objectClass=user)(memberOf:1.2.840.113556.1.4.1941:=CN=Admins \28EU\29,OU=Groups,DC=example,DC=com))`,
+		},
+	} {
+		d := schema.TestResourceDataRaw(t, s, test.config)
+		if got := searchFilter(d); got != test.expected {
+			t.Errorf("%s: got %q, expected %q", name, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// membershipCleanupPageSize is the page size of the searches for the entries
+// referencing a destroyed or renamed entry.
+const membershipCleanupPageSize = 500
+
+// defaultMembershipAttributes are the DN-valued attributes referencing an
+// entry which are updated by default.
+var defaultMembershipAttributes = []string{"member", "uniqueMember", "roleOccupant"}
+
+// referencesSchema returns the schema of the blocks telling where the
+// references to the entry of a resource are, e.g. membership_cleanup.
+func referencesSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"base_dn": {
+					Type:         schema.TypeString,
+					Description:  "The DN of the subtree searched for the entries referencing the entry, e.g. ou=groups,dc=example,dc=com.",
+					Required:     true,
+					ValidateFunc: validateDN,
+				},
+				"attributes": {
+					Type:        schema.TypeList,
+					Description: "The DN-valued attributes holding the references. Default: [\"member\", \"uniqueMember\", \"roleOccupant\"].",
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func membershipCleanupSchema() *schema.Schema {
+	return referencesSchema("Removes the DN of the entry from the attributes of the entries referencing it, e.g. the members " +
+		"of groups, once it is deleted or moved on destroy (see `on_destroy`), like the refint overlay of OpenLDAP does; " +
+		"for the directories without referential integrity.")
+}
+
+// membershipCleanupFilter returns the filter matching the entries which
+// reference the DN in any of the attributes.
+func membershipCleanupFilter(dn string, attributes []string) string {
+	filter := ""
+	for _, attribute := range attributes {
+		filter += fmt.Sprintf("(%s=%s)", attribute, ldap.EscapeFilter(dn))
+	}
+	if len(attributes) == 1 {
+		return filter
+	}
+	return "(|" + filter + ")"
+}
+
+// cleanupMemberships removes a destroyed entry from the attributes of the
+// entries referencing it, according to the membership_cleanup block of its
+// resource.
+func cleanupMemberships(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	return updateReferences(ctx, d, meta, "membership_cleanup", d.Get("dn").(string), "")
+}
+
+// updateReferences replaces oldDN with newDN in the entries referencing it,
+// or removes it when newDN is empty, according to the block of the resource
+// with the given key (see referencesSchema).
+func updateReferences(ctx context.Context, d *schema.ResourceData, meta interface{}, key, oldDN, newDN string) error {
+	if _, ok := d.GetOk(key); !ok {
+		return nil
+	}
+	providerConfig := meta.(*ProviderConfig)
+	baseDN := d.Get(key + ".0.base_dn").(string)
+	attributes := convertToStringSlice(d.Get(key + ".0.attributes").([]interface{}))
+	if len(attributes) == 0 {
+		attributes = defaultMembershipAttributes
+	}
+
+	request := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree,
+		providerConfig.DerefAliases,
+		0,
+		0,
+		false,
+		membershipCleanupFilter(oldDN, attributes),
+		attributes,
+		nil,
+	)
+	sr, err := providerConfig.Connection.SearchWithPaging(request, membershipCleanupPageSize)
+	if err != nil {
+		return fmt.Errorf("error searching the entries referencing %q under %q: %w", oldDN, baseDN, err)
+	}
+
+	for _, entry := range sr.Entries {
+		modify := ldap.NewModifyRequest(entry.DN, nil)
+		for _, attribute := range attributes {
+			// the values are removed as the server holds them
+			values := []string{}
+			for _, value := range entry.GetEqualFoldAttributeValues(attribute) {
+				if normalizeDN(value) == normalizeDN(oldDN) {
+					values = append(values, value)
+				}
+			}
+			if len(values) > 0 {
+				modify.Delete(attribute, values)
+				if newDN != "" {
+					modify.Add(attribute, []string{newDN})
+				}
+			}
+		}
+		if len(modify.Changes) == 0 {
+			continue
+		}
+		tflog.Debug(ctx, "updating a reference", map[string]interface{}{
+			"dn":        oldDN,
+			"new_dn":    newDN,
+			"reference": entry.DN,
+		})
+		// the referencing entry may be managed by a resource too, unless it is
+		// the renamed entry itself, whose lock is already held
+		unlock := func() {}
+		if newDN == "" || normalizeDN(entry.DN) != normalizeDN(newDN) {
+			unlock = lockDNs(entry.DN)
+		}
+		err := providerConfig.Connection.Modify(modify)
+		unlock()
+		if err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && (ldapErr.ResultCode == ldap.LDAPResultNoSuchObject || ldapErr.ResultCode == ldap.LDAPResultNoSuchAttribute) {
+				// removed in the meantime
+				continue
+			}
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultObjectClassViolation {
+				tflog.Warn(ctx, "leaving a reference to a destroyed entry, it is the last value of a required attribute", map[string]interface{}{
+					"dn":        oldDN,
+					"reference": entry.DN,
+				})
+				continue
+			}
+			return fmt.Errorf("error updating the reference to %q in %q: %w", oldDN, entry.DN, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package provider
+
+import "testing"
+
+func TestMembershipCleanupFilter(t *testing.T) {
+	for _, tc := range []struct {
+		dn         string
+		attributes []string
+		expected   string
+	}{
+		{"uid=jdoe,dc=example,dc=com", []string{"member"}, "(member=uid=jdoe,dc=example,dc=com)"},
+		{"uid=jdoe,dc=example,dc=com", defaultMembershipAttributes, "(|(member=uid=jdoe,dc=example,dc=com)(uniqueMember=uid=jdoe,dc=example,dc=com)(roleOccupant=uid=jdoe,dc=example,dc=com))"},
+		{"cn=Doe\\, John (ext),dc=example,dc=com", []string{"member"}, "(member=cn=Doe\\5c, John \\28ext\\29,dc=example,dc=com)"},
+	} {
+		if filter := membershipCleanupFilter(tc.dn, tc.attributes); filter != tc.expected {
+			t.Errorf("membershipCleanupFilter(%q, %q) = %q, expected %q", tc.dn, tc.attributes, filter, tc.expected)
+		}
+	}
+}
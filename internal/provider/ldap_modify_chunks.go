@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// splitModifyRequest splits a modify request into requests adding or
+// deleting at most size values each, for servers limiting the size of
+// modifications, e.g. Active Directory with huge groups. Replacements and
+// deletions of whole attributes, which cannot be split, go into the first
+// request. A size below 1 does not split the request.
+//
+// The Assertion control only goes on the first request, since the first
+// update changes the entry it asserts on (e.g. its entryCSN), and the
+// Post-Read control only on the last one; the other controls go on all the
+// requests.
+func splitModifyRequest(request *ldap.ModifyRequest, size int) []*ldap.ModifyRequest {
+	if size < 1 {
+		return []*ldap.ModifyRequest{request}
+	}
+
+	var controls, assertion, postRead []ldap.Control
+	for _, control := range request.Controls {
+		switch control.GetControlType() {
+		case client.OIDAssertion:
+			assertion = append(assertion, control)
+		case client.OIDPostRead:
+			postRead = append(postRead, control)
+		default:
+			controls = append(controls, control)
+		}
+	}
+
+	newChunk := func() *ldap.ModifyRequest {
+		return ldap.NewModifyRequest(request.DN, append([]ldap.Control(nil), controls...))
+	}
+	first := newChunk()
+	first.Controls = append(first.Controls, assertion...)
+	chunks := []*ldap.ModifyRequest{first}
+	current, count := first, 0
+
+	for _, change := range request.Changes {
+		splittable := change.Operation == ldap.AddAttribute || change.Operation == ldap.DeleteAttribute
+		if !splittable || len(change.Modification.Vals) == 0 {
+			first.Changes = append(first.Changes, change)
+			continue
+		}
+
+		vals := change.Modification.Vals
+		for len(vals) > 0 {
+			if count == size {
+				current, count = newChunk(), 0
+				chunks = append(chunks, current)
+			}
+			n := min(size-count, len(vals))
+			current.Changes = append(current.Changes, ldap.Change{
+				Operation:    change.Operation,
+				Modification: ldap.PartialAttribute{Type: change.Modification.Type, Vals: vals[:n]},
+			})
+			vals = vals[n:]
+			count += n
+		}
+	}
+	last := chunks[len(chunks)-1]
+	last.Controls = append(last.Controls, postRead...)
+	return chunks
+}
+
+// sequenceModifyRequests returns the requests to send one after the other
+// but those without changes, with the Assertion control only on the first
+// one, since it changes the entry the others would assert on.
+func sequenceModifyRequests(requests []*ldap.ModifyRequest) []*ldap.ModifyRequest {
+	var sequence []*ldap.ModifyRequest
+	for _, request := range requests {
+		if len(request.Changes) == 0 {
+			continue
+		}
+		if len(sequence) > 0 {
+			var controls []ldap.Control
+			for _, control := range request.Controls {
+				if control.GetControlType() != client.OIDAssertion {
+					controls = append(controls, control)
+				}
+			}
+			request.Controls = controls
+		}
+		sequence = append(sequence, request)
+	}
+	return sequence
+}
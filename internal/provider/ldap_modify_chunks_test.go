@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestSplitModifyRequest(t *testing.T) {
+	members := func(from, to int) []string {
+		var values []string
+		for i := from; i < to; i++ {
+			values = append(values, fmt.Sprintf("uid=user%d,ou=people,dc=example,dc=com", i))
+		}
+		return values
+	}
+
+	request := ldap.NewModifyRequest("cn=huge,ou=groups,dc=example,dc=com", nil)
+	request.Replace("description", []string{"A huge group"})
+	request.Delete("member", members(0, 3))
+	request.Add("member", members(3, 10))
+	request.Delete("seeAlso", nil)
+
+	if chunks := splitModifyRequest(request, 0); len(chunks) != 1 || chunks[0] != request {
+		t.Fatalf("expected the request unchanged without a chunk size, got %d requests", len(chunks))
+	}
+
+	chunks := splitModifyRequest(request, 4)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(chunks))
+	}
+
+	var deleted, added []string
+	for i, chunk := range chunks {
+		count := 0
+		for _, change := range chunk.Changes {
+			switch {
+			case change.Modification.Type == "member" && change.Operation == ldap.DeleteAttribute:
+				deleted = append(deleted, change.Modification.Vals...)
+				count += len(change.Modification.Vals)
+			case change.Modification.Type == "member" && change.Operation == ldap.AddAttribute:
+				added = append(added, change.Modification.Vals...)
+				count += len(change.Modification.Vals)
+			case i != 0:
+				t.Errorf("unexpected change of %s in request %d", change.Modification.Type, i)
+			}
+		}
+		if count > 4 {
+			t.Errorf("request %d has %d member values, expected at most 4", i, count)
+		}
+	}
+	if fmt.Sprint(deleted) != fmt.Sprint(members(0, 3)) || fmt.Sprint(added) != fmt.Sprint(members(3, 10)) {
+		t.Errorf("the values were not preserved in order: deleted %v, added %v", deleted, added)
+	}
+}
+
+func TestSplitModifyRequestControls(t *testing.T) {
+	assertion, err := client.NewControlAssertion("(entryCSN=20240101000000.000000Z#000000#000#000000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	permissive := ldap.NewControlString(ldap.ControlTypeMicrosoftPermissiveModify, false, "")
+	postRead := &client.ControlPostRead{Attributes: []string{"member"}}
+
+	var members []string
+	for i := 0; i < 10; i++ {
+		members = append(members, fmt.Sprintf("uid=user%d,ou=people,dc=example,dc=com", i))
+	}
+	request := ldap.NewModifyRequest("cn=huge,ou=groups,dc=example,dc=com", []ldap.Control{assertion, permissive, postRead})
+	request.Add("member", members)
+
+	chunks := splitModifyRequest(request, 4)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		var types []string
+		for _, control := range chunk.Controls {
+			types = append(types, control.GetControlType())
+		}
+		want := []string{ldap.ControlTypeMicrosoftPermissiveModify}
+		if i == 0 {
+			want = append(want, client.OIDAssertion)
+		}
+		if i == len(chunks)-1 {
+			want = append(want, client.OIDPostRead)
+		}
+		if fmt.Sprint(types) != fmt.Sprint(want) {
+			t.Errorf("request %d has the controls %v, expected %v", i, types, want)
+		}
+	}
+}
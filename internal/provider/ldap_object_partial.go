@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	ldapObjectModeFull    = "full"
+	ldapObjectModePartial = "partial"
+)
+
+// objectModeSchema returns the schema of the mode of an ldap_object.
+func objectModeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeString,
+		Description: "How much of the entry Terraform manages: `full` creates and destroys the entry and manages all its " +
+			"user attributes, while `partial` manages an existing entry owned by another system (e.g. an HR synchronization), " +
+			"only reading, updating and removing the attributes set in `attributes` and `sensitive_attributes` and leaving the " +
+			"others untouched. In `partial` mode, creating the resource replaces the values of the configured attributes and " +
+			"adds the missing `object_classes`, destroying it only removes the configured attributes whatever `on_destroy`, the object classes are " +
+			"never removed, and changing the DN moves the configured attributes to the new entry. Switching an object, e.g. " +
+			"an imported one, to `partial` leaves the attributes it no longer configures untouched. Defaults to `full`.",
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{ldapObjectModeFull, ldapObjectModePartial}, false),
+	}
+}
+
+// modeGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type modeGetter interface {
+	Get(key string) interface{}
+}
+
+// isPartialObject tells whether the ldap_object only manages the attributes
+// it configures.
+func isPartialObject(d modeGetter) bool {
+	mode, _ := d.Get("mode").(string)
+	return mode == ldapObjectModePartial
+}
+
+// isFullObject is the customdiff condition of the checks which only make
+// sense for the objects managing their whole entry.
+func isFullObject(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+	return !isPartialObject(d)
+}
+
+// customizeDiffPartialObject moves the configured attributes to another entry
+// when the DN of a partial object changes, instead of renaming an entry which
+// is not Terraform's.
+func customizeDiffPartialObject(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !isPartialObject(d) || !d.HasChange("dn") {
+		return nil
+	}
+	return d.ForceNew("dn")
+}
+
+// partialObjectClasses returns the configured object classes the entry has,
+// which is what the state of a partial object holds.
+func partialObjectClasses(configured, entry []string) []string {
+	classes := []string{}
+	for _, class := range configured {
+		if containsFold(entry, class) {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
+// addMissingObjectClasses adds to the request the object classes the entry
+// does not have yet.
+func addMissingObjectClasses(request *ldap.ModifyRequest, meta interface{}, classes []string) error {
+	if len(classes) == 0 {
+		return nil
+	}
+	providerConfig := meta.(*ProviderConfig)
+	entry, err := searchEntry(providerConfig.Connection, request.DN, []string{"objectClass"}, providerConfig.DerefAliases)
+	if err != nil {
+		return fmt.Errorf("error reading the object classes of %q: %w", request.DN, err)
+	}
+	if entry == nil {
+		return fmt.Errorf("entry %q does not exist, a partial object only manages existing entries", request.DN)
+	}
+	missing := []string{}
+	for _, class := range classes {
+		if !containsFold(entry.GetAttributeValues("objectClass"), class) {
+			missing = append(missing, class)
+		}
+	}
+	if len(missing) > 0 {
+		request.Add("objectClass", missing)
+	}
+	return nil
+}
+
+// createPartialLDAPObject sets the configured attributes of an existing entry.
+func createPartialLDAPObject(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dn := d.Get("dn").(string)
+	controls, err := requestControls(d)
+	if err != nil {
+		return err
+	}
+	request := ldap.NewModifyRequest(dn, controls)
+
+	// the object classes are checked first, telling whether the entry exists
+	if err := addMissingObjectClasses(request, meta, convertToStringSlice(d.Get("object_classes").(*schema.Set).List())); err != nil {
+		return err
+	}
+	for name, values := range configuredAttributes(d) {
+		tflog.SubsystemTrace(ctx, subsystemObject, "replacing attribute", map[string]interface{}{
+			"dn":        dn,
+			"attribute": name,
+			"values":    logValues(name, values),
+		})
+		request.Replace(name, values)
+	}
+	if password, ok := passwordWriteOnly(d); ok {
+		request.Replace(passwordAttribute, []string{password})
+		request.Controls = withPasswordPolicyControl(request.Controls)
+	}
+	if err := modifyAccountControl(request, d, meta); err != nil {
+		return err
+	}
+	if err := modifyADTimes(request, d); err != nil {
+		return err
+	}
+	modifyProxyAddresses(request, d)
+	if err := modifyShadowAccount(request, d); err != nil {
+		return err
+	}
+
+	if len(request.Changes) == 0 {
+		return nil
+	}
+	return passwordPolicyError(meta.(*ProviderConfig).Connection.Modify(request))
+}
+
+// deletePartialLDAPObject removes the configured attributes of the entry,
+// one by one so that those already gone are skipped.
+func deletePartialLDAPObject(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	controls, err := requestControls(d)
+	if err != nil {
+		return err
+	}
+	for name := range configuredAttributes(d) {
+		tflog.SubsystemDebug(ctx, subsystemObject, "removing attribute", map[string]interface{}{
+			"dn":        dn,
+			"attribute": name,
+		})
+		request := ldap.NewModifyRequest(dn, controls)
+		request.Delete(name, nil)
+		if err := client.Modify(request); err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				tflog.SubsystemWarn(ctx, subsystemObject, "object not found, considering delete successful", map[string]interface{}{"dn": dn})
+				return nil
+			}
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchAttribute {
+				continue
+			}
+			return fmt.Errorf("error removing %s from %q: %w", name, dn, err)
+		}
+	}
+	return nil
+}
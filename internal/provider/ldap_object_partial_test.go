@@ -0,0 +1,21 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartialObjectClasses(t *testing.T) {
+	entry := []string{"top", "inetOrgPerson", "posixAccount"}
+	for _, tc := range []struct {
+		configured, expected []string
+	}{
+		{nil, []string{}},
+		{[]string{"posixaccount"}, []string{"posixaccount"}},
+		{[]string{"posixAccount", "ldapPublicKey"}, []string{"posixAccount"}},
+	} {
+		if classes := partialObjectClasses(tc.configured, entry); !reflect.DeepEqual(classes, tc.expected) {
+			t.Errorf("partialObjectClasses(%q) = %q, expected %q", tc.configured, classes, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// accountControlDisabled is the ACCOUNTDISABLE flag of userAccountControl.
+const accountControlDisabled = 0x0002
+
+func onDestroySchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "What destroying the resource does to its entry, for the directories where entries must not be deleted. " +
+			"Like the rest of the state, it must be applied before it affects a destroy.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"action": {
+					Type: schema.TypeString,
+					Description: "`delete` removes the entry, `disable` sets `attribute` to `value` instead, and `move_to` moves " +
+						"the entry under `parent_dn`, e.g. an archive organizational unit.",
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{"delete", "disable", "move_to"}, false),
+				},
+				"attribute": {
+					Type:        schema.TypeString,
+					Description: "The attribute disabling the entry, e.g. `userAccountControl` on Active Directory. Default: nsAccountLock.",
+					Optional:    true,
+					Default:     "nsAccountLock",
+				},
+				"value": {
+					Type: schema.TypeString,
+					Description: "The value of `attribute` disabling the entry. Default: TRUE, or the current value with the " +
+						"ACCOUNTDISABLE flag set for `userAccountControl`.",
+					Optional: true,
+				},
+				"parent_dn": {
+					Type:         schema.TypeString,
+					Description:  "The DN under which `move_to` moves the entry, keeping its RDN.",
+					Optional:     true,
+					ValidateFunc: validateDN,
+				},
+			},
+		},
+	}
+}
+
+// customizeDiffOnDestroy checks that the on_destroy block sets the parent DN
+// of its move_to action.
+func customizeDiffOnDestroy(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("on_destroy.0.action").(string) != "move_to" || !d.NewValueKnown("on_destroy.0.parent_dn") {
+		return nil
+	}
+	if d.Get("on_destroy.0.parent_dn").(string) == "" {
+		return fmt.Errorf("on_destroy: parent_dn must be set with the move_to action")
+	}
+	return nil
+}
+
+// destroyLDAPEntry deletes, disables or moves the entry of a resource on
+// destroy, according to its on_destroy block, then removes the references to
+// deleted and moved entries (see cleanupMemberships). The controls of the
+// resource are attached to the delete or modify request.
+func destroyLDAPEntry(ctx context.Context, d *schema.ResourceData, meta interface{}, logPrefix string) error {
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+	fields := map[string]interface{}{"operation": logPrefix, "dn": dn}
+	controls, err := requestControls(d)
+	if err != nil {
+		return err
+	}
+
+	switch d.Get("on_destroy.0.action").(string) {
+	case "disable":
+		attribute := d.Get("on_destroy.0.attribute").(string)
+		value, err := disabledValue(providerConfig, dn, attribute, d.Get("on_destroy.0.value").(string))
+		if err != nil || value == "" {
+			return err
+		}
+		fields["attribute"] = attribute
+		tflog.Debug(ctx, "disabling entry instead of removing it", fields)
+		request := ldap.NewModifyRequest(dn, controls)
+		request.Replace(attribute, []string{value})
+		if err := providerConfig.Connection.Modify(request); err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				tflog.Warn(ctx, "entry does not exist in LDAP, considering destroy successful", fields)
+				return nil
+			}
+			return fmt.Errorf("error disabling %q: %w", dn, err)
+		}
+		return nil
+	case "move_to":
+		attribute, value, _, err := splitDN(dn)
+		if err != nil {
+			return err
+		}
+		newDN := joinDN(attribute, value, d.Get("on_destroy.0.parent_dn").(string))
+		fields["new_dn"] = newDN
+		tflog.Debug(ctx, "moving entry instead of removing it", fields)
+		if err := renameLDAPEntry(ctx, meta, dn, newDN, nil); err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				tflog.Warn(ctx, "entry does not exist in LDAP, considering destroy successful", fields)
+				return nil
+			}
+			return fmt.Errorf("error moving %q to %q: %w", dn, newDN, err)
+		}
+		return cleanupMemberships(ctx, d, meta)
+	default:
+		if err := deleteLDAPEntry(ctx, providerConfig.Connection, dn, logPrefix, controls...); err != nil {
+			return err
+		}
+		return cleanupMemberships(ctx, d, meta)
+	}
+}
+
+// disabledValue returns the value disabling an entry: the configured one, or
+// TRUE, or the current userAccountControl with the ACCOUNTDISABLE flag set;
+// it is empty when the entry no longer exists.
+func disabledValue(providerConfig *ProviderConfig, dn, attribute, value string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if !strings.EqualFold(attribute, accountControlAttribute) {
+		return "TRUE", nil
+	}
+	entry, err := searchEntry(providerConfig.Connection, dn, []string{accountControlAttribute}, providerConfig.DerefAliases)
+	if err != nil || entry == nil {
+		return "", err
+	}
+	flags := int64(accountControlNormal)
+	if current := entry.GetAttributeValue(accountControlAttribute); current != "" {
+		if flags, err = parseAccountControl(current); err != nil {
+			return "", err
+		}
+	}
+	return strconv.FormatInt(flags|accountControlDisabled, 10), nil
+}
@@ -0,0 +1,21 @@
+package provider
+
+import "testing"
+
+func TestDisabledValue(t *testing.T) {
+	for _, tc := range []struct {
+		attribute, value, expected string
+	}{
+		{"nsAccountLock", "", "TRUE"},
+		{"pwdAccountLockedTime", "000001010000Z", "000001010000Z"},
+		{"userAccountControl", "514", "514"},
+	} {
+		value, err := disabledValue(nil, "uid=jdoe,dc=example,dc=com", tc.attribute, tc.value)
+		if err != nil {
+			t.Fatalf("disabledValue(%q, %q) returned an error: %v", tc.attribute, tc.value, err)
+		}
+		if value != tc.expected {
+			t.Errorf("disabledValue(%q, %q) = %q, expected %q", tc.attribute, tc.value, value, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func operationRetrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "Retry the operations failing while the server is busy, unavailable, in maintenance or not " +
+			"responding, with an exponential backoff, so that short outages of a replica do not fail the apply. " +
+			"Updates interrupted by network errors may have been applied before being retried, and then fail, " +
+			"e.g. with entryAlreadyExists.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"attempts": {
+					Type:         schema.TypeInt,
+					Description:  "The maximum number of attempts of each operation (default: 3).",
+					Optional:     true,
+					Default:      3,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"min_backoff": {
+					Type:         schema.TypeString,
+					Description:  "The time to wait after the first failed attempt, doubled at each attempt, as a duration (default: 1s).",
+					Optional:     true,
+					Default:      "1s",
+					ValidateFunc: validateDuration,
+				},
+				"max_backoff": {
+					Type:         schema.TypeString,
+					Description:  "The maximum time to wait between attempts, as a duration (default: 30s).",
+					Optional:     true,
+					Default:      "30s",
+					ValidateFunc: validateDuration,
+				},
+			},
+		},
+	}
+}
+
+// operationRetry returns the number of attempts of the operations and the
+// bounds of the backoff between them set in the operation_retry block;
+// without the block, operations are attempted once.
+func operationRetry(d *schema.ResourceData) (int, time.Duration, time.Duration) {
+	blocks := d.Get("operation_retry").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return 1, 0, 0
+	}
+	block := blocks[0].(map[string]interface{})
+	// the values were validated by validateDuration
+	minBackoff, _ := time.ParseDuration(block["min_backoff"].(string))
+	maxBackoff, _ := time.ParseDuration(block["max_backoff"].(string))
+	return block["attempts"].(int), minBackoff, maxBackoff
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestOperationRetry(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	if attempts, _, _ := operationRetry(d); attempts != 1 {
+		t.Errorf("expected a single attempt without the block, got %d", attempts)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"operation_retry": []interface{}{map[string]interface{}{
+			"attempts":    5,
+			"max_backoff": "1m",
+		}},
+	})
+	attempts, minBackoff, maxBackoff := operationRetry(d)
+	if attempts != 5 || minBackoff != time.Second || maxBackoff != time.Minute {
+		t.Errorf("got %d attempts with a backoff from %s to %s", attempts, minBackoff, maxBackoff)
+	}
+}
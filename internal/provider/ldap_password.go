@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// passwordAttribute is the attribute set from the password_wo write-only
+// argument.
+const passwordAttribute = "userPassword"
+
+// passwordSchema returns the arguments setting the password of an entry
+// without storing it in the state.
+func passwordSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"password_wo": {
+			Type: schema.TypeString,
+			Description: "The password of the entry, set as its `userPassword`. It is write-only: it is sent to the " +
+				"directory but never stored in the plan nor in the state, and requires Terraform 1.11 or later. It is " +
+				"only sent when the entry is created or when `password_version` changes.",
+			Optional:  true,
+			Sensitive: true,
+			WriteOnly: true,
+		},
+		"password_version": {
+			Type: schema.TypeInt,
+			Description: "The version of `password_wo`, starting at 1; change it to send a new password. " +
+				"Required with `password_wo`. While it is set, `userPassword` is not read back from the entry.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+	}
+}
+
+// passwordWriteOnly returns the value of password_wo from the configuration;
+// write-only arguments are not part of the state, so it is only available
+// while applying a change.
+func passwordWriteOnly(d *schema.ResourceData) (string, bool) {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().HasAttribute("password_wo") {
+		return "", false
+	}
+	v := config.GetAttr("password_wo")
+	if v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// managesPassword tells whether the password of the entry is set through
+// password_wo, in which case it is not read back.
+func managesPassword(d *schema.ResourceData, name string) bool {
+	return d.Get("password_version").(int) > 0 && strings.EqualFold(name, passwordAttribute)
+}
+
+// customizeDiffPassword requires password_version along with password_wo, so
+// that password changes can be detected without storing the password.
+func customizeDiffPassword(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().HasAttribute("password_wo") {
+		return nil
+	}
+	if v := config.GetAttr("password_wo"); v.IsNull() {
+		return nil
+	}
+	if !d.NewValueKnown("password_version") {
+		return nil
+	}
+	if d.Get("password_version").(int) == 0 {
+		return fmt.Errorf("password_version must be set along with password_wo")
+	}
+	for name := range configuredAttributes(d) {
+		if strings.EqualFold(name, passwordAttribute) {
+			return fmt.Errorf("%s cannot be set in attributes along with password_wo", passwordAttribute)
+		}
+	}
+	return nil
+}
+
+// withPasswordPolicyControl adds the Password Policy control to the controls
+// of a request writing a password, so that the servers with a password policy
+// tell why they reject it (see passwordPolicyError).
+func withPasswordPolicyControl(controls []ldap.Control) []ldap.Control {
+	return append(controls, ldap.NewControlBeheraPasswordPolicy())
+}
+
+// passwordPolicyError adds to the error of a password write the Password
+// Policy error returned along with it, if any, e.g. "Password is too young to
+// change".
+func passwordPolicyError(err error) error {
+	if policyErr := client.PasswordPolicyError(err); policyErr != "" {
+		return fmt.Errorf("%w (password policy: %s)", err, policyErr)
+	}
+	return err
+}
+
+// passwordCharacters are the characters of generated passwords; they avoid
+// the characters needing escaping in LDIF, filters or shells.
+const passwordCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_.+!@%"
+
+// generatePassword returns a random password of the given length.
+func generatePassword(length int) (string, error) {
+	password := make([]byte, length)
+	max := big.NewInt(int64(len(passwordCharacters)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("error generating a password: %w", err)
+		}
+		password[i] = passwordCharacters[n.Int64()]
+	}
+	return string(password), nil
+}
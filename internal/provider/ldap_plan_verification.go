@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Values of the plan_verification setting of the provider.
+const (
+	planVerificationOff   = "off"
+	planVerificationWarn  = "warn"
+	planVerificationError = "error"
+)
+
+// customizeDiffVerifyPlan submits the planned changes of the attributes and
+// object classes of an existing object with the No-Op control, when the
+// provider's plan_verification asks for it and the server supports the
+// control, so that the changes the server would reject are reported while
+// planning rather than when applying.
+func customizeDiffVerifyPlan(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok || providerConfig == nil || providerConfig.PlanVerification == "" || providerConfig.PlanVerification == planVerificationOff {
+		return nil
+	}
+	// new entries do not exist yet, and renamed ones are modified once
+	// renamed
+	if d.Id() == "" || d.HasChange("dn") {
+		return nil
+	}
+	for _, key := range append([]string{"object_classes"}, attributeKeys...) {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+
+	dn := d.Get("dn").(string)
+	fields := map[string]interface{}{"dn": dn}
+	if !providerConfig.Connection.SupportsControl(client.OIDNoOp) {
+		tflog.Debug(ctx, "the server does not advertise the No-Op control, not verifying the plan", fields)
+		return nil
+	}
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{client.NewControlNoOp()})
+	addPlannedChanges(ctx, request, d)
+	if len(request.Changes) == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "verifying the planned changes with the No-Op control", fields)
+	err := providerConfig.Connection.Modify(request)
+	if err == nil {
+		// the control is critical, so this should not happen
+		tflog.Warn(ctx, "the server applied the changes sent with the No-Op control", fields)
+		return nil
+	}
+	ldapErr, ok := err.(*ldap.Error)
+	switch {
+	case ok && ldapErr.ResultCode == client.ResultNoOperation:
+		return nil
+	case ok && ldapErr.ResultCode == ldap.LDAPResultObjectClassModsProhibited:
+		// the structural object class changes, the entry is replaced (see
+		// customizeDiffObjectClasses)
+		return nil
+	case !ok:
+		fields["error"] = err.Error()
+		tflog.Warn(ctx, "unable to verify the plan", fields)
+		return nil
+	}
+
+	err = fmt.Errorf("the server would reject the planned changes of %q: %w", dn, err)
+	if providerConfig.PlanVerification == planVerificationError {
+		return err
+	}
+	fields["error"] = err.Error()
+	tflog.Warn(ctx, "the server would reject the planned changes", fields)
+	return nil
+}
+
+// addPlannedChanges adds to the request the planned changes of the free-form
+// attributes and object classes of an object, the values of the changed
+// attributes being replaced.
+func addPlannedChanges(ctx context.Context, request *ldap.ModifyRequest, d *schema.ResourceDiff) {
+	if d.HasChange("object_classes") {
+		o, n := d.GetChange("object_classes")
+		if added := n.(*schema.Set).Difference(o.(*schema.Set)); added.Len() > 0 {
+			request.Add("objectClass", convertToStringSlice(added.List()))
+		}
+		// the classes of a partial object are only ever added
+		if removed := o.(*schema.Set).Difference(n.(*schema.Set)); removed.Len() > 0 && !isPartialObject(d) {
+			request.Delete("objectClass", convertToStringSlice(removed.List()))
+		}
+	}
+
+	o, n := attributesChange(d)
+	added, changed, removed := computeDeltas(ctx, o, n)
+	for _, attribute := range append(added, changed...) {
+		request.Replace(attribute.Type, attribute.Vals)
+	}
+	for _, attribute := range removed {
+		request.Delete(attribute.Type, nil)
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// modifyWithPostRead issues a modify request and, when the server supports
+// the Post-Read control (RFC 4527), returns the given attributes of the entry
+// as the server left it; the returned entry is nil otherwise, in which case
+// the caller should read the entry back. Adds have no counterpart, go-ldap
+// not returning the controls of add responses, so that created entries are
+// always read back.
+func modifyWithPostRead(ctx context.Context, meta interface{}, request *ldap.ModifyRequest, attributes []string) (*ldap.Entry, error) {
+	providerConfig := meta.(*ProviderConfig)
+	conn := providerConfig.Connection
+
+	postRead := providerConfig.SupportsPostRead(ctx)
+	if postRead {
+		request.Controls = append(request.Controls, &client.ControlPostRead{Attributes: attributes})
+	}
+
+	result, err := conn.ModifyWithResult(request)
+	if err != nil {
+		return nil, err
+	}
+	if !postRead {
+		return nil, nil
+	}
+
+	entry, err := client.PostReadEntry(result.Controls)
+	if err != nil {
+		tflog.Warn(ctx, "ignoring the Post-Read response", map[string]interface{}{
+			"dn":    request.DN,
+			"error": err.Error(),
+		})
+		return nil, nil
+	}
+	return entry, nil
+}
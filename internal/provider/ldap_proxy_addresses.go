@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// proxyAddressesAttribute is the Exchange attribute set from the
+// proxy_addresses block.
+const proxyAddressesAttribute = "proxyAddresses"
+
+func proxyAddressesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "The Exchange `proxyAddresses` of the entry: the primary SMTP address is written with the " +
+			"`SMTP:` prefix and the aliases with the `smtp:` one. Changes add and remove single values rather than " +
+			"replacing all of them. `proxyAddresses` cannot be set in `attributes` along with this block.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"primary_smtp": {
+					Type:        schema.TypeString,
+					Description: "The primary SMTP address, e.g. jdoe@example.com.",
+					Required:    true,
+				},
+				"aliases": {
+					Type: schema.TypeSet,
+					Description: "The secondary addresses: SMTP addresses (e.g. john.doe@example.com), or addresses " +
+						"of other types with their prefix (e.g. `X500:/o=Example/cn=jdoe` or `sip:jdoe@example.com`).",
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// proxyAddressesConfig returns the fields of the proxy_addresses block, if it
+// is set.
+func proxyAddressesConfig(d attributeGetter) (map[string]interface{}, bool) {
+	v, ok := d.GetOk("proxy_addresses")
+	if !ok {
+		return nil, false
+	}
+	blocks := v.([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, false
+	}
+	return blocks[0].(map[string]interface{}), true
+}
+
+// managesProxyAddresses tells whether proxyAddresses is managed through the
+// proxy_addresses block, in which case it is not read back as an ordinary
+// attribute.
+func managesProxyAddresses(d attributeGetter, name string) bool {
+	_, ok := proxyAddressesConfig(d)
+	return ok && strings.EqualFold(name, proxyAddressesAttribute)
+}
+
+// renderProxyAddresses returns the values of proxyAddresses for a primary
+// SMTP address and its aliases; aliases without a prefix are SMTP addresses.
+func renderProxyAddresses(primary string, aliases []string) []string {
+	values := []string{"SMTP:" + primary}
+	for _, alias := range aliases {
+		prefix, address, found := strings.Cut(alias, ":")
+		switch {
+		case !found:
+			values = append(values, "smtp:"+alias)
+		case strings.EqualFold(prefix, "smtp"):
+			// only the primary address has the upper-case prefix
+			values = append(values, "smtp:"+address)
+		default:
+			values = append(values, alias)
+		}
+	}
+	return sortValues(values)
+}
+
+// parseProxyAddresses returns the primary SMTP address and the aliases held
+// by the values of proxyAddresses; the prefix of SMTP aliases is removed.
+func parseProxyAddresses(values []string) (primary string, aliases []string) {
+	for _, value := range values {
+		prefix, address, found := strings.Cut(value, ":")
+		switch {
+		case found && prefix == "SMTP":
+			primary = address
+		case found && prefix == "smtp":
+			aliases = append(aliases, address)
+		default:
+			aliases = append(aliases, value)
+		}
+	}
+	return primary, aliases
+}
+
+// proxyAddressesValues returns the values of proxyAddresses configured in a
+// proxy_addresses block.
+func proxyAddressesValues(block map[string]interface{}) []string {
+	var aliases []string
+	if v, ok := block["aliases"].(*schema.Set); ok {
+		aliases = convertToStringSlice(v.List())
+	}
+	return renderProxyAddresses(block["primary_smtp"].(string), aliases)
+}
+
+// addProxyAddresses sets proxyAddresses in a request creating an entry.
+func addProxyAddresses(request *ldap.AddRequest, d *schema.ResourceData) {
+	block, ok := proxyAddressesConfig(d)
+	if !ok {
+		return
+	}
+	request.Attribute(proxyAddressesAttribute, proxyAddressesValues(block))
+}
+
+// modifyProxyAddresses removes and adds the values of proxyAddresses which
+// changed in the proxy_addresses block; the removals come first, so that an
+// alias can become the primary address (or the reverse) in one request.
+func modifyProxyAddresses(request *ldap.ModifyRequest, d *schema.ResourceData) {
+	if !d.HasChange("proxy_addresses") {
+		return
+	}
+	block, ok := proxyAddressesConfig(d)
+	if !ok {
+		// the block was removed: the addresses are left as they are
+		return
+	}
+	var old []string
+	if o, _ := d.GetChange("proxy_addresses"); len(o.([]interface{})) > 0 && o.([]interface{})[0] != nil {
+		old = proxyAddressesValues(o.([]interface{})[0].(map[string]interface{}))
+	}
+	current := proxyAddressesValues(block)
+
+	added := valuesNotIn(current, old)
+	removed := valuesNotIn(old, current)
+	if len(removed) > 0 {
+		request.Delete(proxyAddressesAttribute, removed)
+	}
+	if len(added) > 0 {
+		request.Add(proxyAddressesAttribute, added)
+	}
+}
+
+// valuesNotIn returns the values of a which are not in b, comparing them
+// exactly, since proxyAddresses tells the primary address by its case.
+func valuesNotIn(a, b []string) []string {
+	in := map[string]bool{}
+	for _, v := range b {
+		in[v] = true
+	}
+	var diff []string
+	for _, v := range a {
+		if !in[v] {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
+
+// readProxyAddresses sets the proxy_addresses block from the values of
+// proxyAddresses, when the block is set.
+func readProxyAddresses(d *schema.ResourceData, entry *ldap.Entry) error {
+	if _, ok := proxyAddressesConfig(d); !ok {
+		return nil
+	}
+	values := entry.GetEqualFoldAttributeValues(proxyAddressesAttribute)
+	if len(values) == 0 {
+		return d.Set("proxy_addresses", nil)
+	}
+	primary, aliases := parseProxyAddresses(values)
+	return d.Set("proxy_addresses", []interface{}{map[string]interface{}{
+		"primary_smtp": primary,
+		"aliases":      aliases,
+	}})
+}
+
+// customizeDiffProxyAddresses forbids setting proxyAddresses both as an
+// attribute and through the proxy_addresses block.
+func customizeDiffProxyAddresses(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := proxyAddressesConfig(d); !ok {
+		return nil
+	}
+	for name := range configuredAttributes(d) {
+		if strings.EqualFold(name, proxyAddressesAttribute) {
+			return fmt.Errorf("%s cannot be set in attributes along with proxy_addresses", proxyAddressesAttribute)
+		}
+	}
+	return nil
+}
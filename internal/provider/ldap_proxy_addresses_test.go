@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderProxyAddresses(t *testing.T) {
+	got := renderProxyAddresses("jdoe@example.com", []string{
+		"john.doe@example.com",
+		"SMTP:j.doe@example.com",
+		"X500:/o=Example/cn=jdoe",
+	})
+	want := []string{
+		"SMTP:jdoe@example.com",
+		"X500:/o=Example/cn=jdoe",
+		"smtp:j.doe@example.com",
+		"smtp:john.doe@example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderProxyAddresses() = %v, want %v", got, want)
+	}
+}
+
+func TestParseProxyAddresses(t *testing.T) {
+	primary, aliases := parseProxyAddresses([]string{
+		"smtp:john.doe@example.com",
+		"SMTP:jdoe@example.com",
+		"sip:jdoe@example.com",
+	})
+	if primary != "jdoe@example.com" {
+		t.Errorf("expected primary jdoe@example.com, got %q", primary)
+	}
+	if want := []string{"john.doe@example.com", "sip:jdoe@example.com"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("expected aliases %v, got %v", want, aliases)
+	}
+}
+
+func TestValuesNotIn(t *testing.T) {
+	// swapping the primary address and an alias removes and adds both values
+	old := renderProxyAddresses("jdoe@example.com", []string{"john.doe@example.com", "sip:jdoe@example.com"})
+	current := renderProxyAddresses("john.doe@example.com", []string{"jdoe@example.com", "sip:jdoe@example.com"})
+	if got, want := valuesNotIn(old, current), []string{"SMTP:jdoe@example.com", "smtp:john.doe@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected removed values %v, got %v", want, got)
+	}
+	if got, want := valuesNotIn(current, old), []string{"SMTP:john.doe@example.com", "smtp:jdoe@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected added values %v, got %v", want, got)
+	}
+	if got := valuesNotIn(old, old); len(got) != 0 {
+		t.Errorf("expected no values, got %v", got)
+	}
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// rdnKeys are the fields an entry's DN can be declared with instead of dn.
+var rdnKeys = []string{"rdn_attribute", "rdn_value", "parent_dn"}
+
+// rdnSchema returns the fields declaring the DN of an entry by its parts, or
+// relative to the base_dn of the provider; dn is then computed from them, or
+// the parts are computed from dn when it is set.
+func rdnSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"relative_dn": {
+			Type:         schema.TypeString,
+			ValidateFunc: validateDN,
+			Description:  "The DN of the entry relative to the provider's `base_dn` (e.g. `cn=admins,ou=groups`), to declare it instead of `dn`, so that the configuration does not depend on the directory suffix. Changing it moves or renames the entry in place.",
+			Optional:     true,
+		},
+		"rdn_attribute": {
+			Type:         schema.TypeString,
+			Description:  "The attribute naming the entry among its siblings (e.g. cn, uid or ou), to declare it instead of `dn`. Computed from `dn` otherwise.",
+			Optional:     true,
+			Computed:     true,
+			RequiredWith: rdnKeys,
+		},
+		"rdn_value": {
+			Type:         schema.TypeString,
+			Description:  "The value of `rdn_attribute`, unescaped: `dn` is computed with the characters it cannot hold as they are escaped (RFC 4514). Computed from `dn` otherwise.",
+			Optional:     true,
+			Computed:     true,
+			RequiredWith: rdnKeys,
+		},
+		"parent_dn": {
+			Type:         schema.TypeString,
+			ValidateFunc: validateDN,
+			Description:  "The DN of the parent of the entry. Changing it, or `rdn_value`, moves or renames the entry in place. Computed from `dn` otherwise.",
+			Optional:     true,
+			Computed:     true,
+			RequiredWith: rdnKeys,
+		},
+	}
+}
+
+// joinDN returns the DN of the entry named attribute=value under parent.
+func joinDN(attribute, value, parent string) string {
+	dn := attribute + "=" + ldap.EscapeDN(value)
+	if parent != "" {
+		dn += "," + parent
+	}
+	return dn
+}
+
+// validateDN checks that the value is a non-empty DN (RFC 4514), so that
+// typos such as a missing "=" or an unescaped comma are reported when
+// planning.
+func validateDN(v interface{}, k string) (ws []string, errs []error) {
+	dn := v.(string)
+	parsed, err := ldap.ParseDN(dn)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("%s: invalid DN %q: %w", k, dn, err))
+	case len(parsed.RDNs) == 0:
+		errs = append(errs, fmt.Errorf("%s: the DN must not be empty", k))
+	}
+	return
+}
+
+// canonicalDN returns a DN with lower-cased attribute types and without
+// spaces around its separators, keeping the case of its values; DNs which
+// cannot be parsed are returned as is.
+func canonicalDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return dn
+	}
+	rdns := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		parts := make([]string, len(rdn.Attributes))
+		for j, attribute := range rdn.Attributes {
+			parts[j] = strings.ToLower(attribute.Type) + "=" + ldap.EscapeDN(attribute.Value)
+		}
+		rdns[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(rdns, ",")
+}
+
+// hashDN hashes DNs so that equivalent DNs (see normalizeDN) hash the same.
+func hashDN(v interface{}) int {
+	return schema.HashString(normalizeDN(v.(string)))
+}
+
+// suppressEquivalentDN ignores the changes between equivalent DNs in a set of
+// DNs hashed with hashDN.
+func suppressEquivalentDN(k, old, new string, d *schema.ResourceData) bool {
+	if strings.HasSuffix(k, ".#") {
+		return false
+	}
+	return normalizeDN(old) == normalizeDN(new)
+}
+
+// splitDN returns the attribute and unescaped value of the RDN of a DN, and
+// the DN of its parent as it is written. Only the first attribute of
+// multi-valued RDNs is returned.
+func splitDN(dn string) (attribute, value, parent string, err error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid DN %q: %w", dn, err)
+	}
+	if len(parsed.RDNs) == 0 {
+		return "", "", "", fmt.Errorf("invalid DN %q: empty", dn)
+	}
+	// the parent starts after the first comma which is not escaped
+	for i, escaped := 0, false; i < len(dn); i++ {
+		if escaped {
+			escaped = false
+		} else if dn[i] == '\\' {
+			escaped = true
+		} else if dn[i] == ',' {
+			parent = strings.TrimSpace(dn[i+1:])
+			break
+		}
+	}
+	rdn := parsed.RDNs[0].Attributes[0]
+	return rdn.Type, rdn.Value, parent, nil
+}
+
+// declaresRDN tells whether the DN of a resource is declared by its parts
+// rather than by dn.
+func declaresRDN(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	return !config.IsNull() && config.IsKnown() && config.Type().HasAttribute("dn") && config.GetAttr("dn").IsNull()
+}
+
+// declaresRelativeDN tells whether the DN of a resource is declared relative
+// to the base_dn of the provider.
+func declaresRelativeDN(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	return !config.IsNull() && config.IsKnown() && config.Type().HasAttribute("relative_dn") && !config.GetAttr("relative_dn").IsNull()
+}
+
+// baseDNJoin returns the DN of an entry declared by its relative_dn, under the
+// base_dn of the provider.
+func baseDNJoin(relativeDN string, meta interface{}) (string, error) {
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok || providerConfig == nil || providerConfig.BaseDN == "" {
+		return "", fmt.Errorf("relative_dn %q requires the base_dn of the provider to be set", relativeDN)
+	}
+	return relativeDN + "," + providerConfig.BaseDN, nil
+}
+
+// customizeDiffRDN computes dn from relative_dn, or from rdn_attribute,
+// rdn_value and parent_dn when the DN is declared by its parts, and those
+// from dn otherwise.
+func customizeDiffRDN(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	if declaresRelativeDN(d) {
+		if !d.NewValueKnown("relative_dn") {
+			for _, key := range append([]string{"dn"}, rdnKeys...) {
+				if err := d.SetNewComputed(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		dn, err := baseDNJoin(d.Get("relative_dn").(string), meta)
+		if err != nil {
+			return err
+		}
+		if dn != d.Get("dn").(string) {
+			if err := d.SetNew("dn", dn); err != nil {
+				return err
+			}
+		}
+		return setNewRDN(d, dn)
+	}
+	if declaresRDN(d) {
+		for _, key := range rdnKeys {
+			if !d.NewValueKnown(key) {
+				return d.SetNewComputed("dn")
+			}
+		}
+		dn := joinDN(d.Get("rdn_attribute").(string), d.Get("rdn_value").(string), d.Get("parent_dn").(string))
+		if dn == d.Get("dn").(string) {
+			return nil
+		}
+		return d.SetNew("dn", dn)
+	}
+
+	if !d.NewValueKnown("dn") {
+		for _, key := range rdnKeys {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return setNewRDN(d, d.Get("dn").(string))
+}
+
+// setNewRDN plans rdn_attribute, rdn_value and parent_dn from dn.
+func setNewRDN(d *schema.ResourceDiff, dn string) error {
+	attribute, value, parent, err := splitDN(dn)
+	if err != nil {
+		return err
+	}
+	for key, v := range map[string]string{"rdn_attribute": attribute, "rdn_value": value, "parent_dn": parent} {
+		if d.Get(key).(string) != v {
+			if err := d.SetNew(key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setRDN sets rdn_attribute, rdn_value and parent_dn from dn.
+func setRDN(d *schema.ResourceData) error {
+	attribute, value, parent, err := splitDN(d.Get("dn").(string))
+	if err != nil {
+		return err
+	}
+	d.Set("rdn_attribute", attribute)
+	d.Set("rdn_value", value)
+	d.Set("parent_dn", parent)
+	return nil
+}
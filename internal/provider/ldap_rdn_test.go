@@ -0,0 +1,79 @@
+package provider
+
+import "testing"
+
+func TestJoinAndSplitDN(t *testing.T) {
+	for _, tc := range []struct {
+		attribute, value, parent, dn string
+	}{
+		{"cn", "jdoe", "ou=people,dc=example,dc=com", "cn=jdoe,ou=people,dc=example,dc=com"},
+		{"cn", "Doe, John", "ou=people,dc=example,dc=com", `cn=Doe\, John,ou=people,dc=example,dc=com`},
+		{"cn", "a+b=c", `ou=a\,b,dc=example,dc=com`, `cn=a\+b=c,ou=a\,b,dc=example,dc=com`},
+		{"dc", "com", "", "dc=com"},
+	} {
+		if dn := joinDN(tc.attribute, tc.value, tc.parent); dn != tc.dn {
+			t.Errorf("joinDN(%q, %q, %q) = %q, expected %q", tc.attribute, tc.value, tc.parent, dn, tc.dn)
+		}
+		attribute, value, parent, err := splitDN(tc.dn)
+		if err != nil {
+			t.Fatalf("splitDN(%q): %v", tc.dn, err)
+		}
+		if attribute != tc.attribute || value != tc.value || parent != tc.parent {
+			t.Errorf("splitDN(%q) = %q, %q, %q, expected %q, %q, %q", tc.dn, attribute, value, parent, tc.attribute, tc.value, tc.parent)
+		}
+	}
+}
+
+func TestSplitDNInvalid(t *testing.T) {
+	if _, _, _, err := splitDN("not a dn"); err == nil {
+		t.Error("expected an error for an invalid DN")
+	}
+}
+
+func TestCanonicalDN(t *testing.T) {
+	for dn, expected := range map[string]string{
+		"CN=John Doe, OU=People, DC=example,DC=com": "cn=John Doe,ou=People,dc=example,dc=com",
+		`cn=Doe\, John,dc=example,dc=com`:           `cn=Doe\, John,dc=example,dc=com`,
+		"not a dn":                                  "not a dn",
+	} {
+		if canonical := canonicalDN(dn); canonical != expected {
+			t.Errorf("canonicalDN(%q) = %q, expected %q", dn, canonical, expected)
+		}
+	}
+	if hashDN("CN=John Doe, DC=example,DC=com") != hashDN("cn=john doe,dc=example,dc=com") {
+		t.Error("expected equivalent DNs to hash the same")
+	}
+}
+
+func TestValidateDN(t *testing.T) {
+	for dn, valid := range map[string]bool{
+		"cn=jdoe,ou=people,dc=example,dc=com":       true,
+		`cn=Doe\, John,ou=people,dc=example,dc=com`: true,
+		"CN=John Doe, OU=People, DC=example":        true,
+		"cn=Doe, John,dc=example,dc=com":            false,
+		"cn=jdoe,example":                           false,
+		"not a dn":                                  false,
+		"":                                          false,
+	} {
+		_, errs := validateDN(dn, "dn")
+		if valid && len(errs) > 0 {
+			t.Errorf("validateDN(%q): unexpected errors %v", dn, errs)
+		}
+		if !valid && len(errs) == 0 {
+			t.Errorf("validateDN(%q): expected an error", dn)
+		}
+	}
+}
+
+func TestBaseDNJoin(t *testing.T) {
+	dn, err := baseDNJoin("cn=admins,ou=groups", &ProviderConfig{BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dn != "cn=admins,ou=groups,dc=example,dc=com" {
+		t.Errorf("unexpected DN %q", dn)
+	}
+	if _, err := baseDNJoin("cn=admins,ou=groups", &ProviderConfig{}); err == nil {
+		t.Error("expected an error without base_dn")
+	}
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// readAfterWriteMinBackoff is the time waited before reading a written
+	// entry again, doubled at each attempt up to readAfterWriteMaxBackoff.
+	readAfterWriteMinBackoff = 100 * time.Millisecond
+	readAfterWriteMaxBackoff = 2 * time.Second
+)
+
+type afterWriteKey struct{}
+
+// afterWrite marks the context of the reads following a write, which retry
+// while what was written is not visible yet (see readAfterWrite).
+func afterWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, afterWriteKey{}, true)
+}
+
+// readAfterWrite calls read, which tells whether it found what it looks for,
+// e.g. an entry carrying the values written to it (see writesVisible). When
+// the context follows a write (see afterWrite), it is called again with a
+// backoff until it does or the read_after_write_timeout of the provider
+// elapses, since a server behind a load balancer may read from a replica
+// which did not receive the write yet.
+func readAfterWrite(ctx context.Context, meta interface{}, read func() (bool, error)) error {
+	found, err := read()
+	timeout := meta.(*ProviderConfig).ReadAfterWriteTimeout
+	if err != nil || found || timeout <= 0 || ctx.Value(afterWriteKey{}) == nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	backoff := readAfterWriteMinBackoff
+	for attempt := 2; ; attempt++ {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil
+		}
+		if backoff < wait {
+			wait = backoff
+		}
+		tflog.Debug(ctx, "written entry or values not visible yet, reading it again", map[string]interface{}{
+			"attempt": attempt,
+			"wait":    wait.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if found, err = read(); err != nil || found {
+			return err
+		}
+		if backoff *= 2; backoff > readAfterWriteMaxBackoff {
+			backoff = readAfterWriteMaxBackoff
+		}
+	}
+}
+
+// writesVisible tells whether an entry read after a write carries the values
+// written to it through the provider (see client.Pool.Written), among the
+// attributes read, "*" standing for all the user attributes. The values of
+// the password attributes, which are hashed or cannot be read, are not
+// compared.
+func writesVisible(meta interface{}, dn string, entry *ldap.Entry, attributes []string) bool {
+	conn := meta.(*ProviderConfig).Connection
+	if conn == nil {
+		return true
+	}
+	written := conn.Written(dn)
+	if written == nil {
+		return true
+	}
+
+	read := map[string]bool{}
+	for _, attribute := range attributes {
+		read[strings.ToLower(attribute)] = true
+	}
+	for _, attribute := range sensitiveAttributes {
+		delete(written.Present, strings.ToLower(attribute))
+		delete(written.Absent, strings.ToLower(attribute))
+		delete(written.Cleared, strings.ToLower(attribute))
+	}
+	checked := func(attribute string) bool {
+		return read["*"] || read[attribute]
+	}
+	has := func(attribute, value string) bool {
+		for _, v := range entry.GetEqualFoldAttributeValues(attribute) {
+			if matchingOf(meta).normalizeValue(attribute, v) == matchingOf(meta).normalizeValue(attribute, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for attribute, values := range written.Present {
+		for _, value := range values {
+			if checked(attribute) && !has(attribute, value) {
+				return false
+			}
+		}
+	}
+	for attribute, values := range written.Absent {
+		for _, value := range values {
+			if checked(attribute) && has(attribute, value) {
+				return false
+			}
+		}
+	}
+	for attribute := range written.Cleared {
+		if checked(attribute) && len(entry.GetEqualFoldAttributeValues(attribute)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// searchEntryAfterWrite is searchEntry, reading the entry again when it is
+// missing, or does not carry the values written yet, after a write (see
+// readAfterWrite).
+func searchEntryAfterWrite(ctx context.Context, meta interface{}, dn string, attributes []string, derefAliases int) (*ldap.Entry, error) {
+	var entry *ldap.Entry
+	err := readAfterWrite(ctx, meta, func() (found bool, err error) {
+		entry, err = searchEntry(meta.(*ProviderConfig).Connection, dn, attributes, derefAliases)
+		return entry != nil && writesVisible(meta, dn, entry, attributes), err
+	})
+	return entry, err
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestReadAfterWrite(t *testing.T) {
+	meta := &ProviderConfig{ReadAfterWriteTimeout: time.Second}
+	readAfter := func(attempts int) func() (bool, error) {
+		return func() (bool, error) {
+			attempts--
+			return attempts <= 0, nil
+		}
+	}
+
+	// the reads which do not follow a write are not retried
+	reads := 0
+	if err := readAfterWrite(context.Background(), meta, func() (bool, error) {
+		reads++
+		return false, nil
+	}); err != nil || reads != 1 {
+		t.Errorf("readAfterWrite() read %d times, error %v; want 1 read", reads, err)
+	}
+
+	// nor when the timeout is not set
+	reads = 0
+	if err := readAfterWrite(afterWrite(context.Background()), &ProviderConfig{}, func() (bool, error) {
+		reads++
+		return false, nil
+	}); err != nil || reads != 1 {
+		t.Errorf("readAfterWrite() read %d times, error %v; want 1 read", reads, err)
+	}
+
+	// the reads following a write retry until they find what was written
+	if err := readAfterWrite(afterWrite(context.Background()), meta, readAfter(3)); err != nil {
+		t.Errorf("readAfterWrite() error = %v", err)
+	}
+
+	// or until the timeout elapses
+	reads = 0
+	start := time.Now()
+	meta.ReadAfterWriteTimeout = 300 * time.Millisecond
+	if err := readAfterWrite(afterWrite(context.Background()), meta, func() (bool, error) {
+		reads++
+		return false, nil
+	}); err != nil || reads < 2 {
+		t.Errorf("readAfterWrite() read %d times, error %v; want several reads", reads, err)
+	}
+	if elapsed := time.Since(start); elapsed < meta.ReadAfterWriteTimeout || elapsed > 2*meta.ReadAfterWriteTimeout {
+		t.Errorf("readAfterWrite() took %s, want about %s", elapsed, meta.ReadAfterWriteTimeout)
+	}
+}
+
+func TestWritesVisible(t *testing.T) {
+	server, err := ldaptest.NewServer(ldaptest.Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF:         testAccLDIF,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	pool, err := client.NewPool(&client.Config{
+		LDAPHost:     server.Host(),
+		LDAPPort:     server.Port(),
+		BindUser:     "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	meta := &ProviderConfig{Connection: pool}
+
+	const dn = "cn=admin,dc=example,dc=com"
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Replace("description", []string{"updated"})
+	request.Replace("userPassword", []string{"changed"})
+	if err := pool.Modify(request); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.ForgetWritten(dn)
+
+	stale := ldap.NewEntry(dn, map[string][]string{"description": {"former"}})
+	if writesVisible(meta, dn, stale, []string{"*"}) {
+		t.Error("writesVisible() = true for an entry without the written values")
+	}
+	if !writesVisible(meta, dn, stale, []string{"cn"}) {
+		t.Error("writesVisible() = false although the written attributes were not read")
+	}
+	current := ldap.NewEntry(dn, map[string][]string{"description": {"Updated"}, "userPassword": {"{SSHA}hashed"}})
+	if !writesVisible(meta, dn, current, []string{"*"}) {
+		t.Error("writesVisible() = false for an entry with the written values")
+	}
+	if !writesVisible(meta, "cn=other,dc=example,dc=com", stale, []string{"*"}) {
+		t.Error("writesVisible() = false for an entry which was not written")
+	}
+}
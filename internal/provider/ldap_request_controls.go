@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// requestControlsSchema returns the schema of the controls attached to the
+// add, modify and delete requests of a resource.
+func requestControlsSchema() *schema.Schema {
+	return controlsSchema("Controls attached to the add, modify and delete requests of the entry, for the server-specific " +
+		"behaviours the provider does not know of, e.g. LDAP_SERVER_LAZY_COMMIT_OID (1.2.840.113556.1.4.619) on " +
+		"Active Directory. Renames are sent without them.")
+}
+
+// controlsSchema returns the schema of a list of arbitrary controls, e.g.
+// the controls of a resource or the default_controls of the provider.
+func controlsSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"oid": {
+					Type:         schema.TypeString,
+					Description:  "The OID of the control (controlType).",
+					Required:     true,
+					ValidateFunc: validation.StringMatch(oidPattern, "must be a numeric OID"),
+				},
+				"criticality": {
+					Type:        schema.TypeBool,
+					Description: "Whether the server must reject the request when it does not support the control. Default: false.",
+					Optional:    true,
+					Default:     false,
+				},
+				"value_base64": {
+					Type:         schema.TypeString,
+					Description:  "The base64-encoded value of the control (controlValue), usually a BER-encoded ASN.1 structure; controls without value leave it unset.",
+					Optional:     true,
+					ValidateFunc: validation.StringIsBase64,
+				},
+			},
+		},
+	}
+}
+
+// requestControls returns the controls configured by the controls block of a
+// resource (see requestControlsSchema).
+func requestControls(d *schema.ResourceData) ([]ldap.Control, error) {
+	return expandControls(d.Get("controls").([]interface{}))
+}
+
+// expandControls turns the blocks of controlsSchema into controls.
+func expandControls(blocks []interface{}) ([]ldap.Control, error) {
+	controls := []ldap.Control{}
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		control := block.(map[string]interface{})
+		value, err := base64.StdEncoding.DecodeString(control["value_base64"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of control %s: %w", control["oid"], err)
+		}
+		controls = append(controls, ldap.NewControlString(control["oid"].(string), control["criticality"].(bool), string(value)))
+	}
+	return controls, nil
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestExpandControls(t *testing.T) {
+	controls, err := expandControls([]interface{}{
+		map[string]interface{}{"oid": "1.2.840.113556.1.4.619", "criticality": false, "value_base64": ""},
+		map[string]interface{}{"oid": "1.2.3.4", "criticality": true, "value_base64": "MAMCAQE="},
+	})
+	if err != nil {
+		t.Fatalf("expandControls() returned an error: %v", err)
+	}
+	if len(controls) != 2 {
+		t.Fatalf("expandControls() returned %d controls, expected 2", len(controls))
+	}
+	lazyCommit := controls[0].(*ldap.ControlString)
+	if lazyCommit.ControlType != "1.2.840.113556.1.4.619" || lazyCommit.Criticality || lazyCommit.ControlValue != "" {
+		t.Errorf("expandControls() = %v, expected a non-critical control without value", lazyCommit)
+	}
+	critical := controls[1].(*ldap.ControlString)
+	if critical.ControlType != "1.2.3.4" || !critical.Criticality || critical.ControlValue != "\x30\x03\x02\x01\x01" {
+		t.Errorf("expandControls() = %v, expected a critical control with the decoded value", critical)
+	}
+
+	if _, err := expandControls([]interface{}{
+		map[string]interface{}{"oid": "1.2.3.4", "criticality": false, "value_base64": "not base64"},
+	}); err == nil {
+		t.Error("expandControls() accepted an invalid value")
+	}
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldapschema"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// readRootDSE reads the given attributes of the server's root DSE; operational
+// attributes (supportedControl, subschemaSubentry...) must be listed
+// explicitly, or requested all at once with "+".
+func readRootDSE(ctx context.Context, conn client.Client, attributes ...string) (*ldap.Entry, error) {
+	tflog.SubsystemDebug(ctx, subsystemConnection, "reading the root DSE", map[string]interface{}{
+		"attributes": attributes,
+	})
+
+	request := ldap.NewSearchRequest(
+		"",
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		attributes,
+		nil,
+	)
+
+	sr, err := conn.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the root DSE: %w", err)
+	}
+	if len(sr.Entries) == 0 {
+		return nil, fmt.Errorf("error reading the root DSE: no entry returned")
+	}
+	return sr.Entries[0], nil
+}
+
+// readSubschema locates the server's subschema subentry through the root DSE
+// and parses the object classes and attribute types it publishes; it returns
+// the parsed schema along with the DN of the subentry.
+func readSubschema(ctx context.Context, conn client.Client) (*ldapschema.Schema, string, error) {
+	rootDSE, err := readRootDSE(ctx, conn, "subschemaSubentry")
+	if err != nil {
+		return nil, "", err
+	}
+	dn := rootDSE.GetAttributeValue("subschemaSubentry")
+	if dn == "" {
+		return nil, "", fmt.Errorf("the server does not advertise a subschemaSubentry")
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemConnection, "reading the subschema subentry", map[string]interface{}{
+		"dn": dn,
+	})
+
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=subschema)",
+		[]string{"objectClasses", "attributeTypes"},
+		nil,
+	)
+
+	sr, err := conn.Search(request)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading the subschema subentry %q: %w", dn, err)
+	}
+	if len(sr.Entries) == 0 {
+		return nil, "", fmt.Errorf("error reading the subschema subentry %q: no entry returned", dn)
+	}
+
+	s, err := ldapschema.New(
+		sr.Entries[0].GetAttributeValues("objectClasses"),
+		sr.Entries[0].GetAttributeValues("attributeTypes"),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, dn, nil
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ldapGroupTypedAttributes maps the typed fields of ldap_group onto the LDAP
+// attributes they populate, for the purpose of schema validation.
+var ldapGroupTypedAttributes = []typedAttribute{
+	{Field: "description", Attribute: "description"},
+	{Field: "gid_number", Attribute: "gidNumber"},
+	{Field: "member", Attribute: "member"},
+	{Field: "member_uid", Attribute: "memberUid"},
+	{Field: "unique_member", Attribute: "uniqueMember"},
+	{Field: "member_url", Attribute: "memberURL"},
+	{Field: "role_occupant", Attribute: "roleOccupant"},
+}
+
+// customizeDiffRequiredAttributes returns a CustomizeDiff function checking
+// that the planned entry provides every attribute its object classes require
+// according to the server schema; defaultObjectClasses is used when
+// object_classes is not set, and typedAttributes lists the resource fields
+// that map onto LDAP attributes.
+func customizeDiffRequiredAttributes(defaultObjectClasses defaultObjectClassesFunc, typedAttributes []typedAttribute) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		providerConfig, ok := meta.(*ProviderConfig)
+		if !ok || providerConfig == nil || !providerConfig.ValidateSchema {
+			return nil
+		}
+
+		// values only known at apply time cannot be checked
+		for _, key := range append([]string{"dn", "object_classes"}, attributeKeys...) {
+			if !d.NewValueKnown(key) {
+				return nil
+			}
+		}
+		for _, attribute := range typedAttributes {
+			if !d.NewValueKnown(attribute.Field) {
+				return nil
+			}
+		}
+
+		objectClasses := defaultObjectClasses(providerConfig)
+		if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+			objectClasses = convertToStringSlice(v.(*schema.Set).List())
+		}
+
+		dn := d.Get("dn").(string)
+		provided := map[string]bool{"objectclass": true}
+		if parsed, err := ldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 {
+			for _, attribute := range parsed.RDNs[0].Attributes {
+				provided[strings.ToLower(attribute.Type)] = true
+			}
+		}
+		for name := range configuredAttributes(d) {
+			provided[strings.ToLower(name)] = true
+		}
+		for _, attribute := range typedAttributes {
+			if len(typedAttributeValues(d.Get(attribute.Field))) > 0 {
+				provided[strings.ToLower(attribute.Attribute)] = true
+			}
+		}
+
+		s, err := providerConfig.Schema(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "skipping schema validation", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return nil
+		}
+
+		required, err := s.RequiredAttributes(objectClasses)
+		if err != nil {
+			return fmt.Errorf("invalid object classes for %q: %w", dn, err)
+		}
+
+		missing := []string{}
+		for _, at := range required {
+			found := provided[strings.ToLower(at.OID)]
+			for _, name := range at.Names {
+				found = found || provided[strings.ToLower(name)]
+			}
+			if !found {
+				missing = append(missing, at.Name())
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%q is missing attributes required by its object classes (%s): %s",
+				dn, strings.Join(objectClasses, ", "), strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
+
+// customizeDiffAttributeNames checks that the free-form attributes of the
+// planned entry are named after the allowed_attributes of the provider, or
+// after the attribute types of the server schema, with the same case: the
+// server would accept "memberUrl" for "memberURL", but return the latter,
+// and the entry would never match its configuration.
+func customizeDiffAttributeNames(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok || providerConfig == nil || (!providerConfig.ValidateAttributeNames && len(providerConfig.AllowedAttributes) == 0) {
+		return nil
+	}
+	for _, key := range attributeKeys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+
+	attributes := configuredAttributes(d)
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	known := providerConfig.AllowedAttributes
+	if len(known) == 0 {
+		s, err := providerConfig.Schema(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "skipping attribute name validation", map[string]interface{}{
+				"dn":    d.Get("dn").(string),
+				"error": err.Error(),
+			})
+			return nil
+		}
+		for _, at := range s.AttributeTypes {
+			known = append(known, at.OID)
+			known = append(known, at.Names...)
+		}
+	}
+
+	var errs []error
+	for name := range attributes {
+		if err := checkAttributeName(name, known); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return fmt.Errorf("invalid attributes for %q: %w", d.Get("dn").(string), errors.Join(errs...))
+	}
+	return nil
+}
+
+// checkAttributeName checks that the name of an attribute, without its
+// options (e.g. ";binary"), is one of the known names.
+func checkAttributeName(name string, known []string) error {
+	base, _, _ := strings.Cut(name, ";")
+	var suggestion string
+	for _, k := range known {
+		if k == base {
+			return nil
+		}
+		if strings.EqualFold(k, base) {
+			suggestion = k
+		}
+	}
+	if suggestion != "" {
+		return fmt.Errorf("attribute %q is spelled %q by the schema", base, suggestion)
+	}
+	return fmt.Errorf("unknown attribute %q", base)
+}
+
+// customizeDiffObjectClasses replaces the entry when the planned object
+// classes change its structural class, which servers refuse to modify,
+// while auxiliary classes are added or removed in place; the classes are
+// updated in place when the server schema cannot be read.
+func customizeDiffObjectClasses(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("object_classes") || !d.NewValueKnown("object_classes") {
+		return nil
+	}
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok || providerConfig == nil {
+		return nil
+	}
+
+	o, n := d.GetChange("object_classes")
+	oldClasses := convertToStringSlice(o.(*schema.Set).List())
+	newClasses := convertToStringSlice(n.(*schema.Set).List())
+	if len(oldClasses) == 0 || len(newClasses) == 0 {
+		return nil
+	}
+
+	dn := d.Get("dn").(string)
+	s, err := providerConfig.Schema(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "unable to tell whether the structural object class changes, updating the object classes in place", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	oldStructural, err := s.StructuralClass(oldClasses)
+	if err != nil {
+		tflog.Warn(ctx, "unable to tell whether the structural object class changes, updating the object classes in place", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	newStructural, err := s.StructuralClass(newClasses)
+	if err != nil {
+		return fmt.Errorf("invalid object classes for %q: %w", dn, err)
+	}
+
+	if oldStructural != newStructural {
+		tflog.Debug(ctx, "the structural object class changes, replacing the entry", map[string]interface{}{
+			"dn":                 dn,
+			"old_object_classes": oldClasses,
+			"new_object_classes": newClasses,
+		})
+		return d.ForceNew("object_classes")
+	}
+	return nil
+}
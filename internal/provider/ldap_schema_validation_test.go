@@ -0,0 +1,24 @@
+package provider
+
+import "testing"
+
+func TestCheckAttributeName(t *testing.T) {
+	known := []string{"cn", "commonName", "memberURL", "userCertificate", "2.5.4.3"}
+	for name, expected := range map[string]string{
+		"cn":                     "",
+		"commonName":             "",
+		"2.5.4.3":                "",
+		"userCertificate;binary": "",
+		"memberUrl":              `attribute "memberUrl" is spelled "memberURL" by the schema`,
+		"CN;lang-en":             `attribute "CN" is spelled "cn" by the schema`,
+		"memberOfGroup":          `unknown attribute "memberOfGroup"`,
+	} {
+		err := checkAttributeName(name, known)
+		switch {
+		case expected == "" && err != nil:
+			t.Errorf("checkAttributeName(%q): unexpected error %v", name, err)
+		case expected != "" && (err == nil || err.Error() != expected):
+			t.Errorf("checkAttributeName(%q) = %v, expected %q", name, err, expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// searchBaseSchema returns the base_dn argument of data sources, with the
+// given description, which defaults to the provider's search_base.
+func searchBaseSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: description + " Default: the provider's `search_base`.",
+	}
+}
+
+// searchBase returns the base DN of the searches of a data source, setting
+// base_dn to the provider's search_base if it is not set.
+func searchBase(d *schema.ResourceData, meta interface{}) (string, error) {
+	if v, ok := d.GetOk("base_dn"); ok {
+		return v.(string), nil
+	}
+	base := meta.(*ProviderConfig).SearchBase
+	if base == "" {
+		return "", fmt.Errorf("base_dn must be set, as the provider has no search_base")
+	}
+	return base, d.Set("base_dn", base)
+}
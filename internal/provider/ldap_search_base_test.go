@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSearchBase(t *testing.T) {
+	s := dataSourceLDAPSearch().Schema
+
+	for name, test := range map[string]struct {
+		meta     *ProviderConfig
+		config   map[string]interface{}
+		expected string
+	}{
+		"default":  {&ProviderConfig{SearchBase: "dc=example,dc=com"}, map[string]interface{}{"filter": "(uid=*)"}, "dc=example,dc=com"},
+		"override": {&ProviderConfig{SearchBase: "dc=example,dc=com"}, map[string]interface{}{"filter": "(uid=*)", "base_dn": "ou=people,dc=example,dc=com"}, "ou=people,dc=example,dc=com"},
+	} {
+		d := schema.TestResourceDataRaw(t, s, test.config)
+		got, err := searchBase(d, test.meta)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if got != test.expected || d.Get("base_dn").(string) != test.expected {
+			t.Errorf("%s: got %q, base_dn %q, expected %q", name, got, d.Get("base_dn"), test.expected)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{"filter": "(uid=*)"})
+	if _, err := searchBase(d, &ProviderConfig{}); err == nil {
+		t.Error("expected an error without base_dn nor search_base")
+	}
+}
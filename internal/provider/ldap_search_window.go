@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// searchWindowSchema returns the fields of the search data sources sorting
+// results on the server (RFC 2891) and selecting a window of them with a
+// Virtual List View, so that huge result sets can be read a slice at a time.
+func searchWindowSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"sort_by": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Attributes to sort the results by on the server; prefix an attribute with \"-\" to sort in descending order. Required with `window_size`.",
+		},
+		"window_offset": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "The 1-based position, in the sorted results, of the first entry to return when `window_size` is set. Default: 1.",
+		},
+		"window_size": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Only return this many entries, starting at `window_offset`, using a Virtual List View; the server must support VLV and have an index matching the filter and sort order, and `paged_size` is ignored. Default: 0 (all entries).",
+		},
+		"total_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The total number of entries matching the search, as estimated by the server, when `window_size` is set.",
+		},
+	}
+}
+
+// searchWindowControls returns the sorting and Virtual List View controls
+// requested by the data source, and whether a window was requested.
+func searchWindowControls(d *schema.ResourceData) ([]ldap.Control, bool, error) {
+	sortBy := convertToStringSlice(d.Get("sort_by").([]interface{}))
+	size := d.Get("window_size").(int)
+	if size > 0 && len(sortBy) == 0 {
+		return nil, false, fmt.Errorf("sort_by is required with window_size")
+	}
+
+	controls := []ldap.Control{}
+	if len(sortBy) > 0 {
+		keys := make([]*ldap.SortKey, len(sortBy))
+		for i, attribute := range sortBy {
+			keys[i] = &ldap.SortKey{
+				AttributeType: strings.TrimPrefix(attribute, "-"),
+				Reverse:       strings.HasPrefix(attribute, "-"),
+			}
+		}
+		controls = append(controls, ldap.NewControlServerSideSortingWithSortKeys(keys))
+	}
+	if size > 0 {
+		controls = append(controls, &client.ControlVLV{Offset: d.Get("window_offset").(int), Size: size})
+	}
+	return controls, size > 0, nil
+}
+
+// setSearchWindowResult sets total_count from the Virtual List View response.
+func setSearchWindowResult(ctx context.Context, d *schema.ResourceData, sr *ldap.SearchResult) error {
+	total := 0
+	response, err := client.FindVLVResponse(sr.Controls)
+	if err != nil {
+		return err
+	}
+	if response != nil {
+		if response.Result != ldap.LDAPResultSuccess {
+			return fmt.Errorf("virtual list view failed: %s", ldap.LDAPResultCodeMap[uint16(response.Result)])
+		}
+		total = int(response.ContentCount)
+		tflog.Debug(ctx, "virtual list view result", map[string]interface{}{
+			"position": response.TargetPosition,
+			"count":    response.ContentCount,
+		})
+	}
+	return d.Set("total_count", total)
+}
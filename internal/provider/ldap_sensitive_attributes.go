@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// attributeKeys are the fields holding the free-form attributes of an entry,
+// as sets of 1-element maps or as JSON (see attributeSet); the values of
+// sensitive_attributes are hidden from plans and state output.
+var attributeKeys = []string{"attributes", "attributes_json", "sensitive_attributes"}
+
+func sensitiveAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeSet,
+		Description: "Attributes set like `attributes`, but whose values are marked sensitive, so that they are not " +
+			"displayed in plans nor in the output of Terraform commands (e.g. passwords or keys). An attribute " +
+			"cannot be set in both `attributes` and `sensitive_attributes`.",
+		Set:              attributeHash,
+		DiffSuppressFunc: suppressEquivalentAttributeValue,
+		Sensitive:        true,
+		Optional:         true,
+		Elem: &schema.Schema{
+			Type:     schema.TypeMap,
+			MinItems: 1,
+			MaxItems: 1,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// attributeGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type attributeGetter interface {
+	GetOk(key string) (interface{}, bool)
+}
+
+// configuredAttributes returns the values of the free-form attributes of an
+// entry, from both attributes and sensitive_attributes.
+func configuredAttributes(d attributeGetter) map[string][]string {
+	m := map[string][]string{}
+	for _, key := range attributeKeys {
+		v, ok := d.GetOk(key)
+		if !ok {
+			continue
+		}
+		// each map should only have one entry (see resource declaration)
+		for _, attribute := range attributeSet(v).List() {
+			for name, value := range attribute.(map[string]interface{}) {
+				m[name] = append(m[name], value.(string))
+			}
+		}
+	}
+	return m
+}
+
+// changeGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type changeGetter interface {
+	GetChange(key string) (interface{}, interface{})
+}
+
+// attributesChange returns the old and new free-form attributes of an entry,
+// the sensitive ones included, as single sets.
+func attributesChange(d changeGetter) (o, n *schema.Set) {
+	o, n = &schema.Set{F: attributeHash}, &schema.Set{F: attributeHash}
+	for _, key := range attributeKeys {
+		ov, nv := d.GetChange(key)
+		for _, attribute := range attributeSet(ov).List() {
+			o.Add(attribute)
+		}
+		for _, attribute := range attributeSet(nv).List() {
+			n.Add(attribute)
+		}
+	}
+	return o, n
+}
+
+// sensitiveAttributeNames returns the lower-cased names of the attributes
+// set in sensitive_attributes.
+func sensitiveAttributeNames(d attributeGetter) map[string]bool {
+	names := map[string]bool{}
+	if v, ok := d.GetOk("sensitive_attributes"); ok {
+		for _, attribute := range v.(*schema.Set).List() {
+			for name := range attribute.(map[string]interface{}) {
+				names[strings.ToLower(name)] = true
+			}
+		}
+	}
+	return names
+}
+
+// setAttributes sets the free-form attributes read from an entry, the values
+// of the attributes set in sensitive_attributes going back there, and the
+// others going to attributes_json rather than attributes when it is used.
+func setAttributes(d *schema.ResourceData, attributes *schema.Set) error {
+	sensitive := sensitiveAttributeNames(d)
+	plain, hidden := &schema.Set{F: attributeHash}, &schema.Set{F: attributeHash}
+	for _, attribute := range attributes.List() {
+		for name := range attribute.(map[string]interface{}) {
+			if sensitive[strings.ToLower(name)] {
+				hidden.Add(attribute)
+			} else {
+				plain.Add(attribute)
+			}
+		}
+	}
+	if d.Get("attributes_json").(string) != "" {
+		if err := d.Set("attributes_json", encodeAttributesJSON(plain)); err != nil {
+			return err
+		}
+		plain = &schema.Set{F: attributeHash}
+	}
+	if err := d.Set("attributes", plain); err != nil {
+		return err
+	}
+	return d.Set("sensitive_attributes", hidden)
+}
+
+// withSensitiveValuesMasked returns a context whose loggers redact the
+// values of sensitive_attributes, both before and after the change.
+func withSensitiveValuesMasked(ctx context.Context, d *schema.ResourceData) context.Context {
+	values := []string{}
+	o, n := d.GetChange("sensitive_attributes")
+	for _, v := range []interface{}{o, n} {
+		for _, attribute := range v.(*schema.Set).List() {
+			for _, value := range attribute.(map[string]interface{}) {
+				if value.(string) != "" {
+					values = append(values, value.(string))
+				}
+			}
+		}
+	}
+	if len(values) == 0 {
+		return ctx
+	}
+	ctx = tflog.MaskLogStrings(ctx, values...)
+	for _, subsystem := range logSubsystems {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, subsystem, values...)
+	}
+	return ctx
+}
+
+// customizeDiffSensitiveAttributes makes sure that no attribute is set in
+// both attributes (or attributes_json) and sensitive_attributes.
+func customizeDiffSensitiveAttributes(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range attributeKeys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	sensitive := sensitiveAttributeNames(d)
+	for _, key := range []string{"attributes", "attributes_json"} {
+		v, ok := d.GetOk(key)
+		if !ok {
+			continue
+		}
+		for _, attribute := range attributeSet(v).List() {
+			for name := range attribute.(map[string]interface{}) {
+				if sensitive[strings.ToLower(name)] {
+					return fmt.Errorf("attribute %q is set in both %s and sensitive_attributes", name, key)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package provider
+
+import "strings"
+
+// commonServerManagedAttributes holds the lower-cased names of the attributes
+// maintained by servers or their overlays, which are never read into the
+// free-form attributes of resources, so that their values do not show up as
+// drift. The provider's server_managed_attributes complete them on its
+// configuration.
+var commonServerManagedAttributes = map[string]bool{
+	// memberof overlay, and its 389 Directory Server counterpart
+	"memberof":   true,
+	"ismemberof": true,
+	// replication
+	"entrycsn":   true,
+	"contextcsn": true,
+	// password policy (draft-behera-ldap-password-policy)
+	"pwdchangedtime":       true,
+	"pwdaccountlockedtime": true,
+	"pwdfailuretime":       true,
+	"pwdgraceusetime":      true,
+	"pwdhistory":           true,
+	"pwdreset":             true,
+	// 389 Directory Server
+	"nsuniqueid": true,
+	// RFC 4512 and RFC 4530
+	"createtimestamp":   true,
+	"modifytimestamp":   true,
+	"creatorsname":      true,
+	"modifiersname":     true,
+	"entryuuid":         true,
+	"entrydn":           true,
+	"hassubordinates":   true,
+	"subschemasubentry": true,
+}
+
+// newServerManagedAttributes returns the set of the lower-cased names of the
+// provider's server_managed_attributes.
+func newServerManagedAttributes(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return set
+}
+
+// isServerManagedAttribute tells whether an attribute is maintained by the
+// server: either a common one (see commonServerManagedAttributes) or one of
+// the provider's server_managed_attributes.
+func isServerManagedAttribute(meta interface{}, name string) bool {
+	name = strings.ToLower(name)
+	if commonServerManagedAttributes[name] {
+		return true
+	}
+	c, ok := meta.(*ProviderConfig)
+	return ok && c.serverManagedAttributes[name]
+}
@@ -0,0 +1,27 @@
+package provider
+
+import "testing"
+
+func TestServerManagedAttributes(t *testing.T) {
+	for _, name := range []string{"memberOf", "entryCSN", "contextCSN", "pwdChangedTime", "nsUniqueId", "MEMBEROF"} {
+		if !isServerManagedAttribute(nil, name) {
+			t.Errorf("%s should be server-managed", name)
+		}
+	}
+	for _, name := range []string{"cn", "mail", "member"} {
+		if isServerManagedAttribute(nil, name) {
+			t.Errorf("%s should not be server-managed", name)
+		}
+	}
+
+	configured := &ProviderConfig{serverManagedAttributes: newServerManagedAttributes([]string{"dsOverlayCounter"})}
+	if !isServerManagedAttribute(configured, "dsovErlaycounter") {
+		t.Error("dsOverlayCounter should be server-managed once configured")
+	}
+	if !isServerManagedAttribute(configured, "memberOf") {
+		t.Error("memberOf should remain server-managed")
+	}
+	if isServerManagedAttribute(&ProviderConfig{}, "dsOverlayCounter") {
+		t.Error("dsOverlayCounter should only be server-managed by the provider configuring it")
+	}
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dateLayout is the layout of the dates of the shadow_account block, the
+// full-date of RFC 3339.
+const dateLayout = "2006-01-02"
+
+// shadowAttribute binds a field of the shadow_account block to an attribute
+// of the shadowAccount object class (RFC 2307); Date fields hold days since
+// January 1, 1970 as RFC 3339 dates.
+type shadowAttribute struct {
+	Field     string
+	Attribute string
+	Date      bool
+}
+
+// shadowAttributes are the password aging attributes the shadow_account block
+// manages.
+var shadowAttributes = []shadowAttribute{
+	{Field: "last_change", Attribute: "shadowLastChange", Date: true},
+	{Field: "max_days", Attribute: "shadowMax"},
+	{Field: "warning_days", Attribute: "shadowWarning"},
+	{Field: "expire", Attribute: "shadowExpire", Date: true},
+}
+
+func shadowAccountSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "The password aging attributes of the shadowAccount object class (RFC 2307), with dates as " +
+			"RFC 3339 dates (e.g. 2024-02-10) rather than days since 1970. The attributes cannot be set in `attributes` " +
+			"along with this block.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"last_change": {
+					Type:         schema.TypeString,
+					Description:  "The date of the last password change (shadowLastChange).",
+					Optional:     true,
+					ValidateFunc: validateDate,
+				},
+				"max_days": {
+					Type:         schema.TypeInt,
+					Description:  "The number of days a password is valid (shadowMax).",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"warning_days": {
+					Type:         schema.TypeInt,
+					Description:  "The number of days before the password expires the user is warned (shadowWarning).",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"expire": {
+					Type:         schema.TypeString,
+					Description:  "The date the account expires (shadowExpire).",
+					Optional:     true,
+					ValidateFunc: validateDate,
+				},
+			},
+		},
+	}
+}
+
+func validateDate(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := time.Parse(dateLayout, v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%s: expected an RFC 3339 date (e.g. 2024-02-10): %w", k, err))
+	}
+	return
+}
+
+// epochDaysToDate returns the RFC 3339 date of a number of days since
+// January 1, 1970.
+func epochDaysToDate(days int) string {
+	return time.Unix(int64(days)*86400, 0).UTC().Format(dateLayout)
+}
+
+// dateToEpochDays returns the number of days since January 1, 1970 of an
+// RFC 3339 date.
+func dateToEpochDays(date string) (int, error) {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return 0, err
+	}
+	return int(t.Unix() / 86400), nil
+}
+
+// shadowAccountConfig returns the fields of the shadow_account block, if it
+// is set.
+func shadowAccountConfig(d attributeGetter) (map[string]interface{}, bool) {
+	v, ok := d.GetOk("shadow_account")
+	if !ok {
+		return nil, false
+	}
+	blocks := v.([]interface{})
+	if len(blocks) == 0 {
+		return nil, false
+	}
+	// a block without any field set is nil
+	fields, _ := blocks[0].(map[string]interface{})
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	return fields, true
+}
+
+// managesShadowAccount tells whether an attribute is managed through the
+// shadow_account block, in which case it is not read back as an ordinary
+// attribute.
+func managesShadowAccount(d attributeGetter, name string) bool {
+	if _, ok := shadowAccountConfig(d); !ok {
+		return false
+	}
+	for _, attribute := range shadowAttributes {
+		if strings.EqualFold(name, attribute.Attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// shadowAttributeValues returns the values of the attribute of a field of the
+// shadow_account block; unset fields have none.
+func shadowAttributeValues(attribute shadowAttribute, fields map[string]interface{}) ([]string, error) {
+	switch v := fields[attribute.Field].(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		days, err := dateToEpochDays(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", attribute.Field, v, err)
+		}
+		return []string{strconv.Itoa(days)}, nil
+	case int:
+		if v == 0 {
+			return nil, nil
+		}
+		return []string{strconv.Itoa(v)}, nil
+	}
+	return nil, nil
+}
+
+// addShadowAccount sets the attributes of the shadow_account block in a
+// request creating an entry.
+func addShadowAccount(request *ldap.AddRequest, d *schema.ResourceData) error {
+	fields, ok := shadowAccountConfig(d)
+	if !ok {
+		return nil
+	}
+	for _, attribute := range shadowAttributes {
+		values, err := shadowAttributeValues(attribute, fields)
+		if err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			request.Attribute(attribute.Attribute, values)
+		}
+	}
+	return nil
+}
+
+// modifyShadowAccount replaces the attributes of the fields of the
+// shadow_account block which changed; they are left as they are when the
+// block is removed.
+func modifyShadowAccount(request *ldap.ModifyRequest, d *schema.ResourceData) error {
+	fields, ok := shadowAccountConfig(d)
+	if !ok || !d.HasChange("shadow_account") {
+		return nil
+	}
+	for _, attribute := range shadowAttributes {
+		if !d.HasChange("shadow_account.0." + attribute.Field) {
+			continue
+		}
+		values, err := shadowAttributeValues(attribute, fields)
+		if err != nil {
+			return err
+		}
+		request.Replace(attribute.Attribute, values)
+	}
+	return nil
+}
+
+// readShadowAccount sets the shadow_account block from the attributes of the
+// entry, when the block is set.
+func readShadowAccount(d *schema.ResourceData, entry *ldap.Entry) error {
+	if _, ok := shadowAccountConfig(d); !ok {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	for _, attribute := range shadowAttributes {
+		value := entry.GetAttributeValue(attribute.Attribute)
+		if value == "" {
+			continue
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("unable to convert %s value %q to int: %w", attribute.Attribute, value, err)
+		}
+		if attribute.Date {
+			// -1 stands for an unset date
+			if i >= 0 {
+				fields[attribute.Field] = epochDaysToDate(i)
+			}
+			continue
+		}
+		fields[attribute.Field] = i
+	}
+	return d.Set("shadow_account", []interface{}{fields})
+}
+
+// customizeDiffShadowAccount forbids setting the attributes of the
+// shadow_account block as attributes too.
+func customizeDiffShadowAccount(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := shadowAccountConfig(d); !ok {
+		return nil
+	}
+	for name := range configuredAttributes(d) {
+		if managesShadowAccount(d, name) {
+			return fmt.Errorf("%s cannot be set in attributes along with shadow_account", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEpochDays(t *testing.T) {
+	cases := map[int]string{
+		0:     "1970-01-01",
+		19763: "2024-02-10",
+	}
+	for days, date := range cases {
+		if got := epochDaysToDate(days); got != date {
+			t.Errorf("epochDaysToDate(%d) = %q, want %q", days, got, date)
+		}
+		if got, err := dateToEpochDays(date); err != nil || got != days {
+			t.Errorf("dateToEpochDays(%q) = %d, %v, want %d", date, got, err, days)
+		}
+	}
+	if _, err := dateToEpochDays("2024-02-10T00:00:00Z"); err == nil {
+		t.Error("expected an error for a timestamp")
+	}
+}
+
+func TestShadowAttributeValues(t *testing.T) {
+	fields := map[string]interface{}{
+		"last_change":  "2024-02-10",
+		"max_days":     90,
+		"warning_days": 0,
+		"expire":       "",
+	}
+	want := map[string][]string{
+		"shadowLastChange": {"19763"},
+		"shadowMax":        {"90"},
+	}
+	got := map[string][]string{}
+	for _, attribute := range shadowAttributes {
+		values, err := shadowAttributeValues(attribute, fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) > 0 {
+			got[attribute.Attribute] = values
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+func sshTunnelSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "Connect to the server through an SSH tunnel, like ssh -L, e.g. when the directory is only " +
+			"reachable from a jump host. The SSH server is itself reached through `proxy_url`, if set.",
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host": {
+					Type:        schema.TypeString,
+					Description: "The SSH server, as host or host:port (default port: 22).",
+					Required:    true,
+				},
+				"user": {
+					Type:        schema.TypeString,
+					Description: "The user to log in to the SSH server as.",
+					Required:    true,
+				},
+				"private_key": {
+					Type:        schema.TypeString,
+					Description: "The PEM-encoded private key to authenticate with, e.g. file(\"~/.ssh/id_ed25519\").",
+					Optional:    true,
+					Sensitive:   true,
+				},
+				"private_key_passphrase": {
+					Type:        schema.TypeString,
+					Description: "The passphrase of `private_key`, if it is encrypted.",
+					Optional:    true,
+					Sensitive:   true,
+				},
+				"use_agent": {
+					Type:        schema.TypeBool,
+					Description: "Authenticate with the keys of the SSH agent at SSH_AUTH_SOCK (default: false).",
+					Optional:    true,
+					Default:     false,
+				},
+				"host_key": {
+					Type:         schema.TypeString,
+					Description:  "The public key of the SSH server, in the authorized_keys format, e.g. \"ssh-ed25519 AAAA...\". When not set, the key is looked up in `known_hosts_file`.",
+					Optional:     true,
+					ValidateFunc: validateSSHHostKey,
+				},
+				"known_hosts_file": {
+					Type:        schema.TypeString,
+					Description: "The known_hosts file the key of the SSH server is checked against when `host_key` is not set (default: ~/.ssh/known_hosts).",
+					Optional:    true,
+					Default:     "~/.ssh/known_hosts",
+				},
+			},
+		},
+	}
+}
+
+func validateSSHHostKey(v interface{}, k string) (ws []string, errs []error) {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.(string))); err != nil {
+		errs = append(errs, fmt.Errorf("%s: expected a public key in the authorized_keys format: %w", k, err))
+	}
+	return
+}
+
+// sshTunnel returns the SSH tunnel set in the ssh_tunnel block, or nil
+// without the block.
+func sshTunnel(d *schema.ResourceData) (*client.SSHTunnel, error) {
+	blocks := d.Get("ssh_tunnel").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, nil
+	}
+	block := blocks[0].(map[string]interface{})
+
+	tunnel := &client.SSHTunnel{
+		Host:                 block["host"].(string),
+		User:                 block["user"].(string),
+		PrivateKey:           block["private_key"].(string),
+		PrivateKeyPassphrase: block["private_key_passphrase"].(string),
+		UseAgent:             block["use_agent"].(bool),
+		HostKey:              block["host_key"].(string),
+	}
+	if tunnel.PrivateKey == "" && !tunnel.UseAgent {
+		return nil, fmt.Errorf("ssh_tunnel: one of private_key or use_agent must be set")
+	}
+	if _, _, err := net.SplitHostPort(tunnel.Host); err != nil {
+		// no port
+		tunnel.Host = net.JoinHostPort(strings.Trim(tunnel.Host, "[]"), "22")
+	}
+
+	knownHosts, err := expandHome(block["known_hosts_file"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("ssh_tunnel: %w", err)
+	}
+	tunnel.KnownHostsFile = knownHosts
+	return tunnel, nil
+}
+
+// expandHome replaces a leading ~/ of the path with the home directory of
+// the user.
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[2:]), nil
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSSHTunnel(t *testing.T) {
+	for host, expected := range map[string]string{
+		"bastion.example.com":      "bastion.example.com:22",
+		"bastion.example.com:2222": "bastion.example.com:2222",
+		"2001:db8::1":              "[2001:db8::1]:22",
+		"[2001:db8::1]:2222":       "[2001:db8::1]:2222",
+	} {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+			"ssh_tunnel": []interface{}{map[string]interface{}{
+				"host":             host,
+				"user":             "terraform",
+				"use_agent":        true,
+				"known_hosts_file": "/etc/ssh/ssh_known_hosts",
+			}},
+		})
+		tunnel, err := sshTunnel(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tunnel.Host != expected {
+			t.Errorf("host %q: got address %q, expected %q", host, tunnel.Host, expected)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"ssh_tunnel": []interface{}{map[string]interface{}{
+			"host": "bastion.example.com",
+			"user": "terraform",
+		}},
+	})
+	if _, err := sshTunnel(d); err == nil {
+		t.Error("expected an error without private key nor agent")
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	if tunnel, err := sshTunnel(d); tunnel != nil || err != nil {
+		t.Errorf("expected no tunnel without the block, got %v, %v", tunnel, err)
+	}
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// timeLimitSchema returns the time_limit argument of data sources, which
+// defaults to the provider's search_time_limit.
+func timeLimitSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+		Description: "The maximum time in seconds the server may spend on the search, after which it fails with " +
+			"timeLimitExceeded; 0 means no limit. Default: the provider's `search_time_limit`.",
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+}
+
+// timeLimit returns the time limit of the searches of a data source.
+func timeLimit(d *schema.ResourceData, meta interface{}) int {
+	// unlike GetOk, GetOkExists tells an explicit 0 apart from an unset
+	// argument
+	if v, ok := d.GetOkExists("time_limit"); ok {
+		return v.(int)
+	}
+	return meta.(*ProviderConfig).SearchTimeLimit
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestTimeLimit(t *testing.T) {
+	meta := &ProviderConfig{SearchTimeLimit: 30}
+	s := dataSourceLDAPSearch().Schema
+
+	for name, test := range map[string]struct {
+		config   map[string]interface{}
+		expected int
+	}{
+		"default":  {map[string]interface{}{"base_dn": "dc=example,dc=com"}, 30},
+		"override": {map[string]interface{}{"base_dn": "dc=example,dc=com", "time_limit": 5}, 5},
+		"no limit": {map[string]interface{}{"base_dn": "dc=example,dc=com", "time_limit": 0}, 0},
+	} {
+		d := schema.TestResourceDataRaw(t, s, test.config)
+		if got := timeLimit(d, meta); got != test.expected {
+			t.Errorf("%s: got %d, expected %d", name, got, test.expected)
+		}
+	}
+}
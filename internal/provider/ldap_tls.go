@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"crypto/tls"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tlsVersions are the values of tls_min_version.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsVersionNames returns the keys of tlsVersions, sorted.
+func tlsVersionNames() []string {
+	names := make([]string, 0, len(tlsVersions))
+	for name := range tlsVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cipherSuiteIDs returns the IDs of the cipher suites with the given names,
+// as named by the crypto/tls package (and IANA), e.g.
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256; insecure suites are accepted, since
+// old directories may only offer those.
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	known := map[string]uint16{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func validateCipherSuite(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := cipherSuiteIDs([]string{v.(string)}); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", k, err))
+	}
+	return
+}
+
+// fingerprintPattern matches SHA-256 fingerprints, with or without colons
+// between the bytes, as printed by openssl x509 -fingerprint -sha256.
+var fingerprintPattern = regexp.MustCompile(`^[0-9a-fA-F]{2}(:?[0-9a-fA-F]{2}){31}$`)
+
+// normalizeFingerprint returns a SHA-256 fingerprint in lower-case
+// hexadecimal, without colons.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+func TestCipherSuiteIDs(t *testing.T) {
+	ids, err := cipherSuiteIDs([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_AES_128_CBC_SHA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_128_CBC_SHA}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("cipherSuiteIDs() = %v, expected %v", ids, expected)
+	}
+
+	if _, err := cipherSuiteIDs([]string{"TLS_NULL_WITH_NULL_NULL"}); err == nil {
+		t.Error("expected an error for an unknown cipher suite")
+	}
+}
+
+func TestNormalizeFingerprint(t *testing.T) {
+	fingerprint := "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89"
+	if !fingerprintPattern.MatchString(fingerprint) {
+		t.Errorf("expected %q to be a valid fingerprint", fingerprint)
+	}
+	if got, expected := normalizeFingerprint(fingerprint), "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789"; got != expected {
+		t.Errorf("normalizeFingerprint() = %q, expected %q", got, expected)
+	}
+	if fingerprintPattern.MatchString("AB:CD") {
+		t.Error("expected a short fingerprint to be invalid")
+	}
+}
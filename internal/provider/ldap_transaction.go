@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ldapTransaction groups the update operations of a resource into an LDAP
+// transaction (RFC 5805) when the provider is configured to use transactions
+// and the server supports them; otherwise operations are applied one by one
+// as they are issued. The operations of a transaction must all be sent on
+// the connection which started it, so that connection is pinned out of the
+// pool until the transaction ends; it still applies the default controls,
+// the rate limit and the read_only setting of the pool.
+type ldapTransaction struct {
+	conn      client.Client
+	pinned    *client.Pinned
+	id        []byte
+	logPrefix string
+	ctx       context.Context
+}
+
+// beginLDAPTransaction starts a transaction if possible.
+func beginLDAPTransaction(ctx context.Context, meta interface{}, logPrefix string) (*ldapTransaction, error) {
+	providerConfig := meta.(*ProviderConfig)
+	t := &ldapTransaction{conn: providerConfig.Connection, logPrefix: logPrefix, ctx: ctx}
+	if !providerConfig.UseTransactions || providerConfig.Connection.ReadOnly() {
+		// operations go through the pool, which rejects them if the
+		// provider is read-only
+		return t, nil
+	}
+	if !providerConfig.SupportsTransactions(ctx) {
+		tflog.SubsystemWarn(ctx, subsystemConnection, "the server does not support transactions, applying changes one by one", map[string]interface{}{
+			"operation": logPrefix,
+		})
+		return t, nil
+	}
+
+	pinned, err := providerConfig.Connection.Pin()
+	if err != nil {
+		return nil, err
+	}
+	id, err := client.StartTransaction(pinned)
+	if err != nil {
+		pinned.Release()
+		return nil, err
+	}
+	t.conn, t.pinned = pinned, pinned
+	tflog.SubsystemDebug(ctx, subsystemConnection, "started transaction", t.logFields(id))
+	t.id = id
+	return t, nil
+}
+
+// Active tells whether operations are deferred until Commit.
+func (t *ldapTransaction) Active() bool {
+	return t.id != nil
+}
+
+func (t *ldapTransaction) controls() []ldap.Control {
+	if !t.Active() {
+		return nil
+	}
+	return []ldap.Control{&client.ControlTransactionSpecification{ID: t.id}}
+}
+
+// Add issues an add request as part of the transaction.
+func (t *ldapTransaction) Add(request *ldap.AddRequest) error {
+	request.Controls = append(request.Controls, t.controls()...)
+	return t.conn.Add(request)
+}
+
+// Modify issues a modify request as part of the transaction.
+func (t *ldapTransaction) Modify(request *ldap.ModifyRequest) error {
+	request.Controls = append(request.Controls, t.controls()...)
+	return t.conn.Modify(request)
+}
+
+// Del deletes an entry as part of the transaction; outside of a transaction,
+// entries which do not exist are ignored and referrals are handled as by
+// deleteLDAPEntry.
+func (t *ldapTransaction) Del(dn string) error {
+	if !t.Active() {
+		return deleteLDAPEntry(t.ctx, t.conn, dn, t.logPrefix)
+	}
+	return t.conn.Del(ldap.NewDelRequest(dn, t.controls()))
+}
+
+// Commit applies all the operations of the transaction at once.
+func (t *ldapTransaction) Commit() error {
+	if !t.Active() {
+		return nil
+	}
+	tflog.SubsystemDebug(t.ctx, subsystemConnection, "committing transaction", t.logFields(t.id))
+	defer t.release()
+	return client.EndTransaction(t.conn, t.id, true)
+}
+
+// Abort discards the operations of the transaction, after an error.
+func (t *ldapTransaction) Abort() {
+	if !t.Active() {
+		return
+	}
+	tflog.SubsystemDebug(t.ctx, subsystemConnection, "aborting transaction", t.logFields(t.id))
+	defer t.release()
+	if err := client.EndTransaction(t.conn, t.id, false); err != nil {
+		fields := t.logFields(t.id)
+		fields["error"] = err.Error()
+		tflog.SubsystemWarn(t.ctx, subsystemConnection, "error aborting transaction", fields)
+	}
+}
+
+// release gives the connection of the transaction back to the pool.
+func (t *ldapTransaction) release() {
+	if t.pinned != nil {
+		t.pinned.Release()
+		t.pinned = nil
+	}
+}
+
+func (t *ldapTransaction) logFields(id []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"operation":   t.logPrefix,
+		"transaction": fmt.Sprintf("%x", id),
+	}
+}
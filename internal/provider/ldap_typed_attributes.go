@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// typedAttribute binds a typed schema field to the LDAP attribute holding its
+// value(s); string, int and bool fields map to a single value, while set and
+// list fields map to all the values of the attribute. Zero values (empty
+// strings, 0, false and empty collections) of fields left out of the
+// configuration are treated as "attribute absent", so that the entry does not
+// carry values the configuration did not set; false and 0 are written as they
+// are when the configuration sets them.
+type typedAttribute struct {
+	Field     string
+	Attribute string
+	// Default is the value the server assumes when the attribute is absent,
+	// if it is not the zero value of the field, e.g. TRUE for
+	// pwdAllowUserChange.
+	Default string
+}
+
+// typedAttributeValues converts the value of a typed field (as returned by
+// d.Get) into the list of LDAP values it stands for.
+func typedAttributeValues(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case int:
+		if v == 0 {
+			return nil
+		}
+		return []string{strconv.Itoa(v)}
+	case bool:
+		if !v {
+			return nil
+		}
+		return []string{"TRUE"}
+	case *schema.Set:
+		return convertToStringSlice(v.List())
+	case []interface{}:
+		return convertToStringSlice(v)
+	}
+	return nil
+}
+
+// typedFieldValues returns the LDAP values of a typed field to write: unlike
+// typedAttributeValues, false and 0 are kept as FALSE and 0 when the
+// configuration sets them, since the server may assume otherwise when the
+// attribute is absent.
+func typedFieldValues(d *schema.ResourceData, field string) []string {
+	v := d.Get(field)
+	if !configuresField(d, field) {
+		return typedAttributeValues(v)
+	}
+	switch v := v.(type) {
+	case int:
+		return []string{strconv.Itoa(v)}
+	case bool:
+		if !v {
+			return []string{"FALSE"}
+		}
+	}
+	return typedAttributeValues(v)
+}
+
+// configuresField tells whether the configuration sets a field, to a known
+// value.
+func configuresField(d *schema.ResourceData, field string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	value := config.GetAttr(field)
+	return !value.IsNull() && value.IsKnown()
+}
+
+// addTypedAttributes adds the values of all non-empty typed fields to the
+// given add request.
+func addTypedAttributes(request *ldap.AddRequest, d *schema.ResourceData, attributes []typedAttribute) {
+	for _, attribute := range attributes {
+		if values := typedFieldValues(d, attribute.Field); len(values) > 0 {
+			request.Attribute(attribute.Attribute, values)
+		}
+	}
+}
+
+// modifyTypedAttributes appends a replace operation to the modify request for
+// each typed field that has changed; replacing with an empty list of values
+// removes the attribute from the entry.
+func modifyTypedAttributes(request *ldap.ModifyRequest, d *schema.ResourceData, attributes []typedAttribute) {
+	for _, attribute := range attributes {
+		if d.HasChange(attribute.Field) {
+			request.Replace(attribute.Attribute, typedFieldValues(d, attribute.Field))
+		}
+	}
+}
+
+// replaceTypedAttributes appends a replace operation to the modify request for
+// each typed field the configuration sets, changed or not, for resources
+// taking over existing entries: a field set to the zero value has no change
+// against the empty state they start with, but must still be written.
+func replaceTypedAttributes(request *ldap.ModifyRequest, d *schema.ResourceData, attributes []typedAttribute) {
+	for _, attribute := range attributes {
+		if configuresField(d, attribute.Field) {
+			request.Replace(attribute.Attribute, typedFieldValues(d, attribute.Field))
+		}
+	}
+}
+
+// readTypedAttributes sets each typed field from the values of the matching
+// attribute of the entry, or from its default when it is absent; the target
+// type is inferred from the current value of the field.
+func readTypedAttributes(d *schema.ResourceData, entry *ldap.Entry, attributes []typedAttribute) error {
+	for _, attribute := range attributes {
+		values := entry.GetAttributeValues(attribute.Attribute)
+		if len(values) == 0 && attribute.Default != "" {
+			values = []string{attribute.Default}
+		}
+		var value interface{}
+		switch d.Get(attribute.Field).(type) {
+		case string:
+			value = ""
+			if len(values) > 0 {
+				value = values[0]
+			}
+		case int:
+			value = 0
+			if len(values) > 0 {
+				i, err := strconv.Atoi(values[0])
+				if err != nil {
+					return fmt.Errorf("unable to convert %s value %q to int: %w", attribute.Attribute, values[0], err)
+				}
+				value = i
+			}
+		case bool:
+			value = len(values) > 0 && strings.EqualFold(values[0], "TRUE")
+		default:
+			value = sortValues(values)
+		}
+		if err := d.Set(attribute.Field, value); err != nil {
+			return fmt.Errorf("error setting %q from attribute %q of %q: %w", attribute.Field, attribute.Attribute, entry.DN, err)
+		}
+	}
+	return nil
+}
+
+// addRDNAttributes adds the naming attribute(s) found in the RDN of the given
+// DN to the add request, so that the new entry satisfies its own RDN.
+func addRDNAttributes(request *ldap.AddRequest, dn string) error {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return fmt.Errorf("invalid DN %q: %w", dn, err)
+	}
+	if len(parsed.RDNs) == 0 {
+		return fmt.Errorf("invalid DN %q: no RDN", dn)
+	}
+	for _, rdn := range parsed.RDNs[0].Attributes {
+		request.Attribute(rdn.Type, []string{rdn.Value})
+	}
+	return nil
+}
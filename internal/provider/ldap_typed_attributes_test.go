@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestReplaceTypedAttributes(t *testing.T) {
+	r := resourceLDAPOLCGlobal()
+	configured := map[string]cty.Value{
+		"idle_timeout": cty.NumberIntVal(0),
+		"threads":      cty.NumberIntVal(8),
+	}
+	config := map[string]cty.Value{}
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		config[name] = cty.NullVal(ty)
+		if value, ok := configured[name]; ok {
+			config[name] = value
+		}
+	}
+	d := r.Data(&terraform.InstanceState{
+		ID:         olcGlobalDN,
+		Attributes: map[string]string{"idle_timeout": "0", "threads": "8"},
+		RawConfig:  cty.ObjectVal(config),
+	})
+
+	request := ldap.NewModifyRequest(olcGlobalDN, nil)
+	replaceTypedAttributes(request, d, olcGlobalAttributes)
+
+	replaced := map[string][]string{}
+	for _, change := range request.Changes {
+		if change.Operation != ldap.ReplaceAttribute {
+			t.Errorf("unexpected operation %d on %s", change.Operation, change.Modification.Type)
+		}
+		replaced[change.Modification.Type] = change.Modification.Vals
+	}
+	expected := map[string][]string{"olcIdleTimeout": {"0"}, "olcThreads": {"8"}}
+	if !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("expected the configured settings %v to be replaced, got %v", expected, replaced)
+	}
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// orderedValuePrefix matches the {n} index prefixing the values of
+// X-ORDERED attributes (e.g. olcAccess), whose order is significant.
+var orderedValuePrefix = regexp.MustCompile(`^\{(\d+)\}`)
+
+// sortValues returns a sorted copy of the values of an attribute: LDAP does
+// not guarantee the order in which the values of multi-valued attributes are
+// returned, so they are sorted before being written to the state to avoid
+// spurious diffs. Values prefixed with an {n} index are sorted by index.
+func sortValues(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := orderedValuePrefix.FindStringSubmatch(sorted[i]), orderedValuePrefix.FindStringSubmatch(sorted[j])
+		if a != nil && b != nil {
+			m, _ := strconv.Atoi(a[1])
+			n, _ := strconv.Atoi(b[1])
+			if m != n {
+				return m < n
+			}
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
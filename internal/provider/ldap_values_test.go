@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortValues(t *testing.T) {
+	for _, tc := range []struct {
+		values, expected []string
+	}{
+		{[]string{"top", "person", "inetOrgPerson"}, []string{"inetOrgPerson", "person", "top"}},
+		{[]string{"{10}to * by * none", "{2}to attrs=cn by * read", "{0}to * by self write"},
+			[]string{"{0}to * by self write", "{2}to attrs=cn by * read", "{10}to * by * none"}},
+		{nil, []string{}},
+	} {
+		values := append([]string{}, tc.values...)
+		if sorted := sortValues(values); !reflect.DeepEqual(sorted, tc.expected) {
+			t.Errorf("sortValues(%q) = %q, expected %q", tc.values, sorted, tc.expected)
+		}
+		if !reflect.DeepEqual(values, append([]string{}, tc.values...)) {
+			t.Errorf("sortValues(%q) modified its argument", tc.values)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Subsystems of the provider logs. The level of each one can be set apart
+// from the rest of the provider logs with the TF_LOG_PROVIDER_LDAP_<SUBSYSTEM>
+// environment variable, e.g. TF_LOG_PROVIDER_LDAP_CONNECTION=TRACE.
+const (
+	subsystemConnection = "connection"
+	subsystemObject     = "object"
+	subsystemGroup      = "group"
+)
+
+var logSubsystems = []string{subsystemConnection, subsystemObject, subsystemGroup}
+
+// sensitiveAttributes are the attributes whose values are never logged.
+var sensitiveAttributes = []string{
+	"userPassword",
+	"unicodePwd",
+	"sambaNTPassword",
+	"sambaLMPassword",
+	"krbPrincipalKey",
+}
+
+// withLogging returns the context of a provider operation set up with the
+// subsystem loggers, redacting the bind credentials wherever they would
+// appear.
+func withLogging(ctx context.Context, meta interface{}) context.Context {
+	for _, subsystem := range logSubsystems {
+		ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_LDAP", subsystem))
+	}
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok {
+		return ctx
+	}
+	for _, secret := range providerConfig.bindSecrets {
+		if secret == "" {
+			continue
+		}
+		ctx = tflog.MaskLogStrings(ctx, secret)
+		for _, subsystem := range logSubsystems {
+			ctx = tflog.SubsystemMaskLogStrings(ctx, subsystem, secret)
+		}
+	}
+	return ctx
+}
+
+// logValues returns the values of an attribute as they should be logged,
+// redacting those of sensitive attributes.
+func logValues(attribute string, values []string) interface{} {
+	for _, sensitive := range sensitiveAttributes {
+		if strings.EqualFold(attribute, sensitive) {
+			return "<redacted>"
+		}
+	}
+	return values
+}
@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLogValuesRedactsSensitiveAttributes(t *testing.T) {
+	if v := logValues("userpassword", []string{"secret"}); v != "<redacted>" {
+		t.Errorf("expected userPassword to be redacted, got %v", v)
+	}
+	if v := logValues("mail", []string{"jdoe@example.com"}); !reflect.DeepEqual(v, []string{"jdoe@example.com"}) {
+		t.Errorf("expected mail to be logged, got %v", v)
+	}
+}
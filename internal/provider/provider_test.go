@@ -1,9 +1,13 @@
 package provider
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"testing"
 
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,6 +21,49 @@ func init() {
 	}
 }
 
+// testAccLDIF seeds the in-memory server of the acceptance tests like the
+// docker-compose setup of the tests directory.
+const testAccLDIF = `dn: dc=example,dc=com
+objectClass: top
+objectClass: dcObject
+objectClass: organization
+dc: example
+o: Example Inc.
+
+dn: cn=admin,dc=example,dc=com
+objectClass: simpleSecurityObject
+objectClass: organizationalRole
+cn: admin
+userPassword: admin
+`
+
+// TestMain runs the acceptance tests against an in-memory LDAP server when
+// TF_ACC is set and LDAP_HOST and LDAP_URL are not.
+func TestMain(m *testing.M) {
+	if os.Getenv(resource.EnvTfAcc) == "" || os.Getenv("LDAP_HOST") != "" || os.Getenv("LDAP_URL") != "" {
+		os.Exit(m.Run())
+	}
+
+	server, err := ldaptest.NewServer(ldaptest.Config{
+		Suffix:       "dc=example,dc=com",
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		LDIF:         testAccLDIF,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error starting the LDAP server: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("LDAP_HOST", server.Host())
+	os.Setenv("LDAP_PORT", strconv.Itoa(server.Port()))
+	os.Setenv("LDAP_BIND_USER", "cn=admin,dc=example,dc=com")
+	os.Setenv("LDAP_BIND_PASSWORD", "admin")
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -27,6 +74,35 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestValidateLDAPURLList(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"ldaps://ldap.example.com":                                true,
+		"ldap://ldap1.example.com  ldaps://ldap2.example.com:636": true,
+		"ldapi://%2Fvar%2Frun%2Fslapd%2Fldapi":                    true,
+		"ldap://ldap.example.com https://example.com":             false,
+		"ldap.example.com":                                        false,
+	} {
+		if _, errs := validateLDAPURLList(value, "ldap_url"); (len(errs) == 0) != valid {
+			t.Errorf("validateLDAPURLList(%q) = %v, expected valid: %t", value, errs, valid)
+		}
+	}
+}
+
+func TestValidateProxyURL(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"":                                true,
+		"socks5://proxy.example.com:1080": true,
+		"socks5h://user:pw@proxy.example.com:1080": true,
+		"http://proxy.example.com:3128":            true,
+		"https://proxy.example.com:3128":           false,
+		"http://proxy.example.com":                 false,
+	} {
+		if _, errs := validateProxyURL(value, "proxy_url"); (len(errs) == 0) != valid {
+			t.Errorf("validateProxyURL(%q) = %v, expected valid: %t", value, errs, valid)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("LDAP_HOST"); v == "" {
 		t.Fatal("LDAP_HOST must be set for acceptance tests")
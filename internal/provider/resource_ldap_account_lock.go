@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// accountLockedTimeAttribute and accountFailureTimeAttribute are the
+	// operational attributes of the ppolicy overlay recording the lockout of
+	// an account and its failed binds.
+	accountLockedTimeAttribute  = "pwdAccountLockedTime"
+	accountFailureTimeAttribute = "pwdFailureTime"
+
+	// accountLockedPermanently is the pwdAccountLockedTime of the accounts
+	// locked until an administrator unlocks them.
+	accountLockedPermanently = "000001010000Z"
+)
+
+var accountLockAttributes = []string{accountLockedTimeAttribute, accountFailureTimeAttribute}
+
+func resourceLDAPAccountLock() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPAccountLockCreate),
+		ReadContext:   resourceLDAPAccountLockRead,
+		UpdateContext: withDNLock(resourceLDAPAccountLockUpdate),
+		DeleteContext: withDNLock(resourceLDAPAccountLockDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPAccountLockImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The DN of the existing account, e.g. uid=jdoe,ou=users,dc=example,dc=com.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"locked": {
+				Type: schema.TypeBool,
+				Description: "Whether the account is locked. Locking sets pwdAccountLockedTime to 000001010000Z, which locks it " +
+					"until an administrator unlocks it; unlocking removes pwdAccountLockedTime, including the lockouts the " +
+					"server set after too many failed binds.",
+				Required: true,
+			},
+			"clear_failures": {
+				Type:        schema.TypeBool,
+				Description: "Remove the failed binds recorded in pwdFailureTime when unlocking the account, so that it is not locked out again by its next failed bind. Default: true.",
+				Optional:    true,
+				Default:     true,
+			},
+			"unlock_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Unlock the account when the resource is destroyed while `locked` is true; otherwise its lock state is left as is. Default: true.",
+				Optional:    true,
+				Default:     true,
+			},
+			"locked_time": {
+				Type:        schema.TypeString,
+				Description: "The pwdAccountLockedTime of the account, empty when it is not locked.",
+				Computed:    true,
+			},
+			"failure_count": {
+				Type:        schema.TypeInt,
+				Description: "The number of failed binds recorded in pwdFailureTime.",
+				Computed:    true,
+			},
+		},
+
+		Description: "Locks or unlocks an existing account of the OpenLDAP ppolicy overlay, e.g. to lock out a compromised " +
+			"account or to reset a locked out one during an incident. The entry itself is not managed.",
+	}
+}
+
+// setAccountLock locks or unlocks an account, clearing its failed binds when
+// unlocking it if clearFailures is set; the attributes already in the wanted
+// state are left alone.
+func setAccountLock(ctx context.Context, meta interface{}, dn string, locked, clearFailures bool) error {
+	providerConfig := meta.(*ProviderConfig)
+	entry, err := searchEntry(providerConfig.Connection, dn, accountLockAttributes, providerConfig.DerefAliases)
+	if err != nil {
+		return fmt.Errorf("error looking for %q: %w", dn, err)
+	}
+	if entry == nil {
+		return ldap.NewError(ldap.LDAPResultNoSuchObject, fmt.Errorf("account %q not found", dn))
+	}
+
+	request := ldap.NewModifyRequest(dn, nil)
+	lockedTime := entry.GetAttributeValue(accountLockedTimeAttribute)
+	switch {
+	case locked && lockedTime != accountLockedPermanently:
+		// a lockout of the server expires, hence it is replaced
+		request.Replace(accountLockedTimeAttribute, []string{accountLockedPermanently})
+	case !locked && lockedTime != "":
+		request.Delete(accountLockedTimeAttribute, nil)
+	}
+	if !locked && clearFailures && len(entry.GetAttributeValues(accountFailureTimeAttribute)) > 0 {
+		request.Delete(accountFailureTimeAttribute, nil)
+	}
+	if len(request.Changes) == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "updating the lock of account", map[string]interface{}{
+		"dn":     dn,
+		"locked": locked,
+	})
+	if err := providerConfig.Connection.Modify(request); err != nil {
+		return fmt.Errorf("error updating the lock of %q: %w", dn, err)
+	}
+	return nil
+}
+
+func resourceLDAPAccountLockCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dn := d.Get("dn").(string)
+
+	if err := setAccountLock(ctx, meta, dn, d.Get("locked").(bool), d.Get("clear_failures").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+	return resourceLDAPAccountLockRead(ctx, d, meta)
+}
+
+func resourceLDAPAccountLockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for account", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, accountLockAttributes, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "account not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	lockedTime := entry.GetAttributeValue(accountLockedTimeAttribute)
+	d.Set("locked", lockedTime != "")
+	d.Set("locked_time", lockedTime)
+	d.Set("failure_count", len(entry.GetAttributeValues(accountFailureTimeAttribute)))
+	return nil
+}
+
+func resourceLDAPAccountLockUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+
+	if d.HasChanges("locked", "clear_failures") {
+		if err := setAccountLock(ctx, meta, d.Id(), d.Get("locked").(bool), d.Get("clear_failures").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceLDAPAccountLockRead(ctx, d, meta)
+}
+
+func resourceLDAPAccountLockDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	if !d.Get("locked").(bool) || !d.Get("unlock_on_destroy").(bool) {
+		return nil
+	}
+
+	err := setAccountLock(ctx, meta, d.Id(), false, d.Get("clear_failures").(bool))
+	var ldapErr *ldap.Error
+	if errors.As(err, &ldapErr) && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+		tflog.Warn(ctx, "account not found, nothing to unlock", map[string]interface{}{"dn": d.Id()})
+		return nil
+	}
+	return diag.FromErr(err)
+}
+
+func resourceLDAPAccountLockImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	d.Set("clear_failures", true)
+	d.Set("unlock_on_destroy", true)
+	if err := diagnosticsError(resourceLDAPAccountLockRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPAccountLock_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPAccountLockConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "id", "uid=lockuser,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "locked", "true"),
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "locked_time", "000001010000Z"),
+				),
+			},
+			{
+				Config: testAccLDAPAccountLockConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "locked", "false"),
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "locked_time", ""),
+					resource.TestCheckResourceAttr("ldap_account_lock.test", "failure_count", "0"),
+				),
+			},
+			{
+				ResourceName:      "ldap_account_lock.test",
+				ImportState:       true,
+				ImportStateId:     "uid=lockuser,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+	})
+}
+
+func testAccLDAPAccountLockConfig(locked bool) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "user" {
+  dn             = "uid=lockuser,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User" },
+    { cn = "Lock User" },
+  ]
+}
+
+resource "ldap_account_lock" "test" {
+  dn     = ldap_object.user.dn
+  locked = %t
+}
+`, locked)
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/adtime"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// lockoutTimeAttribute is the Active Directory attribute recording when an
+// account was locked out; writing 0 unlocks the account.
+const lockoutTimeAttribute = "lockoutTime"
+
+var adAccountAttributes = []string{accountControlAttribute, lockoutTimeAttribute}
+
+func resourceLDAPADAccount() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPADAccountCreate),
+		ReadContext:   resourceLDAPADAccountRead,
+		UpdateContext: withDNLock(resourceLDAPADAccountUpdate),
+		DeleteContext: resourceLDAPADAccountDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPADAccountImport,
+		},
+
+		CustomizeDiff: customizeDiffADAccount,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The DN of the existing Active Directory account, e.g. CN=John Doe,OU=Users,DC=example,DC=com.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Description: "Whether the account is enabled (ACCOUNTDISABLE of `userAccountControl` clear); the other flags are left as they are. Default: true.",
+				Optional:    true,
+				Default:     true,
+			},
+			"locked_out": {
+				Type: schema.TypeBool,
+				Description: "Whether the account is locked out (`lockoutTime` other than 0). It can only be set to false, " +
+					"which unlocks the account whenever it is found locked out; AD locks accounts out by itself. When it is " +
+					"not set, the lockout is only reported.",
+				Optional: true,
+				Computed: true,
+			},
+			"lockout_time": {
+				Type:        schema.TypeString,
+				Description: "When the account was locked out (`lockoutTime`), as an RFC 3339 timestamp; empty when it is not locked.",
+				Computed:    true,
+			},
+		},
+
+		Description: "Enables, disables and unlocks an existing Active Directory account, for the teams managing the " +
+			"state of the accounts rather than the accounts themselves. The entry itself is not managed, and destroying " +
+			"the resource leaves the account as it is.",
+	}
+}
+
+// customizeDiffADAccount rejects locked_out = true, since the accounts cannot
+// be locked out through lockoutTime.
+func customizeDiffADAccount(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	if v := config.GetAttr("locked_out"); v.IsKnown() && !v.IsNull() && v.True() {
+		return fmt.Errorf("locked_out can only be set to false: Active Directory locks the accounts out by itself")
+	}
+	return nil
+}
+
+// adAccountUnlock tells whether locked_out is set to false in the
+// configuration, i.e. the account is kept unlocked.
+func adAccountUnlock(d *schema.ResourceData) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	v := config.GetAttr("locked_out")
+	return v.IsKnown() && !v.IsNull() && v.False()
+}
+
+// setADAccountState enables or disables an account, and unlocks it if unlock
+// is set; the attributes already in the wanted state are left alone.
+func setADAccountState(ctx context.Context, meta interface{}, dn string, enabled, unlock bool) error {
+	providerConfig := meta.(*ProviderConfig)
+	entry, err := searchEntry(providerConfig.Connection, dn, adAccountAttributes, providerConfig.DerefAliases)
+	if err != nil {
+		return fmt.Errorf("error looking for %q: %w", dn, err)
+	}
+	if entry == nil {
+		return fmt.Errorf("account %q not found", dn)
+	}
+
+	request := ldap.NewModifyRequest(dn, nil)
+	value := int64(accountControlNormal)
+	if current := entry.GetAttributeValue(accountControlAttribute); current != "" {
+		if value, err = parseAccountControl(current); err != nil {
+			return err
+		}
+	}
+	wanted := value | accountControlDisabled
+	if enabled {
+		wanted = value &^ accountControlDisabled
+	}
+	if wanted != value {
+		request.Replace(accountControlAttribute, []string{strconv.FormatInt(wanted, 10)})
+	}
+	if unlock && adAccountLockedOut(entry) {
+		request.Replace(lockoutTimeAttribute, []string{strconv.FormatInt(adtime.Unset, 10)})
+	}
+	if len(request.Changes) == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "updating the state of account", map[string]interface{}{
+		"dn":      dn,
+		"enabled": enabled,
+		"unlock":  unlock,
+	})
+	if err := providerConfig.Connection.Modify(request); err != nil {
+		return fmt.Errorf("error updating the state of %q: %w", dn, err)
+	}
+	return nil
+}
+
+// adAccountLockedOut tells whether the lockoutTime of an account is set.
+func adAccountLockedOut(entry *ldap.Entry) bool {
+	v := entry.GetAttributeValue(lockoutTimeAttribute)
+	if v == "" {
+		return false
+	}
+	lockoutTime, err := adtime.Parse(v)
+	return err != nil || lockoutTime != adtime.Unset
+}
+
+func resourceLDAPADAccountCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dn := d.Get("dn").(string)
+
+	if err := setADAccountState(ctx, meta, dn, d.Get("enabled").(bool), adAccountUnlock(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+	return resourceLDAPADAccountRead(ctx, d, meta)
+}
+
+func resourceLDAPADAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for account", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, adAccountAttributes, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "account not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	enabled := true
+	if current := entry.GetAttributeValue(accountControlAttribute); current != "" {
+		value, err := parseAccountControl(current)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		enabled = value&accountControlDisabled == 0
+	}
+	d.Set("enabled", enabled)
+	d.Set("locked_out", adAccountLockedOut(entry))
+
+	lockoutTime := ""
+	if v := entry.GetAttributeValue(lockoutTimeAttribute); v != "" {
+		if lockoutTime, err = adtime.Format(v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.Set("lockout_time", lockoutTime)
+	return nil
+}
+
+func resourceLDAPADAccountUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+
+	if d.HasChanges("enabled", "locked_out") {
+		if err := setADAccountState(ctx, meta, d.Id(), d.Get("enabled").(bool), adAccountUnlock(d)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceLDAPADAccountRead(ctx, d, meta)
+}
+
+// resourceLDAPADAccountDelete leaves the account as it is.
+func resourceLDAPADAccountDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceLDAPADAccountImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	if err := diagnosticsError(resourceLDAPADAccountRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPADAccount_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPADAccountConfig("enabled = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "locked_out", "true"),
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "lockout_time", "2024-01-01T00:00:00Z"),
+				),
+			},
+			{
+				Config: testAccLDAPADAccountConfig("locked_out = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "locked_out", "false"),
+					resource.TestCheckResourceAttr("ldap_ad_account.test", "lockout_time", ""),
+				),
+			},
+			{
+				Config:      testAccLDAPADAccountConfig("locked_out = true"),
+				ExpectError: regexp.MustCompile("locked_out can only be set to false"),
+			},
+			{
+				ResourceName:      "ldap_ad_account.test",
+				ImportState:       true,
+				ImportStateId:     "uid=aduser,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+	})
+}
+
+func testAccLDAPADAccountConfig(state string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "user" {
+  dn             = "uid=aduser,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User" },
+    { cn = "AD User" },
+    { userAccountControl = "512" },
+    { lockoutTime = "133485408000000000" },
+  ]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_ad_account" "test" {
+  dn = ldap_object.user.dn
+  %s
+}
+`, state)
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPAlias() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPAliasCreate),
+		ReadContext:   resourceLDAPAliasRead,
+		UpdateContext: withDNLock(resourceLDAPAliasUpdate),
+		DeleteContext: withDNLock(resourceLDAPAliasDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPAliasImport,
+		},
+
+		CustomizeDiff: customizeDiffAliasTarget,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the alias entry (e.g. uid=jdoe,ou=staff,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"aliased_object_name": {
+				Type:             schema.TypeString,
+				Description:      "The DN of the entry the alias points to (aliasedObjectName).",
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the alias entry; alias is structural but allows no naming attribute, so an auxiliary class allowing it is needed too. Default: [\"alias\", \"extensibleObject\"].",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"validate_target": {
+				Type:        schema.TypeBool,
+				Description: "Check that the entry the alias points to exists, when planning and before writing the alias. Default: true.",
+				Optional:    true,
+				Default:     true,
+			},
+		},
+
+		Description: "Provides an LDAP alias entry (RFC 4512, section 2.6), which presents another entry at a " +
+			"different place in the tree, e.g. to offer several views of the same users.\n\n" +
+			"The alias itself is always read and written, whatever the provider's `deref_aliases`.",
+	}
+}
+
+// checkAliasTarget returns an error if the entry an alias points to does not
+// exist.
+func checkAliasTarget(conn client.Client, target string) error {
+	exists, err := ldapEntryExists(conn, target)
+	if err != nil {
+		return fmt.Errorf("error looking for the aliased object %q: %w", target, err)
+	}
+	if !exists {
+		return fmt.Errorf("the aliased object %q does not exist", target)
+	}
+	return nil
+}
+
+// customizeDiffAliasTarget checks at plan time that the entry an alias points
+// to exists, when it is known; targets created in the same apply are checked
+// when the alias is written.
+func customizeDiffAliasTarget(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	providerConfig, ok := meta.(*ProviderConfig)
+	if !ok || !d.Get("validate_target").(bool) || !d.NewValueKnown("aliased_object_name") {
+		return nil
+	}
+	if !d.HasChange("aliased_object_name") && d.Id() != "" {
+		return nil
+	}
+	return checkAliasTarget(providerConfig.Connection, d.Get("aliased_object_name").(string))
+}
+
+func resourceLDAPAliasCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	target := d.Get("aliased_object_name").(string)
+
+	if d.Get("validate_target").(bool) {
+		if err := checkAliasTarget(client, target); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Debug(ctx, "creating a new alias", map[string]interface{}{
+		"dn":                  dn,
+		"aliased_object_name": target,
+	})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+
+	objectClasses := []string{"alias", "extensibleObject"}
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	request.Attribute("aliasedObjectName", []string{canonicalDN(target)})
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating alias", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "alias added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPAliasRead(ctx, d, meta)
+}
+
+func resourceLDAPAliasRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for alias", map[string]interface{}{"dn": dn})
+
+	// aliases are never dereferenced here, or the target would be read
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, []string{"objectClass", "aliasedObjectName"}, ldap.NeverDerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "alias not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	d.Set("aliased_object_name", entry.GetAttributeValue("aliasedObjectName"))
+	return nil
+}
+
+func resourceLDAPAliasUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	if d.HasChange("aliased_object_name") {
+		target := d.Get("aliased_object_name").(string)
+		if d.Get("validate_target").(bool) {
+			if err := checkAliasTarget(client, target); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		tflog.Debug(ctx, "updating alias", map[string]interface{}{
+			"dn":                  dn,
+			"aliased_object_name": target,
+		})
+
+		request := ldap.NewModifyRequest(dn, []ldap.Control{})
+		request.Replace("aliasedObjectName", []string{canonicalDN(target)})
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating alias", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPAliasRead(ctx, d, meta)
+}
+
+func resourceLDAPAliasDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing alias", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_alias::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "alias removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+func resourceLDAPAliasImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	d.Set("validate_target", true)
+	if err := diagnosticsError(resourceLDAPAliasRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPAlias_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPAliasConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_alias.test", "dn", "uid=alias-test,ou=views,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_alias.test", "aliased_object_name", "uid=alias-test,ou=aliased,dc=example,dc=com"),
+				),
+			},
+			{
+				ResourceName:            "ldap_alias.test",
+				ImportState:             true,
+				ImportStateId:           "uid=alias-test,ou=views,dc=example,dc=com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"validate_target"},
+			},
+		},
+	})
+}
+
+func TestAccLDAPAlias_missingTarget(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLDAPAliasConfig_missingTarget,
+				ExpectError: regexp.MustCompile(`does not exist`),
+			},
+		},
+	})
+}
+
+const testAccLDAPAliasConfig_basic = `
+resource "ldap_object" "aliased" {
+  dn             = "ou=aliased,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "views" {
+  dn             = "ou=views,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_object" "target" {
+  dn             = "uid=alias-test,${ldap_object.aliased.dn}"
+  object_classes = ["account"]
+}
+
+resource "ldap_alias" "test" {
+  dn                  = "uid=alias-test,${ldap_object.views.dn}"
+  aliased_object_name = ldap_object.target.dn
+}
+`
+
+const testAccLDAPAliasConfig_missingTarget = `
+resource "ldap_alias" "test" {
+  dn                  = "uid=alias-missing,dc=example,dc=com"
+  aliased_object_name = "uid=missing,dc=example,dc=com"
+}
+`
@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPAttribute() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPAttributeCreate),
+		ReadContext:   resourceLDAPAttributeRead,
+		UpdateContext: withDNLock(resourceLDAPAttributeUpdate),
+		DeleteContext: withDNLock(resourceLDAPAttributeDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPAttributeImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The DN of the entry, which must exist and is not managed by Terraform otherwise, e.g. uid=jdoe,ou=users,dc=example,dc=com.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the attribute, e.g. loginShell.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"values": {
+				Type:        schema.TypeSet,
+				Description: "The values of the attribute, replacing those it had before; values which only differ in case or spaces are considered equal for case-insensitive attributes, as are equivalent DNs for DN-valued ones.",
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Manages a single attribute, with all its values, of an entry which is not managed by Terraform, " +
+			"e.g. the loginShell of users provisioned by an HR synchronization.\n\n" +
+			"Creating the resource replaces the values the attribute had, if any; destroying it removes the attribute " +
+			"from the entry, which is left in place. The attribute must not be managed by another resource too, e.g. " +
+			"the `attributes` of an `ldap_object`.\n\n" +
+			"The resource is imported with an ID of the form `<dn>|<name>`.",
+	}
+}
+
+func resourceLDAPAttributeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+	if diags := resourceLDAPAttributeReplace(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	d.SetId(dn + "|" + name)
+	return resourceLDAPAttributeRead(ctx, d, meta)
+}
+
+func resourceLDAPAttributeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+
+	tflog.Debug(ctx, "reading attribute", map[string]interface{}{"dn": dn, "name": name})
+
+	// the values may not be visible yet right after they are written
+	var entry *ldap.Entry
+	var values []string
+	err := readAfterWrite(ctx, meta, func() (found bool, err error) {
+		values = nil
+		if entry, err = searchEntry(client, dn, []string{name}, ldap.NeverDerefAliases); entry != nil {
+			values = entry.GetEqualFoldAttributeValues(name)
+		}
+		return len(values) > 0 && writesVisible(meta, dn, entry, []string{name}), err
+	})
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "entry not found, removing the attribute from the state because the entry no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+	if len(values) == 0 {
+		tflog.Warn(ctx, "attribute not found, removing it from the state because it no longer has values in LDAP", map[string]interface{}{
+			"dn":   dn,
+			"name": name,
+		})
+		d.SetId("")
+		return nil
+	}
+
+	// keep the values as configured unless the server considers them different
+	if !matchingOf(meta).equivalentValues(name, convertToStringSlice(d.Get("values").(*schema.Set).List()), values) {
+		if err := d.Set("values", sortValues(values)); err != nil {
+			return diag.Errorf("error setting values: %v", err)
+		}
+	}
+	return nil
+}
+
+func resourceLDAPAttributeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("values") {
+		if diags := resourceLDAPAttributeReplace(ctx, d, meta); diags.HasError() {
+			return diags
+		}
+	}
+	return resourceLDAPAttributeRead(ctx, d, meta)
+}
+
+// resourceLDAPAttributeReplace replaces the values of the attribute with the
+// configured ones.
+func resourceLDAPAttributeReplace(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+	values := matchingOf(meta).canonicalValues(name, sortValues(convertToStringSlice(d.Get("values").(*schema.Set).List())))
+
+	tflog.Debug(ctx, "replacing attribute values", map[string]interface{}{
+		"dn":     dn,
+		"name":   name,
+		"values": len(values),
+	})
+
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Replace(name, values)
+	if err := client.Modify(request); err != nil {
+		tflog.Error(ctx, "error replacing attribute values", map[string]interface{}{
+			"dn":    dn,
+			"name":  name,
+			"error": err.Error(),
+		})
+		return diag.Errorf("error setting %s on %q: %v", name, dn, err)
+	}
+	return nil
+}
+
+func resourceLDAPAttributeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+
+	tflog.Debug(ctx, "removing attribute", map[string]interface{}{"dn": dn, "name": name})
+
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Delete(name, nil)
+	if err := client.Modify(request); err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && (ldapErr.ResultCode == ldap.LDAPResultNoSuchObject || ldapErr.ResultCode == ldap.LDAPResultNoSuchAttribute) {
+			tflog.Warn(ctx, "attribute does not exist in LDAP, considering delete successful", map[string]interface{}{
+				"dn":   dn,
+				"name": name,
+			})
+			return nil
+		}
+		tflog.Error(ctx, "error removing attribute", map[string]interface{}{
+			"dn":    dn,
+			"name":  name,
+			"error": err.Error(),
+		})
+		return diag.Errorf("error removing %s from %q: %v", name, dn, err)
+	}
+	return nil
+}
+
+func resourceLDAPAttributeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	i := strings.LastIndex(d.Id(), "|")
+	if i <= 0 || i == len(d.Id())-1 {
+		return nil, fmt.Errorf("invalid ID %q, expected <dn>|<name>", d.Id())
+	}
+	d.Set("dn", d.Id()[:i])
+	d.Set("name", d.Id()[i+1:])
+	if err := diagnosticsError(resourceLDAPAttributeRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("attribute %s of %q not found", d.Get("name").(string), d.Get("dn").(string))
+	}
+	return []*schema.ResourceData{d}, nil
+}
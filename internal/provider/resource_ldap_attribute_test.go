@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPAttribute_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPAttributeConfig("Engineer"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_attribute.test", "id", "uid=attruser,dc=example,dc=com|title"),
+					resource.TestCheckResourceAttr("ldap_attribute.test", "values.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ldap_attribute.test", "values.*", "Engineer"),
+				),
+			},
+			{
+				Config: testAccLDAPAttributeConfig("Manager"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_attribute.test", "values.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ldap_attribute.test", "values.*", "Manager"),
+				),
+			},
+			{
+				ResourceName:      "ldap_attribute.test",
+				ImportState:       true,
+				ImportStateId:     "uid=attruser,dc=example,dc=com|title",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccLDAPAttributeConfig(title string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "user" {
+  dn             = "uid=attruser,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User" },
+    { cn = "Attribute User" },
+  ]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_attribute" "test" {
+  dn     = ldap_object.user.dn
+  name   = "title"
+  values = [%q]
+}
+`, title)
+}
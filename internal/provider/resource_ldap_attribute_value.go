@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPAttributeValue() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPAttributeValueCreate),
+		ReadContext:   resourceLDAPAttributeValueRead,
+		DeleteContext: withDNLock(resourceLDAPAttributeValueDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPAttributeValueImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The DN of the entry, which must exist, e.g. uid=jdoe,ou=users,dc=example,dc=com.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the multi-valued attribute, e.g. sshPublicKey.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Description: "The value added to the attribute; a value which only differs in case or spaces is considered equal for case-insensitive attributes, as is an equivalent DN for DN-valued ones.",
+				Required:    true,
+				ForceNew:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					name := d.Get("name").(string)
+					return old != "" && standardMatching.normalizeValue(name, old) == standardMatching.normalizeValue(name, new)
+				},
+			},
+		},
+
+		Description: "Manages a single value of a multi-valued attribute of an existing entry, e.g. one sshPublicKey of a user, " +
+			"so that several configurations can add their own values to the same attribute independently.\n\n" +
+			"Creating the resource fails if the attribute already has the value, which can be imported instead; destroying it " +
+			"only removes that value. The other values of the attribute are left untouched, and it must not be managed as a " +
+			"whole by another resource, e.g. an `ldap_attribute` or the `attributes` of an `ldap_object`.\n\n" +
+			"The resource is imported with an ID of the form `<dn>|<name>|<value>`.",
+	}
+}
+
+func resourceLDAPAttributeValueCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+	value := d.Get("value").(string)
+
+	tflog.Debug(ctx, "adding attribute value", map[string]interface{}{"dn": dn, "name": name})
+
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Add(name, []string{matchingOf(meta).canonicalValue(name, value)})
+	if err := client.Modify(request); err != nil {
+		tflog.Error(ctx, "error adding attribute value", map[string]interface{}{
+			"dn":    dn,
+			"name":  name,
+			"error": err.Error(),
+		})
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultAttributeOrValueExists {
+			return diag.Errorf("%s of %q already has the value %q, import it to manage it: %v", name, dn, value, err)
+		}
+		return diag.Errorf("error adding a value to %s of %q: %v", name, dn, err)
+	}
+
+	d.SetId(dn + "|" + name + "|" + value)
+	return resourceLDAPAttributeValueRead(ctx, d, meta)
+}
+
+func resourceLDAPAttributeValueRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+	value := d.Get("value").(string)
+
+	tflog.Debug(ctx, "reading attribute value", map[string]interface{}{"dn": dn, "name": name})
+
+	// the value may not be visible yet right after it is added
+	var entry *ldap.Entry
+	var found bool
+	err := readAfterWrite(ctx, meta, func() (bool, error) {
+		var err error
+		found = false
+		if entry, err = searchEntry(client, dn, []string{name}, ldap.NeverDerefAliases); entry != nil {
+			for _, v := range entry.GetEqualFoldAttributeValues(name) {
+				found = found || matchingOf(meta).normalizeValue(name, v) == matchingOf(meta).normalizeValue(name, value)
+			}
+		}
+		return found, err
+	})
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "entry not found, removing the attribute value from the state because the entry no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+	if found {
+		return nil
+	}
+	tflog.Warn(ctx, "attribute value not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{
+		"dn":   dn,
+		"name": name,
+	})
+	d.SetId("")
+	return nil
+}
+
+func resourceLDAPAttributeValueDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	name := d.Get("name").(string)
+	value := d.Get("value").(string)
+
+	tflog.Debug(ctx, "removing attribute value", map[string]interface{}{"dn": dn, "name": name})
+
+	request := ldap.NewModifyRequest(dn, nil)
+	request.Delete(name, []string{matchingOf(meta).canonicalValue(name, value)})
+	if err := client.Modify(request); err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && (ldapErr.ResultCode == ldap.LDAPResultNoSuchObject || ldapErr.ResultCode == ldap.LDAPResultNoSuchAttribute) {
+			tflog.Warn(ctx, "attribute value does not exist in LDAP, considering delete successful", map[string]interface{}{
+				"dn":   dn,
+				"name": name,
+			})
+			return nil
+		}
+		tflog.Error(ctx, "error removing attribute value", map[string]interface{}{
+			"dn":    dn,
+			"name":  name,
+			"error": err.Error(),
+		})
+		return diag.Errorf("error removing a value from %s of %q: %v", name, dn, err)
+	}
+	return nil
+}
+
+func resourceLDAPAttributeValueImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// values are more likely than DNs to contain the separator
+	parts := strings.SplitN(d.Id(), "|", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid ID %q, expected <dn>|<name>|<value>", d.Id())
+	}
+	d.Set("dn", parts[0])
+	d.Set("name", parts[1])
+	d.Set("value", parts[2])
+	if err := diagnosticsError(resourceLDAPAttributeValueRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("value %q of %s of %q not found", parts[2], parts[1], parts[0])
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPAttributeValue_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPAttributeValueConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_attribute_value.engineer", "id", "uid=valueuser,dc=example,dc=com|title|Engineer"),
+					resource.TestCheckResourceAttr("ldap_attribute_value.manager", "value", "Manager"),
+				),
+			},
+			{
+				ResourceName:      "ldap_attribute_value.engineer",
+				ImportState:       true,
+				ImportStateId:     "uid=valueuser,dc=example,dc=com|title|Engineer",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccLDAPAttributeValueConfig = `
+resource "ldap_object" "user" {
+  dn             = "uid=valueuser,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "User" },
+    { cn = "Value User" },
+  ]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_attribute_value" "engineer" {
+  dn    = ldap_object.user.dn
+  name  = "title"
+  value = "Engineer"
+}
+
+resource "ldap_attribute_value" "manager" {
+  dn    = ldap_object.user.dn
+  name  = "title"
+  value = "Manager"
+}
+`
@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPEntries() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLDAPEntriesCreate,
+		ReadContext:   resourceLDAPEntriesRead,
+		UpdateContext: resourceLDAPEntriesUpdate,
+		DeleteContext: resourceLDAPEntriesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"entries": {
+				Type: schema.TypeMap,
+				Description: "The entries to manage, as a map of DN to the JSON encoding of an object mapping attribute " +
+					"names to a value or a list of values (typically built with `jsonencode`).",
+				Required:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateFunc:     validateLDAPEntries,
+				DiffSuppressFunc: suppressEquivalentLDAPEntry,
+			},
+		},
+
+		Description: "Manages many entries as a single resource, reading them back with a handful of searches on refresh.\n\n" +
+			"Only the attributes listed for each entry are managed. Entries are created parents first and deleted " +
+			"children first, so a parent and its children can be part of the same map. With the provider's " +
+			"`use_transactions`, the changes of each apply are made in a single LDAP transaction.",
+	}
+}
+
+// parseLDAPEntryAttributes decodes the JSON value of an entry into its
+// attributes, each value being either a string or a list of strings.
+func parseLDAPEntryAttributes(value string) (map[string][]string, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, err
+	}
+	attributes := map[string][]string{}
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			attributes[name] = []string{v}
+		case []interface{}:
+			values := []string{}
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("values of attribute %q must be strings", name)
+				}
+				values = append(values, s)
+			}
+			attributes[name] = values
+		default:
+			return nil, fmt.Errorf("attribute %q must be a string or a list of strings", name)
+		}
+	}
+	return attributes, nil
+}
+
+// canonicalLDAPEntry encodes attributes as a JSON object of sorted lists of
+// values, so that equivalent entries compare equal.
+func canonicalLDAPEntry(attributes map[string][]string) string {
+	for name, values := range attributes {
+		attributes[name] = sortValues(values)
+	}
+	encoded, _ := json.Marshal(attributes)
+	return string(encoded)
+}
+
+func validateLDAPEntries(v interface{}, key string) (warnings []string, errors []error) {
+	for dn, value := range v.(map[string]interface{}) {
+		if _, err := ldap.ParseDN(dn); err != nil {
+			errors = append(errors, fmt.Errorf("%s: invalid DN %q: %v", key, dn, err))
+			continue
+		}
+		attributes, err := parseLDAPEntryAttributes(value.(string))
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: invalid attributes for %q: %v", key, dn, err))
+			continue
+		}
+		if len(attributes) == 0 {
+			errors = append(errors, fmt.Errorf("%s: no attributes for %q", key, dn))
+		}
+	}
+	return
+}
+
+func suppressEquivalentLDAPEntry(k, old, new string, d *schema.ResourceData) bool {
+	if strings.HasSuffix(k, ".%") || old == "" || new == "" {
+		return false
+	}
+	o, err := parseLDAPEntryAttributes(old)
+	if err != nil {
+		return false
+	}
+	n, err := parseLDAPEntryAttributes(new)
+	if err != nil {
+		return false
+	}
+	return canonicalLDAPEntry(o) == canonicalLDAPEntry(n)
+}
+
+// expandLDAPEntries decodes the entries field, returning the DNs sorted
+// parents first along with the attributes of each entry.
+func expandLDAPEntries(v interface{}) ([]string, map[string]map[string][]string, error) {
+	dns := []string{}
+	entries := map[string]map[string][]string{}
+	for dn, value := range v.(map[string]interface{}) {
+		attributes, err := parseLDAPEntryAttributes(value.(string))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid attributes for %q: %w", dn, err)
+		}
+		dns = append(dns, dn)
+		entries[dn] = attributes
+	}
+	sortDNsParentsFirst(dns)
+	return dns, entries, nil
+}
+
+// sortDNsParentsFirst sorts DNs by depth, so that parents come before their
+// children.
+func sortDNsParentsFirst(dns []string) {
+	depth := func(dn string) int {
+		if parsed, err := ldap.ParseDN(dn); err == nil {
+			return len(parsed.RDNs)
+		}
+		return strings.Count(dn, ",") + 1
+	}
+	sort.SliceStable(dns, func(i, j int) bool {
+		if di, dj := depth(dns[i]), depth(dns[j]); di != dj {
+			return di < dj
+		}
+		return dns[i] < dns[j]
+	})
+}
+
+func resourceLDAPEntriesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dns, entries, err := expandLDAPEntries(d.Get("entries"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_entries::create")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, dn := range dns {
+		if err := addLDAPEntry(t, dn, entries[dn]); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+	if err := t.Commit(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(dns, "\n")))))
+	return resourceLDAPEntriesRead(ctx, d, meta)
+}
+
+func resourceLDAPEntriesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	dns, entries, err := expandLDAPEntries(d.Get("entries"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names := map[string]bool{}
+	for _, attributes := range entries {
+		for name := range attributes {
+			names[name] = true
+		}
+	}
+	requested := []string{}
+	for name := range names {
+		requested = append(requested, name)
+	}
+	sort.Strings(requested)
+
+	tflog.Debug(ctx, "reading entries", map[string]interface{}{"entries": len(dns)})
+
+	found, err := batchReadEntries(ctx, client, dns, requested, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "error reading entries", map[string]interface{}{"error": err.Error()})
+		return diag.FromErr(err)
+	}
+
+	result := map[string]interface{}{}
+	for _, dn := range dns {
+		entry, ok := found[normalizeDN(dn)]
+		if !ok {
+			tflog.Warn(ctx, "entry no longer exists in LDAP", map[string]interface{}{"dn": dn})
+			continue
+		}
+		// report the attributes under the names used in the configuration
+		attributes := map[string][]string{}
+		for name := range entries[dn] {
+			values := entry.GetEqualFoldAttributeValues(name)
+			if len(values) > 0 {
+				attributes[name] = values
+			}
+		}
+		result[dn] = canonicalLDAPEntry(attributes)
+	}
+
+	if len(result) == 0 {
+		tflog.Warn(ctx, "none of the entries exist, removing them from the state", map[string]interface{}{"id": d.Id()})
+		d.SetId("")
+		return nil
+	}
+	if err := d.Set("entries", result); err != nil {
+		return diag.Errorf("error setting entries: %v", err)
+	}
+	return nil
+}
+
+func resourceLDAPEntriesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	o, n := d.GetChange("entries")
+	oldDNs, oldEntries, err := expandLDAPEntries(o)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	newDNs, newEntries, err := expandLDAPEntries(n)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_entries::update")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// delete the entries which are no longer managed, children first
+	for i := len(oldDNs) - 1; i >= 0; i-- {
+		dn := oldDNs[i]
+		if _, ok := newEntries[dn]; ok {
+			continue
+		}
+		tflog.Debug(ctx, "removing entry", map[string]interface{}{"dn": dn})
+		if err := t.Del(dn); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+
+	// add the new entries and update the changed ones, parents first
+	for _, dn := range newDNs {
+		current, ok := oldEntries[dn]
+		if !ok {
+			if err := addLDAPEntry(t, dn, newEntries[dn]); err != nil {
+				t.Abort()
+				return diag.FromErr(err)
+			}
+			continue
+		}
+
+		request := ldap.NewModifyRequest(dn, []ldap.Control{})
+		for name, values := range newEntries[dn] {
+			if canonicalLDAPEntry(map[string][]string{name: values}) != canonicalLDAPEntry(map[string][]string{name: current[name]}) {
+				request.Replace(name, values)
+			}
+		}
+		for name := range current {
+			if _, ok := newEntries[dn][name]; !ok {
+				request.Replace(name, []string{})
+			}
+		}
+		if len(request.Changes) == 0 {
+			continue
+		}
+
+		tflog.Debug(ctx, "updating entry", map[string]interface{}{"dn": dn})
+		if err := t.Modify(request); err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				// the entry disappeared since it was last read: recreate it
+				if err := addLDAPEntry(t, dn, newEntries[dn]); err != nil {
+					t.Abort()
+					return diag.FromErr(err)
+				}
+				continue
+			}
+			tflog.Error(ctx, "error updating entry", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := t.Commit(); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLDAPEntriesRead(ctx, d, meta)
+}
+
+func resourceLDAPEntriesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dns, _, err := expandLDAPEntries(d.Get("entries"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_entries::delete")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for i := len(dns) - 1; i >= 0; i-- {
+		tflog.Debug(ctx, "removing entry", map[string]interface{}{"dn": dns[i]})
+		if err := t.Del(dns[i]); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(t.Commit())
+}
+
+// addLDAPEntry creates an entry with the given attributes.
+func addLDAPEntry(t *ldapTransaction, dn string, attributes map[string][]string) error {
+	tflog.Debug(t.ctx, "adding entry", map[string]interface{}{
+		"operation": t.logPrefix,
+		"dn":        dn,
+	})
+
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+	for _, name := range names {
+		request.Attribute(name, attributes[name])
+	}
+	if err := t.Add(request); err != nil {
+		tflog.Error(t.ctx, "error adding entry", map[string]interface{}{
+			"operation": t.logPrefix,
+			"dn":        dn,
+			"error":     err.Error(),
+		})
+		return err
+	}
+	return nil
+}
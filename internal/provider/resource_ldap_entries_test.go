@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPEntries_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPEntriesConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_entries.test", "entries.%", "3"),
+				),
+			},
+			{
+				Config: testAccLDAPEntriesConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_entries.test", "entries.%", "2"),
+					resource.TestCheckResourceAttr("ldap_entries.test", "entries.cn=host1,ou=bulk,dc=example,dc=com",
+						`{"cn":["host1"],"description":["updated"],"objectClass":["device"]}`),
+				),
+			},
+		},
+	})
+}
+
+const testAccLDAPEntriesConfig_basic = `
+resource "ldap_entries" "test" {
+  entries = {
+    "ou=bulk,dc=example,dc=com"          = jsonencode({ objectClass = ["organizationalUnit"], ou = "bulk" })
+    "cn=host1,ou=bulk,dc=example,dc=com" = jsonencode({ objectClass = ["device"], cn = "host1" })
+    "cn=host2,ou=bulk,dc=example,dc=com" = jsonencode({ objectClass = ["device"], cn = "host2" })
+  }
+}
+`
+
+const testAccLDAPEntriesConfig_updated = `
+resource "ldap_entries" "test" {
+  entries = {
+    "ou=bulk,dc=example,dc=com"          = jsonencode({ objectClass = ["organizationalUnit"], ou = "bulk" })
+    "cn=host1,ou=bulk,dc=example,dc=com" = jsonencode({ objectClass = ["device"], cn = "host1", description = "updated" })
+  }
+}
+`
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"regexp"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// oidPattern matches numeric object identifiers, e.g. 1.3.6.1.1.8.
+var oidPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+func resourceLDAPExtendedOperation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLDAPExtendedOperationCreate,
+		ReadContext:   resourceLDAPExtendedOperationRead,
+		DeleteContext: resourceLDAPExtendedOperationDelete,
+
+		Description: "Sends an extended operation (RFC 4511, section 4.12) to the server, e.g. a cancel request, " +
+			"a refresh of a dynamic entry or a vendor-specific operation.\n\n" +
+			"The operation is only sent when the resource is created: it is not sent again on later applies " +
+			"unless `triggers` change, and destroying the resource only removes it from the state.",
+
+		Schema: map[string]*schema.Schema{
+			"request_name": {
+				Type:         schema.TypeString,
+				Description:  "The OID of the extended operation (requestName).",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(oidPattern, "must be a numeric OID"),
+			},
+			"request_value": {
+				Type:         schema.TypeString,
+				Description:  "The base64-encoded value of the request (requestValue), usually a BER-encoded ASN.1 structure defined by the operation.",
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values which, when changed, send the operation again.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"ignore_result_codes": {
+				Type: schema.TypeSet,
+				Description: "LDAP result codes considered a success, so that an operation which has already been " +
+					"applied does not fail (e.g. 119, noSuchOperation, for a cancel request).",
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"result_code": {
+				Type:        schema.TypeInt,
+				Description: "The LDAP result code of the operation.",
+				Computed:    true,
+			},
+			"response_name": {
+				Type:        schema.TypeString,
+				Description: "The OID returned by the server (responseName), if any.",
+				Computed:    true,
+			},
+			"response_value": {
+				Type:        schema.TypeString,
+				Description: "The base64-encoded value returned by the server (responseValue), if any.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceLDAPExtendedOperationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	name := d.Get("request_name").(string)
+
+	request := ldap.NewExtendedRequest(name, nil)
+	if v, ok := d.GetOk("request_value"); ok {
+		value, err := base64.StdEncoding.DecodeString(v.(string))
+		if err != nil {
+			return diag.Errorf("invalid request_value: %v", err)
+		}
+		request.Value = ber.NewString(ber.ClassContext, ber.TypePrimitive, 1, string(value), "Extended Request Value")
+	}
+
+	tflog.Debug(ctx, "sending extended operation", map[string]interface{}{"request_name": name})
+
+	response, err := client.Extended(request)
+	resultCode := ldap.LDAPResultSuccess
+	if err != nil {
+		var ldapErr *ldap.Error
+		if !errors.As(err, &ldapErr) || !d.Get("ignore_result_codes").(*schema.Set).Contains(int(ldapErr.ResultCode)) {
+			return diag.Errorf("extended operation %s failed: %v", name, err)
+		}
+		tflog.Warn(ctx, "extended operation failed with an ignored result code", map[string]interface{}{
+			"request_name": name,
+			"error":        err.Error(),
+		})
+		resultCode = int(ldapErr.ResultCode)
+	}
+
+	d.SetId(id.UniqueId())
+	d.Set("result_code", resultCode)
+	d.Set("response_name", "")
+	d.Set("response_value", "")
+	if response != nil {
+		d.Set("response_name", response.Name)
+		if response.Value != nil && response.Value.Data != nil {
+			d.Set("response_value", base64.StdEncoding.EncodeToString(response.Value.Data.Bytes()))
+		}
+	}
+
+	tflog.Debug(ctx, "extended operation sent", map[string]interface{}{
+		"request_name": name,
+		"result_code":  resultCode,
+	})
+	return nil
+}
+
+// resourceLDAPExtendedOperationRead does nothing: an extended operation
+// leaves nothing behind that could be read back.
+func resourceLDAPExtendedOperationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceLDAPExtendedOperationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	tflog.Debug(ctx, "removing extended operation from the state", map[string]interface{}{
+		"request_name": d.Get("request_name").(string),
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPExtendedOperation_whoAmI(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPExtendedOperationConfig_whoAmI,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_extended_operation.test", "result_code", "0"),
+					resource.TestCheckResourceAttrSet("ldap_extended_operation.test", "response_value"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLDAPExtendedOperation_ignoreResultCodes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPExtendedOperationConfig_cancel,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_extended_operation.test", "result_code", "119"),
+				),
+			},
+		},
+	})
+}
+
+const testAccLDAPExtendedOperationConfig_whoAmI = `
+resource "ldap_extended_operation" "test" {
+  request_name = "1.3.6.1.4.1.4203.1.11.3"
+}
+`
+
+// cancels a message which does not exist: SEQUENCE { cancelID 12345 }
+const testAccLDAPExtendedOperationConfig_cancel = `
+resource "ldap_extended_operation" "test" {
+  request_name        = "1.3.6.1.1.8"
+  request_value       = "MAQCAjA5"
+  ignore_result_codes = [119]
+}
+`
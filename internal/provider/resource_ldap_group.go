@@ -3,31 +3,48 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldapurl"
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceLDAPGroup() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceLDAPGroupCreate,
-		Read:   resourceLDAPGroupRead,
-		Update: resourceLDAPGroupUpdate,
-		Delete: resourceLDAPGroupDelete,
+	r := &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPGroupCreate),
+		ReadContext:   resourceLDAPGroupRead,
+		UpdateContext: withDNLock(resourceLDAPGroupUpdate),
+		DeleteContext: withDNLock(resourceLDAPGroupDelete),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceLDAPGroupImport,
 		},
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffDefaultObjectClasses(defaultGroupObjectClasses),
+			customizeDiffRequiredAttributes(defaultGroupObjectClasses, ldapGroupTypedAttributes),
+			customizeDiffAttributeNames,
+			customizeDiffObjectClasses,
+			customizeDiffSensitiveAttributes,
+			customizeDiffComputedAttributes,
+			customizeDiffRDN,
+			customizeDiffRenameGroupDN,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"dn": {
-				Type:        schema.TypeString,
-				Description: "The Distinguished Name (DN) of the LDAP group.",
-				Required:    true,
-				ForceNew:    true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the LDAP group; changing its RDN (e.g. its cn) renames the group in place, while moving it under another parent replaces it, unless the provider's `use_entry_uuid` is set. Either `dn`, `relative_dn`, or `rdn_attribute`, `rdn_value` and `parent_dn` must be set; it is computed from the latter otherwise.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"dn", "rdn_attribute", "relative_dn"},
 			},
 			"description": {
 				Type:        schema.TypeString,
@@ -40,10 +57,12 @@ func resourceLDAPGroup() *schema.Resource {
 				Optional:    true,
 			},
 			"member": {
-				Type:        schema.TypeSet,
-				Description: "A list of distinguished names (DNs) that are members of the groupOfNames.",
-				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Type:             schema.TypeSet,
+				Description:      "A list of distinguished names (DNs) that are members of the groupOfNames. DNs are compared and stored in canonical form (e.g. `cn=John Doe,dc=example,dc=com` for `CN=John Doe, DC=example, DC=com`).",
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+				Set:              hashDN,
+				DiffSuppressFunc: suppressEquivalentDN,
 			},
 
 			"member_uid": {
@@ -54,24 +73,40 @@ func resourceLDAPGroup() *schema.Resource {
 			},
 
 			"unique_member": {
-				Type:        schema.TypeSet,
-				Description: "A list of distinguished names (DNs) that are members of the groupOfUniqueNames.",
-				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Type:             schema.TypeSet,
+				Description:      "A list of distinguished names (DNs) that are members of the groupOfUniqueNames, compared and stored in canonical form like `member`.",
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+				Set:              hashDN,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+
+			"role_occupant": {
+				Type:             schema.TypeSet,
+				Description:      "A list of distinguished names (DNs) that occupy the organizationalRole, compared and stored in canonical form like `member`.",
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+				Set:              hashDN,
+				DiffSuppressFunc: suppressEquivalentDN,
 			},
 
 			"member_url": {
 				Type:        schema.TypeSet,
-				Description: "A list of LDAP URLs that can dynamically generate the member list for the groupOfURLs.",
+				Description: "A list of LDAP URLs that can dynamically generate the member list for the groupOfURLs, e.g. `ldap:///ou=people,dc=example,dc=com??sub?(departmentNumber=42)`; their syntax, scope and filter are checked when planning (RFC 4516).",
 				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateLDAPURL,
+				},
 			},
 			"attributes": {
 				Type:        schema.TypeSet,
-				Description: "The map of attributes of this object; each attribute can be multi-valued.",
+				Description: "The map of attributes of this object; each attribute can be multi-valued. Values which only differ in case or spaces are considered equal for case-insensitive attributes (e.g. cn or mail), as are equivalent DNs for DN-valued ones (e.g. member).",
 				Set:         attributeHash,
 				MinItems:    0,
 
+				DiffSuppressFunc: suppressEquivalentAttributeValue,
+
 				Elem: &schema.Schema{
 					Type:        schema.TypeMap,
 					Description: "The list of values for a given attribute.",
@@ -84,55 +119,78 @@ func resourceLDAPGroup() *schema.Resource {
 				},
 				Optional: true,
 			},
+			"permissive_modify": {
+				Type:        schema.TypeBool,
+				Description: "Send the changes of the members with the Permissive Modify control of Active Directory, so that adding a member already present or removing one already absent, e.g. changed outside of Terraform, does not fail the update (default: false).",
+				Optional:    true,
+				Default:     false,
+			},
+			"member_chunk_size": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum number of members added or removed by each modify request, for servers rejecting large modifications, e.g. Active Directory with groups of thousands of members; 0 sends all the changes in a single request (default: 0). When a request fails, the ones before it remain applied.",
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"attributes_json":      attributesJSONSchema(),
+			"sensitive_attributes": sensitiveAttributesSchema(),
 			"object_classes": {
 				Type:        schema.TypeSet,
-				Description: "List of object class names to be used for the LDAP group",
+				Description: "List of object class names to be used for the LDAP group (default: the provider's `default_group_object_classes`). Auxiliary classes are added and removed in place, while changing the structural class replaces the group.",
 				Optional:    true,
+				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"create_parents": createParentsSchema(),
+			"rename_references": referencesSchema("Replaces the former DN of the group with the new one in the attributes of the " +
+				"entries referencing it, e.g. the groups it is a member of, when it is renamed; for the directories without " +
+				"referential integrity."),
+			"controls": requestControlsSchema(),
 		},
+
+		Description: "Provides a LDAP group of names, whose members are managed as a set.\n\n" +
+			"When the server supports the Post-Read control (RFC 4527), the state is taken from the response to " +
+			"the updates of the group instead of being read back with another search. Created groups are always " +
+			"read back, as the responses to add requests do not carry their controls through the LDAP client.",
+	}
+	for name, s := range assertionSchema() {
+		r.Schema[name] = s
+	}
+	for name, s := range entryMetadataSchema() {
+		r.Schema[name] = s
+	}
+	for name, s := range rdnSchema() {
+		r.Schema[name] = s
 	}
+	return r
 }
 
-func resourceLDAPGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceLDAPGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withSensitiveValuesMasked(withLogging(ctx, meta), d)
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_group::create - creating a new group with DN %q", dn)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "creating group", map[string]interface{}{"dn": dn})
 
 	// Perform validation for attribute value.
 	if err := validateAttributes(d, providerConfig.InvalidAttributeValues); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	request := ldap.NewAddRequest(dn, []ldap.Control{})
-
-	// Object class
-	var objectClasses []string
-	if v, ok := d.GetOk("object_classes"); ok {
-		for _, oc := range (v.(*schema.Set)).List() {
-			log.Printf("[DEBUG] ldap_object::create - object %q has class: %q", dn, oc.(string))
-			objectClasses = append(objectClasses, oc.(string))
-		}
-	} else {
-		// Apply the default value for object_classes
-		objectClasses = []string{"posixGroup"}
-	}
-
-	errors := validateObjectClasses(objectClasses, d)
-	if len(errors) > 0 {
-		return fmt.Errorf("validation failed for DN %q: %v", dn, errors)
+	controls, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	request := ldap.NewAddRequest(dn, controls)
 
+	// Object class, the provider's default_group_object_classes by default
+	objectClasses := objectClasses(d, meta, defaultGroupObjectClasses)
 	request.Attribute("objectClass", objectClasses)
 
 	// Derive the CN (common name) from the DN
 	cn, err := deriveCNFromDN(dn)
 	if err != nil {
-		// Handle Error
-		log.Printf("[ERROR] failed to derive CN from DN %q: %v", dn, err)
-		return err
+		return diag.Errorf("failed to derive CN from DN %q: %v", dn, err)
 	}
 	request.Attribute("cn", []string{cn})
 
@@ -148,192 +206,230 @@ func resourceLDAPGroupCreate(d *schema.ResourceData, meta interface{}) error {
 		request.Attribute("memberUid", convertToStringSlice(v.(*schema.Set).List()))
 	}
 	if v, ok := d.GetOk("unique_member"); ok && v.(*schema.Set).Len() > 0 {
-		request.Attribute("uniqueMember", convertToStringSlice(v.(*schema.Set).List()))
+		request.Attribute("uniqueMember", matchingOf(meta).canonicalValues("uniqueMember", convertToStringSlice(v.(*schema.Set).List())))
+	}
+	if v, ok := d.GetOk("role_occupant"); ok && v.(*schema.Set).Len() > 0 {
+		request.Attribute("roleOccupant", matchingOf(meta).canonicalValues("roleOccupant", convertToStringSlice(v.(*schema.Set).List())))
 	}
 	if v, ok := d.GetOk("member_url"); ok && v.(*schema.Set).Len() > 0 {
 		request.Attribute("memberURL", convertToStringSlice(v.(*schema.Set).List()))
 	}
 	if v, ok := d.GetOk("member"); ok && v.(*schema.Set).Len() > 0 {
-		request.Attribute("member", convertToStringSlice(v.(*schema.Set).List()))
-	}
-	// if there is a non empty list of attributes, loop though it and
-	// create a new map collecting attribute names and its value(s); we need to
-	// do this because we could not model the attributes as a map[string][]string
-	// due to an appareent limitation in HCL; we have a []map[string]string, so
-	// we loop through the list and accumulate values when they share the same
-	// key, then we use these as attributes in the LDAP client.
-	if v, ok := d.GetOk("attributes"); ok {
-		attributes := v.(*schema.Set).List()
-		if len(attributes) > 0 {
-			log.Printf("[DEBUG] ldap_object::create - object %q has %d attributes", dn, len(attributes))
-			m := make(map[string][]string)
-			for _, attribute := range attributes {
-				log.Printf("[DEBUG] ldap_object::create - %q has attribute of type %T", dn, attribute)
-				// each map should only have one entry (see resource declaration)
-				for name, value := range attribute.(map[string]interface{}) {
-					log.Printf("[DEBUG] ldap_object::create - %q has attribute[%v] => %v (%T)", dn, name, value, value)
-					m[name] = append(m[name], value.(string))
-				}
-			}
-			// now loop through the map and add attributes with theys value(s)
-			for name, values := range m {
-				request.Attribute(name, values)
-			}
-		}
+		request.Attribute("member", matchingOf(meta).canonicalValues("member", convertToStringSlice(v.(*schema.Set).List())))
+	}
+	// add the free-form attributes, sensitive ones included
+	for name, values := range configuredAttributes(d) {
+		request.Attribute(name, values)
 	}
 
 	// Log the LDAP request attributes before sending the request
 	for _, attribute := range request.Attributes {
-		log.Printf("[DEBUG] Attribute being added to LDAP request: %s: %+v", attribute.Type, attribute.Vals)
+		tflog.SubsystemDebug(ctx, subsystemGroup, "attribute being added to the LDAP request", map[string]interface{}{
+			"dn":        dn,
+			"attribute": attribute.Type,
+			"values":    logValues(attribute.Type, attribute.Vals),
+		})
 	}
-	if err := client.Add(request); err != nil {
-		log.Printf("[ERROR] Error while creating LDAP group with DN: %s. Error: %s", dn, err)
-		return err
+	if err := addWithParents(ctx, d, meta, request); err != nil {
+		tflog.SubsystemError(ctx, subsystemGroup, "error creating group", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[DEBUG] ldap_group::create - group %q added to LDAP server", dn)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "group added to the LDAP server", map[string]interface{}{"dn": dn})
 
-	d.SetId(dn)                           // The DN is a unique identifier for the group.
-	return resourceLDAPGroupRead(d, meta) // Read the new group to update the state.
+	d.SetId(dn)                                // The DN is a unique identifier for the group.
+	return resourceLDAPGroupRead(ctx, d, meta) // Read the new group to update the state.
 }
 
-func resourceLDAPGroupRead(d *schema.ResourceData, meta interface{}) error {
-	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
-	dn := d.Get("dn").(string)
-
-	log.Printf("[DEBUG] ldap_group::read - looking for group %q", dn)
+// ldapGroupReadAttributes are the attributes read back from group entries.
+var ldapGroupReadAttributes = []string{"cn", "description", "gidNumber", "memberUid", "uniqueMember", "memberURL", "roleOccupant", "*", "entryCSN", "entryUUID", "createTimestamp", "modifyTimestamp"}
 
-	attributesToRead := []string{"cn", "description", "gidNumber", "memberUid", "uniqueMember", "memberURL", "*"}
+func resourceLDAPGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withSensitiveValuesMasked(withLogging(ctx, meta), d)
+	dn := d.Get("dn").(string)
 
-	request := ldap.NewSearchRequest(
-		dn,
-		ldap.ScopeBaseObject,
-		ldap.NeverDerefAliases,
-		0,
-		0,
-		false,
-		"(objectclass=*)",
-		attributesToRead,
-		nil,
-	)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "looking for group", map[string]interface{}{"dn": dn})
 
-	sr, err := client.Search(request)
+	entry, err := readTrackedEntry(ctx, d, meta, ldapGroupReadAttributes)
 	if err != nil {
-		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
-			log.Printf("[WARN] ldap_group::read - group not found, removing %q from state because it no longer exists in LDAP", dn)
-			d.SetId("")
-			return nil
-		}
-		log.Printf("[ERROR] ldap_group::read - lookup for %q failed: %v", dn, err)
-		return err
-	}
-
-	if len(sr.Entries) == 0 {
-		log.Printf("[WARN] ldap_group::read - no results returned for group %q", dn)
+		tflog.SubsystemError(ctx, subsystemGroup, "group lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.SubsystemWarn(ctx, subsystemGroup, "group not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
 		d.SetId("")
 		return nil
 	}
 
-	entry := sr.Entries[0]
+	setTrackedEntryID(ctx, d, meta, entry)
+	if err := setLDAPGroupState(ctx, d, meta, d.Get("dn").(string), entry); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "finished reading group", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+// setLDAPGroupState populates the state of an ldap_group from its entry.
+func setLDAPGroupState(ctx context.Context, d *schema.ResourceData, meta interface{}, dn string, entry *ldap.Entry) error {
+	matching := matchingOf(meta)
+	d.Set("entry_csn", entry.GetAttributeValue("entryCSN"))
 	d.Set("description", entry.GetAttributeValue("description"))
 	// Handling gidNumber attribute
 	gidNumberStr := entry.GetAttributeValue("gidNumber")
 	if gidNumberStr != "" {
 		gidNumber, err := strconv.Atoi(gidNumberStr)
 		if err != nil {
-			log.Printf("[ERROR] unable to convert gidNumber to int: %s", err)
-			return err
+			return fmt.Errorf("unable to convert gidNumber of %q to int: %w", dn, err)
 		}
 		d.Set("gid_number", gidNumber)
 	}
 
 	// Reading and setting objectClass attribute
 	objectClasses := entry.GetAttributeValues("objectClass")
-	if len(objectClasses) > 0 {
-		d.Set("object_classes", schema.NewSet(schema.HashString, convertToInterfaceSlice(objectClasses)))
-	}
-
-	// Reading and setting memberUid attribute
-	memberUids := entry.GetAttributeValues("memberUid")
-	if len(memberUids) > 0 {
-		d.Set("member_uid", memberUids)
-	}
-
-	// Reading and setting uniqueMember attribute
-	uniqueMembers := entry.GetAttributeValues("uniqueMember")
-	if len(uniqueMembers) > 0 {
-		d.Set("unique_member", uniqueMembers)
-	}
-
-	// Reading and setting memberURL attribute
-	memberURLs := entry.GetAttributeValues("memberURL")
-	if len(memberURLs) > 0 {
-		d.Set("member_url", memberURLs)
-	}
-
-	// Reading and setting member attribute
-	members := entry.GetAttributeValues("member")
-	if len(members) > 0 {
-		d.Set("member", memberURLs)
-	}
+	d.Set("object_classes", schema.NewSet(schema.HashString, convertToInterfaceSlice(objectClasses)))
+
+	// Reading and setting the member-like attributes; they are set even when
+	// empty, so that members removed outside of Terraform show up as drift
+	d.Set("member_uid", entry.GetAttributeValues("memberUid"))
+	d.Set("unique_member", matching.canonicalValues("uniqueMember", entry.GetAttributeValues("uniqueMember")))
+	d.Set("role_occupant", matching.canonicalValues("roleOccupant", entry.GetAttributeValues("roleOccupant")))
+	d.Set("member_url", entry.GetAttributeValues("memberURL"))
+	d.Set("member", matching.canonicalValues("member", entry.GetAttributeValues("member")))
 	// Handle other custom attributes
 	set := &schema.Set{
 		F: attributeHash,
 	}
-	for _, attribute := range sr.Entries[0].Attributes {
-		log.Printf("[DEBUG] ldap_object::read - treating attribute %q of %q (%d values: %v)", attribute.Name, dn, len(attribute.Values), attribute.Values)
-
+	spellings := matching.stateSpellings(d)
+	for _, attribute := range entry.Attributes {
 		// Skip already-handled or system attributes
 		if attribute.Name == "objectClass" || attribute.Name == "cn" || attribute.Name == "description" ||
 			attribute.Name == "gidNumber" || attribute.Name == "memberUid" || attribute.Name == "uniqueMember" ||
-			attribute.Name == "memberURL" || attribute.Name == "member" {
-			log.Printf("[DEBUG] ldap_object::read - skipping attribute %q of %q", attribute.Name, dn)
+			attribute.Name == "memberURL" || attribute.Name == "member" || attribute.Name == "roleOccupant" ||
+			isServerManagedAttribute(meta, attribute.Name) || isComputedAttribute(meta, attribute.Name) {
 			continue
 		}
 		if len(attribute.Values) == 1 {
 			// we don't treat the RDN as an ordinary attribute
 			a := fmt.Sprintf("%s=%s", attribute.Name, attribute.Values[0])
 			if strings.HasPrefix(dn, a) {
-				log.Printf("[DEBUG] ldap_object::read - skipping RDN %q of %q", a, dn)
 				continue
 			}
 		}
-		log.Printf("[DEBUG] ldap_object::read - adding attribute %q to %q (%d values)", attribute.Name, dn, len(attribute.Values))
+		tflog.SubsystemTrace(ctx, subsystemGroup, "reading attribute", map[string]interface{}{
+			"dn":        dn,
+			"attribute": attribute.Name,
+			"values":    logValues(attribute.Name, attribute.Values),
+		})
 		// now add each value as an individual entry into the object, because
 		// we do not handle name => []values, and we have a set of maps each
 		// holding a single entry name => value; multiple maps may share the
 		// same key.
 		for _, value := range attribute.Values {
-			log.Printf("[DEBUG] ldap_object::read - for %q, setting %q => %q", dn, attribute.Name, value)
 			set.Add(map[string]interface{}{
-				attribute.Name: value,
+				attribute.Name: matching.spelling(spellings, attribute.Name, value),
 			})
 		}
 	}
 
-	if err := d.Set("attributes", set); err != nil {
-		log.Printf("[WARN] ldap_object::read - error setting LDAP attributes for %q : %v", dn, err)
-		return err
+	if err := setAttributes(d, set); err != nil {
+		return fmt.Errorf("error setting LDAP attributes for %q: %w", dn, err)
 	}
 
-	log.Printf("[DEBUG] ldap_group::read - finished reading group %q", dn)
-	return nil
+	return readEntryMetadata(d, entry)
+}
+
+// ldapGroupMemberKeys are the arguments of ldap_group listing its members.
+var ldapGroupMemberKeys = []string{"member", "member_uid", "unique_member", "member_url", "role_occupant"}
+
+// permissiveModifyControls returns the Permissive Modify control to send
+// along with the changes of the members of a group configured with
+// permissive_modify, if they change (see ldapGroupModifyRequests).
+func permissiveModifyControls(d *schema.ResourceData) []ldap.Control {
+	if !d.Get("permissive_modify").(bool) || !d.HasChanges(ldapGroupMemberKeys...) {
+		return nil
+	}
+	return []ldap.Control{ldap.NewControlString(ldap.ControlTypeMicrosoftPermissiveModify, false, "")}
 }
 
-func resourceLDAPGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+// ldapGroupModifyRequests returns the requests applying the changes of a
+// group, split into chunks (see splitModifyRequest). With permissive_modify,
+// the changes of its members go into requests of their own carrying the
+// Permissive Modify control, so that the other changes are not made
+// permissive (see sequenceModifyRequests for the Assertion control).
+func ldapGroupModifyRequests(d *schema.ResourceData, request, members *ldap.ModifyRequest) []*ldap.ModifyRequest {
+	size := d.Get("member_chunk_size").(int)
+	var requests []*ldap.ModifyRequest
+	if permissive := permissiveModifyControls(d); len(permissive) == 0 || len(members.Changes) == 0 {
+		request.Changes = append(request.Changes, members.Changes...)
+		requests = splitModifyRequest(request, size)
+	} else {
+		members.Controls = append(append([]ldap.Control(nil), request.Controls...), permissive...)
+		requests = append(splitModifyRequest(request, size), splitModifyRequest(members, size)...)
+	}
+	return sequenceModifyRequests(requests)
+}
+
+// customizeDiffRenameGroupDN renames the groups in place when only their RDN
+// changes, since ACLs and other entries may reference them; the other changes
+// of their DN follow customizeDiffRenameDN.
+func customizeDiffRenameGroupDN(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("dn") && d.NewValueKnown("dn") {
+		o, n := d.GetChange("dn")
+		_, _, oldParent, oldErr := splitDN(o.(string))
+		_, _, newParent, newErr := splitDN(n.(string))
+		if oldErr == nil && newErr == nil && normalizeDN(oldParent) == normalizeDN(newParent) {
+			return nil
+		}
+	}
+	return customizeDiffRenameDN(ctx, d, meta)
+}
+
+func resourceLDAPGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withSensitiveValuesMasked(withLogging(ctx, meta), d)
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
+
+	controls, err := assertionControls(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("dn") {
+		o, n := d.GetChange("dn")
+		if err := renameLDAPEntry(ctx, meta, o.(string), n.(string), controls); err != nil {
+			tflog.SubsystemError(ctx, subsystemGroup, "error renaming group", map[string]interface{}{
+				"dn":    o,
+				"error": err.Error(),
+			})
+			return diag.FromErr(assertionError(o.(string), err))
+		}
+		if controls, err = renamedAssertionControls(ctx, d); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := updateReferences(ctx, d, meta, "rename_references", o.(string), n.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_group::update - updating group %q", dn)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "updating group", map[string]interface{}{"dn": dn})
 
 	// Perform validation for attribute value.
 	if err := validateAttributes(d, providerConfig.InvalidAttributeValues); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	requested, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request := ldap.NewModifyRequest(dn, append(controls, requested...))
 
 	// Update description if it has changed.
 	if d.HasChange("description") {
@@ -344,28 +440,35 @@ func resourceLDAPGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 		request.Replace("gidNumber", []string{strconv.Itoa(d.Get("gid_number").(int))})
 	}
 
-	// Handle updates for member-like attributes
-	if err := updateLDAPAttributeSet(request, d, "member", "member"); err != nil {
-		return err
+	// Add and remove auxiliary object classes; changing the structural
+	// class replaces the group (see customizeDiffObjectClasses)
+	if err := updateLDAPAttributeSet(request, d, meta, "object_classes", "objectClass"); err != nil {
+		return diag.FromErr(err)
 	}
-	if err := updateLDAPAttributeSet(request, d, "member_uid", "memberUid"); err != nil {
-		return err
+
+	// Handle updates for member-like attributes, which may be sent apart
+	// (see ldapGroupModifyRequests)
+	members := ldap.NewModifyRequest(dn, nil)
+	if err := updateLDAPAttributeSet(members, d, meta, "member", "member"); err != nil {
+		return diag.FromErr(err)
 	}
-	if err := updateLDAPAttributeSet(request, d, "unique_member", "uniqueMember"); err != nil {
-		return err
+	if err := updateLDAPAttributeSet(members, d, meta, "member_uid", "memberUid"); err != nil {
+		return diag.FromErr(err)
 	}
-	if err := updateLDAPAttributeSet(request, d, "member_url", "memberURL"); err != nil {
-		return err
+	if err := updateLDAPAttributeSet(members, d, meta, "unique_member", "uniqueMember"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := updateLDAPAttributeSet(members, d, meta, "member_url", "memberURL"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := updateLDAPAttributeSet(members, d, meta, "role_occupant", "roleOccupant"); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if d.HasChange("attributes") {
-		o, n := d.GetChange("attributes")
-		log.Printf("[DEBUG] ldap_object::update - \n%s", printAttributes("old attributes map", o))
-		log.Printf("[DEBUG] ldap_object::update - \n%s", printAttributes("new attributes map", n))
-
-		added, changed, removed := computeDeltas(o.(*schema.Set), n.(*schema.Set))
+	if d.HasChanges(attributeKeys...) {
+		o, n := attributesChange(d)
+		added, changed, removed := computeDeltas(ctx, o, n)
 		if len(added) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes added", len(added))
 			for _, attr := range added {
 				request.Changes = append(request.Changes, ldap.Change{
 					Operation:    ldap.AddAttribute,
@@ -374,7 +477,6 @@ func resourceLDAPGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 		if len(changed) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes changed", len(changed))
 			for _, attr := range changed {
 				request.Changes = append(request.Changes, ldap.Change{
 					Operation:    ldap.ReplaceAttribute,
@@ -383,7 +485,6 @@ func resourceLDAPGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 		if len(removed) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes removed", len(removed))
 			for _, attr := range removed {
 				request.Changes = append(request.Changes, ldap.Change{
 					Operation:    ldap.DeleteAttribute,
@@ -393,47 +494,91 @@ func resourceLDAPGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	// Log the LDAP request modifications before sending the request
-	for _, change := range request.Changes {
-		log.Printf("[DEBUG] Attribute being changed to LDAP request: %s: %+v",
-			change.Modification.Type, change.Modification.Vals)
-	}
+	// all the chunks but the last are sent as is, the last one with the
+	// Post-Read control
+	chunks := ldapGroupModifyRequests(d, request, members)
 
 	// Log the LDAP request modifications before sending the request
-	for _, change := range request.Changes {
-		operation := "" // will hold the LDAP operation as a string
-		switch change.Operation {
-		case ldap.AddAttribute:
-			operation = "Add"
-		case ldap.DeleteAttribute:
-			operation = "Delete"
-		case ldap.ReplaceAttribute:
-			operation = "Replace"
+	for _, chunk := range chunks {
+		for _, change := range chunk.Changes {
+			operation := "" // will hold the LDAP operation as a string
+			switch change.Operation {
+			case ldap.AddAttribute:
+				operation = "Add"
+			case ldap.DeleteAttribute:
+				operation = "Delete"
+			case ldap.ReplaceAttribute:
+				operation = "Replace"
+			}
+			tflog.SubsystemDebug(ctx, subsystemGroup, "modify request change", map[string]interface{}{
+				"dn":        dn,
+				"operation": operation,
+				"attribute": change.Modification.Type,
+				"values":    logValues(change.Modification.Type, change.Modification.Vals),
+			})
 		}
-		log.Printf("[DEBUG] ModifyRequest Change - Operation: %s, Type: %s, Values: %v",
-			operation, change.Modification.Type, change.Modification.Vals)
 	}
 
-	if err := client.Modify(request); err != nil {
-		log.Printf("[ERROR] ldap_group::update - error updating group %q: %v", dn, err)
-		return err
+	if len(chunks) == 0 {
+		return resourceLDAPGroupRead(ctx, d, meta)
+	}
+	for i, chunk := range chunks[:len(chunks)-1] {
+		tflog.SubsystemDebug(ctx, subsystemGroup, "updating group members", map[string]interface{}{
+			"dn":     dn,
+			"chunk":  i + 1,
+			"chunks": len(chunks),
+		})
+		if err := providerConfig.Connection.Modify(chunk); err != nil {
+			tflog.SubsystemError(ctx, subsystemGroup, "error updating group", map[string]interface{}{
+				"dn":     dn,
+				"chunk":  i + 1,
+				"chunks": len(chunks),
+				"error":  err.Error(),
+			})
+			return diag.FromErr(fmt.Errorf("error updating group %s (request %d of %d): %w", dn, i+1, len(chunks), assertionError(dn, err)))
+		}
+	}
+	if len(chunks) > 1 {
+		tflog.SubsystemDebug(ctx, subsystemGroup, "updating group members", map[string]interface{}{
+			"dn":     dn,
+			"chunk":  len(chunks),
+			"chunks": len(chunks),
+		})
+	}
+	entry, err := modifyWithPostRead(ctx, meta, chunks[len(chunks)-1], ldapGroupReadAttributes)
+	if err != nil {
+		tflog.SubsystemError(ctx, subsystemGroup, "error updating group", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(assertionError(dn, err))
+	}
+	if entry != nil {
+		tflog.SubsystemDebug(ctx, subsystemGroup, "populating the state from the Post-Read control", map[string]interface{}{"dn": dn})
+		setTrackedEntryID(ctx, d, meta, entry)
+		return diag.FromErr(setLDAPGroupState(ctx, d, meta, dn, entry))
 	}
 
-	return resourceLDAPGroupRead(d, meta)
+	return resourceLDAPGroupRead(ctx, d, meta)
 }
 
-func resourceLDAPGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceLDAPGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
 	providerConfig := meta.(*ProviderConfig)
 	client := providerConfig.Connection
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_group::delete - removing group %q", dn)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "removing group", map[string]interface{}{"dn": dn})
 
-	if err := deleteLDAPEntry(client, dn, "ldap_group::delete"); err != nil {
-		return err
+	controls, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_group::delete", controls...); err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[DEBUG] ldap_group::delete - group %q removed", dn)
+	tflog.SubsystemDebug(ctx, subsystemGroup, "group removed", map[string]interface{}{"dn": dn})
 	d.SetId("") // This will remove the resource from the state file.
 	return nil
 }
@@ -444,13 +589,21 @@ func resourceLDAPGroupImport(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("dn", dn)
 
 	// Call the read function to ensure the data is fully populated
-	if err := resourceLDAPGroupRead(d, meta); err != nil {
+	if err := diagnosticsError(resourceLDAPGroupRead(ctx, d, meta)); err != nil {
 		return nil, err
 	}
 
 	return []*schema.ResourceData{d}, nil
 }
 
+// validateLDAPURL checks that a value is a valid LDAP URL.
+func validateLDAPURL(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := ldapurl.Parse(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", k, err))
+	}
+	return
+}
+
 // deriveCNFromDN is a helper function to extract the CN from a DN.
 // It assumes the DN is in the form "CN=groupname,OU=subunit,DC=example,DC=com".
 func deriveCNFromDN(dn string) (string, error) {
@@ -475,24 +628,8 @@ func convertToInterfaceSlice(strings []string) []interface{} {
 	return result
 }
 
-// validateObjectClasses checks if all required attributes for given objectClasses are available.
-func validateObjectClasses(objectClasses []string, d *schema.ResourceData) []error {
-	var errors []error
-	for _, objectClass := range objectClasses {
-		switch objectClass {
-		case "posixGroup":
-			if _, ok := d.GetOk("gid_number"); !ok {
-				errors = append(errors, fmt.Errorf("missing required attribute 'gid_number' for objectClass 'posixGroup'"))
-			}
-			// ... check other attributes for posixGroup ...
-		}
-
-	}
-	return errors
-}
-
 // updateLDAPAttributeSet handles the update logic for member-like attributes in a DRY manner.
-func updateLDAPAttributeSet(request *ldap.ModifyRequest, d *schema.ResourceData, tfAttributeName string, ldapAttributeName string) error {
+func updateLDAPAttributeSet(request *ldap.ModifyRequest, d *schema.ResourceData, meta interface{}, tfAttributeName string, ldapAttributeName string) error {
 	if d.HasChange(tfAttributeName) {
 		oldVal, newVal := d.GetChange(tfAttributeName)
 		oldSet := oldVal.(*schema.Set)
@@ -500,7 +637,7 @@ func updateLDAPAttributeSet(request *ldap.ModifyRequest, d *schema.ResourceData,
 
 		// obtaining strings to add
 		for _, add := range newSet.Difference(oldSet).List() {
-			request.Add(ldapAttributeName, []string{add.(string)})
+			request.Add(ldapAttributeName, []string{matchingOf(meta).canonicalValue(ldapAttributeName, add.(string))})
 		}
 
 		// obtaining strings to remove
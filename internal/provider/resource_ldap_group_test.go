@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccLDAPGroup_rename(t *testing.T) {
+	var uuid string
+	entryUUID := func(dn string) (string, error) {
+		conn := testAccProvider.Meta().(*ProviderConfig).Connection
+		entry, err := searchEntry(conn, dn, []string{"entryUUID"}, ldap.NeverDerefAliases)
+		if err != nil || entry == nil {
+			return "", fmt.Errorf("error reading %s: %v", dn, err)
+		}
+		return entry.GetAttributeValue("entryUUID"), nil
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPGroupConfigRename("developers"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.renamed", "dn", "cn=developers,dc=example,dc=com"),
+					func(*terraform.State) (err error) {
+						uuid, err = entryUUID("cn=developers,dc=example,dc=com")
+						return err
+					},
+				),
+			},
+			{
+				// the group is renamed in place and its parent group follows
+				Config: testAccLDAPGroupConfigRename("engineers"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.renamed", "dn", "cn=engineers,dc=example,dc=com"),
+					func(*terraform.State) error {
+						renamed, err := entryUUID("cn=engineers,dc=example,dc=com")
+						if err != nil {
+							return err
+						}
+						if renamed != uuid {
+							return fmt.Errorf("the group was replaced: entryUUID %q, expected %q", renamed, uuid)
+						}
+						conn := testAccProvider.Meta().(*ProviderConfig).Connection
+						entry, err := searchEntry(conn, "cn=staff,dc=example,dc=com", []string{"member"}, ldap.NeverDerefAliases)
+						if err != nil {
+							return err
+						}
+						if members := entry.GetAttributeValues("member"); len(members) != 1 || members[0] != "cn=engineers,dc=example,dc=com" {
+							return fmt.Errorf("members of cn=staff,dc=example,dc=com are %q, expected cn=engineers,dc=example,dc=com", members)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccLDAPGroupConfigRename(cn string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "staff" {
+  dn             = "cn=staff,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  attributes     = [{ member = "cn=developers,dc=example,dc=com" }]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_group" "renamed" {
+  dn             = "cn=%s,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  member         = ["cn=admin,dc=example,dc=com"]
+
+  rename_references {
+    base_dn = "dc=example,dc=com"
+  }
+
+  depends_on = [ldap_object.staff]
+}
+`, cn)
+}
+
+func TestPermissiveModifyControls(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   map[string]interface{}
+		expected bool
+	}{
+		{
+			name:     "members change",
+			config:   map[string]interface{}{"permissive_modify": true, "member": []interface{}{"cn=admin,dc=example,dc=com"}},
+			expected: true,
+		},
+		{
+			name:     "other changes",
+			config:   map[string]interface{}{"permissive_modify": true, "description": "Developers"},
+			expected: false,
+		},
+		{
+			name:     "not permissive",
+			config:   map[string]interface{}{"member": []interface{}{"cn=admin,dc=example,dc=com"}},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceLDAPGroup().Schema, tc.config)
+			controls := permissiveModifyControls(d)
+			found := ldap.FindControl(controls, ldap.ControlTypeMicrosoftPermissiveModify) != nil
+			if found != tc.expected || len(controls) > 1 {
+				t.Errorf("expected the Permissive Modify control: %t, got %v", tc.expected, controls)
+			}
+		})
+	}
+}
+
+func TestLDAPGroupModifyRequests(t *testing.T) {
+	assertion, err := client.NewControlAssertion("(objectClass=groupOfNames)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRequests := func(permissive bool) []*ldap.ModifyRequest {
+		d := schema.TestResourceDataRaw(t, resourceLDAPGroup().Schema, map[string]interface{}{
+			"permissive_modify": permissive,
+			"description":       "Developers",
+			"member":            []interface{}{"cn=admin,dc=example,dc=com"},
+		})
+		request := ldap.NewModifyRequest("cn=developers,dc=example,dc=com", []ldap.Control{assertion})
+		request.Replace("description", []string{"Developers"})
+		members := ldap.NewModifyRequest("cn=developers,dc=example,dc=com", nil)
+		members.Add("member", []string{"cn=admin,dc=example,dc=com"})
+		return ldapGroupModifyRequests(d, request, members)
+	}
+	has := func(request *ldap.ModifyRequest, oid string) bool {
+		return ldap.FindControl(request.Controls, oid) != nil
+	}
+
+	// the member changes go apart, only them being permissive
+	requests := newRequests(true)
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if requests[0].Changes[0].Modification.Type != "description" || len(requests[0].Changes) != 1 ||
+		has(requests[0], ldap.ControlTypeMicrosoftPermissiveModify) || !has(requests[0], client.OIDAssertion) {
+		t.Errorf("expected the description to be changed with the assertion and without Permissive Modify, got %+v", requests[0])
+	}
+	if requests[1].Changes[0].Modification.Type != "member" || len(requests[1].Changes) != 1 ||
+		!has(requests[1], ldap.ControlTypeMicrosoftPermissiveModify) || has(requests[1], client.OIDAssertion) {
+		t.Errorf("expected the members to be changed with Permissive Modify and without the assertion, got %+v", requests[1])
+	}
+
+	// they are sent along with the other changes otherwise
+	requests = newRequests(false)
+	if len(requests) != 1 || len(requests[0].Changes) != 2 || has(requests[0], ldap.ControlTypeMicrosoftPermissiveModify) {
+		t.Errorf("expected a single request without Permissive Modify, got %+v", requests)
+	}
+}
+
+func TestAccLDAPGroup_permissiveModify(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPGroupConfigPermissiveModify(false),
+				Check:  testAccCheckLDAPGroupMembers("cn=permissive,dc=example,dc=com", "cn=admin,dc=example,dc=com", "cn=bob,dc=example,dc=com"),
+			},
+			{
+				// the members are replaced behind the group's back before it
+				// is updated: it adds carol, who is already a member, and
+				// removes bob, who is no longer one
+				Config: testAccLDAPGroupConfigPermissiveModify(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.permissive", "member.#", "2"),
+					resource.TestCheckResourceAttr("ldap_group.permissive", "description", "changed along with the members"),
+					testAccCheckLDAPGroupMembers("cn=permissive,dc=example,dc=com", "cn=admin,dc=example,dc=com", "cn=carol,dc=example,dc=com"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPGroupMembers(dn string, expected ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		conn := testAccProvider.Meta().(*ProviderConfig).Connection
+		entry, err := searchEntry(conn, dn, []string{"member"}, ldap.NeverDerefAliases)
+		if err != nil || entry == nil {
+			return fmt.Errorf("error reading %s: %v", dn, err)
+		}
+		if !standardMatching.equivalentValues("member", entry.GetAttributeValues("member"), expected) {
+			return fmt.Errorf("members of %s are %q, expected %q", dn, entry.GetAttributeValues("member"), expected)
+		}
+		return nil
+	}
+}
+
+func testAccLDAPGroupConfigPermissiveModify(concurrent bool) string {
+	if !concurrent {
+		return `
+resource "ldap_group" "permissive" {
+  dn                = "cn=permissive,dc=example,dc=com"
+  object_classes    = ["groupOfNames"]
+  member            = ["cn=admin,dc=example,dc=com", "cn=bob,dc=example,dc=com"]
+  permissive_modify = true
+}
+`
+	}
+	return `
+# stands for another system changing the members right before the update
+resource "ldap_attribute" "concurrent" {
+  dn     = "cn=permissive,dc=example,dc=com"
+  name   = "member"
+  values = ["cn=admin,dc=example,dc=com", "cn=carol,dc=example,dc=com"]
+}
+
+resource "ldap_group" "permissive" {
+  dn                = "cn=permissive,dc=example,dc=com"
+  object_classes    = ["groupOfNames"]
+  description       = "changed along with the members"
+  member            = ["cn=admin,dc=example,dc=com", "cn=carol,dc=example,dc=com"]
+  permissive_modify = true
+
+  depends_on = [ldap_attribute.concurrent]
+}
+`
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// macAddressPattern matches MAC addresses as RFC 2307 writes them, e.g.
+// 0:1b:21:a:3c:4f or 00:1b:21:0a:3c:4f.
+var macAddressPattern = regexp.MustCompile(`^[0-9a-fA-F]{1,2}(:[0-9a-fA-F]{1,2}){5}$`)
+
+// hostAttributes maps the typed fields of ldap_host onto the attributes of the
+// ipHost, ieee802Device and bootableDevice object classes (RFC 2307).
+var hostAttributes = []typedAttribute{
+	{Field: "ip_host_number", Attribute: "ipHostNumber"},
+	{Field: "mac_address", Attribute: "macAddress"},
+	{Field: "boot_file", Attribute: "bootFile"},
+	{Field: "description", Attribute: "description"},
+}
+
+func resourceLDAPHost() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPHostCreate),
+		ReadContext:   resourceLDAPHostRead,
+		UpdateContext: withDNLock(resourceLDAPHostUpdate),
+		DeleteContext: withDNLock(resourceLDAPHostDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPHostImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the host entry (e.g. cn=web01,ou=hosts,dc=example,dc=com); its RDN is usually the cn of the host.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"object_classes": {
+				Type: schema.TypeSet,
+				Description: "The set of classes of the host entry. Default: [\"device\", \"ipHost\"], along with " +
+					"ieee802Device when `mac_address` is set and bootableDevice when `boot_file` is.",
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ip_host_number": {
+				Type:        schema.TypeSet,
+				Description: "The IP addresses of the host (ipHostNumber).",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsIPAddress,
+				},
+			},
+			"mac_address": {
+				Type:        schema.TypeSet,
+				Description: "The MAC addresses of the host (macAddress), e.g. 00:1b:21:0a:3c:4f.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(macAddressPattern, "must be a MAC address, e.g. 00:1b:21:0a:3c:4f"),
+				},
+			},
+			"boot_file": {
+				Type:        schema.TypeString,
+				Description: "The boot image of the host (bootFile).",
+				Optional:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "A description of the host.",
+				Optional:    true,
+			},
+		},
+
+		Description: "Provides a host entry (RFC 2307 ipHost device), for sites keeping their host inventory in the directory.",
+	}
+}
+
+// hostObjectClasses returns the default object classes of a host entry.
+func hostObjectClasses(d *schema.ResourceData) []string {
+	objectClasses := []string{"device", "ipHost"}
+	if d.Get("mac_address").(*schema.Set).Len() > 0 {
+		objectClasses = append(objectClasses, "ieee802Device")
+	}
+	if d.Get("boot_file").(string) != "" {
+		objectClasses = append(objectClasses, "bootableDevice")
+	}
+	return objectClasses
+}
+
+func resourceLDAPHostCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "creating a new host", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+
+	objectClasses := hostObjectClasses(d)
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	addTypedAttributes(request, d, hostAttributes)
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating host", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "host added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPHostRead(ctx, d, meta)
+}
+
+func resourceLDAPHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for host", map[string]interface{}{"dn": dn})
+
+	attributes := []string{"objectClass"}
+	for _, attribute := range hostAttributes {
+		attributes = append(attributes, attribute.Attribute)
+	}
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, attributes, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "host not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	return diag.FromErr(readTypedAttributes(d, entry, hostAttributes))
+}
+
+func resourceLDAPHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "updating host", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	modifyTypedAttributes(request, d, hostAttributes)
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating host", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPHostRead(ctx, d, meta)
+}
+
+func resourceLDAPHostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing host", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_host::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "host removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+func resourceLDAPHostImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	if err := diagnosticsError(resourceLDAPHostRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
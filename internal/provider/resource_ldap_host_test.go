@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPHost_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPHostConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_host.test", "dn", "cn=web01,ou=hosts,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_host.test", "ip_host_number.#", "1"),
+					resource.TestCheckResourceAttr("ldap_host.test", "object_classes.#", "2"),
+				),
+			},
+			{
+				Config: testAccLDAPHostConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_host.test", "ip_host_number.#", "2"),
+					resource.TestCheckResourceAttr("ldap_host.test", "mac_address.#", "1"),
+					resource.TestCheckResourceAttr("ldap_host.test", "boot_file", "pxelinux.0"),
+					resource.TestCheckResourceAttr("ldap_host.test", "object_classes.#", "4"),
+				),
+			},
+			{
+				ResourceName:      "ldap_host.test",
+				ImportState:       true,
+				ImportStateId:     "cn=web01,ou=hosts,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccLDAPHostConfig_basic = `
+resource "ldap_object" "hosts" {
+  dn             = "ou=hosts,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_host" "test" {
+  dn             = "cn=web01,${ldap_object.hosts.dn}"
+  ip_host_number = ["192.0.2.10"]
+}
+`
+
+const testAccLDAPHostConfig_updated = `
+resource "ldap_object" "hosts" {
+  dn             = "ou=hosts,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_host" "test" {
+  dn             = "cn=web01,${ldap_object.hosts.dn}"
+  object_classes = ["device", "ipHost", "ieee802Device", "bootableDevice"]
+  ip_host_number = ["192.0.2.10", "2001:db8::10"]
+  mac_address    = ["00:1b:21:0a:3c:4f"]
+  boot_file      = "pxelinux.0"
+  description    = "Web server"
+}
+`
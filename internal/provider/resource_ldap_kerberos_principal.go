@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// generalizedTimeLayout is the layout of the GeneralizedTime values written
+// by the provider, in UTC.
+const generalizedTimeLayout = "20060102150405Z"
+
+// kerberosPrincipalAttributes maps the string fields of
+// ldap_kerberos_principal onto the attributes of the krbPrincipal and
+// krbPrincipalAux object classes of the MIT Kerberos LDAP schema.
+var kerberosPrincipalAttributes = []typedAttribute{
+	{Field: "principal_name", Attribute: "krbPrincipalName"},
+	{Field: "password_policy_dn", Attribute: "krbPwdPolicyReference"},
+	{Field: "ticket_policy_dn", Attribute: "krbTicketPolicyReference"},
+}
+
+// kerberosPrincipalTimes maps the timestamp fields of ldap_kerberos_principal
+// onto the GeneralizedTime attributes holding them.
+var kerberosPrincipalTimes = []typedAttribute{
+	{Field: "principal_expiration", Attribute: "krbPrincipalExpiration"},
+	{Field: "password_expiration", Attribute: "krbPasswordExpiration"},
+}
+
+func resourceLDAPKerberosPrincipal() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPKerberosPrincipalCreate),
+		ReadContext:   resourceLDAPKerberosPrincipalRead,
+		UpdateContext: withDNLock(resourceLDAPKerberosPrincipalUpdate),
+		DeleteContext: withDNLock(resourceLDAPKerberosPrincipalDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPKerberosPrincipalImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the principal entry (e.g. krbPrincipalName=jdoe@EXAMPLE.COM,cn=EXAMPLE.COM,cn=krbContainer,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the principal entry. Default: [\"krbPrincipal\", \"krbPrincipalAux\", \"krbTicketPolicyAux\"].",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"principal_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the principal, with its realm (krbPrincipalName), e.g. jdoe@EXAMPLE.COM; changing it replaces the principal.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"password_policy_dn": {
+				Type:             schema.TypeString,
+				Description:      "The DN of the password policy of the principal (krbPwdPolicyReference).",
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"ticket_policy_dn": {
+				Type:             schema.TypeString,
+				Description:      "The DN of the ticket policy of the principal (krbTicketPolicyReference).",
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"principal_expiration": {
+				Type:             schema.TypeString,
+				Description:      "When the principal expires (krbPrincipalExpiration), as an RFC 3339 timestamp.",
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTime,
+			},
+			"password_expiration": {
+				Type:             schema.TypeString,
+				Description:      "When the keys of the principal expire (krbPasswordExpiration), as an RFC 3339 timestamp.",
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTime,
+			},
+		},
+
+		Description: "Provides a Kerberos principal entry of the LDAP backend of an MIT or FreeIPA KDC, so that " +
+			"principals can be created along with the POSIX accounts they belong to. The keys of the principal " +
+			"are not managed: they are set by the KDC (e.g. with `kadmin`).",
+	}
+}
+
+// generalizedTimeValues returns the GeneralizedTime value of an RFC 3339
+// timestamp, or none for an empty one.
+func generalizedTimeValues(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return []string{t.UTC().Format(generalizedTimeLayout)}, nil
+}
+
+// formatGeneralizedTime returns the RFC 3339 timestamp of a GeneralizedTime
+// value, or an empty string for none.
+func formatGeneralizedTime(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	t, err := ber.ParseGeneralizedTime([]byte(s))
+	if err != nil {
+		return "", fmt.Errorf("invalid GeneralizedTime %q: %w", s, err)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func resourceLDAPKerberosPrincipalCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "creating a new Kerberos principal", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+
+	objectClasses := []string{"krbPrincipal", "krbPrincipalAux", "krbTicketPolicyAux"}
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	// the principal name is usually the RDN, and is then added below
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return diag.Errorf("invalid DN %q: %v", dn, err)
+	}
+	for _, rdn := range parsed.RDNs[0].Attributes {
+		if !strings.EqualFold(rdn.Type, "krbPrincipalName") {
+			request.Attribute(rdn.Type, []string{rdn.Value})
+		}
+	}
+	addTypedAttributes(request, d, kerberosPrincipalAttributes)
+	for _, attribute := range kerberosPrincipalTimes {
+		values, err := generalizedTimeValues(d.Get(attribute.Field).(string))
+		if err != nil {
+			return diag.Errorf("invalid %s: %v", attribute.Field, err)
+		}
+		if len(values) > 0 {
+			request.Attribute(attribute.Attribute, values)
+		}
+	}
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating Kerberos principal", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Kerberos principal added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPKerberosPrincipalRead(ctx, d, meta)
+}
+
+func resourceLDAPKerberosPrincipalRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for Kerberos principal", map[string]interface{}{"dn": dn})
+
+	attributes := []string{"objectClass"}
+	for _, attribute := range append(kerberosPrincipalAttributes, kerberosPrincipalTimes...) {
+		attributes = append(attributes, attribute.Attribute)
+	}
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, attributes, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "Kerberos principal not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	if err := readTypedAttributes(d, entry, kerberosPrincipalAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+	for _, attribute := range kerberosPrincipalTimes {
+		value, err := formatGeneralizedTime(entry.GetAttributeValue(attribute.Attribute))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(attribute.Field, value)
+	}
+	return nil
+}
+
+func resourceLDAPKerberosPrincipalUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "updating Kerberos principal", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	modifyTypedAttributes(request, d, kerberosPrincipalAttributes)
+	for _, attribute := range kerberosPrincipalTimes {
+		if !d.HasChange(attribute.Field) {
+			continue
+		}
+		values, err := generalizedTimeValues(d.Get(attribute.Field).(string))
+		if err != nil {
+			return diag.Errorf("invalid %s: %v", attribute.Field, err)
+		}
+		request.Replace(attribute.Attribute, values)
+	}
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating Kerberos principal", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPKerberosPrincipalRead(ctx, d, meta)
+}
+
+func resourceLDAPKerberosPrincipalDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing Kerberos principal", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_kerberos_principal::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Kerberos principal removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+func resourceLDAPKerberosPrincipalImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	if err := diagnosticsError(resourceLDAPKerberosPrincipalRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneralizedTime(t *testing.T) {
+	values, err := generalizedTimeValues("2024-02-10T01:30:00+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"20240210003000Z"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("generalizedTimeValues() = %v, want %v", values, want)
+	}
+	if values, err := generalizedTimeValues(""); err != nil || values != nil {
+		t.Errorf("expected no values for an empty timestamp, got %v, %v", values, err)
+	}
+
+	cases := map[string]string{
+		"20240210003000Z":     "2024-02-10T00:30:00Z",
+		"20240210013000+0100": "2024-02-10T00:30:00Z",
+		"20240210003000.5Z":   "2024-02-10T00:30:00Z",
+		"":                    "",
+	}
+	for in, want := range cases {
+		if got, err := formatGeneralizedTime(in); err != nil || got != want {
+			t.Errorf("formatGeneralizedTime(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := formatGeneralizedTime("2024-02-10"); err == nil {
+		t.Error("expected an error for an invalid GeneralizedTime")
+	}
+}
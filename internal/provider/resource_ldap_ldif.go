@@ -0,0 +1,348 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/ldif"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPLDIF() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLDAPLDIFCreate,
+		ReadContext:   resourceLDAPLDIFRead,
+		UpdateContext: resourceLDAPLDIFUpdate,
+		DeleteContext: resourceLDAPLDIFDelete,
+
+		CustomizeDiff: resourceLDAPLDIFCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Type:        schema.TypeString,
+				Description: "LDIF content records (RFC 2849, without changetype); parent entries must come before their children.",
+				Required:    true,
+			},
+			"entries": {
+				Type:        schema.TypeMap,
+				Description: "The entries managed by this resource, as a map of DN to the JSON encoding of their attributes; it shows which entries change in plans.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Ensures that the entries described by LDIF content exist with the given attributes.\n\n" +
+			"Entries which already exist are updated rather than created; only the attributes listed in the LDIF " +
+			"are managed, and all their values are replaced. Entries removed from the content are deleted, as are " +
+			"all the entries when the resource is destroyed. With the provider's `use_transactions`, the changes " +
+			"of each apply are made in a single LDAP transaction.",
+	}
+}
+
+// ldifEntriesMap converts parsed LDIF entries into the value of the entries
+// field.
+func ldifEntriesMap(entries []*ldif.Entry) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, entry := range entries {
+		attributes := map[string][]string{}
+		for _, attribute := range entry.Attributes {
+			attributes[attribute.Name] = append([]string{}, attribute.Values...)
+		}
+		result[entry.DN] = canonicalLDAPEntry(attributes)
+	}
+	return result
+}
+
+func resourceLDAPLDIFCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("content") {
+		return d.SetNewComputed("entries")
+	}
+	entries, err := ldif.Parse(d.Get("content").(string))
+	if err != nil {
+		return fmt.Errorf("invalid LDIF content: %w", err)
+	}
+	desired := ldifEntriesMap(entries)
+
+	current := d.Get("entries").(map[string]interface{})
+	if len(current) == len(desired) {
+		changed := false
+		for dn, value := range desired {
+			if current[dn] != value {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+	return d.SetNew("entries", desired)
+}
+
+func resourceLDAPLDIFCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	content := d.Get("content").(string)
+
+	entries, err := ldif.Parse(content)
+	if err != nil {
+		return diag.Errorf("invalid LDIF content: %v", err)
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_ldif::create")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, entry := range entries {
+		if err := ensureLDIFEntry(t, entry, nil); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+	if err := t.Commit(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(content))))
+	return resourceLDAPLDIFRead(ctx, d, meta)
+}
+
+func resourceLDAPLDIFRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	entries, err := ldif.Parse(d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("invalid LDIF content: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	for _, entry := range entries {
+		tflog.Debug(ctx, "looking for entry", map[string]interface{}{"dn": entry.DN})
+
+		names := []string{}
+		for _, attribute := range entry.Attributes {
+			names = append(names, attribute.Name)
+		}
+		request := ldap.NewSearchRequest(
+			entry.DN,
+			ldap.ScopeBaseObject,
+			providerConfig.DerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			names,
+			nil,
+		)
+
+		sr, err := client.Search(request)
+		if err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+				tflog.Warn(ctx, "entry no longer exists in LDAP", map[string]interface{}{"dn": entry.DN})
+				continue
+			}
+			tflog.Error(ctx, "lookup failed", map[string]interface{}{
+				"dn":    entry.DN,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+		if len(sr.Entries) == 0 {
+			continue
+		}
+
+		// report the attributes under the names used in the LDIF content
+		attributes := map[string][]string{}
+		for _, attribute := range sr.Entries[0].Attributes {
+			if managed := entry.Attribute(attribute.Name); managed != nil {
+				attributes[managed.Name] = append(attributes[managed.Name], attribute.Values...)
+			}
+		}
+		result[entry.DN] = canonicalLDAPEntry(attributes)
+	}
+
+	if err := d.Set("entries", result); err != nil {
+		return diag.Errorf("error setting entries: %v", err)
+	}
+	return nil
+}
+
+func resourceLDAPLDIFUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	o, n := d.GetChange("content")
+	oldEntries, err := ldif.Parse(o.(string))
+	if err != nil {
+		return diag.Errorf("invalid LDIF content: %v", err)
+	}
+	newEntries, err := ldif.Parse(n.(string))
+	if err != nil {
+		return diag.Errorf("invalid LDIF content: %v", err)
+	}
+
+	// entries as they were last read, keyed by DN
+	read, _ := d.GetChange("entries")
+	current := map[string]map[string][]string{}
+	for dn, value := range read.(map[string]interface{}) {
+		attributes := map[string][]string{}
+		if err := json.Unmarshal([]byte(value.(string)), &attributes); err != nil {
+			return diag.Errorf("invalid state for entry %q: %v", dn, err)
+		}
+		current[strings.ToLower(dn)] = attributes
+	}
+	previous := map[string]*ldif.Entry{}
+	for _, entry := range oldEntries {
+		previous[strings.ToLower(entry.DN)] = entry
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_ldif::update")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// add and update entries, parents first
+	for _, entry := range newEntries {
+		key := strings.ToLower(entry.DN)
+		delete(previous, key)
+		// drop the attributes the previous content managed but the new one
+		// does not, as long as they were present on the entry
+		removed := []string{}
+		for name := range current[key] {
+			if entry.Attribute(name) == nil {
+				removed = append(removed, name)
+			}
+		}
+		if err := ensureLDIFEntry(t, entry, removed); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+
+	// delete the entries which are no longer in the content, children first
+	for i := len(oldEntries) - 1; i >= 0; i-- {
+		if entry, ok := previous[strings.ToLower(oldEntries[i].DN)]; ok {
+			tflog.Debug(ctx, "removing entry", map[string]interface{}{"dn": entry.DN})
+			if err := t.Del(entry.DN); err != nil {
+				t.Abort()
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err := t.Commit(); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLDAPLDIFRead(ctx, d, meta)
+}
+
+func resourceLDAPLDIFDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	entries, err := ldif.Parse(d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("invalid LDIF content: %v", err)
+	}
+
+	t, err := beginLDAPTransaction(ctx, meta, "ldap_ldif::delete")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		tflog.Debug(ctx, "removing entry", map[string]interface{}{"dn": entries[i].DN})
+		if err := t.Del(entries[i].DN); err != nil {
+			t.Abort()
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(t.Commit())
+}
+
+// ensureLDIFEntry creates the entry, or replaces the values of its attributes
+// if it already exists; the removed attributes are deleted from an existing
+// entry.
+func ensureLDIFEntry(t *ldapTransaction, entry *ldif.Entry, removed []string) error {
+	exists := false
+	if t.Active() {
+		// operations are only applied at commit time, so whether the entry
+		// already exists must be checked beforehand
+		var err error
+		if exists, err = ldapEntryExists(t.conn, entry.DN); err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		tflog.Debug(t.ctx, "adding entry", map[string]interface{}{
+			"operation": t.logPrefix,
+			"dn":        entry.DN,
+		})
+
+		request := ldap.NewAddRequest(entry.DN, []ldap.Control{})
+		for _, attribute := range entry.Attributes {
+			request.Attribute(attribute.Name, attribute.Values)
+		}
+
+		err := t.Add(request)
+		if err == nil {
+			return nil
+		}
+		if ldapErr, ok := err.(*ldap.Error); !ok || ldapErr.ResultCode != ldap.LDAPResultEntryAlreadyExists {
+			tflog.Error(t.ctx, "error adding entry", map[string]interface{}{
+				"operation": t.logPrefix,
+				"dn":        entry.DN,
+				"error":     err.Error(),
+			})
+			return err
+		}
+	}
+
+	tflog.Debug(t.ctx, "entry already exists, updating its attributes", map[string]interface{}{
+		"operation": t.logPrefix,
+		"dn":        entry.DN,
+	})
+
+	modify := ldap.NewModifyRequest(entry.DN, []ldap.Control{})
+	for _, attribute := range entry.Attributes {
+		modify.Replace(attribute.Name, attribute.Values)
+	}
+	for _, name := range removed {
+		modify.Replace(name, []string{})
+	}
+	if err := t.Modify(modify); err != nil {
+		tflog.Error(t.ctx, "error updating entry", map[string]interface{}{
+			"operation": t.logPrefix,
+			"dn":        entry.DN,
+			"error":     err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// ldapEntryExists tells whether an entry exists.
+func ldapEntryExists(conn client.Client, dn string) (bool, error) {
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		[]string{"1.1"},
+		nil,
+	)
+	if _, err := conn.Search(request); err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPLDIF_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPLDIFConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ldif.test", "entries.%", "2"),
+					resource.TestCheckResourceAttr("ldap_ldif.test", "entries.uid=ldif,ou=ldif,dc=example,dc=com",
+						`{"cn":["LDIF User"],"objectClass":["inetOrgPerson"],"sn":["User"],"uid":["ldif"]}`),
+				),
+			},
+			{
+				Config: testAccLDAPLDIFConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ldif.test", "entries.%", "1"),
+					resource.TestCheckResourceAttr("ldap_ldif.test", "entries.ou=ldif,dc=example,dc=com",
+						`{"description":["managed by LDIF"],"objectClass":["organizationalUnit"],"ou":["ldif"]}`),
+				),
+			},
+		},
+	})
+}
+
+const testAccLDAPLDIFConfig_basic = `
+resource "ldap_ldif" "test" {
+  content = <<-EOT
+    dn: ou=ldif,dc=example,dc=com
+    objectClass: organizationalUnit
+    ou: ldif
+
+    dn: uid=ldif,ou=ldif,dc=example,dc=com
+    objectClass: inetOrgPerson
+    uid: ldif
+    cn: LDIF User
+    sn: User
+  EOT
+}
+`
+
+const testAccLDAPLDIFConfig_updated = `
+resource "ldap_ldif" "test" {
+  content = <<-EOT
+    dn: ou=ldif,dc=example,dc=com
+    objectClass: organizationalUnit
+    ou: ldif
+    description: managed by LDIF
+  EOT
+}
+`
@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// mailGroupKind describes how a kind of mail group is stored: its default
+// object classes and the attributes holding its members.
+type mailGroupKind struct {
+	ObjectClasses []string
+	// MemberAttribute holds the DNs of the members, if the kind has any
+	MemberAttribute string
+	// AddressAttribute holds the addresses of the members outside the directory
+	AddressAttribute string
+}
+
+// mailGroupKinds are the kinds of mail groups ldap_mail_group can manage.
+var mailGroupKinds = map[string]mailGroupKind{
+	// a groupOfNames also holding the addresses of external members
+	"groupOfNames": {
+		ObjectClasses:    []string{"groupOfNames", "extensibleObject"},
+		MemberAttribute:  "member",
+		AddressAttribute: "rfc822MailMember",
+	},
+	// the mailGroup class of Netscape and Sun directories
+	"mailGroup": {
+		ObjectClasses:    []string{"groupOfNames", "mailGroup"},
+		MemberAttribute:  "member",
+		AddressAttribute: "mgrpRFC822MailMember",
+	},
+	// the RFC 2307 mail alias, used by NIS and by Postfix and Sendmail lookups
+	"nisMailAlias": {
+		ObjectClasses:    []string{"nisMailAlias", "extensibleObject"},
+		AddressAttribute: "rfc822MailMember",
+	},
+}
+
+// mailGroupKindNames returns the names of mailGroupKinds, sorted.
+func mailGroupKindNames() []string {
+	names := make([]string, 0, len(mailGroupKinds))
+	for name := range mailGroupKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func resourceLDAPMailGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPMailGroupCreate),
+		ReadContext:   resourceLDAPMailGroupRead,
+		UpdateContext: withDNLock(resourceLDAPMailGroupUpdate),
+		DeleteContext: withDNLock(resourceLDAPMailGroupDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPMailGroupImport,
+		},
+
+		CustomizeDiff: customizeDiffMailGroupMembers,
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the mail group (e.g. cn=staff,ou=lists,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"kind": {
+				Type: schema.TypeString,
+				Description: "How the group is stored: `groupOfNames` (members as `member`, external addresses as " +
+					"`rfc822MailMember`), `mailGroup` (external addresses as `mgrpRFC822MailMember`) or `nisMailAlias` " +
+					"(only addresses, as `rfc822MailMember`). Default: groupOfNames.",
+				Optional:     true,
+				Default:      "groupOfNames",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(mailGroupKindNames(), false),
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the group entry. Default: the classes of `kind`.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"mail": {
+				Type:        schema.TypeString,
+				Description: "The address of the group.",
+				Optional:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "A description of the group.",
+				Optional:    true,
+			},
+			"members": {
+				Type:             schema.TypeSet,
+				Description:      "The DNs of the entries receiving the mail of the group, compared and stored in canonical form like the `member` of `ldap_group`. Not supported by `nisMailAlias`.",
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+				Set:              hashDN,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"member_addresses": {
+				Type:        schema.TypeSet,
+				Description: "The addresses receiving the mail of the group which are not entries of the directory.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Provides a mail distribution list or mail alias, so that the mail routing stored in the " +
+			"directory can be managed declaratively. Members are added and removed one by one.",
+	}
+}
+
+// mailGroupAttributes returns the typed fields of an ldap_mail_group of the
+// given kind and the attributes holding them.
+func mailGroupAttributes(kind string) []typedAttribute {
+	attributes := []typedAttribute{
+		{Field: "mail", Attribute: "mail"},
+		{Field: "description", Attribute: "description"},
+		{Field: "member_addresses", Attribute: mailGroupKinds[kind].AddressAttribute},
+	}
+	if member := mailGroupKinds[kind].MemberAttribute; member != "" {
+		attributes = append(attributes, typedAttribute{Field: "members", Attribute: member})
+	}
+	return attributes
+}
+
+// customizeDiffMailGroupMembers rejects members for the kinds of groups which
+// cannot hold DNs.
+func customizeDiffMailGroupMembers(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	kind := d.Get("kind").(string)
+	if mailGroupKinds[kind].MemberAttribute == "" && d.Get("members").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("members cannot be set on a mail group of kind %s; use member_addresses instead", kind)
+	}
+	return nil
+}
+
+func resourceLDAPMailGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+	kind := d.Get("kind").(string)
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "creating mail group", map[string]interface{}{
+		"dn":   dn,
+		"kind": kind,
+	})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+
+	objectClasses := mailGroupKinds[kind].ObjectClasses
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	for _, attribute := range mailGroupAttributes(kind) {
+		if values := typedFieldValues(d, attribute.Field); len(values) > 0 {
+			request.Attribute(attribute.Attribute, matchingOf(meta).canonicalValues(attribute.Attribute, values))
+		}
+	}
+
+	if err := client.Add(request); err != nil {
+		tflog.SubsystemError(ctx, subsystemGroup, "error creating mail group", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "mail group added to the LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPMailGroupRead(ctx, d, meta)
+}
+
+func resourceLDAPMailGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+	kind := d.Get("kind").(string)
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "looking for mail group", map[string]interface{}{"dn": dn})
+
+	attributes := mailGroupAttributes(kind)
+	names := []string{"objectClass"}
+	for _, attribute := range attributes {
+		names = append(names, attribute.Attribute)
+	}
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, names, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.SubsystemError(ctx, subsystemGroup, "mail group lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.SubsystemWarn(ctx, subsystemGroup, "mail group not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	return diag.FromErr(readTypedAttributes(d, entry, attributes))
+}
+
+func resourceLDAPMailGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "updating mail group", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	for _, attribute := range mailGroupAttributes(d.Get("kind").(string)) {
+		switch attribute.Field {
+		case "members", "member_addresses":
+			// members are added and removed one by one
+			if err := updateLDAPAttributeSet(request, d, meta, attribute.Field, attribute.Attribute); err != nil {
+				return diag.FromErr(err)
+			}
+		default:
+			if d.HasChange(attribute.Field) {
+				request.Replace(attribute.Attribute, typedFieldValues(d, attribute.Field))
+			}
+		}
+	}
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.SubsystemError(ctx, subsystemGroup, "error updating mail group", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPMailGroupRead(ctx, d, meta)
+}
+
+func resourceLDAPMailGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "removing mail group", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_mail_group::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemDebug(ctx, subsystemGroup, "mail group removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+// resourceLDAPMailGroupImport imports a mail group, whose kind is told from
+// its object classes.
+func resourceLDAPMailGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	providerConfig := meta.(*ProviderConfig)
+	d.Set("dn", d.Id())
+
+	entry, err := searchEntry(providerConfig.Connection, d.Id(), []string{"objectClass"}, providerConfig.DerefAliases)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("mail group %q does not exist", d.Id())
+	}
+	d.Set("kind", mailGroupKindOf(entry.GetAttributeValues("objectClass")))
+
+	if err := diagnosticsError(resourceLDAPMailGroupRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// mailGroupKindOf returns the kind of a mail group with the given object
+// classes.
+func mailGroupKindOf(objectClasses []string) string {
+	kind := "groupOfNames"
+	for _, class := range objectClasses {
+		switch {
+		case strings.EqualFold(class, "nisMailAlias"):
+			return "nisMailAlias"
+		case strings.EqualFold(class, "mailGroup"):
+			kind = "mailGroup"
+		}
+	}
+	return kind
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestMailGroupKindOf(t *testing.T) {
+	cases := map[string][]string{
+		"groupOfNames": {"top", "groupOfNames", "extensibleObject"},
+		"mailGroup":    {"groupOfNames", "MailGroup"},
+		"nisMailAlias": {"nisMailAlias", "extensibleObject"},
+	}
+	for want, classes := range cases {
+		if got := mailGroupKindOf(classes); got != want {
+			t.Errorf("mailGroupKindOf(%v) = %q, want %q", classes, got, want)
+		}
+	}
+}
+
+func TestAccLDAPMailGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPMailGroupConfig("partner@example.org"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_mail_group.test", "mail", "staff@example.com"),
+					resource.TestCheckResourceAttr("ldap_mail_group.test", "members.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ldap_mail_group.test", "member_addresses.*", "partner@example.org"),
+				),
+			},
+			{
+				Config: testAccLDAPMailGroupConfig("contractor@example.net"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_mail_group.test", "member_addresses.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ldap_mail_group.test", "member_addresses.*", "contractor@example.net"),
+				),
+			},
+			{
+				ResourceName:      "ldap_mail_group.test",
+				ImportState:       true,
+				ImportStateId:     "cn=staff,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccLDAPMailGroupConfig(address string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "member" {
+  dn             = "uid=mail-group-member,dc=example,dc=com"
+  object_classes = ["account"]
+}
+
+resource "ldap_mail_group" "test" {
+  dn               = "cn=staff,dc=example,dc=com"
+  mail             = "staff@example.com"
+  members          = [ldap_object.member.dn]
+  member_addresses = [%q]
+}
+`, address)
+}
@@ -2,49 +2,77 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/hashcode"
 	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/set"
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceLDAPObject() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceLDAPObjectCreate,
-		Read:   resourceLDAPObjectRead,
-		Update: resourceLDAPObjectUpdate,
-		Delete: resourceLDAPObjectDelete,
-		Exists: resourceLDAPObjectExists,
+	r := &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPObjectCreate),
+		ReadContext:   resourceLDAPObjectRead,
+		UpdateContext: withDNLock(resourceLDAPObjectUpdate),
+		DeleteContext: withDNLock(resourceLDAPObjectDelete),
 
 		Importer: &schema.ResourceImporter{
-			State: resourceLDAPObjectImport,
+			StateContext: resourceLDAPObjectImport,
 		},
 
+		CustomizeDiff: customdiff.All(
+			customdiff.If(isFullObject, customizeDiffDefaultObjectClasses(defaultUserObjectClasses)),
+			customdiff.If(isFullObject, customizeDiffRequiredAttributes(defaultUserObjectClasses, nil)),
+			customizeDiffAttributeNames,
+			customdiff.If(isFullObject, customizeDiffObjectClasses),
+			customizeDiffManagedAttributes,
+			customizeDiffSensitiveAttributes,
+			customizeDiffComputedAttributes,
+			customizeDiffPassword,
+			customizeDiffAccountControl,
+			customizeDiffADTimes,
+			customizeDiffProxyAddresses,
+			customizeDiffShadowAccount,
+			customizeDiffRDN,
+			customizeDiffRenameDN,
+			customizeDiffPartialObject,
+			customizeDiffOnDestroy,
+			customizeDiffVerifyPlan,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"dn": {
-				Type:        schema.TypeString,
-				Description: "The Distinguished Name (DN) of the object, as the concatenation of its RDN (unique among siblings) and its parent's DN.",
-				Required:    true,
-				ForceNew:    true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the object, as the concatenation of its RDN (unique among siblings) and its parent's DN; changing it replaces the object, unless the provider's `use_entry_uuid` is set. Either `dn`, `relative_dn`, or `rdn_attribute`, `rdn_value` and `parent_dn` must be set; it is computed from the latter otherwise.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"dn", "rdn_attribute", "relative_dn"},
 			},
 			"object_classes": {
 				Type:        schema.TypeSet,
-				Description: "The set of classes this object conforms to (e.g. organizationalUnit, inetOrgPerson).",
+				Description: "The set of classes this object conforms to (e.g. organizationalUnit, inetOrgPerson); required unless the provider sets `default_user_object_classes`. Auxiliary classes are added and removed in place, while changing the structural class replaces the object, since servers refuse to modify it; without the server schema, the classes are always updated in place.",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Set:         schema.HashString,
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 			},
 			"attributes": {
 				Type:        schema.TypeSet,
-				Description: "The map of attributes of this object; each attribute can be multi-valued.",
+				Description: "The map of attributes of this object; each attribute can be multi-valued. Values which only differ in case or spaces are considered equal for case-insensitive attributes (e.g. cn or mail), as are equivalent DNs for DN-valued ones (e.g. member).",
 				Set:         attributeHash,
 				MinItems:    0,
 
+				DiffSuppressFunc: suppressEquivalentAttributeValue,
+
 				Elem: &schema.Schema{
 					Type:        schema.TypeMap,
 					Description: "The list of values for a given attribute.",
@@ -57,17 +85,65 @@ func resourceLDAPObject() *schema.Resource {
 				},
 				Optional: true,
 			},
+			"attributes_json":      attributesJSONSchema(),
+			"sensitive_attributes": sensitiveAttributesSchema(),
+			"all_attributes": {
+				Type:        schema.TypeMap,
+				Description: "All the attributes of the entry as read from the server, including those it computed or defaulted and some operational ones (entryUUID, structuralObjectClass, createTimestamp...) and the provider's `computed_attributes`, multi-valued ones being sorted and comma-separated. The password and `sensitive_attributes` are left out.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"user_account_control": accountControlSchema(),
+			"proxy_addresses":      proxyAddressesSchema(),
+			"shadow_account":       shadowAccountSchema(),
+			"managed_attributes": {
+				Type: schema.TypeSet,
+				Description: "The names of the only attributes Terraform reads and updates; the other attributes of the entry " +
+					"are ignored, so that other systems can manage them. By default, all the user attributes of the entry are managed.",
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+				Optional: true,
+			},
+			"mode":                 objectModeSchema(),
+			"exclusive_attributes": exclusiveAttributesSchema(),
+			"on_destroy":           onDestroySchema(),
+			"create_parents":       createParentsSchema(),
+			"membership_cleanup":   membershipCleanupSchema(),
+			"controls":             requestControlsSchema(),
 		},
 
-		Description: "Provides a LDAP Object.",
+		Description: "Provides a LDAP Object.\n\n" +
+			"When the server supports the Post-Read control (RFC 4527), the state is taken from the response to " +
+			"the updates of the entry instead of being read back with another search. Created entries are always " +
+			"read back, as the responses to add requests do not carry their controls through the LDAP client.",
+	}
+	for name, s := range assertionSchema() {
+		r.Schema[name] = s
+	}
+	for name, s := range entryMetadataSchema() {
+		r.Schema[name] = s
+	}
+	for name, s := range rdnSchema() {
+		r.Schema[name] = s
 	}
+	for name, s := range passwordSchema() {
+		r.Schema[name] = s
+	}
+	for name, s := range adTimeSchema() {
+		r.Schema[name] = s
+	}
+	return r
 }
 
-func resourceLDAPObjectImport(d *schema.ResourceData, meta interface{}) (imported []*schema.ResourceData, err error) {
+func resourceLDAPObjectImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (imported []*schema.ResourceData, err error) {
+	ctx = withLogging(ctx, meta)
 	d.Set("dn", d.Id())
-	err = readLDAPObjectImpl(d, meta, false)
+	err = readLDAPObjectImpl(ctx, d, meta, false)
 	if path := os.Getenv("TF_LDAP_IMPORTER_PATH"); path != "" {
-		log.Printf("[DEBUG] ldap_object::import - dumping imported object to %q", path)
+		tflog.SubsystemDebug(ctx, subsystemObject, "dumping imported object", map[string]interface{}{
+			"dn":   d.Id(),
+			"path": path,
+		})
 		if _, err := os.Stat(path); os.IsNotExist(err) {
 			// the export file does not exist
 			if file, err := os.Create(path); err == nil {
@@ -114,157 +190,246 @@ func resourceLDAPObjectImport(d *schema.ResourceData, meta interface{}) (importe
 	return
 }
 
-func resourceLDAPObjectExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
-	providerConfig := meta.(*ProviderConfig)
-	conn := providerConfig.Connection
-	dn := d.Get("dn").(string)
-
-	log.Printf("[DEBUG] ldap_object::exists - checking if %q exists", dn)
-
-	// search by primary key (that is, set the DN as base DN and use a "base
-	// object" scope); no attributes are retrieved since we are onòy checking
-	// for existence; all objects have an "objectClass" attribute, so the filter
-	// is a "match all"
-	request := ldap.NewSearchRequest(
-		dn,
-		ldap.ScopeBaseObject,
-		ldap.NeverDerefAliases,
-		0,
-		0,
-		false,
-		"(objectClass=*)",
-		nil,
-		nil,
-	)
-
-	_, err := conn.Search(request)
-	if err != nil {
-		if err, ok := err.(*ldap.Error); ok {
-			if err.ResultCode == 32 { // no such object
-				log.Printf("[WARN] ldap_object::exists - lookup for %q returned no value: deleted on server?", dn)
-				return false, nil
-			}
-		}
-		log.Printf("[DEBUG] ldap_object::exists - lookup for %q returned an error %v", dn, err)
-		return false, err
-	}
-
-	log.Printf("[DEBUG] ldap_object::exists - object %q exists", dn)
-	return true, nil
-}
-
-func resourceLDAPObjectCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceLDAPObjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withSensitiveValuesMasked(withLogging(ctx, meta), d)
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_object::create - creating a new object under %q", dn)
+	tflog.SubsystemDebug(ctx, subsystemObject, "creating object", map[string]interface{}{"dn": dn})
 
 	// Perform validation for attribute value.
 	if err := validateAttributes(d, providerConfig.InvalidAttributeValues); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	request := ldap.NewAddRequest(dn, []ldap.Control{})
+	if isPartialObject(d) {
+		if err := createPartialLDAPObject(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.SubsystemDebug(ctx, subsystemObject, "attributes set on the existing object", map[string]interface{}{"dn": dn})
+		d.SetId(dn)
+		return resourceLDAPObjectRead(ctx, d, meta)
+	}
 
-	// retrieve classe from HCL
-	objectClasses := []string{}
-	for _, oc := range (d.Get("object_classes").(*schema.Set)).List() {
-		log.Printf("[DEBUG] ldap_object::create - object %q has class: %q", dn, oc.(string))
-		objectClasses = append(objectClasses, oc.(string))
+	controls, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+	request := ldap.NewAddRequest(dn, controls)
+
+	// retrieve classe from HCL, or the provider's default_user_object_classes
+	objectClasses := objectClasses(d, meta, defaultUserObjectClasses)
+	tflog.SubsystemDebug(ctx, subsystemObject, "object classes", map[string]interface{}{
+		"dn":             dn,
+		"object_classes": objectClasses,
+	})
 	request.Attribute("objectClass", objectClasses)
 
-	// if there is a non empty list of attributes, loop though it and
-	// create a new map collecting attribute names and its value(s); we need to
-	// do this because we could not model the attributes as a map[string][]string
-	// due to an appareent limitation in HCL; we have a []map[string]string, so
-	// we loop through the list and accumulate values when they share the same
-	// key, then we use these as attributes in the LDAP client.
-	if v, ok := d.GetOk("attributes"); ok {
-		attributes := v.(*schema.Set).List()
-		if len(attributes) > 0 {
-			log.Printf("[DEBUG] ldap_object::create - object %q has %d attributes", dn, len(attributes))
-			m := make(map[string][]string)
-			for _, attribute := range attributes {
-				log.Printf("[DEBUG] ldap_object::create - %q has attribute of type %T", dn, attribute)
-				// each map should only have one entry (see resource declaration)
-				for name, value := range attribute.(map[string]interface{}) {
-					log.Printf("[DEBUG] ldap_object::create - %q has attribute[%v] => %v (%T)", dn, name, value, value)
-					m[name] = append(m[name], value.(string))
-				}
-			}
-			// now loop through the map and add attributes with theys value(s)
-			for name, values := range m {
-				request.Attribute(name, values)
-			}
-		}
+	// collect the values of the attributes, which we could not model as a
+	// map[string][]string due to an appareent limitation in HCL: we have sets
+	// of map[string]string, whose values are accumulated when they share the
+	// same key, then we use these as attributes in the LDAP client.
+	for name, values := range configuredAttributes(d) {
+		tflog.SubsystemTrace(ctx, subsystemObject, "adding attribute", map[string]interface{}{
+			"dn":        dn,
+			"attribute": name,
+			"values":    logValues(name, values),
+		})
+		request.Attribute(name, values)
+	}
+	if password, ok := passwordWriteOnly(d); ok {
+		request.Attribute(passwordAttribute, []string{password})
+		request.Controls = withPasswordPolicyControl(request.Controls)
+	}
+	addAccountControl(request, d)
+	if err := addADTimes(request, d); err != nil {
+		return diag.FromErr(err)
+	}
+	addProxyAddresses(request, d)
+	if err := addShadowAccount(request, d); err != nil {
+		return diag.FromErr(err)
 	}
 
-	err := client.Add(request)
-	if err != nil {
-		return err
+	if err := addWithParents(ctx, d, meta, request); err != nil {
+		return diag.FromErr(passwordPolicyError(err))
 	}
 
-	log.Printf("[DEBUG] ldap_object::create - object %q added to LDAP server", dn)
+	tflog.SubsystemDebug(ctx, subsystemObject, "object added to the LDAP server", map[string]interface{}{"dn": dn})
 
 	d.SetId(dn)
-	return resourceLDAPObjectRead(d, meta)
+	return resourceLDAPObjectRead(ctx, d, meta)
 }
 
-func resourceLDAPObjectRead(d *schema.ResourceData, meta interface{}) error {
-	return readLDAPObjectImpl(d, meta, true)
+// ldapObjectOperationalAttributes are the operational attributes read back
+// from object entries, for all_attributes and the fields tracking the entry;
+// they are not ordinary attributes.
+var ldapObjectOperationalAttributes = []string{
+	"entryCSN",
+	"entryUUID",
+	"structuralObjectClass",
+	"createTimestamp",
+	"modifyTimestamp",
+	"creatorsName",
+	"modifiersName",
 }
 
-func resourceLDAPObjectUpdate(d *schema.ResourceData, meta interface{}) error {
+// ldapObjectReadAttributes are the attributes read back from object entries.
+var ldapObjectReadAttributes = append([]string{"*"}, ldapObjectOperationalAttributes...)
+
+// isLDAPObjectOperationalAttribute tells whether the attribute is one of
+// ldapObjectOperationalAttributes.
+func isLDAPObjectOperationalAttribute(name string) bool {
+	for _, attribute := range ldapObjectOperationalAttributes {
+		if strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ldapObjectAttributesToRead returns the attributes to read back from the
+// entry of an ldap_object: all of them, or only the managed ones (along with
+// the configured ones of the objects which are not exclusive) and those the
+// resource always tracks.
+func ldapObjectAttributesToRead(d *schema.ResourceData, meta interface{}) []string {
+	computed := computedAttributeNames(meta)
+	managed := convertToStringSlice(d.Get("managed_attributes").(*schema.Set).List())
+	if !exclusiveAttributes(d) {
+		managed = configuredAttributeNames(d)
+	} else if len(managed) == 0 {
+		return append(append([]string{}, ldapObjectReadAttributes...), computed...)
+	}
+	attributes := append(append([]string{"objectClass"}, ldapObjectOperationalAttributes...), computed...)
+	tracked := len(attributes)
+	attributes = append(attributes, managed...)
+	if _, ok := accountControlConfig(d); ok {
+		attributes = append(attributes, accountControlAttribute)
+	}
+	if isADAccount(d) {
+		for _, attribute := range adTimeAttributes {
+			attributes = append(attributes, attribute.Attribute)
+		}
+	}
+	if _, ok := proxyAddressesConfig(d); ok {
+		attributes = append(attributes, proxyAddressesAttribute)
+	}
+	if _, ok := shadowAccountConfig(d); ok {
+		for _, attribute := range shadowAttributes {
+			attributes = append(attributes, attribute.Attribute)
+		}
+	}
+	// sorted, so that reads of objects managing the same attributes are
+	// batched together
+	sort.Strings(attributes[tracked:])
+	return attributes
+}
+
+// customizeDiffManagedAttributes makes sure that only managed attributes are
+// set when managed_attributes is.
+func customizeDiffManagedAttributes(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("managed_attributes") {
+		return nil
+	}
+	for _, key := range attributeKeys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	managed := d.Get("managed_attributes").(*schema.Set)
+	if managed.Len() == 0 {
+		return nil
+	}
+	for name := range configuredAttributes(d) {
+		found := false
+		for _, m := range managed.List() {
+			if strings.EqualFold(name, m.(string)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("attribute %q is set but is not listed in managed_attributes", name)
+		}
+	}
+	return nil
+}
+
+func resourceLDAPObjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(readLDAPObjectImpl(withSensitiveValuesMasked(withLogging(ctx, meta), d), d, meta, true))
+}
+
+func resourceLDAPObjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withSensitiveValuesMasked(withLogging(ctx, meta), d)
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
 
-	log.Printf("[DEBUG] ldap_object::update - performing update on %q", d.Id())
+	tflog.SubsystemDebug(ctx, subsystemObject, "updating object", map[string]interface{}{"id": d.Id()})
 
 	// Perform validation for attribute value.
 	if err := validateAttributes(d, providerConfig.InvalidAttributeValues); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	request := ldap.NewModifyRequest(d.Id(), []ldap.Control{})
+	controls, err := assertionControls(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	// handle objectClasses
-	if d.HasChange("object_classes") {
-		classes := []string{}
-		for _, oc := range (d.Get("object_classes").(*schema.Set)).List() {
-			classes = append(classes, oc.(string))
+	if d.HasChange("dn") {
+		o, n := d.GetChange("dn")
+		if err := renameLDAPEntry(ctx, meta, o.(string), n.(string), controls); err != nil {
+			tflog.SubsystemError(ctx, subsystemObject, "error renaming object", map[string]interface{}{
+				"dn":    o,
+				"error": err.Error(),
+			})
+			return diag.FromErr(assertionError(o.(string), err))
 		}
-		log.Printf("[DEBUG] ldap_object::update - updating classes of %q, new value: %v", d.Id(), classes)
-		request.Changes = []ldap.Change{
-			{
-				Operation: ldap.ReplaceAttribute,
-				Modification: ldap.PartialAttribute{
-					Type: "objectClass",
-					Vals: classes,
-				},
-			},
+		if controls, err = renamedAssertionControls(ctx, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
+	requested, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request := ldap.NewModifyRequest(d.Get("dn").(string), append(controls, requested...))
 
-	if d.HasChange("attributes") {
+	// handle objectClasses: auxiliary classes are added and removed in place
+	// (see customizeDiffObjectClasses)
+	if d.HasChange("object_classes") {
+		tflog.SubsystemDebug(ctx, subsystemObject, "updating object classes", map[string]interface{}{
+			"id":             d.Id(),
+			"object_classes": convertToStringSlice(d.Get("object_classes").(*schema.Set).List()),
+		})
+		if isPartialObject(d) {
+			// the classes of a partial object are only ever added
+			if err := addMissingObjectClasses(request, meta, convertToStringSlice(d.Get("object_classes").(*schema.Set).List())); err != nil {
+				return diag.FromErr(err)
+			}
+		} else if err := updateLDAPAttributeSet(request, d, meta, "object_classes", "objectClass"); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-		o, n := d.GetChange("attributes")
-		log.Printf("[DEBUG] ldap_object::update - \n%s", printAttributes("old attributes map", o))
-		log.Printf("[DEBUG] ldap_object::update - \n%s", printAttributes("new attributes map", n))
+	if d.HasChanges(attributeKeys...) || (d.HasChanges("mode", "exclusive_attributes") && !exclusiveAttributes(d)) {
 
-		added, changed, removed := computeDeltas(o.(*schema.Set), n.(*schema.Set))
+		o, n := attributesChange(d)
+		var operation uint = ldap.AddAttribute
+		if !exclusiveAttributes(d) {
+			// the attributes newly configured on an object which is not
+			// exclusive may already have values, which they replace
+			o, n = configuredAttributesChange(d)
+			operation = ldap.ReplaceAttribute
+		}
+		added, changed, removed := computeDeltas(ctx, o, n)
 		if len(added) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes added", len(added))
 			for _, attr := range added {
 				request.Changes = append(request.Changes, ldap.Change{
-					Operation:    ldap.AddAttribute,
+					Operation:    operation,
 					Modification: attr,
 				})
 			}
 		}
 		if len(changed) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes changed", len(changed))
 			for _, attr := range changed {
 				request.Changes = append(request.Changes, ldap.Change{
 					Operation:    ldap.ReplaceAttribute,
@@ -273,7 +438,6 @@ func resourceLDAPObjectUpdate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 		if len(removed) > 0 {
-			log.Printf("[DEBUG] ldap_object::update - %d attributes removed", len(removed))
 			for _, attr := range removed {
 				request.Changes = append(request.Changes, ldap.Change{
 					Operation:    ldap.DeleteAttribute,
@@ -284,6 +448,27 @@ func resourceLDAPObjectUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	}
 
+	// send the password again when its version changes
+	if d.HasChange("password_version") && d.Get("password_version").(int) > 0 {
+		password, ok := passwordWriteOnly(d)
+		if !ok {
+			return diag.Errorf("password_version of %q changed but password_wo is not set", d.Id())
+		}
+		request.Replace(passwordAttribute, []string{password})
+		request.Controls = withPasswordPolicyControl(request.Controls)
+	}
+
+	if err := modifyAccountControl(request, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := modifyADTimes(request, d); err != nil {
+		return diag.FromErr(err)
+	}
+	modifyProxyAddresses(request, d)
+	if err := modifyShadowAccount(request, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Log the LDAP request modifications before sending the request
 	for _, change := range request.Changes {
 		operation := "" // will hold the LDAP operation as a string
@@ -295,114 +480,175 @@ func resourceLDAPObjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		case ldap.ReplaceAttribute:
 			operation = "Replace"
 		}
-		log.Printf("[DEBUG] ModifyRequest Change - Operation: %s, Type: %s, Values: %v",
-			operation, change.Modification.Type, change.Modification.Vals)
+		tflog.SubsystemDebug(ctx, subsystemObject, "modify request change", map[string]interface{}{
+			"dn":        request.DN,
+			"operation": operation,
+			"attribute": change.Modification.Type,
+			"values":    logValues(change.Modification.Type, change.Modification.Vals),
+		})
 	}
 
-	err := client.Modify(request)
+	if len(request.Changes) == 0 {
+		return resourceLDAPObjectRead(ctx, d, meta)
+	}
+	entry, err := modifyWithPostRead(ctx, meta, request, ldapObjectAttributesToRead(d, meta))
 	if err != nil {
-		log.Printf("[ERROR] ldap_object::update - error modifying LDAP object %q with values %v", d.Id(), err)
-		return err
+		tflog.SubsystemError(ctx, subsystemObject, "error modifying object", map[string]interface{}{
+			"id":    d.Id(),
+			"error": err.Error(),
+		})
+		return diag.FromErr(passwordPolicyError(assertionError(d.Id(), err)))
+	}
+	if entry != nil {
+		tflog.SubsystemDebug(ctx, subsystemObject, "populating the state from the Post-Read control", map[string]interface{}{"id": d.Id()})
+		setTrackedEntryID(ctx, d, meta, entry)
+		return diag.FromErr(setLDAPObjectState(ctx, d, meta, d.Get("dn").(string), entry))
 	}
-	return resourceLDAPObjectRead(d, meta)
+	return resourceLDAPObjectRead(ctx, d, meta)
 }
 
-func resourceLDAPObjectDelete(d *schema.ResourceData, meta interface{}) error {
-	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
+func resourceLDAPObjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_object::delete - removing %q", dn)
+	tflog.SubsystemDebug(ctx, subsystemObject, "removing object", map[string]interface{}{"dn": dn})
 
-	if err := deleteLDAPEntry(client, dn, "ldap_object::delete"); err != nil {
-		return err
+	if isPartialObject(d) {
+		if err := deletePartialLDAPObject(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.SubsystemDebug(ctx, subsystemObject, "attributes removed from the object", map[string]interface{}{"dn": dn})
+		return nil
+	}
+
+	if err := destroyLDAPEntry(ctx, d, meta, "ldap_object::delete"); err != nil {
+		return diag.FromErr(err)
 	}
-	log.Printf("[DEBUG] ldap_object::delete - %q removed", dn)
+	tflog.SubsystemDebug(ctx, subsystemObject, "object removed", map[string]interface{}{"dn": dn})
 	return nil
 }
 
-func readLDAPObjectImpl(d *schema.ResourceData, meta interface{}, updateState bool) error {
-	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.Connection
+func readLDAPObjectImpl(ctx context.Context, d *schema.ResourceData, meta interface{}, updateState bool) error {
 	dn := d.Get("dn").(string)
 
-	log.Printf("[DEBUG] ldap_object::read - looking for object %q", dn)
-
-	// when searching by DN, you don't need t specify the base DN a search
-	// filter a "subtree" scope: just put the DN (i.e. the primary key) as the
-	// base DN with a "base object" scope, and the returned object will be the
-	// entry, if it exists
-	request := ldap.NewSearchRequest(
-		dn,
-		ldap.ScopeBaseObject,
-		ldap.NeverDerefAliases,
-		0,
-		0,
-		false,
-		"(objectclass=*)",
-		[]string{"*"},
-		nil,
-	)
-
-	sr, err := client.Search(request)
+	tflog.SubsystemDebug(ctx, subsystemObject, "looking for object", map[string]interface{}{"dn": dn})
+
+	entry, err := readTrackedEntry(ctx, d, meta, ldapObjectAttributesToRead(d, meta))
 	if err != nil {
-		if err, ok := err.(*ldap.Error); ok {
-			if err.ResultCode == 32 && updateState { // no such object
-				log.Printf("[WARN] ldap_object::read - object not found, removing %q from state because it no longer exists in LDAP", dn)
-				d.SetId("")
-				return nil
-			}
-		}
-		log.Printf("[DEBUG] ldap_object::read - lookup for %q returned an error %v", dn, err)
+		tflog.SubsystemDebug(ctx, subsystemObject, "object lookup returned an error", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
 		return err
 	}
+	if entry == nil {
+		if updateState {
+			tflog.SubsystemWarn(ctx, subsystemObject, "object not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("object %q does not exist", dn)
+	}
 
-	log.Printf("[DEBUG] ldap_object::read - query for %q returned %v", dn, sr)
+	setTrackedEntryID(ctx, d, meta, entry)
+	return setLDAPObjectState(ctx, d, meta, d.Get("dn").(string), entry)
+}
 
-	d.SetId(dn)
-	d.Set("object_classes", sr.Entries[0].GetAttributeValues("objectClass"))
+// setLDAPObjectState populates the state of an ldap_object from its entry.
+func setLDAPObjectState(ctx context.Context, d *schema.ResourceData, meta interface{}, dn string, entry *ldap.Entry) error {
+	matching := matchingOf(meta)
+	if isPartialObject(d) {
+		d.Set("object_classes", partialObjectClasses(convertToStringSlice(d.Get("object_classes").(*schema.Set).List()), entry.GetAttributeValues("objectClass")))
+	} else {
+		d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	}
+	d.Set("entry_csn", entry.GetAttributeValue("entryCSN"))
 
 	// now deal with attributes
 	set := &schema.Set{
 		F: attributeHash,
 	}
+	spellings := matching.stateSpellings(d)
 
-	for _, attribute := range sr.Entries[0].Attributes {
-		log.Printf("[DEBUG] ldap_object::read - treating attribute %q of %q (%d values: %v)", attribute.Name, dn, len(attribute.Values), attribute.Values)
-		if attribute.Name == "objectClass" {
-			// skip: we don't treat object classes as ordinary attributes
-			log.Printf("[DEBUG] ldap_object::read - skipping attribute %q of %q", attribute.Name, dn)
+	for _, attribute := range entry.Attributes {
+		if attribute.Name == "objectClass" || isLDAPObjectOperationalAttribute(attribute.Name) ||
+			isServerManagedAttribute(meta, attribute.Name) || isComputedAttribute(meta, attribute.Name) {
+			// skip: we don't treat object classes (nor the operational,
+			// server-managed and computed attributes) as ordinary attributes
+			continue
+		}
+		if managesPassword(d, attribute.Name) {
+			// the password is write-only and must not end up in the state
+			continue
+		}
+		if managesAccountControl(d, attribute.Name) || managesADTime(d, attribute.Name) || managesProxyAddresses(d, attribute.Name) || managesShadowAccount(d, attribute.Name) {
+			// read into their typed fields below
 			continue
 		}
 		if len(attribute.Values) == 1 {
 			// we don't treat the RDN as an ordinary attribute
 			a := fmt.Sprintf("%s=%s", attribute.Name, attribute.Values[0])
 			if strings.HasPrefix(dn, a) {
-				log.Printf("[DEBUG] ldap_object::read - skipping RDN %q of %q", a, dn)
+				tflog.SubsystemTrace(ctx, subsystemObject, "skipping RDN attribute", map[string]interface{}{
+					"dn":  dn,
+					"rdn": a,
+				})
 				continue
 			}
 		}
-		log.Printf("[DEBUG] ldap_object::read - adding attribute %q to %q (%d values)", attribute.Name, dn, len(attribute.Values))
+		tflog.SubsystemTrace(ctx, subsystemObject, "reading attribute", map[string]interface{}{
+			"dn":        dn,
+			"attribute": attribute.Name,
+			"values":    logValues(attribute.Name, attribute.Values),
+		})
 		// now add each value as an individual entry into the object, because
 		// we do not handle name => []values, and we have a set of maps each
 		// holding a single entry name => value; multiple maps may share the
 		// same key.
 		for _, value := range attribute.Values {
-			log.Printf("[DEBUG] ldap_object::read - for %q, setting %q => %q", dn, attribute.Name, value)
 			set.Add(map[string]interface{}{
-				attribute.Name: value,
+				attribute.Name: matching.spelling(spellings, attribute.Name, value),
 			})
 		}
 	}
 
-	if err := d.Set("attributes", set); err != nil {
-		log.Printf("[WARN] ldap_object::read - error setting LDAP attributes for %q : %v", dn, err)
+	if err := setAttributes(d, set); err != nil {
+		return fmt.Errorf("error setting LDAP attributes for %q: %w", dn, err)
+	}
+	if err := d.Set("all_attributes", allAttributes(d, entry)); err != nil {
+		return fmt.Errorf("error setting all_attributes for %q: %w", dn, err)
+	}
+	if err := readEntryMetadata(d, entry); err != nil {
 		return err
 	}
-	return nil
+	if err := readAccountControl(d, entry); err != nil {
+		return err
+	}
+	if err := readADTimes(d, entry); err != nil {
+		return err
+	}
+	if err := readProxyAddresses(d, entry); err != nil {
+		return err
+	}
+	return readShadowAccount(d, entry)
+}
+
+// allAttributes returns the attributes of the entry of an ldap_object for
+// all_attributes, without the password nor the sensitive attributes.
+func allAttributes(d *schema.ResourceData, entry *ldap.Entry) map[string]interface{} {
+	sensitive := sensitiveAttributeNames(d)
+	attributes := make(map[string]interface{}, len(entry.Attributes))
+	for _, attribute := range entry.Attributes {
+		if managesPassword(d, attribute.Name) || sensitive[strings.ToLower(attribute.Name)] {
+			continue
+		}
+		attributes[attribute.Name] = strings.Join(sortValues(attribute.Values), ",")
+	}
+	return attributes
 }
 
-// computes the hash of the map representing an attribute in the attributes set
+// computes the hash of the map representing an attribute in the attributes
+// set; values the server considers equal (see standardMatching) hash the same
 func attributeHash(v interface{}) int {
 	m, ok := v.(map[string]interface{})
 
@@ -414,7 +660,7 @@ func attributeHash(v interface{}) int {
 	var buffer bytes.Buffer
 	buffer.WriteString("map {")
 	for k, v := range m {
-		buffer.WriteString(fmt.Sprintf("%q := %q;", k, v.(string)))
+		buffer.WriteString(fmt.Sprintf("%q := %q;", strings.ToLower(k), standardMatching.normalizeValue(k, v.(string))))
 	}
 	buffer.WriteRune('}')
 	text := buffer.String()
@@ -422,21 +668,7 @@ func attributeHash(v interface{}) int {
 	return hash
 }
 
-func printAttributes(prefix string, attributes interface{}) string {
-	var buffer bytes.Buffer
-	buffer.WriteString(fmt.Sprintf("%s: {\n", prefix))
-	if attributes, ok := attributes.(*schema.Set); ok {
-		for _, attribute := range attributes.List() {
-			for k, v := range attribute.(map[string]interface{}) {
-				buffer.WriteString(fmt.Sprintf("    %q: %q\n", k, v.(string)))
-			}
-		}
-		buffer.WriteRune('}')
-	}
-	return buffer.String()
-}
-
-func computeDeltas(os, ns *schema.Set) (added, changed, removed []ldap.PartialAttribute) {
+func computeDeltas(ctx context.Context, os, ns *schema.Set) (added, changed, removed []ldap.PartialAttribute) {
 
 	rk := set.New() // names of removed attributes
 	for _, v := range os.Difference(ns).List() {
@@ -468,7 +700,7 @@ func computeDeltas(os, ns *schema.Set) (added, changed, removed []ldap.PartialAt
 			// been added back, and there is no further value under the same
 			// name among those that were untouched; this means that it has
 			// been dropped and must go among the RemovedAttributes
-			log.Printf("[DEBUG] ldap_object::deltas - dropping attribute %q", k)
+			tflog.SubsystemDebug(ctx, subsystemObject, "dropping attribute", map[string]interface{}{"attribute": k})
 			removed = append(removed, ldap.PartialAttribute{
 				Type: k,
 				Vals: []string{},
@@ -496,7 +728,10 @@ func computeDeltas(os, ns *schema.Set) (added, changed, removed []ldap.PartialAt
 				Type: k,
 				Vals: values,
 			})
-			log.Printf("[DEBUG] ldap_object::deltas - adding new attribute %q with values %v", k, values)
+			tflog.SubsystemDebug(ctx, subsystemObject, "adding new attribute", map[string]interface{}{
+				"attribute": k,
+				"values":    logValues(k, values),
+			})
 		} else {
 			ck.Add(k)
 		}
@@ -520,7 +755,10 @@ func computeDeltas(os, ns *schema.Set) (added, changed, removed []ldap.PartialAt
 			Type: k,
 			Vals: values,
 		})
-		log.Printf("[DEBUG] ldap_object::deltas - changing attribute %q with values %v", k, values)
+		tflog.SubsystemDebug(ctx, subsystemObject, "changing attribute", map[string]interface{}{
+			"attribute": k,
+			"values":    logValues(k, values),
+		})
 	}
 	return
 }
@@ -3,8 +3,10 @@ package provider
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/elastic-infra/terraform-provider-ldap/internal/helper/client"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -26,6 +28,10 @@ func TestAccLDAPObject_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("ldap_object.jdoe", "object_classes.0", "inetOrgPerson"),
 					resource.TestCheckResourceAttr("ldap_object.jdoe", "object_classes.1", "posixAccount"),
 					testAccCheckLDAPObjectAttributes("ldap_object.jdoe"),
+					resource.TestCheckResourceAttr("ldap_object.jdoe", "all_attributes.structuralObjectClass", "inetOrgPerson"),
+					resource.TestCheckResourceAttrSet("ldap_object.jdoe", "all_attributes.entryUUID"),
+					resource.TestCheckResourceAttrSet("ldap_object.jdoe", "create_timestamp"),
+					resource.TestCheckResourceAttrSet("ldap_object.jdoe", "modify_timestamp"),
 				),
 			},
 		},
@@ -33,7 +39,7 @@ func TestAccLDAPObject_Basic(t *testing.T) {
 }
 
 func testAccCheckLDAPObjectDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*ldap.Conn)
+	conn := testAccProvider.Meta().(*ProviderConfig).Connection
 	for _, r := range s.RootModule().Resources {
 		dn := r.Primary.Attributes["dn"]
 		_, err := helperSearchRequest(dn, conn)
@@ -53,7 +59,7 @@ func testAccCheckLDAPObjectDestroy(s *terraform.State) error {
 
 func testAccCheckLDAPObjectExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		conn := testAccProvider.Meta().(*ldap.Conn)
+		conn := testAccProvider.Meta().(*ProviderConfig).Connection
 		for _, r := range s.RootModule().Resources {
 			dn := r.Primary.Attributes["dn"]
 			sr, err := helperSearchRequest(dn, conn)
@@ -81,7 +87,7 @@ func testAccCheckLDAPObjectAttributes(n string) resource.TestCheckFunc {
 	}
 }
 
-func helperSearchRequest(dn string, conn *ldap.Conn) (*ldap.SearchResult, error) {
+func helperSearchRequest(dn string, conn client.Client) (*ldap.SearchResult, error) {
 
 	// search by primary key (that is, set the DN as base DN and use a "base
 	// object" scope); no attributes are retrieved since we are onòy checking
@@ -128,3 +134,769 @@ resource "ldap_object" "jdoe" {
 	]
 }
 `
+
+func TestAccLDAPObject_assertion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigAssertion("first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("ldap_object.guarded", "entry_csn"),
+				),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigAssertion("second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.guarded", "attributes.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigAssertion(description string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "guarded" {
+  dn               = "ou=guarded,dc=example,dc=com"
+  object_classes   = ["organizationalUnit"]
+  attributes       = [{ description = %q }]
+  assertion_filter = "(objectClass=organizationalUnit)"
+  assert_unchanged = true
+}
+`, description)
+}
+
+func TestAccLDAPObject_renameAssertion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigRenameAssertion("guarded", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("ldap_object.guarded", "entry_csn"),
+				),
+			},
+			{
+				// the guarded rename changes the entryCSN, which the update
+				// that follows does not assert any longer
+				Config: testAccCheckLDAPObjectConfigRenameAssertion("renamed", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.guarded", "dn", "ou=renamed,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_object.guarded", "attributes.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigRenameAssertion(name, description string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "guarded" {
+  rdn_attribute    = "ou"
+  rdn_value        = %q
+  parent_dn        = "dc=example,dc=com"
+  object_classes   = ["organizationalUnit"]
+  attributes       = [{ description = %q }]
+  assertion_filter = "(objectClass=organizationalUnit)"
+  assert_unchanged = true
+}
+`, name, description)
+}
+
+func TestAccLDAPObject_managedAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigManagedAttributes,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.shared", "attributes.#", "1"),
+				),
+			},
+			{
+				// an attribute set by another system is not seen as drift
+				PreConfig: func() {
+					conn := testAccProvider.Meta().(*ProviderConfig).Connection
+					request := ldap.NewModifyRequest("ou=shared,dc=example,dc=com", nil)
+					request.Replace("telephoneNumber", []string{"+1 555 0100"})
+					if err := conn.Modify(request); err != nil {
+						t.Fatalf("error setting telephoneNumber: %v", err)
+					}
+				},
+				Config:   testAccCheckLDAPObjectConfigManagedAttributes,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccCheckLDAPObjectConfigManagedAttributes = `
+resource "ldap_object" "shared" {
+  dn                 = "ou=shared,dc=example,dc=com"
+  object_classes     = ["organizationalUnit"]
+  attributes         = [{ description = "managed by Terraform" }]
+  managed_attributes = ["description"]
+}
+`
+
+func TestAccLDAPObject_exclusiveAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigExclusiveAttributes,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.additive", "exclusive_attributes", "false"),
+					resource.TestCheckResourceAttr("ldap_object.additive", "attributes.#", "1"),
+				),
+			},
+			{
+				// an attribute added by another system is left untouched
+				PreConfig: func() {
+					conn := testAccProvider.Meta().(*ProviderConfig).Connection
+					request := ldap.NewModifyRequest("ou=additive,dc=example,dc=com", nil)
+					request.Replace("telephoneNumber", []string{"+1 555 0100"})
+					if err := conn.Modify(request); err != nil {
+						t.Fatalf("error setting telephoneNumber: %v", err)
+					}
+				},
+				Config:   testAccCheckLDAPObjectConfigExclusiveAttributes,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccCheckLDAPObjectConfigExclusiveAttributes = `
+resource "ldap_object" "additive" {
+  dn                   = "ou=additive,dc=example,dc=com"
+  object_classes       = ["organizationalUnit"]
+  attributes           = [{ description = "managed by Terraform" }]
+  exclusive_attributes = false
+}
+`
+
+func TestAccLDAPObject_partial(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigPartial(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.partial", "attributes.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("ldap_object.partial", "attributes.*", map[string]string{"description": "managed by Terraform"}),
+				),
+			},
+			{
+				// destroying the partial object only removes its attributes
+				Config: testAccCheckLDAPObjectConfigPartial(false),
+				Check: func(*terraform.State) error {
+					conn := testAccProvider.Meta().(*ProviderConfig).Connection
+					entry, err := searchEntry(conn, "ou=hr,dc=example,dc=com", []string{"*"}, ldap.NeverDerefAliases)
+					if err != nil {
+						return err
+					}
+					if entry == nil {
+						return fmt.Errorf("ou=hr,dc=example,dc=com was removed")
+					}
+					if entry.GetAttributeValue("description") != "" {
+						return fmt.Errorf("description was not removed")
+					}
+					if entry.GetAttributeValue("telephoneNumber") == "" {
+						return fmt.Errorf("telephoneNumber was removed")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigPartial(partial bool) string {
+	config := `
+resource "ldap_object" "owner" {
+  dn             = "ou=hr,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+  attributes     = [{ telephoneNumber = "+1 555 0100" }]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+`
+	if partial {
+		config += `
+resource "ldap_object" "partial" {
+  dn         = ldap_object.owner.dn
+  mode       = "partial"
+  attributes = [{ description = "managed by Terraform" }]
+}
+`
+	}
+	return config
+}
+
+func TestAccLDAPObject_onDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigOnDestroyKeeper + testAccCheckLDAPObjectConfigOnDestroy,
+				Check:  resource.TestCheckResourceAttr("ldap_object.leaver", "on_destroy.0.action", "disable"),
+			},
+			{
+				// the entry is disabled rather than removed
+				Config: testAccCheckLDAPObjectConfigOnDestroyKeeper,
+				Check: func(*terraform.State) error {
+					conn := testAccProvider.Meta().(*ProviderConfig).Connection
+					dn := "uid=leaver,dc=example,dc=com"
+					entry, err := searchEntry(conn, dn, []string{"description"}, ldap.NeverDerefAliases)
+					if err != nil {
+						return err
+					}
+					if entry == nil {
+						return fmt.Errorf("%s was removed", dn)
+					}
+					if description := entry.GetAttributeValue("description"); description != "disabled" {
+						return fmt.Errorf("description of %s is %q, expected disabled", dn, description)
+					}
+					return conn.Del(ldap.NewDelRequest(dn, nil))
+				},
+			},
+		},
+	})
+}
+
+const testAccCheckLDAPObjectConfigOnDestroyKeeper = `
+resource "ldap_object" "keeper" {
+  dn             = "ou=keeper,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+`
+
+const testAccCheckLDAPObjectConfigOnDestroy = `
+resource "ldap_object" "leaver" {
+  dn             = "uid=leaver,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "Leaver" },
+    { cn = "Leaver" },
+  ]
+
+  on_destroy {
+    action    = "disable"
+    attribute = "description"
+    value     = "disabled"
+  }
+}
+`
+
+func TestAccLDAPObject_membershipCleanup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigMembershipCleanup(true),
+			},
+			{
+				// destroying the member removes it from the group
+				Config: testAccCheckLDAPObjectConfigMembershipCleanup(false),
+				Check: func(*terraform.State) error {
+					conn := testAccProvider.Meta().(*ProviderConfig).Connection
+					entry, err := searchEntry(conn, "cn=cleanup,dc=example,dc=com", []string{"member"}, ldap.NeverDerefAliases)
+					if err != nil {
+						return err
+					}
+					if members := entry.GetAttributeValues("member"); len(members) != 1 || members[0] != "cn=admin,dc=example,dc=com" {
+						return fmt.Errorf("members of cn=cleanup,dc=example,dc=com are %q, expected cn=admin,dc=example,dc=com", members)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigMembershipCleanup(member bool) string {
+	config := `
+resource "ldap_object" "group" {
+  dn             = "cn=cleanup,dc=example,dc=com"
+  object_classes = ["groupOfNames"]
+  attributes = [
+    { member = "cn=admin,dc=example,dc=com" },
+    { member = "uid=cleanup,dc=example,dc=com" },
+  ]
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+`
+	if member {
+		config += `
+resource "ldap_object" "member" {
+  dn             = "uid=cleanup,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes = [
+    { sn = "Cleanup" },
+    { cn = "Cleanup" },
+  ]
+
+  membership_cleanup {
+    base_dn = "dc=example,dc=com"
+  }
+}
+`
+	}
+	return config
+}
+
+func TestAccLDAPObject_createParents(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigCreateParents,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLDAPObjectExists("ldap_object.nested"),
+					func(*terraform.State) error {
+						conn := testAccProvider.Meta().(*ProviderConfig).Connection
+						entry, err := searchEntry(conn, "ou=nested,dc=example,dc=com", []string{"objectClass"}, ldap.NeverDerefAliases)
+						if err != nil {
+							return err
+						}
+						if entry == nil {
+							return fmt.Errorf("ou=nested,dc=example,dc=com was not created")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckLDAPObjectConfigCreateParents = `
+resource "ldap_object" "nested" {
+  dn             = "ou=leaf,ou=deep,ou=nested,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+  create_parents = true
+}
+`
+
+func TestAccLDAPObject_controls(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				// unsupported controls are ignored unless they are critical
+				Config: testAccCheckLDAPObjectConfigControls("staff", false),
+				Check:  testAccCheckLDAPObjectExists("ldap_object.controls"),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigControls("managers", false),
+				Check:  resource.TestCheckResourceAttr("ldap_object.controls", "all_attributes.description", "managers"),
+			},
+			{
+				Config:      testAccCheckLDAPObjectConfigControls("managers", true),
+				ExpectError: regexp.MustCompile("unsupported critical control"),
+			},
+		},
+	})
+}
+
+// testAccCheckLDAPObjectConfigControls adds an object with an unsupported
+// critical control when rejected is set, whose creation fails.
+func testAccCheckLDAPObjectConfigControls(description string, rejected bool) string {
+	config := fmt.Sprintf(`
+resource "ldap_object" "controls" {
+  dn             = "ou=controls,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+  attributes     = [{ description = %q }]
+
+  controls {
+    oid = "1.2.840.113556.1.4.619"
+  }
+}
+`, description)
+	if rejected {
+		config += `
+resource "ldap_object" "rejected" {
+  dn             = "ou=rejected,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+
+  controls {
+    oid         = "1.2.3.4"
+    criticality = true
+  }
+}
+`
+	}
+	return config
+}
+
+func TestAccLDAPObject_sensitiveAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigSensitiveAttributes("secret1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.service", "attributes.#", "1"),
+					resource.TestCheckResourceAttr("ldap_object.service", "sensitive_attributes.#", "1"),
+					resource.TestCheckResourceAttr("ldap_object.service", "all_attributes.description", "service account"),
+					resource.TestCheckNoResourceAttr("ldap_object.service", "all_attributes.userPassword"),
+				),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigSensitiveAttributes("secret2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.service", "sensitive_attributes.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigSensitiveAttributes(password string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "service" {
+  dn                   = "cn=service,dc=example,dc=com"
+  object_classes       = ["simpleSecurityObject", "organizationalRole"]
+  attributes           = [{ description = "service account" }]
+  sensitive_attributes = [{ userPassword = %q }]
+}
+`, password)
+}
+
+func TestAccLDAPObject_writeOnlyPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigWriteOnlyPassword("secret1", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_object.robot", "password_wo"),
+					resource.TestCheckResourceAttr("ldap_object.robot", "password_version", "1"),
+					resource.TestCheckResourceAttr("ldap_object.robot", "attributes.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigWriteOnlyPassword("secret2", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_object.robot", "password_wo"),
+					resource.TestCheckResourceAttr("ldap_object.robot", "password_version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigWriteOnlyPassword(password string, version int) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "robot" {
+  dn               = "cn=robot,dc=example,dc=com"
+  object_classes   = ["simpleSecurityObject", "organizationalRole"]
+  attributes       = [{ description = "robot account" }]
+  password_wo      = %q
+  password_version = %d
+}
+`, password, version)
+}
+
+func TestAccLDAPObject_planVerification(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigPlanVerification(`{ displayName = "John Doe" }`),
+				Check:  resource.TestCheckResourceAttr("ldap_object.verified", "all_attributes.displayName", "John Doe"),
+			},
+			{
+				// displayName is single-valued
+				Config:      testAccCheckLDAPObjectConfigPlanVerification(`{ displayName = "John Doe" }, { displayName = "J. Doe" }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("the server would reject the planned changes"),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigPlanVerification(`{ displayName = "J. Doe" }`),
+				Check:  resource.TestCheckResourceAttr("ldap_object.verified", "all_attributes.displayName", "J. Doe"),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigPlanVerification(displayNames string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  plan_verification = "error"
+}
+
+resource "ldap_object" "verified" {
+  dn             = "uid=verified,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes     = [{ cn = "John Doe" }, { sn = "Doe" }, %s]
+}
+`, displayNames)
+}
+
+func TestAccLDAPObject_entryUUID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigEntryUUID("tracked"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("ldap_object.tracked", "id", regexp.MustCompile(`^[^=]+$`)),
+				),
+			},
+			{
+				// the entry is renamed in place rather than replaced
+				Config: testAccCheckLDAPObjectConfigEntryUUID("renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.tracked", "dn", "ou=renamed,dc=example,dc=com"),
+					resource.TestMatchResourceAttr("ldap_object.tracked", "id", regexp.MustCompile(`^[^=]+$`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigEntryUUID(name string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  use_entry_uuid = true
+}
+
+resource "ldap_object" "tracked" {
+  dn             = "ou=%s,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+`, name)
+}
+
+func TestAccLDAPObject_rdn(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigRDN("Sales, EMEA"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.unit", "dn", `ou=Sales\, EMEA,dc=example,dc=com`),
+					resource.TestCheckResourceAttr("ldap_object.unit", "rdn_value", "Sales, EMEA"),
+				),
+			},
+			{
+				// the entry is renamed in place rather than replaced
+				Config: testAccCheckLDAPObjectConfigRDN("Sales, APAC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.unit", "dn", `ou=Sales\, APAC,dc=example,dc=com`),
+					resource.TestCheckResourceAttr("ldap_object.unit", "id", `ou=Sales\, APAC,dc=example,dc=com`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigRDN(name string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "unit" {
+  rdn_attribute  = "ou"
+  rdn_value      = %q
+  parent_dn      = "dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+`, name)
+}
+
+func TestAccLDAPObject_attributesJSON(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigAttributesJSON(`["jdoe@example.com", "john.doe@example.com"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.jdoe", "attributes.#", "0"),
+					resource.TestCheckResourceAttr("ldap_object.jdoe", "attributes_json",
+						`{"mail":["jdoe@example.com","john.doe@example.com"],"sn":["Doe"]}`),
+				),
+			},
+			{
+				Config: testAccCheckLDAPObjectConfigAttributesJSON(`["jdoe@example.com"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.jdoe", "attributes_json",
+						`{"mail":["jdoe@example.com"],"sn":["Doe"]}`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigAttributesJSON(mail string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "jdoe" {
+  dn             = "cn=jdoe,dc=example,dc=com"
+  object_classes = ["inetOrgPerson"]
+  attributes_json = jsonencode({
+    sn   = ["Doe"]
+    mail = %s
+  })
+}
+`, mail)
+}
+
+func TestAccLDAPObject_objectClasses(t *testing.T) {
+	var entryUUID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigObjectClasses(`["person"]`),
+				Check:  testAccCheckLDAPObjectEntryUUID("ldap_object.jdoe", &entryUUID, false),
+			},
+			{
+				// auxiliary classes are added in place
+				Config: testAccCheckLDAPObjectConfigObjectClasses(`["person", "extensibleObject"]`),
+				Check:  testAccCheckLDAPObjectEntryUUID("ldap_object.jdoe", &entryUUID, false),
+			},
+			{
+				// and removed in place
+				Config: testAccCheckLDAPObjectConfigObjectClasses(`["person"]`),
+				Check:  testAccCheckLDAPObjectEntryUUID("ldap_object.jdoe", &entryUUID, false),
+			},
+			{
+				// changing the structural class replaces the entry
+				Config: testAccCheckLDAPObjectConfigObjectClasses(`["inetOrgPerson"]`),
+				Check:  testAccCheckLDAPObjectEntryUUID("ldap_object.jdoe", &entryUUID, true),
+			},
+		},
+	})
+}
+
+// testAccCheckLDAPObjectEntryUUID checks whether the entryUUID of an entry
+// changed since the previous check, and records it.
+func testAccCheckLDAPObjectEntryUUID(n string, entryUUID *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		r, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("%s not found", n)
+		}
+		current := r.Primary.Attributes["all_attributes.entryUUID"]
+		if *entryUUID != "" && (current != *entryUUID) != changed {
+			return fmt.Errorf("entryUUID of %s: %q, previously %q", n, current, *entryUUID)
+		}
+		*entryUUID = current
+		return nil
+	}
+}
+
+func testAccCheckLDAPObjectConfigObjectClasses(classes string) string {
+	return fmt.Sprintf(`
+resource "ldap_object" "jdoe" {
+  dn             = "cn=jdoe,dc=example,dc=com"
+  object_classes = %s
+  attributes = [
+    { sn = "Doe" },
+  ]
+}
+`, classes)
+}
+
+func TestAccLDAPObject_defaultObjectClasses(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigDefaultObjectClasses,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.jdoe", "object_classes.#", "2"),
+					resource.TestCheckTypeSetElemAttr("ldap_object.jdoe", "object_classes.*", "inetOrgPerson"),
+					resource.TestCheckTypeSetElemAttr("ldap_object.jdoe", "object_classes.*", "extensibleObject"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckLDAPObjectConfigDefaultObjectClasses = `
+provider "ldap" {
+  default_user_object_classes = ["inetOrgPerson", "extensibleObject"]
+}
+
+resource "ldap_object" "jdoe" {
+  dn = "cn=jdoe,dc=example,dc=com"
+  attributes = [
+    { sn = "Doe" },
+  ]
+}
+`
+
+func TestAccLDAPObject_relativeDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLDAPObjectConfigRelativeDN("sales"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.unit", "dn", "ou=sales,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_object.unit", "parent_dn", "dc=example,dc=com"),
+				),
+			},
+			{
+				// the entry is renamed in place rather than replaced
+				Config: testAccCheckLDAPObjectConfigRelativeDN("marketing"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_object.unit", "dn", "ou=marketing,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_object.unit", "id", "ou=marketing,dc=example,dc=com"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPObjectConfigRelativeDN(name string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  base_dn = "dc=example,dc=com"
+}
+
+resource "ldap_object" "unit" {
+  relative_dn    = "ou=%s"
+  object_classes = ["organizationalUnit"]
+}
+`, name)
+}
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// olcDatabaseType is the backend of the databases ldap_olc_database manages.
+const olcDatabaseType = "mdb"
+
+// olcDatabaseAttributes maps the typed fields of ldap_olc_database onto the
+// attributes of the olcDatabaseConfig and olcMdbConfig object classes.
+var olcDatabaseAttributes = []typedAttribute{
+	{Field: "suffix", Attribute: "olcSuffix"},
+	{Field: "directory", Attribute: "olcDbDirectory"},
+	{Field: "root_dn", Attribute: "olcRootDN"},
+	{Field: "root_password", Attribute: "olcRootPW"},
+	{Field: "max_size", Attribute: "olcDbMaxSize"},
+	{Field: "indexes", Attribute: "olcDbIndex"},
+	{Field: "access", Attribute: "olcAccess"},
+}
+
+func resourceLDAPOLCDatabase() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPOLCDatabaseCreate),
+		ReadContext:   resourceLDAPOLCDatabaseRead,
+		UpdateContext: withDNLock(resourceLDAPOLCDatabaseUpdate),
+		DeleteContext: withDNLock(resourceLDAPOLCDatabaseDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPOLCDatabaseImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type: schema.TypeInt,
+				Description: "The position of the database among the databases of the server (the {n} of " +
+					"olcDatabase={n}mdb). Default: after the existing databases. Databases placed after it are " +
+					"renumbered by the server.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Description: "The DN of the database entry, as assigned by the server (e.g. olcDatabase={2}mdb,cn=config).",
+				Computed:    true,
+			},
+			"suffix": {
+				Type:             schema.TypeString,
+				Description:      "The naming context served by the database (olcSuffix), e.g. dc=example,dc=com; it identifies the database.",
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"directory": {
+				Type:        schema.TypeString,
+				Description: "The directory holding the files of the database on the server (olcDbDirectory); it must exist.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"root_dn": {
+				Type:             schema.TypeString,
+				Description:      "The DN not subject to access control in the database (olcRootDN).",
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentDN,
+			},
+			"root_password": {
+				Type:        schema.TypeString,
+				Description: "The password of `root_dn`, preferably hashed, e.g. with `slappasswd` (olcRootPW).",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"max_size": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum size of the database in bytes (olcDbMaxSize).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"indexes": {
+				Type:        schema.TypeSet,
+				Description: "The indices of the database (olcDbIndex), e.g. [\"objectClass eq\", \"uid eq,sub\"].",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"access": {
+				Type:        schema.TypeList,
+				Description: "The access control directives of the database, in order and without their {n} index (olcAccess).",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Manages an OpenLDAP mdb database entry under `cn=config` (olcDatabase={n}mdb), so that the " +
+			"databases of a server can be provisioned along with their content.\n\n" +
+			"Like `ldap_olc_global`, this needs a connection allowed to write `cn=config`. The database is " +
+			"identified by its suffix, since the server renumbers databases when others are inserted before it. " +
+			"Deleting a database needs OpenLDAP 2.5 or later.",
+	}
+}
+
+// olcDatabaseIndex returns the {n} index of an olcDatabase value, e.g. 2 for
+// "{2}mdb".
+func olcDatabaseIndex(value string) (int, error) {
+	match := orderedValuePrefix.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("olcDatabase %q has no ordering index", value)
+	}
+	return strconv.Atoi(match[1])
+}
+
+func resourceLDAPOLCDatabaseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	suffix := d.Get("suffix").(string)
+
+	// without an index, slapd appends the database after the existing ones
+	database := olcDatabaseType
+	if v, ok := d.GetOk("index"); ok {
+		database = fmt.Sprintf("{%d}%s", v.(int), olcDatabaseType)
+	}
+	dn := fmt.Sprintf("olcDatabase=%s,%s", database, olcGlobalDN)
+
+	tflog.Debug(ctx, "creating database", map[string]interface{}{
+		"dn":     dn,
+		"suffix": suffix,
+	})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+	request.Attribute("objectClass", []string{"olcDatabaseConfig", "olcMdbConfig"})
+	request.Attribute("olcDatabase", []string{database})
+	addTypedAttributes(request, d, olcDatabaseAttributes)
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating database", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	// slapd renames the entry to olcDatabase={n}mdb: look it up to get the real DN
+	entry, err := findLDAPOLCDatabase(meta, suffix)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		return diag.Errorf("database of %q not found after creation", suffix)
+	}
+
+	tflog.Debug(ctx, "database added to the LDAP server", map[string]interface{}{
+		"suffix": suffix,
+		"dn":     entry.DN,
+	})
+
+	d.SetId(entry.DN)
+	return resourceLDAPOLCDatabaseRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCDatabaseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	suffix := d.Get("suffix").(string)
+
+	tflog.Debug(ctx, "looking for database", map[string]interface{}{
+		"dn":     d.Id(),
+		"suffix": suffix,
+	})
+
+	entry, err := findLDAPOLCDatabase(meta, suffix)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"suffix": suffix,
+			"error":  err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "database not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"suffix": suffix})
+		d.SetId("")
+		return nil
+	}
+
+	index, err := olcDatabaseIndex(entry.GetAttributeValue("olcDatabase"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the DN changes when the server renumbers the databases
+	d.SetId(entry.DN)
+	d.Set("dn", entry.DN)
+	d.Set("index", index)
+	if err := readTypedAttributes(d, entry, olcDatabaseAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+	access := []string{}
+	for _, value := range entry.GetAttributeValues("olcAccess") {
+		access = append(access, stripOrderingIndex(value))
+	}
+	return diag.FromErr(d.Set("access", access))
+}
+
+func resourceLDAPOLCDatabaseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Id()
+
+	tflog.Debug(ctx, "updating database", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	modifyTypedAttributes(request, d, olcDatabaseAttributes)
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating database", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+	return resourceLDAPOLCDatabaseRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCDatabaseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Id()
+
+	tflog.Debug(ctx, "removing database", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_olc_database::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "database removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+// resourceLDAPOLCDatabaseImport accepts either the DN of the database entry
+// or the suffix of the database.
+func resourceLDAPOLCDatabaseImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	suffix := d.Id()
+	if strings.HasPrefix(strings.ToLower(d.Id()), "olcdatabase=") {
+		providerConfig := meta.(*ProviderConfig)
+		entry, err := searchEntry(providerConfig.Connection, d.Id(), []string{"olcSuffix"}, ldap.NeverDerefAliases)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("database %q does not exist", d.Id())
+		}
+		suffix = entry.GetAttributeValue("olcSuffix")
+	}
+	d.Set("suffix", suffix)
+	if err := diagnosticsError(resourceLDAPOLCDatabaseRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("no database of %q found under %q", suffix, olcGlobalDN)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// findLDAPOLCDatabase looks up the mdb database serving the given suffix,
+// regardless of the ordering index slapd assigned to it; it returns nil if
+// not found.
+func findLDAPOLCDatabase(meta interface{}, suffix string) (*ldap.Entry, error) {
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	attributes := []string{"olcDatabase"}
+	for _, attribute := range olcDatabaseAttributes {
+		attributes = append(attributes, attribute.Attribute)
+	}
+	request := ldap.NewSearchRequest(
+		olcGlobalDN,
+		ldap.ScopeSingleLevel,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		fmt.Sprintf("(&(objectClass=olcMdbConfig)(olcSuffix=%s))", ldap.EscapeFilter(suffix)),
+		attributes,
+		nil,
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(sr.Entries) == 0 {
+		return nil, nil
+	}
+	return sr.Entries[0], nil
+}
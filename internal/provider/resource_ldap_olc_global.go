@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// olcGlobalDN is the DN of the OpenLDAP global configuration entry.
+const olcGlobalDN = "cn=config"
+
+// olcGlobalAttributes maps the typed fields of ldap_olc_global onto the
+// attributes of the olcGlobal object class.
+var olcGlobalAttributes = []typedAttribute{
+	{Field: "log_level", Attribute: "olcLogLevel"},
+	{Field: "size_limit", Attribute: "olcSizeLimit"},
+	{Field: "time_limit", Attribute: "olcTimeLimit"},
+	{Field: "idle_timeout", Attribute: "olcIdleTimeout"},
+	{Field: "write_timeout", Attribute: "olcWriteTimeout"},
+	{Field: "threads", Attribute: "olcThreads"},
+	{Field: "password_hash", Attribute: "olcPasswordHash"},
+	{Field: "tls_certificate_file", Attribute: "olcTLSCertificateFile"},
+	{Field: "tls_certificate_key_file", Attribute: "olcTLSCertificateKeyFile"},
+	{Field: "tls_ca_certificate_file", Attribute: "olcTLSCACertificateFile"},
+	{Field: "tls_ca_certificate_path", Attribute: "olcTLSCACertificatePath"},
+	{Field: "tls_cipher_suite", Attribute: "olcTLSCipherSuite"},
+	{Field: "tls_protocol_min", Attribute: "olcTLSProtocolMin"},
+	{Field: "tls_verify_client", Attribute: "olcTLSVerifyClient"},
+}
+
+func resourceLDAPOLCGlobal() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLDAPOLCGlobalCreate,
+		ReadContext:   resourceLDAPOLCGlobalRead,
+		UpdateContext: resourceLDAPOLCGlobalUpdate,
+		DeleteContext: resourceLDAPOLCGlobalDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPOLCGlobalImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"log_level": {
+				Type:        schema.TypeSet,
+				Description: "The server log levels, by name or number (olcLogLevel), e.g. [\"stats\", \"sync\"].",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+			},
+			"size_limit": {
+				Type:        schema.TypeString,
+				Description: "The default maximum number of entries returned by a search, e.g. \"500\" or \"unlimited\" (olcSizeLimit).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"time_limit": {
+				Type:        schema.TypeString,
+				Description: "The default maximum number of seconds spent answering a search, e.g. \"3600\" or \"unlimited\" (olcTimeLimit).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"idle_timeout": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds after which idle client connections are closed; 0 disables the timeout (olcIdleTimeout).",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"write_timeout": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds after which connections with a pending write are closed; 0 disables the timeout (olcWriteTimeout).",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"threads": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum size of the server's worker thread pool (olcThreads).",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"password_hash": {
+				Type:        schema.TypeSet,
+				Description: "The password hash schemes used for new passwords, e.g. [\"{SSHA}\"] (olcPasswordHash).",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+			},
+			"tls_certificate_file": {
+				Type:        schema.TypeString,
+				Description: "Path of the server certificate, on the LDAP server (olcTLSCertificateFile).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_certificate_key_file": {
+				Type:        schema.TypeString,
+				Description: "Path of the server certificate's private key, on the LDAP server (olcTLSCertificateKeyFile).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_ca_certificate_file": {
+				Type:        schema.TypeString,
+				Description: "Path of the file with the trusted CA certificates, on the LDAP server (olcTLSCACertificateFile).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_ca_certificate_path": {
+				Type:        schema.TypeString,
+				Description: "Path of the directory with the trusted CA certificates, on the LDAP server (olcTLSCACertificatePath).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_cipher_suite": {
+				Type:        schema.TypeString,
+				Description: "The accepted TLS ciphers, in the syntax of the server's TLS library (olcTLSCipherSuite).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_protocol_min": {
+				Type:        schema.TypeString,
+				Description: "The minimum accepted SSL/TLS protocol version, e.g. \"3.3\" for TLS 1.2 (olcTLSProtocolMin).",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tls_verify_client": {
+				Type:         schema.TypeString,
+				Description:  "Whether client certificates are requested and checked: never, allow, try or demand (olcTLSVerifyClient).",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"never", "allow", "try", "demand", "hard", "true"}, false),
+			},
+		},
+
+		Description: "Manages the OpenLDAP global settings stored in the `cn=config` entry (olcGlobal).\n\n" +
+			"The `cn=config` tree is usually only writable by the local root user, so the provider is typically configured " +
+			"with `ldapi_socket` and `bind_method = \"external\"`. There can be only one instance of this resource per server; " +
+			"settings that are not configured are left as they are on the server, and destroying the resource only removes it " +
+			"from the Terraform state.",
+	}
+}
+
+func resourceLDAPOLCGlobalCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	tflog.Debug(ctx, "taking ownership of the global settings", map[string]interface{}{"dn": olcGlobalDN})
+
+	// all the configured settings are written, zero values included, as the
+	// settings the server holds are not known yet
+	request := ldap.NewModifyRequest(olcGlobalDN, []ldap.Control{})
+	replaceTypedAttributes(request, d, olcGlobalAttributes)
+
+	d.SetId(olcGlobalDN)
+	if err := modifyLDAPOLCGlobal(ctx, meta, request); err != nil {
+		d.SetId("")
+		return diag.FromErr(err)
+	}
+	return resourceLDAPOLCGlobalRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCGlobalRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	tflog.Debug(ctx, "reading the global settings", map[string]interface{}{"dn": olcGlobalDN})
+
+	request := ldap.NewSearchRequest(
+		olcGlobalDN,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=olcGlobal)",
+		[]string{"*"},
+		nil,
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    olcGlobalDN,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if len(sr.Entries) == 0 {
+		return diag.Errorf("%q is not an OpenLDAP olcGlobal entry", olcGlobalDN)
+	}
+
+	return diag.FromErr(readTypedAttributes(d, sr.Entries[0], olcGlobalAttributes))
+}
+
+func resourceLDAPOLCGlobalUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	tflog.Debug(ctx, "updating the global settings", map[string]interface{}{"dn": olcGlobalDN})
+
+	request := ldap.NewModifyRequest(olcGlobalDN, []ldap.Control{})
+	modifyTypedAttributes(request, d, olcGlobalAttributes)
+	if err := modifyLDAPOLCGlobal(ctx, meta, request); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLDAPOLCGlobalRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCGlobalDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	tflog.Debug(ctx, "releasing the global settings, server settings are left untouched", map[string]interface{}{"dn": olcGlobalDN})
+	d.SetId("")
+	return nil
+}
+
+func resourceLDAPOLCGlobalImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if d.Id() != olcGlobalDN {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %q", d.Id(), olcGlobalDN)
+	}
+	if err := diagnosticsError(resourceLDAPOLCGlobalRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// modifyLDAPOLCGlobal sends all the settings to write in a single modify
+// request: slapd requires some of them (e.g. the TLS certificate and its key)
+// to be changed together.
+func modifyLDAPOLCGlobal(ctx context.Context, meta interface{}, request *ldap.ModifyRequest) error {
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	if len(request.Changes) == 0 {
+		return nil
+	}
+
+	for _, change := range request.Changes {
+		tflog.Debug(ctx, "replacing setting", map[string]interface{}{
+			"attribute": change.Modification.Type,
+			"values":    change.Modification.Vals,
+		})
+	}
+
+	if err := client.Modify(request); err != nil {
+		tflog.Error(ctx, "error modifying the global settings", map[string]interface{}{
+			"dn":    olcGlobalDN,
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// olcSchemaBaseDN is the DN under which OpenLDAP keeps its schema entries.
+const olcSchemaBaseDN = "cn=schema,cn=config"
+
+// olcSchemaAttributes maps the typed fields of ldap_olc_schema onto the
+// attributes of the olcSchemaConfig object class.
+var olcSchemaAttributes = []typedAttribute{
+	{Field: "object_identifiers", Attribute: "olcObjectIdentifier"},
+	{Field: "attribute_types", Attribute: "olcAttributeTypes"},
+	{Field: "object_classes", Attribute: "olcObjectClasses"},
+}
+
+// orderingIndexRegexp matches the "{n}" prefix slapd adds to the naming
+// attribute and to the values of ordered attributes under cn=config.
+var orderingIndexRegexp = regexp.MustCompile(`^\{-?\d+\}`)
+
+// stripOrderingIndex removes the leading "{n}" ordering index, if any.
+func stripOrderingIndex(value string) string {
+	return orderingIndexRegexp.ReplaceAllString(value, "")
+}
+
+func resourceLDAPOLCSchema() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPOLCSchemaCreate),
+		ReadContext:   resourceLDAPOLCSchemaRead,
+		UpdateContext: withDNLock(resourceLDAPOLCSchemaUpdate),
+		DeleteContext: withDNLock(resourceLDAPOLCSchemaDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPOLCSchemaImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the schema entry (its cn, without the {n} ordering index), e.g. \"example\".",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Description: "The DN of the schema entry, as assigned by the server (e.g. cn={4}example,cn=schema,cn=config).",
+				Computed:    true,
+			},
+			"object_identifiers": {
+				Type:        schema.TypeList,
+				Description: "OID macros (olcObjectIdentifier), e.g. [\"exampleOID 1.3.6.1.4.1.99999\"].",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"attribute_types": {
+				Type:        schema.TypeList,
+				Description: "Attribute type definitions in RFC 4512 syntax (olcAttributeTypes); types must be listed after the ones they derive from.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"object_classes": {
+				Type:        schema.TypeList,
+				Description: "Object class definitions in RFC 4512 syntax (olcObjectClasses); classes must be listed after their superclasses.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Manages a custom OpenLDAP schema entry under `cn=schema,cn=config` (olcSchemaConfig).\n\n" +
+			"Like `ldap_olc_global`, this needs a connection allowed to write `cn=config`. Note that most slapd versions " +
+			"refuse to delete schema entries and to remove definitions still in use, so destroying or shrinking a " +
+			"schema may need manual intervention.",
+	}
+}
+
+func resourceLDAPOLCSchemaCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	name := d.Get("name").(string)
+	dn := fmt.Sprintf("cn=%s,%s", ldap.EscapeDN(name), olcSchemaBaseDN)
+
+	tflog.Debug(ctx, "creating schema", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+	request.Attribute("objectClass", []string{"olcSchemaConfig"})
+	request.Attribute("cn", []string{name})
+	addTypedAttributes(request, d, olcSchemaAttributes)
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating schema", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	// slapd renames the entry to cn={n}name: look it up to get the real DN
+	entry, err := findLDAPOLCSchema(meta, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		return diag.Errorf("schema %q not found after creation", name)
+	}
+
+	tflog.Debug(ctx, "schema added to the LDAP server", map[string]interface{}{
+		"name": name,
+		"dn":   entry.DN,
+	})
+
+	d.SetId(entry.DN)
+	return resourceLDAPOLCSchemaRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCSchemaRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Id()
+
+	tflog.Debug(ctx, "looking for schema", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=olcSchemaConfig)",
+		[]string{"cn", "olcObjectIdentifier", "olcAttributeTypes", "olcObjectClasses"},
+		nil,
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			tflog.Warn(ctx, "schema not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+			d.SetId("")
+			return nil
+		}
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if len(sr.Entries) == 0 {
+		return diag.Errorf("%q is not an OpenLDAP schema entry", dn)
+	}
+
+	entry := sr.Entries[0]
+	d.Set("dn", entry.DN)
+	d.Set("name", stripOrderingIndex(entry.GetAttributeValue("cn")))
+	for _, attribute := range olcSchemaAttributes {
+		values := []string{}
+		for _, value := range entry.GetAttributeValues(attribute.Attribute) {
+			values = append(values, stripOrderingIndex(value))
+		}
+		if err := d.Set(attribute.Field, values); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceLDAPOLCSchemaUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Id()
+
+	tflog.Debug(ctx, "updating schema", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	modifyTypedAttributes(request, d, olcSchemaAttributes)
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating schema", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+	return resourceLDAPOLCSchemaRead(ctx, d, meta)
+}
+
+func resourceLDAPOLCSchemaDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Id()
+
+	tflog.Debug(ctx, "removing schema", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_olc_schema::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "schema removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+// resourceLDAPOLCSchemaImport accepts either the DN of the schema entry or
+// its bare name.
+func resourceLDAPOLCSchemaImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.Contains(d.Id(), "=") {
+		entry, err := findLDAPOLCSchema(meta, d.Id())
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("schema %q not found under %q", d.Id(), olcSchemaBaseDN)
+		}
+		d.SetId(entry.DN)
+	}
+	if err := diagnosticsError(resourceLDAPOLCSchemaRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// findLDAPOLCSchema looks up the schema entry with the given name, regardless
+// of the ordering index slapd assigned to it; it returns nil if not found.
+func findLDAPOLCSchema(meta interface{}, name string) (*ldap.Entry, error) {
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+
+	escaped := ldap.EscapeFilter(name)
+	request := ldap.NewSearchRequest(
+		olcSchemaBaseDN,
+		ldap.ScopeSingleLevel,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		fmt.Sprintf("(&(objectClass=olcSchemaConfig)(|(cn=%s)(cn={*}%s)))", escaped, escaped),
+		[]string{"cn"},
+		nil,
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range sr.Entries {
+		if stripOrderingIndex(entry.GetAttributeValue("cn")) == name {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
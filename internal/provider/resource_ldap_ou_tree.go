@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPOUTree() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPOUTreeCreate),
+		ReadContext:   resourceLDAPOUTreeRead,
+		UpdateContext: resourceLDAPOUTreeUpdate,
+		DeleteContext: withDNLock(resourceLDAPOUTreeDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPOUTreeImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The DN of the deepest container, e.g. ou=a,ou=b,ou=c,dc=example,dc=com; it and its missing parents are created.",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"object_classes": {
+				Type:        schema.TypeList,
+				Description: "The object classes of the containers created, whose RDN attribute must be allowed by these classes. Default: [\"organizationalUnit\"].",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"remove_empty_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Remove the containers created by the resource on destroy, from the deepest one, as long as they are empty; the containers which existed before are never removed. Default: false.",
+				Optional:    true,
+				Default:     false,
+			},
+			"created_dns": {
+				Type:        schema.TypeList,
+				Description: "The DNs of the containers created by the resource, from the top.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Ensures a container and all its parents exist, creating the missing ones from the top, so that deep " +
+			"structures do not need a resource per level. The containers are not managed further: their attributes are only " +
+			"set when they are created.",
+	}
+}
+
+// ouTreeDNs returns the DNs of a container and of all its parents, from the
+// top.
+func ouTreeDNs(dn string) ([]string, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return nil, err
+	}
+	dns := make([]string, len(parsed.RDNs))
+	for i := range parsed.RDNs {
+		dns[len(parsed.RDNs)-1-i] = (&ldap.DN{RDNs: parsed.RDNs[i:]}).String()
+	}
+	return dns, nil
+}
+
+// createContainers creates a container and its missing parents, from the top,
+// with the given object classes (organizationalUnit by default); it returns
+// the DNs of the containers it created, even when it fails.
+func createContainers(ctx context.Context, meta interface{}, dn string, objectClasses []string) ([]string, error) {
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	if len(objectClasses) == 0 {
+		objectClasses = []string{"organizationalUnit"}
+	}
+
+	dns, err := ouTreeDNs(dn)
+	if err != nil {
+		return nil, err
+	}
+
+	// look for the deepest existing container
+	missing := len(dns)
+	for missing > 0 {
+		entry, err := searchEntry(client, dns[missing-1], []string{"1.1"}, providerConfig.DerefAliases)
+		if err != nil {
+			return nil, fmt.Errorf("error looking for %q: %w", dns[missing-1], err)
+		}
+		if entry != nil {
+			break
+		}
+		missing--
+	}
+
+	created := []string{}
+	for _, container := range dns[missing:] {
+		tflog.Debug(ctx, "creating container", map[string]interface{}{"dn": container})
+		request := ldap.NewAddRequest(container, nil)
+		request.Attribute("objectClass", objectClasses)
+		if err := addRDNAttributes(request, container); err != nil {
+			return created, err
+		}
+		if err := client.Add(request); err != nil {
+			if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultEntryAlreadyExists {
+				// created in the meantime, e.g. by another resource
+				continue
+			}
+			tflog.Error(ctx, "error creating container", map[string]interface{}{
+				"dn":    container,
+				"error": err.Error(),
+			})
+			return created, fmt.Errorf("error creating %q: %w", container, err)
+		}
+		created = append(created, container)
+	}
+	return created, nil
+}
+
+func resourceLDAPOUTreeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dn := d.Get("dn").(string)
+	objectClasses := convertToStringSlice(d.Get("object_classes").([]interface{}))
+
+	created, err := createContainers(ctx, meta, dn, objectClasses)
+	if err != nil {
+		// the containers created so far are recorded for their removal
+		if len(created) > 0 {
+			d.SetId(dn)
+			d.Set("created_dns", created)
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+	d.Set("created_dns", created)
+	return resourceLDAPOUTreeRead(ctx, d, meta)
+}
+
+func resourceLDAPOUTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for container", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, []string{"1.1"}, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "container not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+	}
+	return nil
+}
+
+// resourceLDAPOUTreeUpdate only records remove_empty_on_destroy.
+func resourceLDAPOUTreeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceLDAPOUTreeRead(ctx, d, meta)
+}
+
+func resourceLDAPOUTreeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	if !d.Get("remove_empty_on_destroy").(bool) {
+		return nil
+	}
+
+	created := convertToStringSlice(d.Get("created_dns").([]interface{}))
+	for i := len(created) - 1; i >= 0; i-- {
+		tflog.Debug(ctx, "removing container", map[string]interface{}{"dn": created[i]})
+		if err := client.Del(ldap.NewDelRequest(created[i], nil)); err != nil {
+			ldapErr, ok := err.(*ldap.Error)
+			switch {
+			case ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject:
+				continue
+			case ok && ldapErr.ResultCode == ldap.LDAPResultNotAllowedOnNonLeaf:
+				// it and its parents are still in use
+				tflog.Warn(ctx, "leaving container which is not empty", map[string]interface{}{"dn": created[i]})
+				return nil
+			default:
+				return diag.Errorf("error removing %q: %v", created[i], err)
+			}
+		}
+	}
+	return nil
+}
+
+func resourceLDAPOUTreeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	d.Set("remove_empty_on_destroy", false)
+	if err := diagnosticsError(resourceLDAPOUTreeRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestOUTreeDNs(t *testing.T) {
+	dns, err := ouTreeDNs("ou=a,ou=b,dc=example,dc=com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"dc=com", "dc=example,dc=com", "ou=b,dc=example,dc=com", "ou=a,ou=b,dc=example,dc=com"}
+	if !reflect.DeepEqual(dns, expected) {
+		t.Errorf("ouTreeDNs() = %q, expected %q", dns, expected)
+	}
+}
+
+func TestAccLDAPOUTree_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPOUTreeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ou_tree.test", "created_dns.#", "3"),
+					resource.TestCheckResourceAttr("ldap_ou_tree.test", "created_dns.0", "ou=sites,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_ou_tree.test", "created_dns.2", "ou=paris,ou=europe,ou=sites,dc=example,dc=com"),
+				),
+			},
+			{
+				ResourceName:            "ldap_ou_tree.test",
+				ImportState:             true,
+				ImportStateId:           "ou=paris,ou=europe,ou=sites,dc=example,dc=com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_dns", "remove_empty_on_destroy"},
+			},
+		},
+		CheckDestroy: testAccCheckLDAPObjectDestroy,
+	})
+}
+
+const testAccLDAPOUTreeConfig = `
+resource "ldap_ou_tree" "test" {
+  dn                      = "ou=paris,ou=europe,ou=sites,dc=example,dc=com"
+  remove_empty_on_destroy = true
+}
+`
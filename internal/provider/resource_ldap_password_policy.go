@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// passwordPolicyAttributes maps the typed fields of ldap_password_policy onto
+// the attributes of the pwdPolicy object class (draft-behera-ldap-password-policy).
+var passwordPolicyAttributes = []typedAttribute{
+	{Field: "pwd_attribute", Attribute: "pwdAttribute"},
+	{Field: "pwd_min_age", Attribute: "pwdMinAge"},
+	{Field: "pwd_max_age", Attribute: "pwdMaxAge"},
+	{Field: "pwd_in_history", Attribute: "pwdInHistory"},
+	{Field: "pwd_check_quality", Attribute: "pwdCheckQuality"},
+	{Field: "pwd_min_length", Attribute: "pwdMinLength"},
+	{Field: "pwd_max_length", Attribute: "pwdMaxLength"},
+	{Field: "pwd_expire_warning", Attribute: "pwdExpireWarning"},
+	{Field: "pwd_grace_authn_limit", Attribute: "pwdGraceAuthNLimit"},
+	{Field: "pwd_grace_expiry", Attribute: "pwdGraceExpiry"},
+	{Field: "pwd_lockout", Attribute: "pwdLockout"},
+	{Field: "pwd_lockout_duration", Attribute: "pwdLockoutDuration"},
+	{Field: "pwd_max_failure", Attribute: "pwdMaxFailure"},
+	{Field: "pwd_failure_count_interval", Attribute: "pwdFailureCountInterval"},
+	{Field: "pwd_must_change", Attribute: "pwdMustChange"},
+	{Field: "pwd_allow_user_change", Attribute: "pwdAllowUserChange", Default: "TRUE"},
+	{Field: "pwd_safe_modify", Attribute: "pwdSafeModify"},
+	{Field: "pwd_min_delay", Attribute: "pwdMinDelay"},
+	{Field: "pwd_max_delay", Attribute: "pwdMaxDelay"},
+	{Field: "pwd_max_idle", Attribute: "pwdMaxIdle"},
+}
+
+func resourceLDAPPasswordPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPPasswordPolicyCreate),
+		ReadContext:   resourceLDAPPasswordPolicyRead,
+		UpdateContext: withDNLock(resourceLDAPPasswordPolicyUpdate),
+		DeleteContext: withDNLock(resourceLDAPPasswordPolicyDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPPasswordPolicyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the password policy entry (e.g. cn=default,ou=policies,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the policy entry; pwdPolicy is auxiliary, so a structural class is needed too. Default: [\"device\", \"pwdPolicy\"].",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+			},
+			"pwd_attribute": {
+				Type:        schema.TypeString,
+				Description: "The attribute the policy applies to (pwdAttribute).",
+				Optional:    true,
+				Default:     "userPassword",
+			},
+			"pwd_min_age": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds that must elapse between password changes (pwdMinAge).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_max_age": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds after which a password expires; 0 means it never expires (pwdMaxAge).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_in_history": {
+				Type:         schema.TypeInt,
+				Description:  "Number of previous passwords kept in history and not allowed to be reused (pwdInHistory).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_check_quality": {
+				Type:         schema.TypeInt,
+				Description:  "Password quality checking: 0 disabled, 1 check if possible, 2 always check (pwdCheckQuality).",
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 2),
+			},
+			"pwd_min_length": {
+				Type:         schema.TypeInt,
+				Description:  "Minimum number of characters in a password (pwdMinLength).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_max_length": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of characters in a password (pwdMaxLength).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_expire_warning": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds before expiration during which warnings are returned on bind (pwdExpireWarning).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_grace_authn_limit": {
+				Type:         schema.TypeInt,
+				Description:  "Number of binds allowed with an expired password (pwdGraceAuthNLimit).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_grace_expiry": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds after expiration during which grace binds are allowed (pwdGraceExpiry).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_lockout": {
+				Type:        schema.TypeBool,
+				Description: "Whether accounts are locked after pwd_max_failure consecutive failed binds (pwdLockout).",
+				Optional:    true,
+			},
+			"pwd_lockout_duration": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds an account stays locked; 0 means until reset by an administrator (pwdLockoutDuration).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_max_failure": {
+				Type:         schema.TypeInt,
+				Description:  "Number of consecutive failed binds after which the account is locked (pwdMaxFailure).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_failure_count_interval": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds after which failed bind attempts are purged from the failure counter (pwdFailureCountInterval).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_must_change": {
+				Type:        schema.TypeBool,
+				Description: "Whether users must change their password after it has been reset by an administrator (pwdMustChange).",
+				Optional:    true,
+			},
+			"pwd_allow_user_change": {
+				Type:        schema.TypeBool,
+				Description: "Whether users are allowed to change their own password (pwdAllowUserChange). Default: true.",
+				Optional:    true,
+				Default:     true,
+			},
+			"pwd_safe_modify": {
+				Type:        schema.TypeBool,
+				Description: "Whether the existing password must be sent along with the new one when changing it (pwdSafeModify).",
+				Optional:    true,
+			},
+			"pwd_min_delay": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds to delay responses to the first failed bind (pwdMinDelay).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_max_delay": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of seconds to delay responses to failed binds (pwdMaxDelay).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pwd_max_idle": {
+				Type:         schema.TypeInt,
+				Description:  "Number of seconds an account may go without a successful bind before it is locked (pwdMaxIdle).",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+
+		Description: "Provides an OpenLDAP password policy (ppolicy overlay) entry.",
+	}
+}
+
+func resourceLDAPPasswordPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "creating a new password policy", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewAddRequest(dn, []ldap.Control{})
+
+	objectClasses := []string{"device", "pwdPolicy"}
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	addTypedAttributes(request, d, passwordPolicyAttributes)
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating password policy", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "password policy added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPPasswordPolicyRead(ctx, d, meta)
+}
+
+func resourceLDAPPasswordPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for password policy", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		providerConfig.DerefAliases,
+		0,
+		0,
+		false,
+		"(objectclass=*)",
+		[]string{"*"},
+		nil,
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			tflog.Warn(ctx, "password policy not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+			d.SetId("")
+			return nil
+		}
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	if len(sr.Entries) == 0 {
+		// e.g. hidden by an ACL
+		tflog.Warn(ctx, "password policy not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	entry := sr.Entries[0]
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	return diag.FromErr(readTypedAttributes(d, entry, passwordPolicyAttributes))
+}
+
+func resourceLDAPPasswordPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "updating password policy", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewModifyRequest(dn, []ldap.Control{})
+	// add and remove the object classes which changed only, as the
+	// structural class cannot be replaced
+	if err := updateLDAPAttributeSet(request, d, meta, "object_classes", "objectClass"); err != nil {
+		return diag.FromErr(err)
+	}
+	modifyTypedAttributes(request, d, passwordPolicyAttributes)
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating password policy", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPPasswordPolicyRead(ctx, d, meta)
+}
+
+func resourceLDAPPasswordPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	client := providerConfig.Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing password policy", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntry(ctx, client, dn, "ldap_password_policy::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "password policy removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+func resourceLDAPPasswordPolicyImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	if err := diagnosticsError(resourceLDAPPasswordPolicyRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
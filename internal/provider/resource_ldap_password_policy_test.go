@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccLDAPPasswordPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPPasswordPolicyConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "dn", "cn=default,ou=policies,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_attribute", "userPassword"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_min_length", "12"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_lockout", "true"),
+				),
+			},
+			{
+				Config: testAccLDAPPasswordPolicyConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_min_length", "16"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_lockout", "false"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_max_failure", "0"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_allow_user_change", "true"),
+				),
+			},
+			{
+				// false is written as FALSE rather than removing the
+				// attribute, which the server would take as TRUE
+				Config: testAccLDAPPasswordPolicyConfig_disallowUserChange,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_allow_user_change", "false"),
+					resource.TestCheckResourceAttr("ldap_password_policy.test", "pwd_safe_modify", "false"),
+					testAccCheckLDAPPasswordPolicyAttribute("pwdAllowUserChange", "FALSE"),
+					testAccCheckLDAPPasswordPolicyAttribute("pwdSafeModify", "FALSE"),
+					testAccCheckLDAPPasswordPolicyAttribute("pwdMaxFailure", "0"),
+				),
+			},
+			{
+				ResourceName:      "ldap_password_policy.test",
+				ImportState:       true,
+				ImportStateId:     "cn=default,ou=policies,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckLDAPPasswordPolicyAttribute(attribute, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*ProviderConfig).Connection
+		entry, err := searchEntry(conn, "cn=default,ou=policies,dc=example,dc=com", []string{attribute}, 0)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return fmt.Errorf("password policy not found")
+		}
+		if got := entry.GetAttributeValues(attribute); len(got) != 1 || got[0] != value {
+			return fmt.Errorf("expected %s to be %q, got %q", attribute, value, got)
+		}
+		return nil
+	}
+}
+
+const testAccLDAPPasswordPolicyConfig_basic = `
+resource "ldap_object" "policies" {
+  dn             = "ou=policies,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_password_policy" "test" {
+  dn              = "cn=default,${ldap_object.policies.dn}"
+  pwd_min_length  = 12
+  pwd_lockout     = true
+  pwd_max_failure = 5
+}
+`
+
+const testAccLDAPPasswordPolicyConfig_updated = `
+resource "ldap_object" "policies" {
+  dn             = "ou=policies,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_password_policy" "test" {
+  dn             = "cn=default,${ldap_object.policies.dn}"
+  pwd_min_length = 16
+}
+`
+
+const testAccLDAPPasswordPolicyConfig_disallowUserChange = `
+resource "ldap_object" "policies" {
+  dn             = "ou=policies,dc=example,dc=com"
+  object_classes = ["organizationalUnit"]
+}
+
+resource "ldap_password_policy" "test" {
+  dn                    = "cn=default,${ldap_object.policies.dn}"
+  pwd_min_length        = 16
+  pwd_max_failure       = 0
+  pwd_allow_user_change = false
+  pwd_safe_modify       = false
+}
+`
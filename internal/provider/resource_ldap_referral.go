@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPReferral() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPReferralCreate),
+		ReadContext:   resourceLDAPReferralRead,
+		UpdateContext: withDNLock(resourceLDAPReferralUpdate),
+		DeleteContext: withDNLock(resourceLDAPReferralDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPReferralImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the referral entry (e.g. ou=emea,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"ref": {
+				Type:        schema.TypeSet,
+				Description: "The LDAP URLs of the servers holding the subordinate naming context (ref), e.g. ldap://emea.example.com/ou=emea,dc=example,dc=com.",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateLDAPURL,
+				},
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the referral entry; referral is structural but allows no naming attribute, so an auxiliary class allowing it is needed too. Default: [\"referral\", \"extensibleObject\"].",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Description: "Provides an LDAP referral entry (RFC 3296), a subordinate knowledge reference pointing clients " +
+			"to the servers holding part of the tree.\n\n" +
+			"All the operations on the entry are sent with the ManageDsaIT control, so that the referral itself " +
+			"is managed rather than followed.",
+	}
+}
+
+// manageDsaITControls returns the controls of the operations on referral
+// entries themselves.
+func manageDsaITControls() []ldap.Control {
+	return []ldap.Control{ldap.NewControlManageDsaIT(true)}
+}
+
+func resourceLDAPReferralCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "creating a new referral", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewAddRequest(dn, manageDsaITControls())
+
+	objectClasses := []string{"referral", "extensibleObject"}
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	request.Attribute("ref", sortValues(convertToStringSlice(d.Get("ref").(*schema.Set).List())))
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating referral", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "referral added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	return resourceLDAPReferralRead(ctx, d, meta)
+}
+
+func resourceLDAPReferralRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for referral", map[string]interface{}{"dn": dn})
+
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		[]string{"objectClass", "ref"},
+		manageDsaITControls(),
+	)
+
+	sr, err := client.Search(request)
+	if err != nil {
+		if ldapErr, ok := err.(*ldap.Error); ok && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			tflog.Warn(ctx, "referral not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+			d.SetId("")
+			return nil
+		}
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if len(sr.Entries) == 0 {
+		tflog.Warn(ctx, "referral not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	entry := sr.Entries[0]
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	d.Set("ref", entry.GetAttributeValues("ref"))
+	return nil
+}
+
+func resourceLDAPReferralUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	if d.HasChange("ref") {
+		tflog.Debug(ctx, "updating referral", map[string]interface{}{"dn": dn})
+
+		request := ldap.NewModifyRequest(dn, manageDsaITControls())
+		request.Replace("ref", sortValues(convertToStringSlice(d.Get("ref").(*schema.Set).List())))
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating referral", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPReferralRead(ctx, d, meta)
+}
+
+func resourceLDAPReferralDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing referral", map[string]interface{}{"dn": dn})
+
+	if err := deleteLDAPEntryWithManageDsaIT(ctx, client, dn, "ldap_referral::delete", nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "referral removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+func resourceLDAPReferralImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	if err := diagnosticsError(resourceLDAPReferralRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
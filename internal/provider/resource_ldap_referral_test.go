@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLDAPReferral_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPReferralConfig("ldap://emea.example.com/ou=emea,dc=example,dc=com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_referral.test", "dn", "ou=emea,dc=example,dc=com"),
+					resource.TestCheckTypeSetElemAttr("ldap_referral.test", "ref.*", "ldap://emea.example.com/ou=emea,dc=example,dc=com"),
+				),
+			},
+			{
+				Config: testAccLDAPReferralConfig("ldap://emea2.example.com/ou=emea,dc=example,dc=com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_referral.test", "ref.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ldap_referral.test", "ref.*", "ldap://emea2.example.com/ou=emea,dc=example,dc=com"),
+				),
+			},
+			{
+				ResourceName:      "ldap_referral.test",
+				ImportState:       true,
+				ImportStateId:     "ou=emea,dc=example,dc=com",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccLDAPReferralConfig(ref string) string {
+	return fmt.Sprintf(`
+resource "ldap_referral" "test" {
+  dn  = "ou=emea,dc=example,dc=com"
+  ref = [%q]
+}
+`, ref)
+}
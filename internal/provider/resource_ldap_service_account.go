@@ -0,0 +1,301 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLDAPServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withDNLock(resourceLDAPServiceAccountCreate),
+		ReadContext:   resourceLDAPServiceAccountRead,
+		UpdateContext: withDNLock(resourceLDAPServiceAccountUpdate),
+		DeleteContext: withDNLock(resourceLDAPServiceAccountDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPServiceAccountImport,
+		},
+
+		CustomizeDiff: customdiff.All(
+			customizeDiffServiceAccountRotation,
+			customizeDiffOnDestroy,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"dn": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateDN,
+				Description:  "The Distinguished Name (DN) of the account entry (e.g. uid=backup,ou=services,dc=example,dc=com).",
+				Required:     true,
+				ForceNew:     true,
+			},
+			"object_classes": {
+				Type:        schema.TypeSet,
+				Description: "The set of classes of the account entry. Default: [\"account\", \"simpleSecurityObject\"].",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "The description of the account.",
+				Optional:    true,
+			},
+			"password_length": {
+				Type:         schema.TypeInt,
+				Description:  "The length of the generated passwords; changing it generates a new password. Default: 32.",
+				Optional:     true,
+				Default:      32,
+				ValidateFunc: validation.IntBetween(8, 128),
+			},
+			"rotation": {
+				Type:        schema.TypeList,
+				Description: "When to generate a new password for the account.",
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keepers": {
+							Type:        schema.TypeMap,
+							Description: "Arbitrary values which, when changed, generate a new password.",
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"rotation_days": {
+							Type:         schema.TypeInt,
+							Description:  "The number of days after which a new password is generated, on the first apply once they have elapsed.",
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Description: "The generated password of the account, set as its `userPassword`.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"password_version": {
+				Type:        schema.TypeInt,
+				Description: "The number of passwords generated for the account, starting at 1; suitable for the `password_version` of write-only arguments receiving `password`.",
+				Computed:    true,
+			},
+			"rotated_at": {
+				Type:        schema.TypeString,
+				Description: "When the current password was generated (RFC 3339).",
+				Computed:    true,
+			},
+			"on_destroy":         onDestroySchema(),
+			"membership_cleanup": membershipCleanupSchema(),
+			"controls":           requestControlsSchema(),
+		},
+
+		Description: "Provides an LDAP service account whose password is generated by the provider, and generated " +
+			"again when the `keepers` of its `rotation` change or after its `rotation_days`.\n\n" +
+			"The password is stored in the state, as a sensitive value, so that it can be passed on to the " +
+			"applications using the account; it is not read back from the directory.",
+	}
+}
+
+// rotationDue tells whether a password generated at rotatedAt (RFC 3339) is
+// older than the given number of days; invalid times are always due.
+func rotationDue(rotatedAt string, days int, now time.Time) bool {
+	if days <= 0 {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, rotatedAt)
+	if err != nil {
+		return true
+	}
+	return !now.Before(t.AddDate(0, 0, days))
+}
+
+// customizeDiffServiceAccountRotation plans a new password when the keepers or
+// the password length change, when the password is older than rotation_days,
+// or when it is unknown because the account was imported.
+func customizeDiffServiceAccountRotation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	password, _ := d.GetChange("password")
+	rotate := password.(string) == "" || d.HasChange("rotation.0.keepers") || d.HasChange("password_length")
+	if !rotate {
+		rotatedAt, _ := d.GetChange("rotated_at")
+		rotate = rotationDue(rotatedAt.(string), d.Get("rotation.0.rotation_days").(int), time.Now())
+	}
+	if !rotate {
+		return nil
+	}
+	for _, key := range []string{"password", "password_version", "rotated_at"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setServiceAccountPassword records a newly generated password in the state.
+func setServiceAccountPassword(d *schema.ResourceData, password string, version int) {
+	d.Set("password", password)
+	d.Set("password_version", version)
+	d.Set("rotated_at", time.Now().UTC().Format(time.RFC3339))
+}
+
+func resourceLDAPServiceAccountCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "creating a new service account", map[string]interface{}{"dn": dn})
+
+	password, err := generatePassword(d.Get("password_length").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	controls, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request := ldap.NewAddRequest(dn, controls)
+
+	objectClasses := []string{"account", "simpleSecurityObject"}
+	if v, ok := d.GetOk("object_classes"); ok && v.(*schema.Set).Len() > 0 {
+		objectClasses = convertToStringSlice(v.(*schema.Set).List())
+	}
+	request.Attribute("objectClass", objectClasses)
+
+	if err := addRDNAttributes(request, dn); err != nil {
+		return diag.FromErr(err)
+	}
+	if v, ok := d.GetOk("description"); ok {
+		request.Attribute("description", []string{v.(string)})
+	}
+	request.Attribute(passwordAttribute, []string{password})
+	request.Controls = withPasswordPolicyControl(request.Controls)
+
+	if err := client.Add(request); err != nil {
+		tflog.Error(ctx, "error creating service account", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(passwordPolicyError(err))
+	}
+
+	tflog.Debug(ctx, "service account added to LDAP server", map[string]interface{}{"dn": dn})
+
+	d.SetId(dn)
+	setServiceAccountPassword(d, password, 1)
+	return resourceLDAPServiceAccountRead(ctx, d, meta)
+}
+
+func resourceLDAPServiceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	providerConfig := meta.(*ProviderConfig)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "looking for service account", map[string]interface{}{"dn": dn})
+
+	entry, err := searchEntryAfterWrite(ctx, meta, dn, []string{"objectClass", "description"}, providerConfig.DerefAliases)
+	if err != nil {
+		tflog.Error(ctx, "lookup failed", map[string]interface{}{
+			"dn":    dn,
+			"error": err.Error(),
+		})
+		return diag.FromErr(err)
+	}
+	if entry == nil {
+		tflog.Warn(ctx, "service account not found, removing it from the state because it no longer exists in LDAP", map[string]interface{}{"dn": dn})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("object_classes", entry.GetAttributeValues("objectClass"))
+	d.Set("description", entry.GetAttributeValue("description"))
+	return nil
+}
+
+func resourceLDAPServiceAccountUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	client := meta.(*ProviderConfig).Connection
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "updating service account", map[string]interface{}{"dn": dn})
+
+	controls, err := requestControls(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request := ldap.NewModifyRequest(dn, controls)
+	if d.HasChange("description") {
+		if v, ok := d.GetOk("description"); ok {
+			request.Replace("description", []string{v.(string)})
+		} else {
+			request.Replace("description", []string{})
+		}
+	}
+
+	// rotated_at is unknown in the plan when a new password is due
+	var password string
+	rotate := d.HasChange("rotated_at")
+	if rotate {
+		var err error
+		if password, err = generatePassword(d.Get("password_length").(int)); err != nil {
+			return diag.FromErr(err)
+		}
+		request.Replace(passwordAttribute, []string{password})
+		request.Controls = withPasswordPolicyControl(request.Controls)
+	}
+
+	if len(request.Changes) > 0 {
+		if err := client.Modify(request); err != nil {
+			tflog.Error(ctx, "error updating service account", map[string]interface{}{
+				"dn":    dn,
+				"error": err.Error(),
+			})
+			return diag.FromErr(passwordPolicyError(err))
+		}
+	}
+
+	if rotate {
+		version, _ := d.GetChange("password_version")
+		setServiceAccountPassword(d, password, version.(int)+1)
+		tflog.Info(ctx, "service account password rotated", map[string]interface{}{"dn": dn})
+	}
+
+	return resourceLDAPServiceAccountRead(ctx, d, meta)
+}
+
+func resourceLDAPServiceAccountDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogging(ctx, meta)
+	dn := d.Get("dn").(string)
+
+	tflog.Debug(ctx, "removing service account", map[string]interface{}{"dn": dn})
+
+	if err := destroyLDAPEntry(ctx, d, meta, "ldap_service_account::delete"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "service account removed", map[string]interface{}{"dn": dn})
+	return nil
+}
+
+// resourceLDAPServiceAccountImport imports an account without its password,
+// which cannot be read back: a new one is generated on the next apply.
+func resourceLDAPServiceAccountImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("dn", d.Id())
+	d.Set("password_length", 32)
+	if err := diagnosticsError(resourceLDAPServiceAccountRead(ctx, d, meta)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword(40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(password) != 40 {
+		t.Errorf("expected 40 characters, got %d", len(password))
+	}
+	for _, c := range password {
+		if !strings.ContainsRune(passwordCharacters, c) {
+			t.Errorf("unexpected character %q in %q", c, password)
+		}
+	}
+	other, err := generatePassword(40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == password {
+		t.Errorf("expected different passwords, got %q twice", password)
+	}
+}
+
+func TestRotationDue(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		rotatedAt string
+		days      int
+		want      bool
+	}{
+		{"2024-03-01T12:00:00Z", 0, false},
+		{"2024-03-01T12:00:00Z", 30, false},
+		{"2024-03-01T12:00:00Z", 9, true},
+		{"2024-03-01T12:00:01Z", 9, false},
+		{"", 30, true},
+	}
+	for _, c := range cases {
+		if got := rotationDue(c.rotatedAt, c.days, now); got != c.want {
+			t.Errorf("rotationDue(%q, %d) = %v, want %v", c.rotatedAt, c.days, got, c.want)
+		}
+	}
+}
+
+func TestAccLDAPServiceAccount_rotation(t *testing.T) {
+	var password string
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLDAPServiceAccountConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_service_account.test", "dn", "uid=svc-test,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("ldap_service_account.test", "password_version", "1"),
+					testAccCheckServiceAccountPassword(&password, false),
+				),
+			},
+			{
+				Config: testAccLDAPServiceAccountConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_service_account.test", "password_version", "1"),
+					testAccCheckServiceAccountPassword(&password, false),
+				),
+			},
+			{
+				Config: testAccLDAPServiceAccountConfig("2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_service_account.test", "password_version", "2"),
+					testAccCheckServiceAccountPassword(&password, true),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckServiceAccountPassword checks whether the password of the
+// account changed since the previous step.
+func testAccCheckServiceAccountPassword(previous *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		password := s.RootModule().Resources["ldap_service_account.test"].Primary.Attributes["password"]
+		if password == "" {
+			return fmt.Errorf("password is not set")
+		}
+		if *previous != "" && (password != *previous) != changed {
+			return fmt.Errorf("expected the password to change: %v", changed)
+		}
+		*previous = password
+		return nil
+	}
+}
+
+func testAccLDAPServiceAccountConfig(keeper string) string {
+	return fmt.Sprintf(`
+resource "ldap_service_account" "test" {
+  dn          = "uid=svc-test,dc=example,dc=com"
+  description = "test service account"
+
+  rotation {
+    keepers = {
+      version = %q
+    }
+  }
+}
+`, keeper)
+}
@@ -18,7 +18,7 @@ func main() {
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{ProviderFunc: provider.Provider}
+	opts := &plugin.ServeOpts{GRPCProviderFunc: provider.ProtocolProvider}
 
 	if debugMode {
 		err := plugin.Debug(context.Background(), "registry.terraform.io/elastic-infra/terraform-provider-ldap", opts)